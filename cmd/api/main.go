@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -9,6 +11,7 @@ import (
 	"github.com/ownafarm/ownafarm-backend/internal/database"
 	"github.com/ownafarm/ownafarm-backend/internal/handlers"
 	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
 	"github.com/ownafarm/ownafarm-backend/internal/repositories"
 	"github.com/ownafarm/ownafarm-backend/internal/routes"
 	"github.com/ownafarm/ownafarm-backend/internal/services"
@@ -19,13 +22,15 @@ func main() {
 	// Load config
 	cfg := config.LoadConfig()
 
-	// Validate JWT secret
-	if cfg.JWT.Secret == "" {
-		log.Fatal("JWT_SECRET environment variable is required")
+	// Load the keyring used to encrypt farmer PII
+	keyring, err := pii.NewKeyring(&cfg.PII)
+	if err != nil {
+		log.Fatal("Failed to load PII keyring:", err)
 	}
+	pii.SetDefault(keyring)
 
 	// 1. Connect to database
-	err := database.Connect(&cfg.DB)
+	err = database.Connect(&cfg.DB)
 	if err != nil {
 		panic(err)
 	}
@@ -39,20 +44,44 @@ func main() {
 
 	// 3. Setup router
 	router := gin.Default()
+	// Only proxies in front of the API may set the client IP used for per-IP rate limits
+	if err := router.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 
 	// 4. Setup CORS - Allow all origins
 	router.Use(cors.New(cors.Config{
-		AllowAllOrigins:  true,
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowAllOrigins: true,
+		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:    []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		ExposeHeaders: []string{
+			"Content-Length",
+			middleware.RateLimitLimitHeader,
+			middleware.RateLimitRemainingHeader,
+			middleware.RateLimitResetHeader,
+			middleware.RateLimitPolicyHeader,
+			middleware.RetryAfterHeader,
+		},
 		AllowCredentials: false, // Must be false when AllowAllOrigins is true
 	}))
 
 	// 5. Initialize Utils
-	jwtUtil := utils.NewJWTUtil(&cfg.JWT)
-	adminJwtUtil := utils.NewAdminJWTUtil(&cfg.JWT)
-	farmerJwtUtil := utils.NewFarmerJWTUtil(&cfg.JWT)
+	jwtUtil, err := utils.NewJWTUtil(&cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to load investor JWT keys:", err)
+	}
+	adminJwtUtil, err := utils.NewAdminJWTUtil(&cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to load admin JWT keys:", err)
+	}
+	farmerJwtUtil, err := utils.NewFarmerJWTUtil(&cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to load farmer JWT keys:", err)
+	}
+	sessionJwtUtil, err := utils.NewSessionJWTUtil(&cfg.JWT)
+	if err != nil {
+		log.Fatal("Failed to load session JWT keys:", err)
+	}
 
 	// 6. Initialize Storage Service
 	storageService, err := services.NewR2StorageService(&cfg.R2)
@@ -63,8 +92,10 @@ func main() {
 	// 7. Initialize Services
 	nonceService := services.NewNonceService(database.Valkey, &cfg.Auth)
 	farmerNonceService := services.NewFarmerNonceService(database.Valkey, &cfg.Auth)
-	authService := services.NewAuthService(&cfg.Auth)
 	rateLimitService := services.NewRateLimitService(database.Valkey)
+	tokenService := services.NewTokenService(database.Valkey, &cfg.JWT)
+	streamTicketService := services.NewStreamTicketService(database.Valkey)
+	locker := services.NewValkeyLocker(database.Valkey)
 
 	// 8. Initialize Repositories
 	userRepo := repositories.NewUserRepository(database.DB)
@@ -73,7 +104,15 @@ func main() {
 	auditLogRepo := repositories.NewAuditLogRepository(database.DB)
 	farmRepo := repositories.NewFarmRepository(database.DB)
 	invoiceRepo := repositories.NewInvoiceRepository(database.DB)
+	invoiceVersionRepo := repositories.NewInvoiceVersionRepository(database.DB)
+	invoiceTransitionRepo := repositories.NewInvoiceStatusTransitionRepository(database.DB)
 	investmentRepo := repositories.NewInvestmentRepository(database.DB)
+	notificationRepo := repositories.NewNotificationRepository(database.DB)
+	achievementRepo := repositories.NewAchievementRepository(database.DB)
+	farmerNotificationRepo := repositories.NewFarmerNotificationRepository(database.DB)
+	farmerProfileChangeRepo := repositories.NewFarmerProfileChangeRepository(database.DB)
+	farmerSubmissionRepo := repositories.NewFarmerSubmissionRepository(database.DB)
+	dataSubjectRepo := repositories.NewDataSubjectRepository(database.DB)
 
 	// 9. Initialize Blockchain Service
 	blockchainService, err := services.NewBlockchainService(&cfg.Blockchain)
@@ -82,52 +121,140 @@ func main() {
 	}
 
 	// 10. Initialize Services
-	farmerService := services.NewFarmerService(farmerRepo, storageService, auditLogRepo)
-	farmService := services.NewFarmService(farmRepo)
-	invoiceService := services.NewInvoiceService(invoiceRepo, farmRepo, storageService, auditLogRepo)
-	investmentService := services.NewInvestmentService(investmentRepo, invoiceRepo, userRepo, blockchainService)
+	authService := services.NewAuthService(&cfg.Auth, blockchainService)
+	auditService := services.NewAuditService(auditLogRepo)
+	identityService := services.NewIdentityService(userRepo, farmerRepo, adminUserRepo)
+	eventBus := services.NewValkeyEventBus(database.Valkey)
+	notificationChannels, err := services.NewNotificationChannels(&cfg.Notification, eventBus)
+	if err != nil {
+		log.Fatal("Failed to initialize notification channels:", err)
+	}
+	notificationService := services.NewNotificationService(notificationRepo, notificationChannels)
+	farmerNotificationTransports, err := services.NewFarmerNotificationTransports(&cfg.FarmerNotification)
+	if err != nil {
+		log.Fatal("Failed to initialize farmer notification transports:", err)
+	}
+	farmerNotificationService := services.NewFarmerNotificationService(
+		farmerNotificationRepo,
+		farmerRepo,
+		farmerNotificationTransports,
+		cfg.FarmerNotification.MaxAttempts,
+		time.Duration(cfg.FarmerNotification.RetryBaseSeconds)*time.Second,
+	)
+	go farmerNotificationService.Run(context.Background(), time.Duration(cfg.FarmerNotification.RetryPollSeconds)*time.Second)
+	documentPolicy, err := services.NewRequiredDocumentPolicy(&cfg.DocumentPolicy)
+	if err != nil {
+		log.Fatal("Failed to load required document policy:", err)
+	}
+	farmerService := services.NewFarmerService(farmerRepo, storageService, auditService, farmerSubmissionRepo, documentPolicy, farmerNotificationService)
+	farmerProfileService := services.NewFarmerProfileService(farmerRepo, farmerProfileChangeRepo, invoiceRepo, auditService, farmerNotificationService)
+	adminUserService := services.NewAdminUserService(adminUserRepo, auditService)
+	auditLogService := services.NewAuditLogService(auditLogRepo, auditService)
+	dataSubjectService := services.NewDataSubjectService(dataSubjectRepo, invoiceRepo, storageService, auditService)
+	farmService := services.NewFarmService(farmRepo, auditService)
+	invoiceService := services.NewInvoiceService(
+		invoiceRepo,
+		invoiceVersionRepo,
+		invoiceTransitionRepo,
+		farmRepo,
+		farmerRepo,
+		storageService,
+		auditService,
+		farmerNotificationService,
+		time.Duration(cfg.InvoiceLifecycle.FundingWindowDays)*24*time.Hour,
+	)
+	invoiceLifecycleService := services.NewInvoiceLifecycleService(
+		invoiceRepo,
+		invoiceTransitionRepo,
+		farmRepo,
+		investmentRepo,
+		blockchainService,
+		farmerNotificationService,
+		notificationService,
+		locker,
+		time.Duration(cfg.InvoiceLifecycle.GraceDays)*24*time.Hour,
+	)
+	go invoiceLifecycleService.Run(context.Background(), time.Duration(cfg.InvoiceLifecycle.PollSeconds)*time.Second)
+	investmentService := services.NewInvestmentService(investmentRepo, invoiceRepo, userRepo, achievementRepo, blockchainService, invoiceLifecycleService, notificationService, eventBus)
 	leaderboardRepo := repositories.NewLeaderboardRepository(database.DB)
-	leaderboardService := services.NewLeaderboardService(leaderboardRepo, database.Valkey)
+	leaderboardService := services.NewLeaderboardService(leaderboardRepo, database.Valkey, eventBus)
+	eventStreamService := services.NewEventStreamService(
+		eventBus,
+		locker,
+		userRepo,
+		investmentService,
+		leaderboardService,
+		time.Duration(cfg.EventStream.TickSeconds)*time.Second,
+	)
 	adminAuthService := services.NewAdminAuthService(
 		adminUserRepo,
 		rateLimitService,
 		adminJwtUtil,
 		authService,
 		database.Valkey,
+		tokenService,
+		auditService,
 		cfg.Auth.NonceTTLMinutes,
 	)
 
 	// 11. Initialize Handlers
 	userHandler := handlers.NewUserHandler(userRepo)
-	authHandler := handlers.NewAuthHandler(userRepo, nonceService, authService, jwtUtil)
+	authHandler := handlers.NewAuthHandler(userRepo, nonceService, authService, jwtUtil, tokenService, auditService)
+	sessionHandler := handlers.NewSessionHandler(identityService, userRepo, adminUserRepo, nonceService, authService, sessionJwtUtil, tokenService, auditService)
 	farmerHandler := handlers.NewFarmerHandler(farmerService)
-	farmerAuthHandler := handlers.NewFarmerAuthHandler(farmerRepo, farmerNonceService, authService, farmerJwtUtil)
+	farmerProfileHandler := handlers.NewFarmerProfileHandler(farmerProfileService)
+	dataSubjectHandler := handlers.NewDataSubjectHandler(dataSubjectService)
+	adminUserHandler := handlers.NewAdminUserHandler(adminUserService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditLogService)
+	farmerAuthHandler := handlers.NewFarmerAuthHandler(farmerRepo, farmerNonceService, authService, farmerJwtUtil, tokenService, auditService)
 	adminAuthHandler := handlers.NewAdminAuthHandler(adminAuthService)
+	jwksHandler := handlers.NewJWKSHandler(jwtUtil.KeySet(), farmerJwtUtil.KeySet(), adminJwtUtil.KeySet(), sessionJwtUtil.KeySet())
 	farmHandler := handlers.NewFarmHandler(farmService)
 	invoiceHandler := handlers.NewInvoiceHandler(invoiceService)
 	investmentHandler := handlers.NewInvestmentHandler(investmentService)
 	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	eventStreamHandler := handlers.NewEventStreamHandler(
+		eventStreamService,
+		streamTicketService,
+		time.Duration(cfg.EventStream.TickSeconds)*time.Second,
+		time.Duration(cfg.EventStream.HeartbeatSeconds)*time.Second,
+	)
 
 	// 12. Initialize Middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtUtil)
-	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(adminJwtUtil)
-	farmerAuthMiddleware := middleware.NewFarmerAuthMiddleware(farmerJwtUtil)
+	authMiddleware := middleware.NewAuthMiddleware(jwtUtil, sessionJwtUtil, tokenService, streamTicketService)
+	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(adminJwtUtil, sessionJwtUtil, adminUserRepo, tokenService)
+	farmerAuthMiddleware := middleware.NewFarmerAuthMiddleware(farmerJwtUtil, sessionJwtUtil, farmerRepo, tokenService)
+	sessionAuthMiddleware := middleware.NewSessionAuthMiddleware(sessionJwtUtil, tokenService)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitService, &cfg.RateLimit)
+	auditMiddleware := middleware.NewAuditMiddleware(auditService)
+	router.Use(auditMiddleware.Audit())
 
 	// 13. Routes
 	routes.SetupRoutes(
 		router,
 		userHandler,
 		authHandler,
+		sessionHandler,
 		farmerHandler,
 		farmerAuthHandler,
 		adminAuthHandler,
+		jwksHandler,
 		farmHandler,
 		invoiceHandler,
 		investmentHandler,
 		leaderboardHandler,
+		notificationHandler,
+		eventStreamHandler,
+		farmerProfileHandler,
+		dataSubjectHandler,
+		adminUserHandler,
+		auditLogHandler,
 		authMiddleware,
 		adminAuthMiddleware,
 		farmerAuthMiddleware,
+		sessionAuthMiddleware,
+		rateLimitMiddleware,
 	)
 
 	// 13. Run the server
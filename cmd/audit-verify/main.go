@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/auditchain"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/database"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+)
+
+var (
+	batchSize = flag.Int("batch", 1000, "entries loaded per batch")
+	seal      = flag.Bool("seal", false, "hash the entries written before the chain existed before verifying")
+	anchor    = flag.Bool("anchor", false, "store the Merkle root of the entries since the last anchor and publish it on-chain")
+)
+
+// Walks the admin audit log hash chain and reports every break: modified entries, deleted entries
+// and anchored ranges that no longer match their Merkle root. Exits with status 1 on a break.
+// Run it periodically with -anchor to publish new Merkle roots. Roots are built and checked
+// with auditchain.MerkleRoot, the domain-separated RFC 6962 tree over the entry hashes.
+func main() {
+	flag.Parse()
+	fmt.Println("=== Audit Log Chain Verification ===")
+
+	cfg := config.LoadConfig()
+	if err := database.Connect(&cfg.DB); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	chainRepo := repositories.NewAuditChainRepository(database.DB)
+
+	if *seal {
+		sealed, err := chainRepo.SealPending()
+		if err != nil {
+			log.Fatal("Failed to seal audit logs:", err)
+		}
+		fmt.Printf("sealed: %d entries\n", sealed)
+	}
+
+	verifier := auditchain.NewVerifier()
+	var lastSequence int64
+	for {
+		entries, err := chainRepo.ListBySequence(lastSequence, *batchSize)
+		if err != nil {
+			log.Fatal("Failed to load audit logs:", err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+		for i := range entries {
+			if err := verifier.Add(&entries[i]); err != nil {
+				log.Fatal(err)
+			}
+		}
+		lastSequence = entries[len(entries)-1].Sequence
+	}
+
+	anchors, err := chainRepo.GetAnchors()
+	if err != nil {
+		log.Fatal("Failed to load anchors:", err)
+	}
+	for i := range anchors {
+		verifier.CheckAnchor(&anchors[i])
+	}
+
+	fmt.Printf("entries: %d checked, %d unsealed\n", verifier.Checked, verifier.Unsealed)
+	fmt.Printf("anchors: %d checked\n", len(anchors))
+	if len(verifier.Breaks) > 0 {
+		fmt.Printf("\n❌ %d breaks found:\n", len(verifier.Breaks))
+		for _, b := range verifier.Breaks {
+			fmt.Println("  " + b.String())
+		}
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ Audit log chain is intact")
+
+	if *anchor {
+		if err := anchorChain(cfg, chainRepo, verifier, anchors); err != nil {
+			log.Fatal("Failed to anchor audit log chain:", err)
+		}
+	}
+}
+
+// anchorChain stores the Merkle root of the entries after the last anchor and publishes every
+// root that is not on-chain yet
+func anchorChain(cfg *config.Config, chainRepo repositories.AuditChainRepository, verifier *auditchain.Verifier, anchors []models.AuditLogAnchor) error {
+	var from int64 = 1
+	if len(anchors) > 0 {
+		from = anchors[len(anchors)-1].ToSequence + 1
+	}
+	to := verifier.LastSequence()
+
+	if from <= to {
+		hashes, ok := verifier.Hashes(from, to)
+		if !ok {
+			return fmt.Errorf("entries #%d to #%d are not sealed, run with -seal", from, to)
+		}
+		root, err := auditchain.MerkleRoot(hashes)
+		if err != nil {
+			return err
+		}
+		newAnchor := models.AuditLogAnchor{FromSequence: from, ToSequence: to, MerkleRoot: root}
+		if err := chainRepo.CreateAnchor(&newAnchor); err != nil {
+			return fmt.Errorf("failed to create anchor: %w", err)
+		}
+		anchors = append(anchors, newAnchor)
+		fmt.Printf("anchor: entries #%d to #%d, root %s\n", from, to, root)
+	} else {
+		fmt.Println("anchor: no new entries")
+	}
+
+	if cfg.Blockchain.AnchorKey == "" {
+		fmt.Println("anchor: AUDIT_ANCHOR_PRIVATE_KEY is not set, roots are stored but not published")
+		return nil
+	}
+	blockchainService, err := services.NewBlockchainService(&cfg.Blockchain)
+	if err != nil {
+		return err
+	}
+
+	for i := range anchors {
+		if anchors[i].TxHash != nil {
+			continue
+		}
+		root, err := hex.DecodeString(anchors[i].MerkleRoot)
+		if err != nil || len(root) != 32 {
+			return fmt.Errorf("anchor %s has an invalid Merkle root", anchors[i].ID)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		txHash, err := blockchainService.AnchorHash(ctx, [32]byte(root))
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		anchors[i].TxHash = &txHash
+		anchors[i].AnchoredAt = &now
+		if err := chainRepo.UpdateAnchor(&anchors[i]); err != nil {
+			return fmt.Errorf("failed to update anchor: %w", err)
+		}
+		fmt.Printf("anchor: entries #%d to #%d published in %s\n", anchors[i].FromSequence, anchors[i].ToSequence, txHash)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/utils"
+)
+
+var keyID = flag.String("id", time.Now().UTC().Format("20060102"), "key id, unique within the audience")
+
+// Generates an Ed25519 signing key entry for JWT_INVESTOR_KEYS, JWT_FARMER_KEYS, JWT_ADMIN_KEYS or JWT_SESSION_KEYS.
+// Generate a separate key for every audience.
+func main() {
+	flag.Parse()
+
+	seed, err := utils.GenerateSigningKey()
+	if err != nil {
+		log.Fatal("Failed to generate key:", err)
+	}
+	fmt.Printf("%s:%s\n", *keyID, seed)
+}
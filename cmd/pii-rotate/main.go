@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/database"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
+)
+
+// farmerPII holds the raw encrypted columns of a farmer
+type farmerPII struct {
+	ID                string
+	IDNumber          string
+	DateOfBirth       string
+	NPWP              *string
+	BankAccountNumber string
+	IDNumberBidx      *string `gorm:"column:id_number_bidx"`
+}
+
+// jsonRow is a row of a table that copies farmer PII into a JSON column
+type jsonRow struct {
+	ID      string
+	Payload json.RawMessage
+}
+
+var (
+	batchSize = flag.Int("batch", 100, "rows loaded per batch")
+	decrypt   = flag.Bool("decrypt", false, "write plaintext values back, before rolling back migration 000018")
+)
+
+// Re-encrypts farmer PII with the active key: plaintext rows from before encryption was enabled
+// and rows encrypted with an older key. Blind indexes are filled in on the way.
+func main() {
+	flag.Parse()
+	fmt.Println("=== Farmer PII Key Rotation ===")
+
+	cfg := config.LoadConfig()
+
+	keyring, err := pii.NewKeyring(&cfg.PII)
+	if err != nil {
+		log.Fatal("Failed to load PII keyring:", err)
+	}
+	pii.SetDefault(keyring)
+
+	if err := database.Connect(&cfg.DB); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	farmers, err := rotateFarmers(keyring)
+	if err != nil {
+		log.Fatal("Failed to rotate farmers:", err)
+	}
+	fmt.Printf("farmers: %d updated\n", farmers)
+
+	for _, target := range []struct{ table, column string }{
+		{"farmer_submissions", "snapshot"},
+		{"farmer_profile_changes", "old_values"},
+		{"farmer_profile_changes", "new_values"},
+	} {
+		updated, err := rotateJSONColumn(keyring, target.table, target.column)
+		if err != nil {
+			log.Fatalf("Failed to rotate %s.%s: %v", target.table, target.column, err)
+		}
+		fmt.Printf("%s.%s: %d updated\n", target.table, target.column, updated)
+	}
+
+	if *decrypt {
+		fmt.Println("\n✅ Farmer PII is stored in plaintext")
+		return
+	}
+	fmt.Printf("\n✅ Farmer PII is encrypted with key %s\n", keyring.ActiveKeyID())
+}
+
+// rotateFarmers rewrites the encrypted columns of every farmer that is not on the active key
+func rotateFarmers(keyring *pii.Keyring) (int, error) {
+	updated := 0
+	lastID := ""
+	for {
+		var rows []farmerPII
+		err := database.DB.Table("farmers").
+			Select("id, id_number, date_of_birth, npwp, bank_account_number, id_number_bidx").
+			Where("id::text > ?", lastID).
+			Order("id::text").
+			Limit(*batchSize).
+			Find(&rows).Error
+		if err != nil {
+			return updated, err
+		}
+		if len(rows) == 0 {
+			return updated, nil
+		}
+		lastID = rows[len(rows)-1].ID
+
+		for _, row := range rows {
+			npwp := ""
+			if row.NPWP != nil {
+				npwp = *row.NPWP
+			}
+			if !needsRotation(keyring, row.IDNumber, row.DateOfBirth, npwp, row.BankAccountNumber) && (*decrypt || row.IDNumberBidx != nil) {
+				continue
+			}
+
+			idNumber, err := rewrite(keyring, row.IDNumber)
+			if err != nil {
+				return updated, fmt.Errorf("farmer %s id_number: %w", row.ID, err)
+			}
+			dateOfBirth, err := rewrite(keyring, row.DateOfBirth)
+			if err != nil {
+				return updated, fmt.Errorf("farmer %s date_of_birth: %w", row.ID, err)
+			}
+			bankAccountNumber, err := rewrite(keyring, row.BankAccountNumber)
+			if err != nil {
+				return updated, fmt.Errorf("farmer %s bank_account_number: %w", row.ID, err)
+			}
+			columns := map[string]interface{}{
+				"id_number":           idNumber,
+				"date_of_birth":       dateOfBirth,
+				"bank_account_number": bankAccountNumber,
+			}
+			if row.NPWP != nil {
+				if columns["npwp"], err = rewrite(keyring, npwp); err != nil {
+					return updated, fmt.Errorf("farmer %s npwp: %w", row.ID, err)
+				}
+			}
+
+			plainIDNumber, err := keyring.Decrypt(row.IDNumber)
+			if err != nil {
+				return updated, fmt.Errorf("farmer %s id_number: %w", row.ID, err)
+			}
+			columns["id_number_bidx"] = nil
+			if index := keyring.BlindIndex("id_number", plainIDNumber); index != "" {
+				columns["id_number_bidx"] = index
+			}
+
+			// Column updates skip hooks and updated_at, rotation is not a profile change
+			if err := database.DB.Table("farmers").Where("id = ?", row.ID).UpdateColumns(columns).Error; err != nil {
+				return updated, fmt.Errorf("farmer %s: %w", row.ID, err)
+			}
+			updated++
+		}
+	}
+}
+
+// rotateJSONColumn rewrites the PII fields inside a JSON column
+func rotateJSONColumn(keyring *pii.Keyring, table, column string) (int, error) {
+	updated := 0
+	lastID := ""
+	for {
+		var rows []jsonRow
+		err := database.DB.Table(table).
+			Select("id, "+column+" AS payload").
+			Where("id::text > ?", lastID).
+			Order("id::text").
+			Limit(*batchSize).
+			Find(&rows).Error
+		if err != nil {
+			return updated, err
+		}
+		if len(rows) == 0 {
+			return updated, nil
+		}
+		lastID = rows[len(rows)-1].ID
+
+		for _, row := range rows {
+			var values map[string]interface{}
+			if err := json.Unmarshal(row.Payload, &values); err != nil {
+				return updated, fmt.Errorf("%s %s: %w", table, row.ID, err)
+			}
+
+			stale := false
+			for _, field := range models.FarmerPIIFields {
+				if value, ok := values[field].(string); ok && needsRotation(keyring, value) {
+					stale = true
+				}
+			}
+			if !stale {
+				continue
+			}
+
+			if err := pii.OpenFields(values, models.FarmerPIIFields...); err != nil {
+				return updated, fmt.Errorf("%s %s: %w", table, row.ID, err)
+			}
+			if !*decrypt {
+				if err := pii.SealFields(values, models.FarmerPIIFields...); err != nil {
+					return updated, fmt.Errorf("%s %s: %w", table, row.ID, err)
+				}
+			}
+			rewritten, err := json.Marshal(values)
+			if err != nil {
+				return updated, fmt.Errorf("%s %s: %w", table, row.ID, err)
+			}
+
+			if err := database.DB.Table(table).Where("id = ?", row.ID).UpdateColumn(column, string(rewritten)).Error; err != nil {
+				return updated, fmt.Errorf("%s %s: %w", table, row.ID, err)
+			}
+			updated++
+		}
+	}
+}
+
+// needsRotation reports whether any value is not yet in the target form: encrypted with the
+// active key, or plaintext when decrypting
+func needsRotation(keyring *pii.Keyring, values ...string) bool {
+	for _, value := range values {
+		if *decrypt {
+			if pii.IsEncrypted(value) {
+				return true
+			}
+		} else if !keyring.IsCurrent(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewrite decrypts a stored value and encrypts it again with the active key
+func rewrite(keyring *pii.Keyring, value string) (string, error) {
+	plaintext, err := keyring.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	if *decrypt || plaintext == "" {
+		return plaintext, nil
+	}
+	return keyring.Encrypt(plaintext)
+}
@@ -42,11 +42,14 @@ func main() {
 	// Normalize wallet address to checksum format
 	walletAddress = strings.ToLower(walletAddress)
 
-	fmt.Print("Enter admin role (default: admin): ")
+	fmt.Print("Enter admin role (super_admin, reviewer, finance, read_only; default: super_admin): ")
 	role, _ := reader.ReadString('\n')
 	role = strings.TrimSpace(role)
 	if role == "" {
-		role = "admin"
+		role = models.AdminRoleSuperAdmin
+	}
+	if !models.IsValidAdminRole(role) {
+		log.Fatal("Invalid admin role. Must be one of super_admin, reviewer, finance, read_only.")
 	}
 
 	// Create admin user
@@ -0,0 +1,156 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// ActorType is the kind of account that performed an action
+type ActorType string
+
+const (
+	ActorAdmin    ActorType = "admin"
+	ActorFarmer   ActorType = "farmer"
+	ActorInvestor ActorType = "investor"
+	ActorSystem   ActorType = "system"
+)
+
+// Actor identifies who performed an action
+type Actor struct {
+	Type ActorType
+	ID   string
+}
+
+// Entry is an action to record. Before and After are structs or maps; only the fields that
+// differ between them are stored, or all fields of the side that is nil for creates and deletes.
+type Entry struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     interface{}
+	After      interface{}
+
+	// Actor overrides the actor of the request, for actions such as logins where the actor is
+	// only known once the action succeeded
+	Actor *Actor
+}
+
+// Recorder records audit entries
+type Recorder interface {
+	// Record stores an entry. Failures are logged and never fail the action.
+	Record(ctx context.Context, entry Entry)
+	// RecordRequired stores an entry and returns the failure, for actions that must not
+	// happen without an audit trail
+	RecordRequired(ctx context.Context, entry Entry) error
+}
+
+// ignoredFields are bookkeeping fields left out of diffs
+var ignoredFields = map[string]bool{"created_at": true, "updated_at": true}
+
+// Diff returns the fields that differ between two values, as they are serialized to JSON
+func Diff(before, after interface{}) (map[string]interface{}, map[string]interface{}) {
+	oldValues := toMap(before)
+	newValues := toMap(after)
+	if oldValues == nil || newValues == nil {
+		return oldValues, newValues
+	}
+
+	for key, oldValue := range oldValues {
+		if newValue, ok := newValues[key]; ok && reflect.DeepEqual(oldValue, newValue) {
+			delete(oldValues, key)
+			delete(newValues, key)
+		}
+	}
+	return oldValues, newValues
+}
+
+// toMap converts a struct or map to its JSON fields, nil for nil values
+func toMap(value interface{}) map[string]interface{} {
+	if value == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(value); (v.Kind() == reflect.Ptr || v.Kind() == reflect.Map) && v.IsNil() {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	for key := range ignoredFields {
+		delete(fields, key)
+	}
+	return fields
+}
+
+// Request holds the origin of the request being handled
+type Request struct {
+	IPAddress string
+	UserAgent string
+
+	resolveActor func() Actor
+	mu           sync.Mutex
+	recorded     bool
+	skipped      bool
+}
+
+// NewRequest creates the audit state of a request. The actor is resolved when an entry is
+// recorded, after authentication has run.
+func NewRequest(ipAddress, userAgent string, resolveActor func() Actor) *Request {
+	return &Request{IPAddress: ipAddress, UserAgent: userAgent, resolveActor: resolveActor}
+}
+
+// Actor returns the authenticated actor of the request, empty for public routes
+func (r *Request) Actor() Actor {
+	if r.resolveActor == nil {
+		return Actor{}
+	}
+	return r.resolveActor()
+}
+
+// MarkRecorded notes that the request produced an audit entry
+func (r *Request) MarkRecorded() {
+	r.mu.Lock()
+	r.recorded = true
+	r.mu.Unlock()
+}
+
+// Recorded reports whether the request produced an audit entry
+func (r *Request) Recorded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recorded
+}
+
+// Skip excludes the request from the fallback entry of mutating routes
+func (r *Request) Skip() {
+	r.mu.Lock()
+	r.skipped = true
+	r.mu.Unlock()
+}
+
+// Skipped reports whether the request is excluded from the fallback entry
+func (r *Request) Skipped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skipped
+}
+
+type requestKey struct{}
+
+// WithRequest returns a context carrying the audit state of a request
+func WithRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// FromContext returns the audit state of the request, nil outside of a request
+func FromContext(ctx context.Context) *Request {
+	req, _ := ctx.Value(requestKey{}).(*Request)
+	return req
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type farm struct {
+		Name      string    `json:"name"`
+		Location  string    `json:"location"`
+		IsActive  bool      `json:"is_active"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	before := farm{Name: "Kebun A", Location: "Bogor", IsActive: true, UpdatedAt: time.Now()}
+	after := before
+	after.Location = "Bandung"
+	after.UpdatedAt = time.Now().Add(time.Minute)
+
+	t.Run("changed fields only", func(t *testing.T) {
+		oldValues, newValues := Diff(before, after)
+		assert.Equal(t, map[string]interface{}{"location": "Bogor"}, oldValues)
+		assert.Equal(t, map[string]interface{}{"location": "Bandung"}, newValues)
+	})
+
+	t.Run("create keeps every field", func(t *testing.T) {
+		oldValues, newValues := Diff(nil, &after)
+		assert.Nil(t, oldValues)
+		assert.Equal(t, map[string]interface{}{"name": "Kebun A", "location": "Bandung", "is_active": true}, newValues)
+	})
+
+	t.Run("maps", func(t *testing.T) {
+		oldValues, newValues := Diff(
+			map[string]interface{}{"status": "pending"},
+			map[string]interface{}{"status": "approved", "fields": []string{"bank_name"}},
+		)
+		assert.Equal(t, map[string]interface{}{"status": "pending"}, oldValues)
+		assert.Equal(t, map[string]interface{}{"status": "approved", "fields": []interface{}{"bank_name"}}, newValues)
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		oldValues, newValues := Diff(before, before)
+		assert.Empty(t, oldValues)
+		assert.Empty(t, newValues)
+	})
+}
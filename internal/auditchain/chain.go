@@ -0,0 +1,151 @@
+package auditchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
+
+// GenesisHash is the previous hash of the first entry of the chain
+var GenesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// chainedEntry is the canonical form of an audit log entry that is hashed. Values are normalized
+// the same way Postgres stores them, so the hash computed on insert matches the one recomputed
+// from the stored row. Admin actors are covered by admin_id, Actor is only set for other actors so
+// hashes from before actor types existed stay valid.
+type chainedEntry struct {
+	Sequence   int64           `json:"sequence"`
+	PrevHash   string          `json:"prev_hash"`
+	ID         string          `json:"id"`
+	AdminID    string          `json:"admin_id"`
+	Actor      string          `json:"actor,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	OldValues  json.RawMessage `json:"old_values"`
+	NewValues  json.RawMessage `json:"new_values"`
+	IPAddress  string          `json:"ip_address"`
+	UserAgent  string          `json:"user_agent"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+// Hash returns the hex SHA-256 of an entry linked to the hash of the entry before it
+func Hash(entry *models.AdminAuditLog, prevHash string) (string, error) {
+	oldValues, err := canonicalJSON(entry.OldValues)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize old values: %w", err)
+	}
+	newValues, err := canonicalJSON(entry.NewValues)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize new values: %w", err)
+	}
+
+	canonical := chainedEntry{
+		Sequence:   entry.Sequence,
+		PrevHash:   prevHash,
+		ID:         entry.ID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		OldValues:  oldValues,
+		NewValues:  newValues,
+		CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if entry.AdminID != nil {
+		canonical.AdminID = *entry.AdminID
+	}
+	if entry.ActorType != "" && entry.ActorType != "admin" {
+		canonical.Actor = entry.ActorType
+		if entry.ActorID != nil {
+			canonical.Actor += ":" + *entry.ActorID
+		}
+	}
+	if entry.IPAddress != nil {
+		canonical.IPAddress = *entry.IPAddress
+		if ip := net.ParseIP(*entry.IPAddress); ip != nil {
+			canonical.IPAddress = ip.String()
+		}
+	}
+	if entry.UserAgent != nil {
+		canonical.UserAgent = *entry.UserAgent
+	}
+
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON re-encodes a JSON value with sorted keys and no insignificant whitespace, as jsonb
+// does not keep the original formatting
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// Domain separation prefixes of the Merkle tree, as in RFC 6962
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// MerkleRoot returns the hex root of the Merkle tree over entry hashes. The tree is the Merkle
+// Tree Hash of RFC 6962 section 2.1 over the 32 byte entry hashes as leaves: a leaf is
+// SHA-256(0x00 || hash) and an internal node is SHA-256(0x01 || left || right), so a leaf can
+// never be passed off as an internal node. An odd node is carried up to the next level
+// unchanged, which gives the same root as the RFC's split at the largest power of two. Anchors
+// are created and checked with this function, so cmd/audit-verify and the verifier always use
+// the same construction.
+func MerkleRoot(hashes []string) (string, error) {
+	if len(hashes) == 0 {
+		return "", fmt.Errorf("no hashes to build a Merkle root from")
+	}
+
+	level := make([][]byte, 0, len(hashes))
+	for _, h := range hashes {
+		entryHash, err := hex.DecodeString(h)
+		if err != nil || len(entryHash) != sha256.Size {
+			return "", fmt.Errorf("invalid entry hash %q", h)
+		}
+		level = append(level, merkleHash(merkleLeafPrefix, entryHash))
+	}
+
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, merkleHash(merkleNodePrefix, level[i], level[i+1]))
+		}
+		level = next
+	}
+	return hex.EncodeToString(level[0]), nil
+}
+
+// merkleHash returns SHA-256 of the domain prefix followed by the given parts
+func merkleHash(prefix byte, parts ...[]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{prefix})
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
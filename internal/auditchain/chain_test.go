@@ -0,0 +1,166 @@
+package auditchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildChain returns n correctly linked entries
+func buildChain(t *testing.T, n int) []models.AdminAuditLog {
+	t.Helper()
+	entries := make([]models.AdminAuditLog, n)
+	prevHash := GenesisHash
+	for i := range entries {
+		ip := "203.0.113.10"
+		adminID := "11111111-1111-1111-1111-111111111111"
+		entries[i] = models.AdminAuditLog{
+			ID:         fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1),
+			ActorType:  "admin",
+			ActorID:    &adminID,
+			AdminID:    &adminID,
+			Action:     models.AuditActionApproveInvoice,
+			EntityType: models.AuditEntityTypeInvoice,
+			EntityID:   "22222222-2222-2222-2222-222222222222",
+			NewValues:  json.RawMessage(`{"status":"approved","amount":1500.50}`),
+			IPAddress:  &ip,
+			CreatedAt:  time.Date(2026, 1, 1, 0, 0, i, 123000, time.UTC),
+			Sequence:   int64(i + 1),
+		}
+		hash, err := Hash(&entries[i], prevHash)
+		require.NoError(t, err)
+		prev := prevHash
+		entries[i].PrevHash = &prev
+		entries[i].Hash = &hash
+		prevHash = hash
+	}
+	return entries
+}
+
+func verify(t *testing.T, entries []models.AdminAuditLog, anchors ...models.AuditLogAnchor) *Verifier {
+	t.Helper()
+	verifier := NewVerifier()
+	for i := range entries {
+		require.NoError(t, verifier.Add(&entries[i]))
+	}
+	for i := range anchors {
+		verifier.CheckAnchor(&anchors[i])
+	}
+	return verifier
+}
+
+func TestHash_MatchesStoredForm(t *testing.T) {
+	entry := buildChain(t, 1)[0]
+
+	// jsonb reorders keys and drops whitespace, Postgres may print the local time zone
+	stored := entry
+	stored.NewValues = json.RawMessage(`{"amount": 1500.50, "status": "approved"}`)
+	stored.CreatedAt = entry.CreatedAt.In(time.FixedZone("WIB", 7*60*60))
+
+	hash, err := Hash(&stored, *entry.PrevHash)
+	require.NoError(t, err)
+	assert.Equal(t, *entry.Hash, hash)
+}
+
+func TestVerifier(t *testing.T) {
+	t.Run("intact chain", func(t *testing.T) {
+		verifier := verify(t, buildChain(t, 5))
+		assert.Empty(t, verifier.Breaks)
+		assert.Equal(t, 5, verifier.Checked)
+		assert.Equal(t, int64(5), verifier.LastSequence())
+	})
+
+	t.Run("modified entry", func(t *testing.T) {
+		entries := buildChain(t, 5)
+		entries[2].NewValues = json.RawMessage(`{"status":"rejected","amount":1500.50}`)
+
+		verifier := verify(t, entries)
+		require.Len(t, verifier.Breaks, 1)
+		assert.Equal(t, int64(3), verifier.Breaks[0].Sequence)
+	})
+
+	t.Run("deleted entry", func(t *testing.T) {
+		entries := buildChain(t, 5)
+		entries = append(entries[:2], entries[3:]...)
+
+		verifier := verify(t, entries)
+		require.Len(t, verifier.Breaks, 2)
+		assert.Contains(t, verifier.Breaks[0].Reason, "#3 to #3 are missing")
+		assert.Equal(t, int64(4), verifier.Breaks[1].Sequence)
+	})
+
+	t.Run("unsealed legacy entries before the chain", func(t *testing.T) {
+		entries := buildChain(t, 3)
+		entries[0].PrevHash, entries[0].Hash = nil, nil
+
+		verifier := verify(t, entries[:1])
+		assert.Empty(t, verifier.Breaks)
+		assert.Equal(t, 1, verifier.Unsealed)
+	})
+
+	t.Run("anchors", func(t *testing.T) {
+		entries := buildChain(t, 5)
+		hashes := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			hashes = append(hashes, *entry.Hash)
+		}
+		root, err := MerkleRoot(hashes)
+		require.NoError(t, err)
+		anchor := models.AuditLogAnchor{ID: "anchor", FromSequence: 1, ToSequence: 5, MerkleRoot: root}
+
+		assert.Empty(t, verify(t, entries, anchor).Breaks)
+
+		// Truncating anchored entries is detected even though the remaining chain links
+		verifier := verify(t, entries[:3], anchor)
+		require.Len(t, verifier.Breaks, 1)
+		assert.Contains(t, verifier.Breaks[0].Reason, "anchored up to #5")
+	})
+}
+
+func TestMerkleRoot(t *testing.T) {
+	sha := func(parts ...[]byte) []byte {
+		sum := sha256.Sum256(bytes.Join(parts, nil))
+		return sum[:]
+	}
+	leaf := func(h string) []byte {
+		b, err := hex.DecodeString(h)
+		require.NoError(t, err)
+		return sha([]byte{0x00}, b)
+	}
+	node := func(left, right []byte) []byte {
+		return sha([]byte{0x01}, left, right)
+	}
+
+	root, err := MerkleRoot([]string{GenesisHash})
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(leaf(GenesisHash)), root)
+
+	entries := buildChain(t, 3)
+	a, b, c := *entries[0].Hash, *entries[1].Hash, *entries[2].Hash
+	abc, err := MerkleRoot([]string{a, b, c})
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(node(node(leaf(a), leaf(b)), leaf(c))), abc)
+	cba, err := MerkleRoot([]string{c, b, a})
+	require.NoError(t, err)
+	assert.NotEqual(t, abc, cba)
+
+	// An internal node presented as a leaf does not reproduce the root
+	ab, err := MerkleRoot([]string{a, b})
+	require.NoError(t, err)
+	forged, err := MerkleRoot([]string{hex.EncodeToString(node(leaf(a), leaf(b)))})
+	require.NoError(t, err)
+	assert.NotEqual(t, ab, forged)
+
+	_, err = MerkleRoot(nil)
+	assert.Error(t, err)
+	_, err = MerkleRoot([]string{"not hex"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package auditchain
+
+import (
+	"fmt"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
+
+// Break is a place where the stored chain does not match the entries
+type Break struct {
+	Sequence int64
+	EntryID  string
+	Reason   string
+}
+
+func (b Break) String() string {
+	if b.EntryID == "" {
+		return fmt.Sprintf("#%d: %s", b.Sequence, b.Reason)
+	}
+	return fmt.Sprintf("#%d (%s): %s", b.Sequence, b.EntryID, b.Reason)
+}
+
+// Verifier walks the entries of the chain in sequence order and records every break. After a
+// break it continues from the stored hash, so later breaks are reported independently.
+type Verifier struct {
+	Checked  int
+	Unsealed int
+	Breaks   []Break
+
+	prevHash     string
+	nextSequence int64
+	sealed       bool
+	hashes       map[int64]string
+}
+
+// NewVerifier creates a verifier positioned before the first entry
+func NewVerifier() *Verifier {
+	return &Verifier{
+		prevHash:     GenesisHash,
+		nextSequence: 1,
+		hashes:       make(map[int64]string),
+	}
+}
+
+// Add checks the next entry of the chain
+func (v *Verifier) Add(entry *models.AdminAuditLog) error {
+	v.Checked++
+
+	if entry.Sequence != v.nextSequence {
+		if entry.Sequence > v.nextSequence {
+			v.addBreak(v.nextSequence, "", fmt.Sprintf("entries #%d to #%d are missing", v.nextSequence, entry.Sequence-1))
+		} else {
+			v.addBreak(entry.Sequence, entry.ID, "sequence is out of order")
+		}
+	}
+	v.nextSequence = entry.Sequence + 1
+
+	// Entries written before the chain existed are sealed on the next write
+	if entry.Hash == nil {
+		if v.sealed {
+			v.addBreak(entry.Sequence, entry.ID, "hash is missing")
+		} else {
+			v.Unsealed++
+		}
+		return nil
+	}
+	v.sealed = true
+
+	if entry.PrevHash == nil || *entry.PrevHash != v.prevHash {
+		v.addBreak(entry.Sequence, entry.ID, "previous hash does not match the previous entry")
+	}
+	prevHash := v.prevHash
+	if entry.PrevHash != nil {
+		prevHash = *entry.PrevHash
+	}
+	hash, err := Hash(entry, prevHash)
+	if err != nil {
+		return fmt.Errorf("failed to hash entry #%d: %w", entry.Sequence, err)
+	}
+	if hash != *entry.Hash {
+		v.addBreak(entry.Sequence, entry.ID, "hash does not match the entry, it was modified")
+	}
+
+	v.prevHash = *entry.Hash
+	v.hashes[entry.Sequence] = *entry.Hash
+	return nil
+}
+
+// LastSequence returns the sequence of the last entry added
+func (v *Verifier) LastSequence() int64 {
+	return v.nextSequence - 1
+}
+
+// CheckAnchor recomputes the Merkle root of an anchored range from the stored hashes
+func (v *Verifier) CheckAnchor(anchor *models.AuditLogAnchor) {
+	if anchor.ToSequence > v.LastSequence() {
+		v.addBreak(anchor.ToSequence, "", fmt.Sprintf("entries after #%d are missing but were anchored up to #%d", v.LastSequence(), anchor.ToSequence))
+		return
+	}
+
+	hashes := make([]string, 0, anchor.ToSequence-anchor.FromSequence+1)
+	for sequence := anchor.FromSequence; sequence <= anchor.ToSequence; sequence++ {
+		hash, ok := v.hashes[sequence]
+		if !ok {
+			v.addBreak(sequence, "", fmt.Sprintf("anchored entry is missing or unsealed (anchor %s)", anchor.ID))
+			return
+		}
+		hashes = append(hashes, hash)
+	}
+
+	root, err := MerkleRoot(hashes)
+	if err != nil || root != anchor.MerkleRoot {
+		v.addBreak(anchor.FromSequence, "", fmt.Sprintf("entries #%d to #%d do not match the Merkle root of anchor %s", anchor.FromSequence, anchor.ToSequence, anchor.ID))
+	}
+}
+
+// Hashes returns the stored hashes of a sequence range, for building a new anchor
+func (v *Verifier) Hashes(from, to int64) ([]string, bool) {
+	hashes := make([]string, 0, to-from+1)
+	for sequence := from; sequence <= to; sequence++ {
+		hash, ok := v.hashes[sequence]
+		if !ok {
+			return nil, false
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, true
+}
+
+func (v *Verifier) addBreak(sequence int64, entryID, reason string) {
+	v.Breaks = append(v.Breaks, Break{Sequence: sequence, EntryID: entryID, Reason: reason})
+}
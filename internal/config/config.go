@@ -4,23 +4,35 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App        AppConfig
-	DB         DBConfig
-	Valkey     ValkeyConfig
-	JWT        JWTConfig
-	Auth       AuthConfig
-	R2         R2Config
-	Blockchain BlockchainConfig
+	App                AppConfig
+	DB                 DBConfig
+	Valkey             ValkeyConfig
+	JWT                JWTConfig
+	Auth               AuthConfig
+	R2                 R2Config
+	Blockchain         BlockchainConfig
+	Notification       NotificationConfig
+	EventStream        EventStreamConfig
+	FarmerNotification FarmerNotificationConfig
+	InvoiceLifecycle   InvoiceLifecycleConfig
+	DocumentPolicy     DocumentPolicyConfig
+	PII                PIIConfig
+	RateLimit          RateLimitConfig
 }
 
 type AppConfig struct {
 	Port string
 	Env  string
+	// TrustedProxies are the proxy IPs or CIDRs whose X-Forwarded-For header is used for the
+	// client IP. Empty trusts none, so the client IP is the address of the connection.
+	TrustedProxies []string
 }
 
 type DBConfig struct {
@@ -39,12 +51,26 @@ type ValkeyConfig struct {
 }
 
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Investor              JWTKeyConfig
+	Farmer                JWTKeyConfig
+	Admin                 JWTKeyConfig
+	Session               JWTKeyConfig // Session tokens carry every role of a wallet
+	KeyGraceMinutes       int          // How long retired keys keep verifying the tokens they signed
+	AccessTokenTTLMinutes int
+	RefreshTokenTTLHours  int
+}
+
+// JWTKeyConfig holds the signing keys of one token audience
+type JWTKeyConfig struct {
+	ActiveKeyID string   // Key used to sign new tokens
+	Keys        []string // Ed25519 seeds as id:base64 pairs; retired keys get :<RFC 3339 retirement time> appended
 }
 
 type AuthConfig struct {
 	NonceTTLMinutes int
+	LoginMessage    string   // siwe, or eip712 for the legacy EIP-712 typed data login
+	SIWEDomains     []string // host[:port] of the sites allowed to ask for a SIWE signature, the first is the default
+	SIWEChainID     int64
 	EIP712Name      string
 	EIP712Version   string
 	EIP712ChainID   int64
@@ -62,6 +88,72 @@ type R2Config struct {
 type BlockchainConfig struct {
 	MantleRPCURL    string
 	OwnaFarmNFTAddr string
+	AnchorKey       string // Hex private key that publishes audit log Merkle roots, optional
+}
+
+type NotificationConfig struct {
+	Channels              []string // External delivery channels in addition to in-app (stream, webhook, log)
+	WebhookURL            string
+	WebhookSecret         string
+	WebhookTimeoutSeconds int
+}
+
+type EventStreamConfig struct {
+	TickSeconds      int // How often time-driven state is refreshed for a connected investor
+	HeartbeatSeconds int // Keep-alive comment interval so proxies do not close idle streams
+}
+
+type FarmerNotificationConfig struct {
+	Channels          []string // Outbound channels used to reach farmers (email, sms, whatsapp)
+	MaxAttempts       int      // Attempts before a delivery is marked failed
+	RetryBaseSeconds  int      // First retry delay; doubled after every failed attempt
+	RetryPollSeconds  int      // How often the retry worker looks for due deliveries
+	SMTPHost          string
+	SMTPPort          string
+	SMTPUsername      string
+	SMTPPassword      string
+	SMTPFrom          string
+	SMSGatewayURL     string
+	SMSGatewayToken   string
+	WhatsAppURL       string
+	WhatsAppToken     string
+	GatewayTimeoutSec int
+}
+
+// DefaultInvoiceFundingWindowDays is the funding window when INVOICE_FUNDING_WINDOW_DAYS is
+// not set. Migration 000026 backfilled invoices approved before deadlines existed with it.
+const DefaultInvoiceFundingWindowDays = 30
+
+type InvoiceLifecycleConfig struct {
+	FundingWindowDays int // Days an approved invoice is open for funding before it fails
+	PollSeconds       int // How often the lifecycle worker moves invoices on from their dates and chain state
+	GraceDays         int // Days after the maturity date before an unrepaid invoice defaults
+}
+
+type DocumentPolicyConfig struct {
+	RequiredForAll     []string            // Documents every farmer must submit
+	RequiredByBusiness map[string][]string // Additional documents per business type
+}
+
+type PIIConfig struct {
+	ActiveKeyID   string   // Key used to encrypt new values
+	Keys          []string // Key encryption keys as id:base64 pairs; old ids stay listed until rotated out
+	BlindIndexKey string   // Base64 HMAC key for the searchable blind indexes
+}
+
+type RateLimitConfig struct {
+	Enabled       bool
+	Login         RateLimitPolicy // Nonce, login, refresh and logout of every audience, per IP
+	Register      RateLimitPolicy // Farmer registration, per IP
+	Presign       RateLimitPolicy // Upload URLs, per account or IP
+	CropSync      RateLimitPolicy // On-chain investment sync, which calls the RPC, per wallet
+	Authenticated RateLimitPolicy // Every other authenticated route, per account
+}
+
+// RateLimitPolicy allows Limit requests in any sliding Window, a zero Limit turns it off
+type RateLimitPolicy struct {
+	Limit  int
+	Window time.Duration
 }
 
 func getEnv(key, fallback string) string {
@@ -72,6 +164,45 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// splitList splits a comma separated env value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// documentList reads a comma separated list of document types, where "none" means an empty list
+func documentList(key, fallback string) []string {
+	value := getEnv(key, fallback)
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return nil
+	}
+	return splitList(value)
+}
+
+// rateLimitPolicy reads a rate limit policy written as requests/window, like 10/1m
+func rateLimitPolicy(key, fallback string) RateLimitPolicy {
+	value := getEnv(key, fallback)
+	limit, window, ok := strings.Cut(value, "/")
+	policy := RateLimitPolicy{}
+	var err error
+	if ok {
+		policy.Limit, err = strconv.Atoi(strings.TrimSpace(limit))
+		if err == nil {
+			policy.Window, err = time.ParseDuration(strings.TrimSpace(window))
+		}
+	}
+	if !ok || err != nil || policy.Limit < 0 || policy.Window <= 0 {
+		log.Fatalf("env: %s must be requests/window, like 10/1m", key)
+	}
+	return policy
+}
+
 func LoadConfig() *Config {
 	// Load .env file
 	err := godotenv.Load()
@@ -89,9 +220,19 @@ func LoadConfig() *Config {
 		log.Fatal("env: VALKEY_TLS must be a boolean")
 	}
 
-	jwtExpirationHours, err := strconv.Atoi(getEnv("JWT_EXPIRATION_HOURS", "24"))
+	accessTokenTTLMinutes, err := strconv.Atoi(getEnv("JWT_ACCESS_TOKEN_TTL_MINUTES", "15"))
 	if err != nil {
-		log.Fatal("env: JWT_EXPIRATION_HOURS must be an integer")
+		log.Fatal("env: JWT_ACCESS_TOKEN_TTL_MINUTES must be an integer")
+	}
+
+	refreshTokenTTLHours, err := strconv.Atoi(getEnv("JWT_REFRESH_TOKEN_TTL_HOURS", "720"))
+	if err != nil {
+		log.Fatal("env: JWT_REFRESH_TOKEN_TTL_HOURS must be an integer")
+	}
+
+	jwtKeyGraceMinutes, err := strconv.Atoi(getEnv("JWT_KEY_GRACE_MINUTES", "60"))
+	if err != nil {
+		log.Fatal("env: JWT_KEY_GRACE_MINUTES must be an integer")
 	}
 
 	nonceTTLMinutes, err := strconv.Atoi(getEnv("NONCE_TTL_MINUTES", "5"))
@@ -104,10 +245,76 @@ func LoadConfig() *Config {
 		log.Fatal("env: EIP712_CHAIN_ID must be an integer")
 	}
 
+	siweChainID, err := strconv.ParseInt(getEnv("SIWE_CHAIN_ID", getEnv("EIP712_CHAIN_ID", "5000")), 10, 64)
+	if err != nil {
+		log.Fatal("env: SIWE_CHAIN_ID must be an integer")
+	}
+
+	loginMessage := getEnv("AUTH_LOGIN_MESSAGE", "siwe")
+	if loginMessage != "siwe" && loginMessage != "eip712" {
+		log.Fatal("env: AUTH_LOGIN_MESSAGE must be siwe or eip712")
+	}
+
+	webhookTimeoutSeconds, err := strconv.Atoi(getEnv("NOTIFICATION_WEBHOOK_TIMEOUT_SECONDS", "5"))
+	if err != nil {
+		log.Fatal("env: NOTIFICATION_WEBHOOK_TIMEOUT_SECONDS must be an integer")
+	}
+
+	streamTickSeconds, err := strconv.Atoi(getEnv("EVENT_STREAM_TICK_SECONDS", "30"))
+	if err != nil || streamTickSeconds <= 0 {
+		log.Fatal("env: EVENT_STREAM_TICK_SECONDS must be a positive integer")
+	}
+
+	streamHeartbeatSeconds, err := strconv.Atoi(getEnv("EVENT_STREAM_HEARTBEAT_SECONDS", "15"))
+	if err != nil || streamHeartbeatSeconds <= 0 {
+		log.Fatal("env: EVENT_STREAM_HEARTBEAT_SECONDS must be a positive integer")
+	}
+
+	farmerNotifyMaxAttempts, err := strconv.Atoi(getEnv("FARMER_NOTIFICATION_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		log.Fatal("env: FARMER_NOTIFICATION_MAX_ATTEMPTS must be an integer")
+	}
+
+	farmerNotifyRetryBase, err := strconv.Atoi(getEnv("FARMER_NOTIFICATION_RETRY_BASE_SECONDS", "60"))
+	if err != nil || farmerNotifyRetryBase < 0 {
+		log.Fatal("env: FARMER_NOTIFICATION_RETRY_BASE_SECONDS must be a non-negative integer")
+	}
+
+	farmerNotifyRetryPoll, err := strconv.Atoi(getEnv("FARMER_NOTIFICATION_RETRY_POLL_SECONDS", "30"))
+	if err != nil || farmerNotifyRetryPoll <= 0 {
+		log.Fatal("env: FARMER_NOTIFICATION_RETRY_POLL_SECONDS must be a positive integer")
+	}
+
+	invoiceFundingWindow, err := strconv.Atoi(getEnv("INVOICE_FUNDING_WINDOW_DAYS", strconv.Itoa(DefaultInvoiceFundingWindowDays)))
+	if err != nil || invoiceFundingWindow <= 0 {
+		log.Fatal("env: INVOICE_FUNDING_WINDOW_DAYS must be a positive integer")
+	}
+
+	invoiceLifecyclePoll, err := strconv.Atoi(getEnv("INVOICE_LIFECYCLE_POLL_SECONDS", "300"))
+	if err != nil || invoiceLifecyclePoll <= 0 {
+		log.Fatal("env: INVOICE_LIFECYCLE_POLL_SECONDS must be a positive integer")
+	}
+
+	invoiceGraceDays, err := strconv.Atoi(getEnv("INVOICE_REPAYMENT_GRACE_DAYS", "14"))
+	if err != nil || invoiceGraceDays < 0 {
+		log.Fatal("env: INVOICE_REPAYMENT_GRACE_DAYS must be a non-negative integer")
+	}
+
+	farmerNotifyGatewayTimeout, err := strconv.Atoi(getEnv("FARMER_NOTIFICATION_GATEWAY_TIMEOUT_SECONDS", "10"))
+	if err != nil {
+		log.Fatal("env: FARMER_NOTIFICATION_GATEWAY_TIMEOUT_SECONDS must be an integer")
+	}
+
+	rateLimitEnabled, err := strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "true"))
+	if err != nil {
+		log.Fatal("env: RATE_LIMIT_ENABLED must be a boolean")
+	}
+
 	return &Config{
 		App: AppConfig{
-			Port: getEnv("APP_PORT", "8080"),
-			Env:  getEnv("APP_ENV", "development"),
+			Port:           getEnv("APP_PORT", "8080"),
+			Env:            getEnv("APP_ENV", "development"),
+			TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "")),
 		},
 		DB: DBConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -123,11 +330,31 @@ func LoadConfig() *Config {
 			TLS:      valkeyTLS,
 		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", ""),
-			ExpirationHours: jwtExpirationHours,
+			Investor: JWTKeyConfig{
+				ActiveKeyID: getEnv("JWT_INVESTOR_ACTIVE_KEY_ID", ""),
+				Keys:        splitList(getEnv("JWT_INVESTOR_KEYS", "")),
+			},
+			Farmer: JWTKeyConfig{
+				ActiveKeyID: getEnv("JWT_FARMER_ACTIVE_KEY_ID", ""),
+				Keys:        splitList(getEnv("JWT_FARMER_KEYS", "")),
+			},
+			Admin: JWTKeyConfig{
+				ActiveKeyID: getEnv("JWT_ADMIN_ACTIVE_KEY_ID", ""),
+				Keys:        splitList(getEnv("JWT_ADMIN_KEYS", "")),
+			},
+			Session: JWTKeyConfig{
+				ActiveKeyID: getEnv("JWT_SESSION_ACTIVE_KEY_ID", ""),
+				Keys:        splitList(getEnv("JWT_SESSION_KEYS", "")),
+			},
+			KeyGraceMinutes:       jwtKeyGraceMinutes,
+			AccessTokenTTLMinutes: accessTokenTTLMinutes,
+			RefreshTokenTTLHours:  refreshTokenTTLHours,
 		},
 		Auth: AuthConfig{
 			NonceTTLMinutes: nonceTTLMinutes,
+			LoginMessage:    loginMessage,
+			SIWEDomains:     splitList(getEnv("SIWE_DOMAINS", "localhost:3000")),
+			SIWEChainID:     siweChainID,
 			EIP712Name:      getEnv("EIP712_NAME", "OwnaFarm"),
 			EIP712Version:   getEnv("EIP712_VERSION", "1"),
 			EIP712ChainID:   eip712ChainID,
@@ -143,6 +370,61 @@ func LoadConfig() *Config {
 		Blockchain: BlockchainConfig{
 			MantleRPCURL:    getEnv("MANTLE_RPC_URL", "https://rpc.sepolia.mantle.xyz"),
 			OwnaFarmNFTAddr: getEnv("OWNAFARM_NFT_ADDRESS", "0xC51601dde25775bA2740EE14D633FA54e12Ef6C7"),
+			AnchorKey:       getEnv("AUDIT_ANCHOR_PRIVATE_KEY", ""),
+		},
+		Notification: NotificationConfig{
+			Channels:              splitList(getEnv("NOTIFICATION_CHANNELS", "stream,log")),
+			WebhookURL:            getEnv("NOTIFICATION_WEBHOOK_URL", ""),
+			WebhookSecret:         getEnv("NOTIFICATION_WEBHOOK_SECRET", ""),
+			WebhookTimeoutSeconds: webhookTimeoutSeconds,
+		},
+		EventStream: EventStreamConfig{
+			TickSeconds:      streamTickSeconds,
+			HeartbeatSeconds: streamHeartbeatSeconds,
+		},
+		FarmerNotification: FarmerNotificationConfig{
+			Channels:          splitList(getEnv("FARMER_NOTIFICATION_CHANNELS", "email,whatsapp")),
+			MaxAttempts:       farmerNotifyMaxAttempts,
+			RetryBaseSeconds:  farmerNotifyRetryBase,
+			RetryPollSeconds:  farmerNotifyRetryPoll,
+			SMTPHost:          getEnv("SMTP_HOST", ""),
+			SMTPPort:          getEnv("SMTP_PORT", "587"),
+			SMTPUsername:      getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:      getEnv("SMTP_PASSWORD", ""),
+			SMTPFrom:          getEnv("SMTP_FROM", "OwnaFarm <no-reply@ownafarm.com>"),
+			SMSGatewayURL:     getEnv("SMS_GATEWAY_URL", ""),
+			SMSGatewayToken:   getEnv("SMS_GATEWAY_TOKEN", ""),
+			WhatsAppURL:       getEnv("WHATSAPP_GATEWAY_URL", ""),
+			WhatsAppToken:     getEnv("WHATSAPP_GATEWAY_TOKEN", ""),
+			GatewayTimeoutSec: farmerNotifyGatewayTimeout,
+		},
+		InvoiceLifecycle: InvoiceLifecycleConfig{
+			FundingWindowDays: invoiceFundingWindow,
+			PollSeconds:       invoiceLifecyclePoll,
+			GraceDays:         invoiceGraceDays,
+		},
+		DocumentPolicy: DocumentPolicyConfig{
+			RequiredForAll: documentList("REQUIRED_DOCUMENTS_ALL", "ktp_photo,selfie_with_ktp"),
+			RequiredByBusiness: map[string][]string{
+				"individual":  documentList("REQUIRED_DOCUMENTS_INDIVIDUAL", "none"),
+				"cv":          documentList("REQUIRED_DOCUMENTS_CV", "business_license,npwp_photo"),
+				"pt":          documentList("REQUIRED_DOCUMENTS_PT", "business_license,npwp_photo"),
+				"ud":          documentList("REQUIRED_DOCUMENTS_UD", "none"),
+				"cooperative": documentList("REQUIRED_DOCUMENTS_COOPERATIVE", "none"),
+			},
+		},
+		PII: PIIConfig{
+			ActiveKeyID:   getEnv("PII_ACTIVE_KEY_ID", ""),
+			Keys:          splitList(getEnv("PII_KEYS", "")),
+			BlindIndexKey: getEnv("PII_BLIND_INDEX_KEY", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:       rateLimitEnabled,
+			Login:         rateLimitPolicy("RATE_LIMIT_LOGIN", "20/1m"),
+			Register:      rateLimitPolicy("RATE_LIMIT_REGISTER", "5/1h"),
+			Presign:       rateLimitPolicy("RATE_LIMIT_PRESIGN", "30/1m"),
+			CropSync:      rateLimitPolicy("RATE_LIMIT_CROP_SYNC", "6/1m"),
+			Authenticated: rateLimitPolicy("RATE_LIMIT_AUTHENTICATED", "300/1m"),
 		},
 	}
 }
@@ -5,9 +5,55 @@ type AdminWalletLoginRequest struct {
 	WalletAddress string `json:"wallet_address" binding:"required"`
 	Signature     string `json:"signature" binding:"required"`
 	Nonce         string `json:"nonce" binding:"required"`
+	Message       string `json:"message"` // Signed SIWE message, omitted for EIP-712 logins
 }
 
 // RejectFarmerRequest represents the request body for rejecting a farmer
 type RejectFarmerRequest struct {
 	Reason *string `json:"reason" binding:"omitempty,max=500"`
 }
+
+// SuspendFarmerRequest represents the request body for suspending a farmer
+type SuspendFarmerRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// RejectProfileChangeRequest represents the request body for rejecting a farmer profile change
+type RejectProfileChangeRequest struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// RevealFarmerPIIRequest represents the request body for revealing masked farmer identity and bank data
+type RevealFarmerPIIRequest struct {
+	Fields []string `json:"fields" binding:"required,min=1,dive,oneof=id_number date_of_birth npwp bank_account_number"`
+	Reason string   `json:"reason" binding:"required,max=500"`
+}
+
+// EraseDataSubjectRequest represents the request body for erasing the personal data of a farmer or investor
+type EraseDataSubjectRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ListAdminUsersRequest contains query parameters for listing admin users
+type ListAdminUsersRequest struct {
+	Role     string `form:"role" binding:"omitempty,oneof=super_admin reviewer finance read_only"`
+	IsActive *bool  `form:"is_active"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// InviteAdminRequest represents the request body for inviting an admin by wallet address
+type InviteAdminRequest struct {
+	WalletAddress string `json:"wallet_address" binding:"required"`
+	Role          string `json:"role" binding:"required,oneof=super_admin reviewer finance read_only"`
+}
+
+// ChangeAdminRoleRequest represents the request body for changing the role of an admin
+type ChangeAdminRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=super_admin reviewer finance read_only"`
+}
+
+// DeactivateAdminRequest represents the request body for deactivating an admin
+type DeactivateAdminRequest struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
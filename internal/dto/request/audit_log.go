@@ -0,0 +1,32 @@
+package request
+
+// AuditLogQuery contains the filters shared by the audit log list and export
+type AuditLogQuery struct {
+	ActorType  string   `form:"actor_type" json:"actor_type,omitempty" binding:"omitempty,oneof=admin farmer investor system"`
+	ActorID    string   `form:"actor_id" json:"actor_id,omitempty" binding:"omitempty,uuid"`
+	AdminID    string   `form:"admin_id" json:"admin_id,omitempty" binding:"omitempty,uuid"`
+	Action     []string `form:"action" json:"action,omitempty"` // Can be repeated
+	EntityType string   `form:"entity_type" json:"entity_type,omitempty"`
+	EntityID   string   `form:"entity_id" json:"entity_id,omitempty" binding:"omitempty,uuid"`
+	From       string   `form:"from" json:"from,omitempty"` // RFC3339 or YYYY-MM-DD, inclusive
+	To         string   `form:"to" json:"to,omitempty"`     // RFC3339 (exclusive) or YYYY-MM-DD (whole day included)
+}
+
+// ListAuditLogsRequest contains query parameters for listing audit logs
+type ListAuditLogsRequest struct {
+	AuditLogQuery
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// ExportAuditLogsRequest contains query parameters for exporting audit logs
+type ExportAuditLogsRequest struct {
+	AuditLogQuery
+	Format string `form:"format" binding:"omitempty,oneof=csv json"` // Default csv
+}
+
+// AuditLogTimelineRequest contains query parameters for the audit timeline of one entity
+type AuditLogTimelineRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
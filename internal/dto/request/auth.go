@@ -0,0 +1,12 @@
+package request
+
+// RefreshTokenRequest represents the request body for exchanging a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest represents the request body for logging out. The refresh token is optional;
+// without it only the access token of the request is revoked.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
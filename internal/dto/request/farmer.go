@@ -20,6 +20,9 @@ type PersonalInfoRequest struct {
 	District      string `json:"district" binding:"required,max=100"`
 	PostalCode    string `json:"postal_code" binding:"required,max=10"`
 	WalletAddress string `json:"wallet_address" binding:"required,len=42,startswith=0x"`
+
+	// Optional: language for notifications, defaults to id
+	PreferredLanguage *string `json:"preferred_language" binding:"omitempty,oneof=id en"`
 }
 
 // BusinessInfoRequest contains business information for farmer registration
@@ -50,10 +53,41 @@ type PresignDocumentsRequest struct {
 
 // ListFarmerRequest contains query parameters for listing farmers (admin)
 type ListFarmerRequest struct {
-	Status    []string `form:"status"` // Filter: pending, under_review, approved, rejected, suspended
+	Status    []string `form:"status"` // Filter: pending, under_review, approved, rejected, suspended, erased
 	Page      int      `form:"page" binding:"omitempty,min=1"`
 	Limit     int      `form:"limit" binding:"omitempty,min=1,max=100"`
 	SortBy    string   `form:"sort_by"`    // created_at, full_name, status
 	SortOrder string   `form:"sort_order"` // asc, desc
 	Search    string   `form:"search"`     // Search in name, email, phone
 }
+
+// UpdateFarmerProfileRequest contains the profile fields a farmer may change (PATCH /farmer/me).
+// Only fields present in the body are changed. Sensitive fields (id_number, npwp and the
+// bank account) are not applied directly but create a change request for admin review.
+type UpdateFarmerProfileRequest struct {
+	// Applied directly
+	PhoneNumber       *string   `json:"phone_number,omitempty" binding:"omitempty,min=1,max=20"`
+	Address           *string   `json:"address,omitempty" binding:"omitempty,min=1"`
+	Province          *string   `json:"province,omitempty" binding:"omitempty,min=1,max=100"`
+	City              *string   `json:"city,omitempty" binding:"omitempty,min=1,max=100"`
+	District          *string   `json:"district,omitempty" binding:"omitempty,min=1,max=100"`
+	PostalCode        *string   `json:"postal_code,omitempty" binding:"omitempty,min=1,max=10"`
+	BusinessName      *string   `json:"business_name,omitempty" binding:"omitempty,max=200"`
+	YearsOfExperience *int      `json:"years_of_experience,omitempty" binding:"omitempty,min=0"`
+	CropsExpertise    *[]string `json:"crops_expertise,omitempty"`
+	PreferredLanguage *string   `json:"preferred_language,omitempty" binding:"omitempty,oneof=id en"`
+
+	// Require admin review
+	IDNumber          *string `json:"id_number,omitempty" binding:"omitempty,min=1,max=20"`
+	NPWP              *string `json:"npwp,omitempty" binding:"omitempty,max=30"`
+	BankName          *string `json:"bank_name,omitempty" binding:"omitempty,min=1,max=100"`
+	BankAccountNumber *string `json:"bank_account_number,omitempty" binding:"omitempty,min=1,max=30"`
+	BankAccountName   *string `json:"bank_account_name,omitempty" binding:"omitempty,min=1,max=100"`
+}
+
+// ListProfileChangesRequest contains query parameters for listing farmer profile changes
+type ListProfileChangesRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=applied pending approved rejected"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
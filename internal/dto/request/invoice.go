@@ -14,6 +14,19 @@ type CreateInvoiceRequest struct {
 	TxHash       *string `json:"tx_hash,omitempty"`  // Transaction hash of submitInvoice
 }
 
+// UpdateInvoiceRequest is the request body for editing a pending invoice. Every editable
+// field is replaced; ImageKey keeps the current image when omitted.
+type UpdateInvoiceRequest struct {
+	FarmID       string  `json:"farm_id" binding:"required,uuid"`
+	Name         string  `json:"name" binding:"required,max=200"`
+	Description  *string `json:"description,omitempty"`
+	ImageKey     *string `json:"image_key,omitempty"` // File key from presigned upload
+	TargetFund   float64 `json:"target_fund" binding:"required,gt=0"`
+	YieldPercent float64 `json:"yield_percent" binding:"required,gt=0,max=100"`
+	DurationDays int     `json:"duration_days" binding:"required,min=1"`
+	OfftakerID   *string `json:"offtaker_id,omitempty" binding:"omitempty,max=100"`
+}
+
 // PresignInvoiceImageRequest is the request body for getting presigned URL for invoice image
 type PresignInvoiceImageRequest struct {
 	FileName    string `json:"file_name" binding:"required"`
@@ -23,7 +36,7 @@ type PresignInvoiceImageRequest struct {
 // ListInvoiceRequest contains query parameters for listing invoices
 type ListInvoiceRequest struct {
 	FarmID    string   `form:"farm_id"`
-	Status    []string `form:"status"` // pending, approved, rejected
+	Status    []string `form:"status" binding:"omitempty,dive,oneof=pending rejected withdrawn funding funded funding_failed growing matured completed defaulted"`
 	Page      int      `form:"page" binding:"omitempty,min=1"`
 	Limit     int      `form:"limit" binding:"omitempty,min=1,max=100"`
 	SortBy    string   `form:"sort_by"`    // created_at, name, target_fund, status
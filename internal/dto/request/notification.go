@@ -0,0 +1,9 @@
+package request
+
+// ListNotificationsRequest contains query parameters for listing notifications
+type ListNotificationsRequest struct {
+	UnreadOnly bool   `form:"unread_only"`
+	Type       string `form:"type" binding:"omitempty,oneof=crop_ready harvest_synced level_up achievement_unlocked invoice_fully_funded"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
@@ -17,8 +17,10 @@ type AdminNonceResponse struct {
 
 // AdminLoginResponse represents the response body for successful admin login
 type AdminLoginResponse struct {
-	Token string    `json:"token"`
-	Admin AdminInfo `json:"admin"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresIn    int64     `json:"expires_in"` // Access token lifetime in seconds
+	Admin        AdminInfo `json:"admin"`
 }
 
 // AdminLoginErrorResponse represents the response body for failed admin login
@@ -28,7 +30,7 @@ type AdminLoginErrorResponse struct {
 	RetryAfterSeconds *int64 `json:"retry_after_seconds,omitempty"`
 }
 
-// FarmerStatusUpdateResponse represents the response for farmer approve/reject
+// FarmerStatusUpdateResponse represents the response for farmer status changes (approve, reject, claim, release, suspend, reinstate)
 type FarmerStatusUpdateResponse struct {
 	FarmerID   string    `json:"farmer_id"`
 	Status     string    `json:"status"`
@@ -36,3 +38,40 @@ type FarmerStatusUpdateResponse struct {
 	ReviewedAt time.Time `json:"reviewed_at"`
 	Reason     *string   `json:"reason,omitempty"`
 }
+
+// FarmerPIIResponse represents the unmasked values returned by a reveal
+type FarmerPIIResponse struct {
+	FarmerID   string            `json:"farmer_id"`
+	Values     map[string]string `json:"values"`
+	RevealedBy string            `json:"revealed_by"`
+	RevealedAt time.Time         `json:"revealed_at"`
+}
+
+// ErasureResponse represents the result of a personal data erasure
+type ErasureResponse struct {
+	SubjectType      string    `json:"subject_type"` // farmer or user
+	SubjectID        string    `json:"subject_id"`
+	ErasedBy         string    `json:"erased_by"`
+	ErasedAt         time.Time `json:"erased_at"`
+	DeletedDocuments int       `json:"deleted_documents"`
+}
+
+// AdminUserResponse represents an admin user in the admin management endpoints
+type AdminUserResponse struct {
+	ID                 string     `json:"id"`
+	WalletAddress      string     `json:"wallet_address"`
+	Role               string     `json:"role"`
+	IsActive           bool       `json:"is_active"`
+	InvitedBy          *string    `json:"invited_by,omitempty"`
+	LastLoginAt        *time.Time `json:"last_login_at,omitempty"`
+	DeactivatedAt      *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy      *string    `json:"deactivated_by,omitempty"`
+	DeactivationReason *string    `json:"deactivation_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// ListAdminUsersResponse is the paginated response for listing admin users
+type ListAdminUsersResponse struct {
+	Admins     []AdminUserResponse `json:"admins"`
+	Pagination PaginationMeta      `json:"pagination"`
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLogResponse represents an audit log entry
+type AuditLogResponse struct {
+	ID         string          `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    *string         `json:"actor_id,omitempty"`
+	AdminID    *string         `json:"admin_id,omitempty"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	OldValues  json.RawMessage `json:"old_values,omitempty"`
+	NewValues  json.RawMessage `json:"new_values,omitempty"`
+	IPAddress  *string         `json:"ip_address,omitempty"`
+	UserAgent  *string         `json:"user_agent,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Sequence   int64           `json:"sequence"`
+	PrevHash   *string         `json:"prev_hash,omitempty"`
+	Hash       *string         `json:"hash,omitempty"`
+}
+
+// ListAuditLogsResponse is a page of audit log entries. Pass next_cursor as cursor to get the
+// next page; it is omitted on the last page.
+type ListAuditLogsResponse struct {
+	Logs       []AuditLogResponse `json:"logs"`
+	NextCursor *string            `json:"next_cursor,omitempty"`
+}
+
+// AuditLogTimelineResponse lists the audit entries of one entity, oldest first
+type AuditLogTimelineResponse struct {
+	EntityType string             `json:"entity_type"`
+	EntityID   string             `json:"entity_id"`
+	Logs       []AuditLogResponse `json:"logs"`
+	NextCursor *string            `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,9 @@
+package response
+
+// TokenResponse represents a new access token and refresh token pair
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`      // Access token lifetime in seconds
+	Scope        string `json:"scope,omitempty"` // Farmer tokens only
+}
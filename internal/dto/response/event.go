@@ -0,0 +1,42 @@
+package response
+
+import "time"
+
+// CropProgressEvent is pushed when a crop's progress or status changes
+type CropProgressEvent struct {
+	CropID     string `json:"crop_id"`
+	Progress   int    `json:"progress"`
+	Status     string `json:"status"`
+	DaysLeft   int    `json:"days_left"`
+	CanHarvest bool   `json:"can_harvest"`
+}
+
+// WaterRegenEvent is pushed when the investor's water points regenerate
+type WaterRegenEvent struct {
+	WaterPoints    int        `json:"water_points"`
+	MaxWaterPoints int        `json:"max_water_points"`
+	LastRegenAt    *time.Time `json:"last_regen_at,omitempty"`
+}
+
+// InvoiceFundingEvent is pushed when new investments change an invoice's funding
+type InvoiceFundingEvent struct {
+	InvoiceID       string  `json:"invoice_id"`
+	TotalFunded     float64 `json:"total_funded"`
+	TargetFund      float64 `json:"target_fund"`
+	FundingProgress float64 `json:"funding_progress"` // 0-100
+	IsFullyFunded   bool    `json:"is_fully_funded"`
+}
+
+// LeaderboardRankEvent is pushed when the investor's rank on a leaderboard changes
+type LeaderboardRankEvent struct {
+	Type         string  `json:"type"` // xp, wealth, profit
+	Rank         int     `json:"rank"`
+	PreviousRank int     `json:"previous_rank"`
+	Score        float64 `json:"score"`
+}
+
+// StreamTicketResponse is a single-use ticket for opening /events/stream
+type StreamTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
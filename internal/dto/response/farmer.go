@@ -1,6 +1,10 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
 
 // RegisterFarmerResponse is the response for farmer registration
 type RegisterFarmerResponse struct {
@@ -61,32 +65,35 @@ type PaginationMeta struct {
 
 // FarmerDetailResponse is the response for farmer detail (admin)
 type FarmerDetailResponse struct {
-	ID                string               `json:"id"`
-	Status            string               `json:"status"`
-	WalletAddress     string               `json:"wallet_address"`
-	FullName          string               `json:"full_name"`
-	Email             string               `json:"email"`
-	PhoneNumber       string               `json:"phone_number"`
-	IDNumber          string               `json:"id_number"`
-	DateOfBirth       string               `json:"date_of_birth"`
-	Address           string               `json:"address"`
-	Province          string               `json:"province"`
-	City              string               `json:"city"`
-	District          string               `json:"district"`
-	PostalCode        string               `json:"postal_code"`
-	BusinessName      *string              `json:"business_name,omitempty"`
-	BusinessType      string               `json:"business_type"`
-	NPWP              *string              `json:"npwp,omitempty"`
-	BankName          string               `json:"bank_name"`
-	BankAccountNumber string               `json:"bank_account_number"`
-	BankAccountName   string               `json:"bank_account_name"`
-	YearsOfExperience int                  `json:"years_of_experience"`
-	CropsExpertise    []string             `json:"crops_expertise"`
-	Documents         []FarmerDocumentItem `json:"documents"`
-	ReviewedBy        *string              `json:"reviewed_by,omitempty"`
-	ReviewedAt        *string              `json:"reviewed_at,omitempty"`
-	RejectionReason   *string              `json:"rejection_reason,omitempty"`
-	CreatedAt         string               `json:"created_at"`
+	ID                string                  `json:"id"`
+	Status            string                  `json:"status"`
+	WalletAddress     string                  `json:"wallet_address"`
+	FullName          string                  `json:"full_name"`
+	Email             string                  `json:"email"`
+	PhoneNumber       string                  `json:"phone_number"`
+	IDNumber          string                  `json:"id_number"`
+	DateOfBirth       string                  `json:"date_of_birth"`
+	Address           string                  `json:"address"`
+	Province          string                  `json:"province"`
+	City              string                  `json:"city"`
+	District          string                  `json:"district"`
+	PostalCode        string                  `json:"postal_code"`
+	BusinessName      *string                 `json:"business_name,omitempty"`
+	BusinessType      string                  `json:"business_type"`
+	NPWP              *string                 `json:"npwp,omitempty"`
+	BankName          string                  `json:"bank_name"`
+	BankAccountNumber string                  `json:"bank_account_number"`
+	BankAccountName   string                  `json:"bank_account_name"`
+	YearsOfExperience int                     `json:"years_of_experience"`
+	CropsExpertise    []string                `json:"crops_expertise"`
+	Documents         []FarmerDocumentItem    `json:"documents"`
+	DocumentChecklist []DocumentChecklistItem `json:"document_checklist"`
+	DocumentsComplete bool                    `json:"documents_complete"`
+	Submissions       []FarmerSubmissionItem  `json:"submissions"`
+	ReviewedBy        *string                 `json:"reviewed_by,omitempty"`
+	ReviewedAt        *string                 `json:"reviewed_at,omitempty"`
+	RejectionReason   *string                 `json:"rejection_reason,omitempty"`
+	CreatedAt         string                  `json:"created_at"`
 }
 
 // FarmerDocumentItem represents a document in the farmer detail response
@@ -97,3 +104,59 @@ type FarmerDocumentItem struct {
 	DownloadURL  string  `json:"download_url"`
 	ExpiresIn    int     `json:"expires_in"` // seconds
 }
+
+// DocumentChecklistItem shows whether a required document has been submitted
+type DocumentChecklistItem struct {
+	DocumentType string `json:"document_type"`
+	Submitted    bool   `json:"submitted"`
+}
+
+// FarmerProfileChangeResponse represents a recorded farmer profile change
+type FarmerProfileChangeResponse struct {
+	ID              string                 `json:"id"`
+	FarmerID        string                 `json:"farmer_id"`
+	FarmerName      string                 `json:"farmer_name,omitempty"`
+	Status          string                 `json:"status"`
+	RequiresReview  bool                   `json:"requires_review"`
+	Fields          []string               `json:"fields"`
+	OldValues       map[string]interface{} `json:"old_values"`
+	NewValues       map[string]interface{} `json:"new_values"`
+	ReviewedBy      *string                `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time             `json:"reviewed_at,omitempty"`
+	RejectionReason *string                `json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+}
+
+// UpdateFarmerProfileResponse is the response for PATCH /farmer/me
+type UpdateFarmerProfileResponse struct {
+	Profile       *models.Farmer               `json:"profile"`
+	AppliedChange *FarmerProfileChangeResponse `json:"applied_change,omitempty"` // Fields changed right away
+	PendingChange *FarmerProfileChangeResponse `json:"pending_change,omitempty"` // Sensitive fields waiting for admin review
+}
+
+// ListProfileChangesResponse is the paginated response for farmer profile changes
+type ListProfileChangesResponse struct {
+	Changes    []FarmerProfileChangeResponse `json:"changes"`
+	Pagination PaginationMeta                `json:"pagination"`
+}
+
+// FarmerSubmissionItem represents one registration or resubmission and its review decision
+type FarmerSubmissionItem struct {
+	ID               string     `json:"id"`
+	SubmissionNumber int        `json:"submission_number"`
+	Status           string     `json:"status"`
+	RejectionReason  *string    `json:"rejection_reason,omitempty"`
+	ReviewedBy       *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
+	SubmittedAt      time.Time  `json:"submitted_at"`
+}
+
+// FarmerApplicationResponse is the registration status seen by a farmer with an application scope token
+type FarmerApplicationResponse struct {
+	Status          string                 `json:"status"`
+	RejectionReason *string                `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time             `json:"reviewed_at,omitempty"`
+	CanResubmit     bool                   `json:"can_resubmit"`
+	Profile         *models.Farmer         `json:"profile"`
+	Submissions     []FarmerSubmissionItem `json:"submissions"`
+}
@@ -26,11 +26,44 @@ type InvoiceResponse struct {
 	ReviewedBy      *string         `json:"reviewed_by,omitempty"`
 	ReviewedAt      *time.Time      `json:"reviewed_at,omitempty"`
 	ApprovedAt      *time.Time      `json:"approved_at,omitempty"`
+	WithdrawnAt     *time.Time      `json:"withdrawn_at,omitempty"`
 	FundingDeadline *time.Time      `json:"funding_deadline,omitempty"`
 	MaturityDate    *time.Time      `json:"maturity_date,omitempty"`
 	ApprovalTxHash  *string         `json:"approval_tx_hash,omitempty"`
 	CreatedAt       time.Time       `json:"created_at"`
 	UpdatedAt       time.Time       `json:"updated_at"`
+
+	// Edit history, on the admin detail only
+	ChangesSinceSubmission *InvoiceChanges               `json:"changes_since_submission,omitempty"`
+	Versions               []InvoiceVersionItem          `json:"versions,omitempty"`
+	StatusHistory          []InvoiceStatusTransitionItem `json:"status_history,omitempty"`
+}
+
+// InvoiceChanges holds the old and new values of the invoice fields that changed
+type InvoiceChanges struct {
+	OldValues map[string]interface{} `json:"old_values"`
+	NewValues map[string]interface{} `json:"new_values"`
+}
+
+// InvoiceVersionItem represents the submitted invoice or one edit of it
+type InvoiceVersionItem struct {
+	ID            string                 `json:"id"`
+	VersionNumber int                    `json:"version_number"`
+	Snapshot      map[string]interface{} `json:"snapshot"`
+	OldValues     map[string]interface{} `json:"old_values,omitempty"`
+	NewValues     map[string]interface{} `json:"new_values,omitempty"`
+	EditedBy      *string                `json:"edited_by,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// InvoiceStatusTransitionItem represents one status change of an invoice
+type InvoiceStatusTransitionItem struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ActorType  string    `json:"actor_type"`
+	ActorID    *string   `json:"actor_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 // InvoiceListItem represents an invoice in the list response
@@ -93,13 +126,15 @@ type PresignInvoiceImageResponse struct {
 
 // InvoiceStatusUpdateResponse is the response for approve/reject invoice
 type InvoiceStatusUpdateResponse struct {
-	InvoiceID      string    `json:"invoice_id"`
-	Status         string    `json:"status"`
-	TokenID        *int64    `json:"token_id,omitempty"`
-	ApprovalTxHash *string   `json:"approval_tx_hash,omitempty"`
-	ReviewedBy     string    `json:"reviewed_by"`
-	ReviewedAt     time.Time `json:"reviewed_at"`
-	Reason         *string   `json:"reason,omitempty"`
+	InvoiceID       string     `json:"invoice_id"`
+	Status          string     `json:"status"`
+	TokenID         *int64     `json:"token_id,omitempty"`
+	ApprovalTxHash  *string    `json:"approval_tx_hash,omitempty"`
+	FundingDeadline *time.Time `json:"funding_deadline,omitempty"`
+	MaturityDate    *time.Time `json:"maturity_date,omitempty"`
+	ReviewedBy      string     `json:"reviewed_by"`
+	ReviewedAt      time.Time  `json:"reviewed_at"`
+	Reason          *string    `json:"reason,omitempty"`
 }
 
 // MarketplaceInvoiceItem represents an invoice in the marketplace list response
@@ -114,6 +149,7 @@ type MarketplaceInvoiceItem struct {
 	FundingProgress float64         `json:"funding_progress"` // Percentage: (total_funded / target_fund) * 100
 	YieldPercent    decimal.Decimal `json:"yield_percent"`
 	DurationDays    int             `json:"duration_days"`
+	FundingDeadline *time.Time      `json:"funding_deadline,omitempty"`
 	MaturityDate    *time.Time      `json:"maturity_date,omitempty"`
 
 	// Farm details
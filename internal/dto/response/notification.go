@@ -0,0 +1,17 @@
+package response
+
+import "github.com/ownafarm/ownafarm-backend/internal/models"
+
+// ListNotificationsResponse represents the response for listing notifications
+type ListNotificationsResponse struct {
+	Notifications []models.Notification `json:"notifications"`
+	UnreadCount   int64                 `json:"unread_count"`
+	TotalCount    int64                 `json:"total_count"`
+	Page          int                   `json:"page"`
+	Limit         int                   `json:"limit"`
+}
+
+// UnreadNotificationCountResponse represents the unread notification counter
+type UnreadNotificationCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
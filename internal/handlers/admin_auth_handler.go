@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
 	"github.com/ownafarm/ownafarm-backend/internal/services"
 )
 
@@ -48,8 +49,14 @@ func (h *AdminAuthHandler) GetNonce(c *gin.Context) {
 		return
 	}
 
-	result, err := h.adminAuthService.GetNonce(c.Request.Context(), walletAddress)
+	result, err := h.adminAuthService.GetNonce(c.Request.Context(), walletAddress, c.GetHeader("Origin"))
 	if err != nil {
+		if errors.Is(err, services.ErrOriginNotAllowed) {
+			c.JSON(http.StatusBadRequest, response.AdminLoginErrorResponse{
+				Error: "Origin is not allowed to sign in",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.AdminLoginErrorResponse{
 			Error: "Failed to generate nonce",
 		})
@@ -84,7 +91,7 @@ func (h *AdminAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	result, rateLimitInfo, err := h.adminAuthService.Login(c.Request.Context(), req.WalletAddress, req.Signature, req.Nonce)
+	result, rateLimitInfo, err := h.adminAuthService.Login(c.Request.Context(), req.WalletAddress, req.Signature, req.Nonce, req.Message, c.GetHeader("Origin"))
 	if err != nil {
 		// Handle rate limit exceeded
 		if errors.Is(err, services.ErrRateLimitExceeded) {
@@ -134,12 +141,95 @@ func (h *AdminAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, response.AdminLoginResponse{
-		Token: result.Token,
+	c.JSON(http.StatusOK, newAdminLoginResponse(result))
+}
+
+// Refresh handles exchanging an admin refresh token
+// @Summary Refresh admin token
+// @Description Exchange a refresh token for a new access token and refresh token with the current role of the admin
+// @Tags Admin Auth
+// @Accept json
+// @Produce json
+// @Param request body request.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} response.AdminLoginResponse
+// @Failure 400 {object} response.AdminLoginErrorResponse
+// @Failure 401 {object} response.AdminLoginErrorResponse
+// @Failure 500 {object} response.AdminLoginErrorResponse
+// @Router /admin/auth/refresh [post]
+func (h *AdminAuthHandler) Refresh(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.AdminLoginErrorResponse{
+			Error: "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.adminAuthService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, response.AdminLoginErrorResponse{
+				Error: "Refresh token has already been used, please log in again",
+			})
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrAdminNotFound):
+			c.JSON(http.StatusUnauthorized, response.AdminLoginErrorResponse{
+				Error: "Invalid or expired refresh token",
+			})
+		case errors.Is(err, services.ErrAccountInactive):
+			c.JSON(http.StatusUnauthorized, response.AdminLoginErrorResponse{
+				Error: "Account is inactive. Please contact support.",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, response.AdminLoginErrorResponse{
+				Error: "Internal server error",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminLoginResponse(result))
+}
+
+// Logout handles admin logout
+// @Summary Admin logout
+// @Description Revoke the access token of the request and, if given, the refresh token of the session
+// @Tags Admin Auth
+// @Accept json
+// @Produce json
+// @Param request body request.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} response.AdminLoginErrorResponse
+// @Failure 500 {object} response.AdminLoginErrorResponse
+// @Router /admin/auth/logout [post]
+func (h *AdminAuthHandler) Logout(c *gin.Context) {
+	var req request.LogoutRequest
+	_ = c.ShouldBindJSON(&req) // The body is optional
+
+	adminID, _ := middleware.GetAdminID(c)
+	tokenID, expiresAt, _ := middleware.GetAccessToken(c)
+	if err := h.adminAuthService.Logout(c.Request.Context(), adminID, tokenID, expiresAt, req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, response.AdminLoginErrorResponse{
+			Error: "Failed to log out",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Logged out successfully",
+	})
+}
+
+func newAdminLoginResponse(result *services.AdminLoginResult) response.AdminLoginResponse {
+	return response.AdminLoginResponse{
+		Token:        result.Token,
+		RefreshToken: result.RefreshToken,
+		ExpiresIn:    result.ExpiresIn,
 		Admin: response.AdminInfo{
 			ID:            result.Admin.ID,
 			WalletAddress: result.Admin.WalletAddress,
 			Role:          result.Admin.Role,
 		},
-	})
+	}
 }
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+)
+
+// AdminUserHandler handles admin user management requests
+type AdminUserHandler struct {
+	adminUserService services.AdminUserServiceInterface
+}
+
+// NewAdminUserHandler creates a new AdminUserHandler instance
+func NewAdminUserHandler(adminUserService services.AdminUserServiceInterface) *AdminUserHandler {
+	return &AdminUserHandler{
+		adminUserService: adminUserService,
+	}
+}
+
+// List handles listing admin users
+// GET /admin/users
+func (h *AdminUserHandler) List(c *gin.Context) {
+	var req request.ListAdminUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.adminUserService.List(c.Request.Context(), &req)
+	if err != nil {
+		log.Printf("[ERROR] Failed to list admin users: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get admin users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Invite handles inviting an admin by wallet address
+// POST /admin/users
+func (h *AdminUserHandler) Invite(c *gin.Context) {
+	var req request.InviteAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	actorID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return
+	}
+
+	resp, err := h.adminUserService.Invite(c.Request.Context(), actorID, &req)
+	if err != nil {
+		writeAdminUserError(c, err, "Failed to invite admin")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// ChangeRole handles changing the role of an admin
+// PATCH /admin/users/:id/role
+func (h *AdminUserHandler) ChangeRole(c *gin.Context) {
+	adminID, actorID, ok := adminUserRequest(c)
+	if !ok {
+		return
+	}
+
+	var req request.ChangeAdminRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.adminUserService.ChangeRole(c.Request.Context(), actorID, adminID, req.Role)
+	if err != nil {
+		writeAdminUserError(c, err, "Failed to change admin role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Deactivate handles deactivating an admin. Existing tokens of the admin stop working.
+// PATCH /admin/users/:id/deactivate
+func (h *AdminUserHandler) Deactivate(c *gin.Context) {
+	adminID, actorID, ok := adminUserRequest(c)
+	if !ok {
+		return
+	}
+
+	var req request.DeactivateAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// If body is empty or invalid, continue with nil reason
+		req.Reason = nil
+	}
+
+	resp, err := h.adminUserService.Deactivate(c.Request.Context(), actorID, adminID, req.Reason)
+	if err != nil {
+		writeAdminUserError(c, err, "Failed to deactivate admin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Reactivate handles reactivating a deactivated admin
+// PATCH /admin/users/:id/reactivate
+func (h *AdminUserHandler) Reactivate(c *gin.Context) {
+	adminID, actorID, ok := adminUserRequest(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.adminUserService.Reactivate(c.Request.Context(), actorID, adminID)
+	if err != nil {
+		writeAdminUserError(c, err, "Failed to reactivate admin")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// adminUserRequest validates the target admin ID and reads the acting admin from the context
+func adminUserRequest(c *gin.Context) (string, string, bool) {
+	adminID := c.Param("id")
+	if !uuidRegex.MatchString(adminID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid admin ID format",
+		})
+		return "", "", false
+	}
+
+	actorID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return "", "", false
+	}
+	return adminID, actorID, true
+}
+
+// writeAdminUserError maps admin user service errors to responses
+func writeAdminUserError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWalletAddress):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid wallet address format",
+		})
+	case errors.Is(err, services.ErrInvalidAdminRole):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid admin role",
+		})
+	case errors.Is(err, services.ErrAdminNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Admin not found",
+		})
+	case errors.Is(err, services.ErrCannotModifySelf):
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Admins cannot change their own role or status",
+		})
+	case errors.Is(err, services.ErrAdminAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Admin with this wallet address already exists",
+		})
+	case errors.Is(err, services.ErrLastSuperAdmin):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "The last active super admin cannot be demoted or deactivated",
+		})
+	case errors.Is(err, services.ErrAdminAlreadyActive):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Admin is already active",
+		})
+	case errors.Is(err, services.ErrAdminAlreadyInactive):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Admin is already inactive",
+		})
+	default:
+		log.Printf("[ERROR] %s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": message,
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+)
+
+// AuditLogHandler handles audit log requests
+type AuditLogHandler struct {
+	auditLogService services.AuditLogServiceInterface
+}
+
+// NewAuditLogHandler creates a new AuditLogHandler instance
+func NewAuditLogHandler(auditLogService services.AuditLogServiceInterface) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditLogService: auditLogService,
+	}
+}
+
+// List handles listing audit logs with filters and cursor pagination
+// GET /admin/audit-logs
+func (h *AuditLogHandler) List(c *gin.Context) {
+	var req request.ListAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.auditLogService.List(c.Request.Context(), &req)
+	if err != nil {
+		writeAuditLogError(c, err, "Failed to get audit logs")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Timeline handles listing every action recorded on one entity, oldest first
+// GET /admin/audit-logs/entities/:type/:id
+func (h *AuditLogHandler) Timeline(c *gin.Context) {
+	entityID := c.Param("id")
+	if !uuidRegex.MatchString(entityID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid entity ID format",
+		})
+		return
+	}
+
+	var req request.AuditLogTimelineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.auditLogService.Timeline(c.Request.Context(), c.Param("type"), entityID, &req)
+	if err != nil {
+		writeAuditLogError(c, err, "Failed to get audit log timeline")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Export handles downloading the audit logs matching the filters as CSV or JSON
+// GET /admin/audit-logs/export
+func (h *AuditLogHandler) Export(c *gin.Context) {
+	var req request.ExportAuditLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	adminID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return
+	}
+
+	export, err := h.auditLogService.Export(c.Request.Context(), adminID, &req)
+	if err != nil {
+		writeAuditLogError(c, err, "Failed to export audit logs")
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName()))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, a failure can only be logged
+	if err := export.Write(c.Request.Context(), c.Writer); err != nil {
+		log.Printf("[ERROR] Failed to write audit log export: %v", err)
+	}
+}
+
+// writeAuditLogError maps audit log service errors to responses
+func writeAuditLogError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid cursor",
+		})
+	case errors.Is(err, services.ErrInvalidDateRange):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid date range",
+			"details": err.Error(),
+		})
+	default:
+		log.Printf("[ERROR] %s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": message,
+		})
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"regexp"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
 	"github.com/ownafarm/ownafarm-backend/internal/repositories"
 	"github.com/ownafarm/ownafarm-backend/internal/services"
@@ -20,6 +24,8 @@ type AuthHandler struct {
 	nonceService services.NonceServiceInterface
 	authService  services.AuthServiceInterface
 	jwtUtil      *utils.JWTUtil
+	tokenService services.TokenServiceInterface
+	auditor      audit.Recorder
 }
 
 func NewAuthHandler(
@@ -27,12 +33,16 @@ func NewAuthHandler(
 	nonceService services.NonceServiceInterface,
 	authService services.AuthServiceInterface,
 	jwtUtil *utils.JWTUtil,
+	tokenService services.TokenServiceInterface,
+	auditor audit.Recorder,
 ) *AuthHandler {
 	return &AuthHandler{
 		userRepo:     userRepo,
 		nonceService: nonceService,
 		authService:  authService,
 		jwtUtil:      jwtUtil,
+		tokenService: tokenService,
+		auditor:      auditor,
 	}
 }
 
@@ -74,8 +84,28 @@ func (h *AuthHandler) GetNonce(c *gin.Context) {
 		return
 	}
 
-	// Build sign message
-	message := h.nonceService.BuildSignMessage(nonce)
+	// Build the message for the site that asks for the signature
+	message, err := h.authService.BuildLoginMessage(services.LoginMessageRequest{
+		WalletAddress: req.WalletAddress,
+		Nonce:         nonce,
+		Statement:     services.LoginStatementInvestor,
+		Origin:        c.GetHeader("Origin"),
+		LegacyMessage: h.nonceService.BuildSignMessage(nonce),
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrOriginNotAllowed) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Origin is not allowed to sign in",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to build sign message",
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
@@ -90,11 +120,14 @@ type LoginRequest struct {
 	WalletAddress string `json:"wallet_address" binding:"required"`
 	Signature     string `json:"signature" binding:"required"`
 	Nonce         string `json:"nonce" binding:"required"`
+	Message       string `json:"message"` // Signed SIWE message, omitted for EIP-712 logins
 }
 
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  *models.User `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int64        `json:"expires_in"` // Access token lifetime in seconds
+	User         *models.User `json:"user"`
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -132,9 +165,23 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Build message and verify signature
-	message := h.nonceService.BuildSignMessage(req.Nonce)
-	if err := h.authService.VerifySignature(req.WalletAddress, req.Signature, message); err != nil {
+	// Verify the signed message
+	if err := h.authService.VerifyLogin(c.Request.Context(), services.LoginProof{
+		WalletAddress: req.WalletAddress,
+		Signature:     req.Signature,
+		Nonce:         req.Nonce,
+		Message:       req.Message,
+		Origin:        c.GetHeader("Origin"),
+		LegacyMessage: h.nonceService.BuildSignMessage(req.Nonce),
+	}); err != nil {
+		if errors.Is(err, services.ErrInvalidLoginMessage) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid sign-in message",
+				"details": err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, services.ErrInvalidSignature) ||
 			errors.Is(err, services.ErrInvalidWalletFormat) ||
 			errors.Is(err, services.ErrSignatureMismatch) {
@@ -178,6 +225,15 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		}
 	}
 
+	// Erased accounts keep the wallet for their investments but cannot log in again
+	if user.ErasedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Account has been erased",
+		})
+		return
+	}
+
 	// Update last login
 	if err := h.userRepo.UpdateLastLogin(user.ID); err != nil {
 		// Log error but don't fail the login
@@ -194,11 +250,143 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	refreshToken, err := h.tokenService.IssueRefreshToken(c.Request.Context(), services.TokenAudienceInvestor, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate token",
+		})
+		return
+	}
+
+	h.auditor.Record(c.Request.Context(), audit.Entry{
+		Action:     models.AuditActionInvestorLogin,
+		EntityType: models.AuditEntityTypeUser,
+		EntityID:   user.ID,
+		Actor:      &audit.Actor{Type: audit.ActorInvestor, ID: user.ID},
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data": LoginResponse{
-			Token: token,
-			User:  user,
+			Token:        token,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int64(h.jwtUtil.TTL().Seconds()),
+			User:         user,
+		},
+	})
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	session, refreshToken, err := h.tokenService.RotateRefreshToken(c.Request.Context(), services.TokenAudienceInvestor, req.RefreshToken)
+	if err != nil {
+		respondRefreshError(c, err)
+		return
+	}
+
+	// Erased accounts lose their sessions
+	user, err := h.userRepo.GetByID(session.Subject)
+	if err != nil || user.ErasedAt != nil {
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Failed to get user",
+			})
+			return
+		}
+		_ = h.tokenService.RevokeRefreshToken(c.Request.Context(), services.TokenAudienceInvestor, session.Subject, refreshToken)
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Account is no longer available",
+		})
+		return
+	}
+
+	token, err := h.jwtUtil.GenerateToken(user.ID, user.WalletAddress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": response.TokenResponse{
+			Token:        token,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int64(h.jwtUtil.TTL().Seconds()),
 		},
 	})
 }
+
+// Logout revokes the access token of the request and the refresh token in the body, if any
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	logout(c, h.tokenService, services.TokenAudienceInvestor, userID)
+}
+
+// respondRefreshError writes the response for a refresh token that could not be rotated
+func respondRefreshError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRefreshTokenReused):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Refresh token has already been used, please log in again",
+		})
+	case errors.Is(err, services.ErrInvalidRefreshToken):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Invalid or expired refresh token",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to refresh token",
+		})
+	}
+}
+
+// logout revokes the access token of the request and, when the body has one, the refresh token
+// of the session. Unknown refresh tokens are ignored so logging out twice succeeds.
+func logout(c *gin.Context, tokenService services.TokenServiceInterface, audience, subject string) {
+	var req request.LogoutRequest
+	_ = c.ShouldBindJSON(&req) // The body is optional
+
+	if tokenID, expiresAt, ok := middleware.GetAccessToken(c); ok {
+		if err := tokenService.RevokeAccessToken(c.Request.Context(), tokenID, expiresAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Failed to log out",
+			})
+			return
+		}
+	}
+
+	if req.RefreshToken != "" {
+		err := tokenService.RevokeRefreshToken(c.Request.Context(), audience, subject, req.RefreshToken)
+		if err != nil && !errors.Is(err, services.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Failed to log out",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Logged out successfully",
+	})
+}
@@ -7,13 +7,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
 	"github.com/ownafarm/ownafarm-backend/internal/services"
 	"github.com/ownafarm/ownafarm-backend/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -236,6 +240,15 @@ func (m *mockAuthService) NormalizeWalletAddress(walletAddress string) string {
 	return walletAddress
 }
 
+// BuildLoginMessage returns the legacy message, as in EIP-712 mode
+func (m *mockAuthService) BuildLoginMessage(req services.LoginMessageRequest) (string, error) {
+	return req.LegacyMessage, nil
+}
+
+func (m *mockAuthService) VerifyLogin(ctx context.Context, proof services.LoginProof) error {
+	return m.VerifySignature(proof.WalletAddress, proof.Signature, proof.LegacyMessage)
+}
+
 type mockUserRepositoryForAuth struct {
 	GetByIDFunc            func(id string) (*models.User, error)
 	GetByWalletAddressFunc func(walletAddress string) (*models.User, error)
@@ -287,6 +300,74 @@ func (m *mockUserRepositoryForAuth) RegenerateWater(userID string) (*models.User
 	return nil, nil
 }
 
+type mockRecorder struct {
+	entries []audit.Entry
+}
+
+func (m *mockRecorder) Record(ctx context.Context, entry audit.Entry) {
+	m.entries = append(m.entries, entry)
+}
+
+func (m *mockRecorder) RecordRequired(ctx context.Context, entry audit.Entry) error {
+	m.Record(ctx, entry)
+	return nil
+}
+
+// mockTokenService keeps refresh tokens and revoked access tokens in memory
+type mockTokenService struct {
+	RotateRefreshTokenFunc func(ctx context.Context, audience, refreshToken string) (*services.RefreshSession, string, error)
+	revokedRefreshTokens   []string
+	revokedAccessTokens    []string
+}
+
+func (m *mockTokenService) IssueRefreshToken(ctx context.Context, audience, subject string) (string, error) {
+	return "refresh-" + subject, nil
+}
+
+func (m *mockTokenService) RotateRefreshToken(ctx context.Context, audience, refreshToken string) (*services.RefreshSession, string, error) {
+	if m.RotateRefreshTokenFunc != nil {
+		return m.RotateRefreshTokenFunc(ctx, audience, refreshToken)
+	}
+	return nil, "", services.ErrInvalidRefreshToken
+}
+
+func (m *mockTokenService) RevokeRefreshToken(ctx context.Context, audience, subject, refreshToken string) error {
+	m.revokedRefreshTokens = append(m.revokedRefreshTokens, refreshToken)
+	return nil
+}
+
+func (m *mockTokenService) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	m.revokedAccessTokens = append(m.revokedAccessTokens, tokenID)
+	return nil
+}
+
+func (m *mockTokenService) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	for _, revoked := range m.revokedAccessTokens {
+		if revoked == tokenID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockTokenService) RefreshTokenTTL() time.Duration {
+	return 720 * time.Hour
+}
+
+// newTestJWTUtil returns an investor JWT util with a new signing key
+func newTestJWTUtil(t *testing.T) *utils.JWTUtil {
+	t.Helper()
+	seed, err := utils.GenerateSigningKey()
+	require.NoError(t, err)
+	jwtUtil, err := utils.NewJWTUtil(&config.JWTConfig{
+		Investor:              config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:" + seed}},
+		KeyGraceMinutes:       60,
+		AccessTokenTTLMinutes: 15,
+	})
+	require.NoError(t, err)
+	return jwtUtil
+}
+
 // TestGetNonce_Success tests successful nonce generation
 func TestGetNonce_Success(t *testing.T) {
 	router := gin.New()
@@ -302,6 +383,7 @@ func TestGetNonce_Success(t *testing.T) {
 
 	handler := &AuthHandler{
 		nonceService: mockNonce,
+		authService:  &mockAuthService{},
 	}
 	router.GET("/auth/nonce", handler.GetNonce)
 
@@ -322,6 +404,54 @@ func TestGetNonce_Success(t *testing.T) {
 	assert.Contains(t, data["message"], "Sign this message to login to OwnaFarm")
 }
 
+// TestGetNonce_SIWE tests that the nonce comes with a SIWE message for the requesting site
+func TestGetNonce_SIWE(t *testing.T) {
+	router := gin.New()
+
+	mockNonce := &mockNonceService{
+		GenerateNonceFunc: func(ctx context.Context, walletAddress string) (string, error) {
+			return "abc123def456", nil
+		},
+	}
+	authService := services.NewAuthService(&config.AuthConfig{
+		NonceTTLMinutes: 5,
+		LoginMessage:    services.LoginMessageSIWE,
+		SIWEDomains:     []string{"app.ownafarm.com"},
+		SIWEChainID:     5000,
+	}, nil)
+
+	handler := &AuthHandler{
+		nonceService: mockNonce,
+		authService:  authService,
+	}
+	router.GET("/auth/nonce", handler.GetNonce)
+
+	walletAddress := "0x1234567890abcdef1234567890abcdef12345678"
+
+	req, _ := http.NewRequest("GET", "/auth/nonce?wallet_address="+walletAddress, nil)
+	req.Header.Set("Origin", "https://app.ownafarm.com")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	message := response["data"].(map[string]interface{})["message"].(string)
+	assert.Contains(t, message, "app.ownafarm.com wants you to sign in with your Ethereum account:")
+	assert.Contains(t, message, "Nonce: abc123def456")
+
+	// Sites that are not ours cannot ask for a sign-in message
+	req, _ = http.NewRequest("GET", "/auth/nonce?wallet_address="+walletAddress, nil)
+	req.Header.Set("Origin", "https://ownafarm.phish")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // TestLogin_Success_ExistingUser tests successful login for an existing user
 func TestLogin_Success_ExistingUser(t *testing.T) {
 	router := gin.New()
@@ -358,17 +488,16 @@ func TestLogin_Success_ExistingUser(t *testing.T) {
 		},
 	}
 
-	jwtCfg := &config.JWTConfig{
-		Secret:          "test-secret-key-for-jwt-testing-32chars",
-		ExpirationHours: 24,
-	}
-	jwtUtil := utils.NewJWTUtil(jwtCfg)
+	jwtUtil := newTestJWTUtil(t)
 
+	recorder := &mockRecorder{}
 	handler := &AuthHandler{
 		userRepo:     mockUserRepo,
 		nonceService: mockNonce,
 		authService:  mockAuth,
 		jwtUtil:      jwtUtil,
+		tokenService: &mockTokenService{},
+		auditor:      recorder,
 	}
 	router.POST("/auth/login", handler.Login)
 
@@ -393,7 +522,13 @@ func TestLogin_Success_ExistingUser(t *testing.T) {
 
 	data := response["data"].(map[string]interface{})
 	assert.NotEmpty(t, data["token"])
+	assert.Equal(t, "refresh-user-uuid-123", data["refresh_token"])
+	assert.Equal(t, float64(15*60), data["expires_in"])
 	assert.NotNil(t, data["user"])
+	if assert.Len(t, recorder.entries, 1) {
+		assert.Equal(t, models.AuditActionInvestorLogin, recorder.entries[0].Action)
+		assert.Equal(t, audit.ActorInvestor, recorder.entries[0].Actor.Type)
+	}
 }
 
 // TestLogin_Success_NewUser tests successful login and user creation for a new user
@@ -431,17 +566,15 @@ func TestLogin_Success_NewUser(t *testing.T) {
 		},
 	}
 
-	jwtCfg := &config.JWTConfig{
-		Secret:          "test-secret-key-for-jwt-testing-32chars",
-		ExpirationHours: 24,
-	}
-	jwtUtil := utils.NewJWTUtil(jwtCfg)
+	jwtUtil := newTestJWTUtil(t)
 
 	handler := &AuthHandler{
 		userRepo:     mockUserRepo,
 		nonceService: mockNonce,
 		authService:  mockAuth,
 		jwtUtil:      jwtUtil,
+		tokenService: &mockTokenService{},
+		auditor:      &mockRecorder{},
 	}
 	router.POST("/auth/login", handler.Login)
 
@@ -550,3 +683,96 @@ func TestLogin_SignatureMismatch(t *testing.T) {
 	assert.Equal(t, "error", response["status"])
 	assert.Equal(t, "Invalid signature", response["message"])
 }
+
+// TestRefresh tests exchanging a refresh token
+func TestRefresh(t *testing.T) {
+	jwtUtil := newTestJWTUtil(t)
+	erasedAt := time.Now()
+
+	tests := []struct {
+		name        string
+		rotateErr   error
+		user        *models.User
+		wantCode    int
+		wantRevoked bool
+	}{
+		{name: "rotated", user: &models.User{ID: "user-uuid-123"}, wantCode: http.StatusOK},
+		{name: "reused", rotateErr: services.ErrRefreshTokenReused, wantCode: http.StatusUnauthorized},
+		{name: "invalid", rotateErr: services.ErrInvalidRefreshToken, wantCode: http.StatusUnauthorized},
+		{name: "erased user", user: &models.User{ID: "user-uuid-123", ErasedAt: &erasedAt}, wantCode: http.StatusForbidden, wantRevoked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenService := &mockTokenService{
+				RotateRefreshTokenFunc: func(ctx context.Context, audience, refreshToken string) (*services.RefreshSession, string, error) {
+					assert.Equal(t, services.TokenAudienceInvestor, audience)
+					if tt.rotateErr != nil {
+						return nil, "", tt.rotateErr
+					}
+					return &services.RefreshSession{Subject: "user-uuid-123"}, "rotated-token", nil
+				},
+			}
+			handler := &AuthHandler{
+				userRepo: &mockUserRepositoryForAuth{
+					GetByIDFunc: func(id string) (*models.User, error) { return tt.user, nil },
+				},
+				jwtUtil:      jwtUtil,
+				tokenService: tokenService,
+			}
+			router := gin.New()
+			router.POST("/auth/refresh", handler.Refresh)
+
+			req, _ := http.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token":"old-token"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].(map[string]interface{})
+				assert.NotEmpty(t, data["token"])
+				assert.Equal(t, "rotated-token", data["refresh_token"])
+			}
+			if tt.wantRevoked {
+				assert.Equal(t, []string{"rotated-token"}, tokenService.revokedRefreshTokens)
+			}
+		})
+	}
+}
+
+// TestLogout tests that logout revokes the access token and the refresh token
+func TestLogout(t *testing.T) {
+	jwtUtil := newTestJWTUtil(t)
+	token, err := jwtUtil.GenerateToken("user-uuid-123", "0x1234567890abcdef1234567890abcdef12345678")
+	assert.NoError(t, err)
+	claims, err := jwtUtil.ValidateToken(token)
+	assert.NoError(t, err)
+
+	tokenService := &mockTokenService{}
+	handler := &AuthHandler{tokenService: tokenService}
+	router := gin.New()
+	router.POST("/auth/logout", middleware.NewAuthMiddleware(jwtUtil, nil, tokenService, nil).AuthRequired(), handler.Logout)
+	router.GET("/me", middleware.NewAuthMiddleware(jwtUtil, nil, tokenService, nil).AuthRequired(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("POST", "/auth/logout", bytes.NewBufferString(`{"refresh_token":"refresh-user-uuid-123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{claims.ID}, tokenService.revokedAccessTokens)
+	assert.Equal(t, []string{"refresh-user-uuid-123"}, tokenService.revokedRefreshTokens)
+
+	// The access token no longer works
+	req, _ = http.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+)
+
+// DataSubjectHandler handles personal data export and erasure requests
+type DataSubjectHandler struct {
+	dataSubjectService services.DataSubjectServiceInterface
+}
+
+// NewDataSubjectHandler creates a new DataSubjectHandler instance
+func NewDataSubjectHandler(dataSubjectService services.DataSubjectServiceInterface) *DataSubjectHandler {
+	return &DataSubjectHandler{
+		dataSubjectService: dataSubjectService,
+	}
+}
+
+// ExportFarmer streams a zip with all data tied to a farmer, including the documents
+// GET /admin/farmers/:id/export
+func (h *DataSubjectHandler) ExportFarmer(c *gin.Context) {
+	h.export(c, "farmer", h.dataSubjectService.ExportFarmer)
+}
+
+// ExportUser streams a zip with all data tied to an investor
+// GET /admin/investors/:id/export
+func (h *DataSubjectHandler) ExportUser(c *gin.Context) {
+	h.export(c, "investor", h.dataSubjectService.ExportUser)
+}
+
+// EraseFarmer anonymizes the personal data of a farmer
+// POST /admin/farmers/:id/erase
+func (h *DataSubjectHandler) EraseFarmer(c *gin.Context) {
+	h.erase(c, "farmer", h.dataSubjectService.EraseFarmer)
+}
+
+// EraseUser anonymizes the personal data of an investor
+// POST /admin/investors/:id/erase
+func (h *DataSubjectHandler) EraseUser(c *gin.Context) {
+	h.erase(c, "investor", h.dataSubjectService.EraseUser)
+}
+
+type exportFunc func(ctx context.Context, subjectID, adminID string) (*services.DataExport, error)
+
+func (h *DataSubjectHandler) export(c *gin.Context, subject string, export exportFunc) {
+	subjectID, adminID, ok := subjectRequest(c, subject)
+	if !ok {
+		return
+	}
+
+	data, err := export(c.Request.Context(), subjectID, adminID)
+	if err != nil {
+		writeDataSubjectError(c, subject, "export", err)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", data.FileName()))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, a failure can only be logged
+	if err := data.WriteZip(c.Request.Context(), c.Writer); err != nil {
+		log.Printf("[ERROR] Failed to write %s export %s: %v", subject, subjectID, err)
+	}
+}
+
+type eraseFunc func(ctx context.Context, subjectID, adminID, reason string) (*response.ErasureResponse, error)
+
+func (h *DataSubjectHandler) erase(c *gin.Context, subject string, erase eraseFunc) {
+	subjectID, adminID, ok := subjectRequest(c, subject)
+	if !ok {
+		return
+	}
+
+	var req request.EraseDataSubjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := erase(c.Request.Context(), subjectID, adminID, req.Reason)
+	if err != nil {
+		writeDataSubjectError(c, subject, "erase", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// subjectRequest validates the subject ID and reads the admin from the context
+func subjectRequest(c *gin.Context, subject string) (string, string, bool) {
+	subjectID := c.Param("id")
+	if !uuidRegex.MatchString(subjectID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("Invalid %s ID format", subject),
+		})
+		return "", "", false
+	}
+
+	adminID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return "", "", false
+	}
+	return subjectID, adminID, true
+}
+
+// writeDataSubjectError maps data subject service errors to responses
+func writeDataSubjectError(c *gin.Context, subject, operation string, err error) {
+	switch {
+	case errors.Is(err, services.ErrFarmerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Farmer not found",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Investor not found",
+		})
+	case errors.Is(err, services.ErrAlreadyErased):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Personal data has already been erased",
+		})
+	case errors.Is(err, services.ErrErasurePendingPayouts):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Farmer has invoices with pending payouts",
+		})
+	case errors.Is(err, services.ErrErasureActiveInvestments):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Investor has investments that are not harvested yet",
+		})
+	default:
+		log.Printf("[ERROR] Failed to %s %s data: %v", operation, subject, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("Failed to %s %s data", operation, subject),
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+)
+
+// streamBufferSize is how many events may queue for a slow client before new ones are dropped
+const streamBufferSize = 64
+
+// EventStreamHandler handles the investor Server-Sent Events stream
+type EventStreamHandler struct {
+	eventStreamService  services.EventStreamServiceInterface
+	streamTicketService services.StreamTicketServiceInterface
+	tickInterval        time.Duration
+	heartbeatInterval   time.Duration
+}
+
+// NewEventStreamHandler creates a new EventStreamHandler instance
+func NewEventStreamHandler(
+	eventStreamService services.EventStreamServiceInterface,
+	streamTicketService services.StreamTicketServiceInterface,
+	tickInterval, heartbeatInterval time.Duration,
+) *EventStreamHandler {
+	return &EventStreamHandler{
+		eventStreamService:  eventStreamService,
+		streamTicketService: streamTicketService,
+		tickInterval:        tickInterval,
+		heartbeatInterval:   heartbeatInterval,
+	}
+}
+
+// IssueTicket issues a single-use ticket for opening the stream with EventSource
+// POST /events/tickets
+func (h *EventStreamHandler) IssueTicket(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "User not authenticated",
+		})
+		return
+	}
+	walletAddress, _ := middleware.GetWalletAddress(c)
+
+	ticket, expiresAt, err := h.streamTicketService.IssueStreamTicket(c.Request.Context(), userID, walletAddress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to issue stream ticket",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Stream ticket issued",
+		"data": response.StreamTicketResponse{
+			Ticket:    ticket,
+			ExpiresAt: expiresAt,
+		},
+	})
+}
+
+// Stream pushes live crop, water, funding, leaderboard and notification events
+// GET /events/stream?ticket=<ticket>&invoice_ids=<uuid>,<uuid>
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	var invoiceIDs []string
+	for _, id := range strings.Split(c.Query("invoice_ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !uuidRegex.MatchString(id) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Invalid invoice ID format",
+				"details": id,
+			})
+			return
+		}
+		invoiceIDs = append(invoiceIDs, id)
+	}
+	if len(invoiceIDs) > services.MaxStreamInvoices {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": fmt.Sprintf("At most %d invoices can be watched", services.MaxStreamInvoices),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	events := make(chan services.Event, streamBufferSize)
+	go func() {
+		// Closing the stream when the subscription dies lets the client reconnect
+		defer cancel()
+		err := h.eventStreamService.Subscribe(ctx, userID, invoiceIDs, func(event services.Event) {
+			select {
+			case events <- event:
+			default:
+				log.Printf("[EventStream] WARNING: dropping %s event for slow client %s", event.Type, userID)
+			}
+		})
+		if err != nil {
+			log.Printf("[EventStream] WARNING: subscription for user %s ended: %v", userID, err)
+		}
+	}()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.SSEvent("connected", gin.H{
+		"user_id":     userID,
+		"invoice_ids": invoiceIDs,
+	})
+	c.Writer.Flush()
+
+	tick := time.NewTicker(h.tickInterval)
+	defer tick.Stop()
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event := <-events:
+			c.SSEvent(event.Type, event)
+			return true
+		case <-tick.C:
+			// Tick publishes through Valkey; the changes come back on the events channel
+			h.eventStreamService.Tick(ctx, userID)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEventBus delivers published events to subscribers in the same process
+type memoryEventBus struct {
+	mu          sync.Mutex
+	subscribers map[string][]func(channel string, event services.Event)
+	subscribed  chan []string
+	unsubscribe chan []string
+}
+
+func newMemoryEventBus() *memoryEventBus {
+	return &memoryEventBus{
+		subscribers: map[string][]func(channel string, event services.Event){},
+		subscribed:  make(chan []string, 1),
+		unsubscribe: make(chan []string, 1),
+	}
+}
+
+func (b *memoryEventBus) Publish(ctx context.Context, channel string, eventType string, data interface{}) error {
+	b.mu.Lock()
+	handlers := b.subscribers[channel]
+	b.mu.Unlock()
+
+	event := services.Event{Type: eventType, Data: data, OccurredAt: time.Now()}
+	for _, handler := range handlers {
+		handler(channel, event)
+	}
+	return nil
+}
+
+func (b *memoryEventBus) Subscribe(ctx context.Context, channels []string, handler func(channel string, event services.Event)) error {
+	b.mu.Lock()
+	for _, channel := range channels {
+		b.subscribers[channel] = append(b.subscribers[channel], handler)
+	}
+	b.mu.Unlock()
+	b.subscribed <- channels
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	for _, channel := range channels {
+		delete(b.subscribers, channel)
+	}
+	b.mu.Unlock()
+	b.unsubscribe <- channels
+	return nil
+}
+
+func TestEventStream_RelaysEventsUntilClientDisconnects(t *testing.T) {
+	bus := newMemoryEventBus()
+	// The tick interval is long enough that no tick runs during the test
+	eventStreamService := services.NewEventStreamService(bus, nil, nil, nil, nil, time.Hour)
+	handler := NewEventStreamHandler(eventStreamService, nil, time.Hour, time.Hour)
+
+	streamDone := make(chan struct{})
+	router := gin.New()
+	router.GET("/events/stream", func(c *gin.Context) {
+		c.Set(middleware.ContextKeyUserID, "user-1")
+		c.Next()
+		close(streamDone)
+	}, handler.Stream)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	invoiceID := "3f1c2b7e-8d4a-4c5e-9f6a-1b2c3d4e5f60"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events/stream?invoice_ids="+invoiceID, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/event-stream")
+
+	select {
+	case channels := <-bus.subscribed:
+		assert.ElementsMatch(t, []string{services.UserEventChannel("user-1"), services.InvoiceEventChannel(invoiceID)}, channels)
+	case <-time.After(time.Second):
+		t.Fatal("stream did not subscribe to the event bus")
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	readEvent := func() (string, string) {
+		var name, data string
+		for lines.Scan() {
+			line := lines.Text()
+			switch {
+			case strings.HasPrefix(line, "event:"):
+				name = strings.TrimPrefix(line, "event:")
+			case strings.HasPrefix(line, "data:"):
+				data = strings.TrimPrefix(line, "data:")
+			case line == "" && name != "":
+				return name, data
+			}
+		}
+		return name, data
+	}
+
+	name, _ := readEvent()
+	assert.Equal(t, "connected", name)
+
+	require.NoError(t, bus.Publish(ctx, services.UserEventChannel("user-1"), services.EventTypeCropProgress, gin.H{"crop_id": "crop-1"}))
+	name, data := readEvent()
+	assert.Equal(t, services.EventTypeCropProgress, name)
+	assert.Contains(t, data, `"crop_id":"crop-1"`)
+
+	require.NoError(t, bus.Publish(ctx, services.InvoiceEventChannel(invoiceID), services.EventTypeInvoiceFunding, gin.H{"invoice_id": invoiceID}))
+	name, data = readEvent()
+	assert.Equal(t, services.EventTypeInvoiceFunding, name)
+	assert.Contains(t, data, invoiceID)
+
+	// Closing the client connection ends the handler and the subscription
+	cancel()
+	select {
+	case <-streamDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream handler did not return after the client disconnected")
+	}
+	select {
+	case <-bus.unsubscribe:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event bus subscription was not closed")
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"regexp"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
 	"github.com/ownafarm/ownafarm-backend/internal/repositories"
 	"github.com/ownafarm/ownafarm-backend/internal/services"
@@ -21,6 +25,8 @@ type FarmerAuthHandler struct {
 	farmerNonceService services.FarmerNonceServiceInterface
 	authService        services.AuthServiceInterface
 	farmerJwtUtil      *utils.FarmerJWTUtil
+	tokenService       services.TokenServiceInterface
+	auditor            audit.Recorder
 }
 
 // NewFarmerAuthHandler creates a new FarmerAuthHandler instance
@@ -29,12 +35,16 @@ func NewFarmerAuthHandler(
 	farmerNonceService services.FarmerNonceServiceInterface,
 	authService services.AuthServiceInterface,
 	farmerJwtUtil *utils.FarmerJWTUtil,
+	tokenService services.TokenServiceInterface,
+	auditor audit.Recorder,
 ) *FarmerAuthHandler {
 	return &FarmerAuthHandler{
 		farmerRepo:         farmerRepo,
 		farmerNonceService: farmerNonceService,
 		authService:        authService,
 		farmerJwtUtil:      farmerJwtUtil,
+		tokenService:       tokenService,
+		auditor:            auditor,
 	}
 }
 
@@ -49,12 +59,16 @@ type FarmerLoginRequest struct {
 	WalletAddress string `json:"wallet_address" binding:"required"`
 	Signature     string `json:"signature" binding:"required"`
 	Nonce         string `json:"nonce" binding:"required"`
+	Message       string `json:"message"` // Signed SIWE message, omitted for EIP-712 logins
 }
 
 // FarmerLoginResponse represents the login response for farmer auth
 type FarmerLoginResponse struct {
-	Token  string                  `json:"token"`
-	Farmer FarmerLoginResponseData `json:"farmer"`
+	Token        string                  `json:"token"`
+	RefreshToken string                  `json:"refresh_token"`
+	ExpiresIn    int64                   `json:"expires_in"` // Access token lifetime in seconds
+	Scope        string                  `json:"scope"`      // full, or application for farmers that are not approved
+	Farmer       FarmerLoginResponseData `json:"farmer"`
 }
 
 // FarmerLoginResponseData contains farmer data in login response
@@ -63,6 +77,7 @@ type FarmerLoginResponseData struct {
 	WalletAddress string `json:"wallet_address"`
 	FullName      string `json:"full_name"`
 	Email         string `json:"email"`
+	Status        string `json:"status"`
 }
 
 // GetNonce handles nonce generation for farmer wallet authentication
@@ -105,8 +120,28 @@ func (h *FarmerAuthHandler) GetNonce(c *gin.Context) {
 		return
 	}
 
-	// Build sign message
-	message := h.farmerNonceService.BuildSignMessage(nonce)
+	// Build the message for the site that asks for the signature
+	message, err := h.authService.BuildLoginMessage(services.LoginMessageRequest{
+		WalletAddress: walletAddress,
+		Nonce:         nonce,
+		Statement:     services.LoginStatementFarmer,
+		Origin:        c.GetHeader("Origin"),
+		LegacyMessage: h.farmerNonceService.BuildSignMessage(nonce),
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrOriginNotAllowed) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Origin is not allowed to sign in",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to build sign message",
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
@@ -119,7 +154,7 @@ func (h *FarmerAuthHandler) GetNonce(c *gin.Context) {
 
 // Login handles farmer login with wallet signature
 // @Summary Farmer login
-// @Description Authenticate farmer with wallet signature (only approved farmers can login)
+// @Description Authenticate farmer with wallet signature (non-approved farmers get an application scope token)
 // @Tags Farmer Auth
 // @Accept json
 // @Produce json
@@ -165,9 +200,23 @@ func (h *FarmerAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Build message and verify signature
-	message := h.farmerNonceService.BuildSignMessage(req.Nonce)
-	if err := h.authService.VerifySignature(req.WalletAddress, req.Signature, message); err != nil {
+	// Verify the signed message
+	if err := h.authService.VerifyLogin(c.Request.Context(), services.LoginProof{
+		WalletAddress: req.WalletAddress,
+		Signature:     req.Signature,
+		Nonce:         req.Nonce,
+		Message:       req.Message,
+		Origin:        c.GetHeader("Origin"),
+		LegacyMessage: h.farmerNonceService.BuildSignMessage(req.Nonce),
+	}); err != nil {
+		if errors.Is(err, services.ErrInvalidLoginMessage) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid sign-in message",
+				"details": err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, services.ErrInvalidSignature) ||
 			errors.Is(err, services.ErrInvalidWalletFormat) ||
 			errors.Is(err, services.ErrSignatureMismatch) {
@@ -204,20 +253,23 @@ func (h *FarmerAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Check if farmer is approved
-	if farmer.Status != models.FarmerStatusApproved {
-		c.JSON(http.StatusForbidden, gin.H{
+	scope, ok := services.FarmerTokenScope(farmer.Status)
+	if !ok {
+		respondFarmerCannotLogin(c, farmer)
+		return
+	}
+
+	// Generate JWT token
+	token, err := h.farmerJwtUtil.GenerateScopedToken(farmer.ID, farmer.WalletAddress, scope)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
-			"message": "Farmer account is not approved",
-			"data": gin.H{
-				"current_status": farmer.Status,
-			},
+			"message": "Failed to generate token",
 		})
 		return
 	}
 
-	// Generate JWT token
-	token, err := h.farmerJwtUtil.GenerateToken(farmer.ID, farmer.WalletAddress)
+	refreshToken, err := h.tokenService.IssueRefreshToken(c.Request.Context(), services.TokenAudienceFarmer, farmer.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -226,16 +278,136 @@ func (h *FarmerAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	h.auditor.Record(c.Request.Context(), audit.Entry{
+		Action:     models.AuditActionFarmerLogin,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmer.ID,
+		After:      map[string]interface{}{"scope": scope},
+		Actor:      &audit.Actor{Type: audit.ActorFarmer, ID: farmer.ID},
+	})
+
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data": FarmerLoginResponse{
-			Token: token,
+			Token:        token,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int64(h.farmerJwtUtil.TTL().Seconds()),
+			Scope:        scope,
 			Farmer: FarmerLoginResponseData{
 				ID:            farmer.ID,
 				WalletAddress: farmer.WalletAddress,
 				FullName:      farmer.FullName,
 				Email:         farmer.Email,
+				Status:        string(farmer.Status),
 			},
 		},
 	})
 }
+
+// Refresh handles exchanging a farmer refresh token
+// @Summary Refresh farmer token
+// @Description Exchange a refresh token for a new access token and refresh token. The scope follows the current farmer status.
+// @Tags Farmer Auth
+// @Accept json
+// @Produce json
+// @Param request body request.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} response.TokenResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /farmer/auth/refresh [post]
+func (h *FarmerAuthHandler) Refresh(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	session, refreshToken, err := h.tokenService.RotateRefreshToken(c.Request.Context(), services.TokenAudienceFarmer, req.RefreshToken)
+	if err != nil {
+		respondRefreshError(c, err)
+		return
+	}
+
+	farmer, err := h.farmerRepo.GetByID(session.Subject)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = h.tokenService.RevokeRefreshToken(c.Request.Context(), services.TokenAudienceFarmer, session.Subject, refreshToken)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Farmer account not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get farmer",
+		})
+		return
+	}
+
+	// The status may have changed since login, e.g. approved or suspended
+	scope, ok := services.FarmerTokenScope(farmer.Status)
+	if !ok {
+		_ = h.tokenService.RevokeRefreshToken(c.Request.Context(), services.TokenAudienceFarmer, farmer.ID, refreshToken)
+		respondFarmerCannotLogin(c, farmer)
+		return
+	}
+
+	token, err := h.farmerJwtUtil.GenerateScopedToken(farmer.ID, farmer.WalletAddress, scope)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": response.TokenResponse{
+			Token:        token,
+			RefreshToken: refreshToken,
+			ExpiresIn:    int64(h.farmerJwtUtil.TTL().Seconds()),
+			Scope:        scope,
+		},
+	})
+}
+
+// Logout handles farmer logout
+// @Summary Farmer logout
+// @Description Revoke the access token of the request and, if given, the refresh token of the session
+// @Tags Farmer Auth
+// @Accept json
+// @Produce json
+// @Param request body request.LogoutRequest false "Refresh token to revoke"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /farmer/auth/logout [post]
+func (h *FarmerAuthHandler) Logout(c *gin.Context) {
+	farmerID, _ := middleware.GetFarmerID(c)
+	logout(c, h.tokenService, services.TokenAudienceFarmer, farmerID)
+}
+
+// respondFarmerCannotLogin writes the response for farmers whose status cannot log in
+func respondFarmerCannotLogin(c *gin.Context, farmer *models.Farmer) {
+	if farmer.Status == models.FarmerStatusErased {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Farmer account has been erased",
+		})
+		return
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"status":  "error",
+		"message": "Farmer account is not approved",
+		"data": gin.H{
+			"current_status": farmer.Status,
+		},
+	})
+}
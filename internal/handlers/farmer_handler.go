@@ -10,6 +10,7 @@ import (
 	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
 	"github.com/ownafarm/ownafarm-backend/internal/middleware"
 	"github.com/ownafarm/ownafarm-backend/internal/services"
+	"github.com/ownafarm/ownafarm-backend/internal/validation"
 )
 
 // FarmerHandler handles farmer-related HTTP requests
@@ -39,6 +40,15 @@ func (h *FarmerHandler) Register(c *gin.Context) {
 
 	farmer, err := h.farmerService.Register(c.Request.Context(), &req)
 	if err != nil {
+		var fieldErrs validation.Errors
+		if errors.As(err, &fieldErrs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validation failed",
+				"details": fieldErrs,
+			})
+			return
+		}
 		if errors.Is(err, services.ErrFarmerAlreadyExists) {
 			c.JSON(http.StatusConflict, gin.H{
 				"status":  "error",
@@ -46,6 +56,20 @@ func (h *FarmerHandler) Register(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, services.ErrIDNumberAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Farmer with this NIK already exists",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrFarmerRejectedResubmit) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Registration for this wallet address was rejected, log in with your wallet to fix and resubmit it",
+			})
+			return
+		}
 		if errors.Is(err, services.ErrWalletAddressAlreadyExists) {
 			c.JSON(http.StatusConflict, gin.H{
 				"status":  "error",
@@ -217,6 +241,61 @@ func (h *FarmerHandler) GetDetailForAdmin(c *gin.Context) {
 	})
 }
 
+// RevealPII handles revealing the masked identity and bank values of a farmer. The reveal is
+// audit logged.
+// POST /admin/farmers/:id/reveal
+func (h *FarmerHandler) RevealPII(c *gin.Context) {
+	farmerID := c.Param("id")
+	if !uuidRegex.MatchString(farmerID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid farmer ID format",
+		})
+		return
+	}
+
+	var req request.RevealFarmerPIIRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	adminID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return
+	}
+
+	resp, err := h.farmerService.RevealPII(c.Request.Context(), farmerID, adminID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrFarmerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Farmer not found",
+			})
+			return
+		}
+		log.Printf("[ERROR] Failed to reveal farmer data: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to reveal farmer data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
 // ApproveFarmer handles approving a farmer registration
 // PATCH /admin/farmers/:id/approve
 func (h *FarmerHandler) ApproveFarmer(c *gin.Context) {
@@ -239,10 +318,7 @@ func (h *FarmerHandler) ApproveFarmer(c *gin.Context) {
 		return
 	}
 
-	ipAddress, _ := middleware.GetIPAddress(c)
-	userAgent, _ := middleware.GetUserAgent(c)
-
-	resp, err := h.farmerService.ApproveFarmer(c.Request.Context(), farmerID, adminID, ipAddress, userAgent)
+	resp, err := h.farmerService.ApproveFarmer(c.Request.Context(), farmerID, adminID)
 	if err != nil {
 		if errors.Is(err, services.ErrFarmerNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -254,7 +330,22 @@ func (h *FarmerHandler) ApproveFarmer(c *gin.Context) {
 		if errors.Is(err, services.ErrFarmerAlreadyProcessed) {
 			c.JSON(http.StatusConflict, gin.H{
 				"status":  "error",
-				"message": "Farmer has already been processed (not in pending status)",
+				"message": "Farmer has already been processed (not in pending or under_review status)",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrFarmerClaimedByOther) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Farmer application is claimed by another admin",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrRequiredDocumentsMissing) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Farmer is missing required documents",
+				"details": err.Error(),
 			})
 			return
 		}
@@ -300,10 +391,7 @@ func (h *FarmerHandler) RejectFarmer(c *gin.Context) {
 		return
 	}
 
-	ipAddress, _ := middleware.GetIPAddress(c)
-	userAgent, _ := middleware.GetUserAgent(c)
-
-	resp, err := h.farmerService.RejectFarmer(c.Request.Context(), farmerID, adminID, req.Reason, ipAddress, userAgent)
+	resp, err := h.farmerService.RejectFarmer(c.Request.Context(), farmerID, adminID, req.Reason)
 	if err != nil {
 		if errors.Is(err, services.ErrFarmerNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -315,7 +403,14 @@ func (h *FarmerHandler) RejectFarmer(c *gin.Context) {
 		if errors.Is(err, services.ErrFarmerAlreadyProcessed) {
 			c.JSON(http.StatusConflict, gin.H{
 				"status":  "error",
-				"message": "Farmer has already been processed (not in pending status)",
+				"message": "Farmer has already been processed (not in pending or under_review status)",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrFarmerClaimedByOther) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Farmer application is claimed by another admin",
 			})
 			return
 		}
@@ -332,6 +427,103 @@ func (h *FarmerHandler) RejectFarmer(c *gin.Context) {
 	})
 }
 
+// ClaimFarmer handles claiming a pending farmer application for review
+// PATCH /admin/farmers/:id/claim
+func (h *FarmerHandler) ClaimFarmer(c *gin.Context) {
+	h.changeStatus(c, "Failed to claim farmer", func(farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
+		return h.farmerService.ClaimFarmer(c.Request.Context(), farmerID, adminID)
+	})
+}
+
+// ReleaseFarmer handles returning a claimed farmer application to the pending queue
+// PATCH /admin/farmers/:id/release
+func (h *FarmerHandler) ReleaseFarmer(c *gin.Context) {
+	h.changeStatus(c, "Failed to release farmer", func(farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
+		return h.farmerService.ReleaseFarmer(c.Request.Context(), farmerID, adminID)
+	})
+}
+
+// SuspendFarmer handles suspending an approved farmer
+// PATCH /admin/farmers/:id/suspend
+func (h *FarmerHandler) SuspendFarmer(c *gin.Context) {
+	var req request.SuspendFarmerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.changeStatus(c, "Failed to suspend farmer", func(farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
+		return h.farmerService.SuspendFarmer(c.Request.Context(), farmerID, adminID, req.Reason)
+	})
+}
+
+// ReinstateFarmer handles lifting the suspension of a farmer
+// PATCH /admin/farmers/:id/reinstate
+func (h *FarmerHandler) ReinstateFarmer(c *gin.Context) {
+	h.changeStatus(c, "Failed to reinstate farmer", func(farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
+		return h.farmerService.ReinstateFarmer(c.Request.Context(), farmerID, adminID)
+	})
+}
+
+// changeStatus runs an admin farmer status change and maps its errors to HTTP responses
+func (h *FarmerHandler) changeStatus(c *gin.Context, fallback string, change func(farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error)) {
+	farmerID := c.Param("id")
+	if !uuidRegex.MatchString(farmerID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid farmer ID format",
+		})
+		return
+	}
+
+	// Get admin info from context
+	adminID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return
+	}
+
+	resp, err := change(farmerID, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFarmerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Farmer not found",
+			})
+		case errors.Is(err, services.ErrInvalidStatusTransition):
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Farmer status does not allow this action",
+			})
+		case errors.Is(err, services.ErrFarmerClaimedByOther):
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Farmer application is claimed by another admin",
+			})
+		default:
+			log.Printf("[ERROR] %s: %v", fallback, err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": fallback,
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
 // GetMe handles getting current logged-in farmer data
 // GET /farmer/me
 func (h *FarmerHandler) GetMe(c *gin.Context) {
@@ -366,3 +558,117 @@ func (h *FarmerHandler) GetMe(c *gin.Context) {
 		"data":   farmer,
 	})
 }
+
+// GetApplication returns the registration status, rejection reason and submission history
+// of the logged-in farmer
+// GET /farmer/application
+func (h *FarmerHandler) GetApplication(c *gin.Context) {
+	farmerID, exists := middleware.GetFarmerID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Farmer not authenticated",
+		})
+		return
+	}
+
+	resp, err := h.farmerService.GetApplication(c.Request.Context(), farmerID)
+	if err != nil {
+		if errors.Is(err, services.ErrFarmerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Farmer not found",
+			})
+			return
+		}
+		log.Printf("[ERROR] Failed to get farmer application: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get application",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Resubmit replaces a rejected registration with corrected data and documents
+// PUT /farmer/application
+func (h *FarmerHandler) Resubmit(c *gin.Context) {
+	farmerID, exists := middleware.GetFarmerID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Farmer not authenticated",
+		})
+		return
+	}
+
+	var req request.RegisterFarmerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.farmerService.Resubmit(c.Request.Context(), farmerID, &req)
+	if err != nil {
+		var fieldErrs validation.Errors
+		switch {
+		case errors.As(err, &fieldErrs):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validation failed",
+				"details": fieldErrs,
+			})
+		case errors.Is(err, services.ErrFarmerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Farmer not found",
+			})
+		case errors.Is(err, services.ErrFarmerNotRejected):
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Only rejected registrations can be resubmitted",
+			})
+		case errors.Is(err, services.ErrWalletAddressMismatch):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Wallet address does not match the logged-in farmer",
+			})
+		case errors.Is(err, services.ErrFarmerAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Another farmer with this email or phone number already exists",
+			})
+		case errors.Is(err, services.ErrIDNumberAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Another farmer with this NIK already exists",
+			})
+		case errors.Is(err, services.ErrInvalidDateFormat):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Invalid date format for date_of_birth, expected YYYY-MM-DD",
+			})
+		default:
+			log.Printf("[ERROR] Failed to resubmit farmer registration: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Failed to resubmit registration",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
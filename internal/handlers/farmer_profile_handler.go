@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+	"github.com/ownafarm/ownafarm-backend/internal/validation"
+)
+
+// FarmerProfileHandler handles farmer profile self-service and the admin review of sensitive changes
+type FarmerProfileHandler struct {
+	profileService services.FarmerProfileServiceInterface
+}
+
+// NewFarmerProfileHandler creates a new FarmerProfileHandler instance
+func NewFarmerProfileHandler(profileService services.FarmerProfileServiceInterface) *FarmerProfileHandler {
+	return &FarmerProfileHandler{
+		profileService: profileService,
+	}
+}
+
+// UpdateMe updates the profile of the logged-in farmer
+// PATCH /farmer/me
+func (h *FarmerProfileHandler) UpdateMe(c *gin.Context) {
+	farmerID, exists := middleware.GetFarmerID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Farmer not authenticated",
+		})
+		return
+	}
+
+	var req request.UpdateFarmerProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.profileService.UpdateProfile(c.Request.Context(), farmerID, &req)
+	if err != nil {
+		var fieldErrs validation.Errors
+		if errors.As(err, &fieldErrs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validation failed",
+				"details": fieldErrs,
+			})
+			return
+		}
+		if errors.Is(err, services.ErrFarmerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Farmer not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrPhoneNumberInUse) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Phone number is already used by another farmer",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrIDNumberAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "NIK is already used by another farmer",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrProfileChangePending) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "A change to sensitive fields is already waiting for admin review",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrBankAccountLocked) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Bank account cannot be changed while payouts are pending",
+			})
+			return
+		}
+		log.Printf("[ERROR] Failed to update farmer profile: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to update profile",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// ListMyChanges lists the profile change history of the logged-in farmer
+// GET /farmer/me/change-requests
+func (h *FarmerProfileHandler) ListMyChanges(c *gin.Context) {
+	farmerID, exists := middleware.GetFarmerID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Farmer not authenticated",
+		})
+		return
+	}
+
+	var req request.ListProfileChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.profileService.ListMyChanges(c.Request.Context(), farmerID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get profile changes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// ListForAdmin lists sensitive profile change requests
+// GET /admin/farmer-change-requests
+func (h *FarmerProfileHandler) ListForAdmin(c *gin.Context) {
+	var req request.ListProfileChangesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.profileService.ListForAdmin(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get profile change requests",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// ApproveChange approves a sensitive profile change and applies it
+// PATCH /admin/farmer-change-requests/:id/approve
+func (h *FarmerProfileHandler) ApproveChange(c *gin.Context) {
+	changeID := c.Param("id")
+	if !uuidRegex.MatchString(changeID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid change request ID format",
+		})
+		return
+	}
+
+	adminID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return
+	}
+
+	resp, err := h.profileService.ApproveChange(c.Request.Context(), changeID, adminID)
+	if err != nil {
+		h.handleReviewError(c, err, "Failed to approve profile change")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// RejectChange rejects a sensitive profile change
+// PATCH /admin/farmer-change-requests/:id/reject
+func (h *FarmerProfileHandler) RejectChange(c *gin.Context) {
+	changeID := c.Param("id")
+	if !uuidRegex.MatchString(changeID) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid change request ID format",
+		})
+		return
+	}
+
+	// Parse request body (optional reason)
+	var req request.RejectProfileChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// If body is empty or invalid, continue with nil reason
+		req.Reason = nil
+	}
+
+	adminID, exists := middleware.GetAdminID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Admin ID not found in context",
+		})
+		return
+	}
+
+	resp, err := h.profileService.RejectChange(c.Request.Context(), changeID, adminID, req.Reason)
+	if err != nil {
+		h.handleReviewError(c, err, "Failed to reject profile change")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// handleReviewError maps admin review errors to HTTP responses
+func (h *FarmerProfileHandler) handleReviewError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrProfileChangeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Profile change request not found",
+		})
+	case errors.Is(err, services.ErrFarmerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": "Farmer not found",
+		})
+	case errors.Is(err, services.ErrProfileChangeAlreadyProcessed):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Profile change request has already been processed (not in pending status)",
+		})
+	case errors.Is(err, services.ErrBankAccountLocked):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Bank account cannot be changed while payouts are pending",
+		})
+	case errors.Is(err, services.ErrIDNumberAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "NIK is already used by another farmer",
+		})
+	case errors.Is(err, services.ErrFarmerNotApproved):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": "Farmer is not approved, the change cannot be applied",
+		})
+	default:
+		log.Printf("[ERROR] %s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": fallback,
+		})
+	}
+}
@@ -53,6 +53,13 @@ func (h *InvoiceHandler) Create(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, services.ErrFarmerNotApproved) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Farmer account is not approved or is suspended",
+			})
+			return
+		}
 		if errors.Is(err, services.ErrFarmNotActive) {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  "error",
@@ -120,6 +127,141 @@ func (h *InvoiceHandler) GetByID(c *gin.Context) {
 	})
 }
 
+// Update handles editing a pending invoice
+// PUT /farmer/invoices/:id
+func (h *InvoiceHandler) Update(c *gin.Context) {
+	farmerID, exists := middleware.GetFarmerID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Farmer not authenticated",
+		})
+		return
+	}
+
+	invoiceID := c.Param("id")
+	if invoiceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invoice ID is required",
+		})
+		return
+	}
+
+	var req request.UpdateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.invoiceService.Update(c.Request.Context(), farmerID, invoiceID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvoiceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Invoice not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvoiceNotEditable) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Invoice can only be changed while pending and not on chain",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrFarmNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Farm not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrFarmerNotApproved) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Farmer account is not approved or is suspended",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrFarmNotActive) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Farm is not active",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to update invoice",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"invoice": resp,
+		},
+	})
+}
+
+// Withdraw handles withdrawing a pending invoice
+// DELETE /farmer/invoices/:id
+func (h *InvoiceHandler) Withdraw(c *gin.Context) {
+	farmerID, exists := middleware.GetFarmerID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Farmer not authenticated",
+		})
+		return
+	}
+
+	invoiceID := c.Param("id")
+	if invoiceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invoice ID is required",
+		})
+		return
+	}
+
+	resp, err := h.invoiceService.Withdraw(c.Request.Context(), farmerID, invoiceID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvoiceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": "Invoice not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvoiceNotEditable) {
+			c.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": "Invoice can only be changed while pending and not on chain",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to withdraw invoice",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"invoice": resp,
+		},
+	})
+}
+
 // List handles listing invoices for the authenticated farmer
 // GET /farmer/invoices
 func (h *InvoiceHandler) List(c *gin.Context) {
@@ -323,10 +465,7 @@ func (h *InvoiceHandler) ApproveInvoice(c *gin.Context) {
 		return
 	}
 
-	ipAddress, _ := middleware.GetIPAddress(c)
-	userAgent, _ := middleware.GetUserAgent(c)
-
-	resp, err := h.invoiceService.ApproveInvoice(c.Request.Context(), invoiceID, adminID, &req, ipAddress, userAgent)
+	resp, err := h.invoiceService.ApproveInvoice(c.Request.Context(), invoiceID, adminID, &req)
 	if err != nil {
 		if errors.Is(err, services.ErrInvoiceNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -382,10 +521,7 @@ func (h *InvoiceHandler) RejectInvoice(c *gin.Context) {
 		req = request.RejectInvoiceRequest{}
 	}
 
-	ipAddress, _ := middleware.GetIPAddress(c)
-	userAgent, _ := middleware.GetUserAgent(c)
-
-	resp, err := h.invoiceService.RejectInvoice(c.Request.Context(), invoiceID, adminID, req.Reason, ipAddress, userAgent)
+	resp, err := h.invoiceService.RejectInvoice(c.Request.Context(), invoiceID, adminID, req.Reason)
 	if err != nil {
 		if errors.Is(err, services.ErrInvoiceNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
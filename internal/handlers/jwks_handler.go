@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/utils"
+)
+
+// JWKSHandler publishes the public keys of the token signing key sets
+type JWKSHandler struct {
+	keySets []*utils.KeySet
+}
+
+// NewJWKSHandler creates a new JWKSHandler instance
+func NewJWKSHandler(keySets ...*utils.KeySet) *JWKSHandler {
+	return &JWKSHandler{keySets: keySets}
+}
+
+// JWKSResponse is a JSON Web Key Set (RFC 7517)
+type JWKSResponse struct {
+	Keys []utils.JWK `json:"keys"`
+}
+
+// GetJWKS returns the public keys that verify OwnaFarm tokens
+// @Summary Get JSON Web Key Set
+// @Description Public Ed25519 keys of investor, farmer, admin and session tokens, including retired keys still in their grace period. Tokens name their key in the kid header and their audience in the aud claim.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} JWKSResponse
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	resp := JWKSResponse{Keys: []utils.JWK{}}
+	for _, keySet := range h.keySets {
+		resp.Keys = append(resp.Keys, keySet.PublicKeys()...)
+	}
+
+	// Verifiers cache the keys; a new key is published before it signs anything
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, resp)
+}
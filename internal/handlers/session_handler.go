@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/ownafarm/ownafarm-backend/internal/services"
+	"github.com/ownafarm/ownafarm-backend/internal/utils"
+)
+
+// SessionHandler handles the single wallet login, whose token carries every role of the wallet
+type SessionHandler struct {
+	identityService services.IdentityServiceInterface
+	userRepo        repositories.UserRepository
+	adminRepo       repositories.AdminUserRepository
+	nonceService    services.NonceServiceInterface
+	authService     services.AuthServiceInterface
+	sessionJwtUtil  *utils.SessionJWTUtil
+	tokenService    services.TokenServiceInterface
+	auditor         audit.Recorder
+}
+
+// NewSessionHandler creates a new SessionHandler instance
+func NewSessionHandler(
+	identityService services.IdentityServiceInterface,
+	userRepo repositories.UserRepository,
+	adminRepo repositories.AdminUserRepository,
+	nonceService services.NonceServiceInterface,
+	authService services.AuthServiceInterface,
+	sessionJwtUtil *utils.SessionJWTUtil,
+	tokenService services.TokenServiceInterface,
+	auditor audit.Recorder,
+) *SessionHandler {
+	return &SessionHandler{
+		identityService: identityService,
+		userRepo:        userRepo,
+		adminRepo:       adminRepo,
+		nonceService:    nonceService,
+		authService:     authService,
+		sessionJwtUtil:  sessionJwtUtil,
+		tokenService:    tokenService,
+		auditor:         auditor,
+	}
+}
+
+// SessionResponse is the session token of a wallet with the accounts of its roles
+type SessionResponse struct {
+	Token        string              `json:"token"`
+	RefreshToken string              `json:"refresh_token"`
+	ExpiresIn    int64               `json:"expires_in"` // Access token lifetime in seconds
+	Roles        []string            `json:"roles"`
+	User         *models.User        `json:"user,omitempty"`
+	Farmer       *models.Farmer      `json:"farmer,omitempty"`
+	Admin        *response.AdminInfo `json:"admin,omitempty"`
+}
+
+// GetNonce returns the nonce and message a wallet signs to start a session
+func (h *SessionHandler) GetNonce(c *gin.Context) {
+	var req GetNonceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "wallet_address is required",
+		})
+		return
+	}
+
+	// Validate wallet address format
+	if !walletAddressRegex.MatchString(req.WalletAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid wallet address format",
+		})
+		return
+	}
+
+	// Generate nonce
+	nonce, err := h.nonceService.GenerateNonce(c.Request.Context(), req.WalletAddress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate nonce",
+		})
+		return
+	}
+
+	// Build the message for the site that asks for the signature
+	message, err := h.authService.BuildLoginMessage(services.LoginMessageRequest{
+		WalletAddress: req.WalletAddress,
+		Nonce:         nonce,
+		Statement:     services.LoginStatementInvestor,
+		Origin:        c.GetHeader("Origin"),
+		LegacyMessage: h.nonceService.BuildSignMessage(nonce),
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrOriginNotAllowed) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Origin is not allowed to sign in",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to build sign message",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": GetNonceResponse{
+			Nonce:   nonce,
+			Message: message,
+		},
+	})
+}
+
+// Login verifies the signed message and returns a session token with every role of the wallet
+func (h *SessionHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	// Validate wallet address format
+	if !walletAddressRegex.MatchString(req.WalletAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid wallet address format",
+		})
+		return
+	}
+
+	// Validate nonce
+	if err := h.nonceService.ValidateAndDeleteNonce(c.Request.Context(), req.WalletAddress, req.Nonce); err != nil {
+		if errors.Is(err, services.ErrNonceNotFound) || errors.Is(err, services.ErrNonceMismatch) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid or expired nonce",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to validate nonce",
+		})
+		return
+	}
+
+	// Verify the signed message
+	if err := h.authService.VerifyLogin(c.Request.Context(), services.LoginProof{
+		WalletAddress: req.WalletAddress,
+		Signature:     req.Signature,
+		Nonce:         req.Nonce,
+		Message:       req.Message,
+		Origin:        c.GetHeader("Origin"),
+		LegacyMessage: h.nonceService.BuildSignMessage(req.Nonce),
+	}); err != nil {
+		if errors.Is(err, services.ErrInvalidLoginMessage) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid sign-in message",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidSignature) ||
+			errors.Is(err, services.ErrInvalidWalletFormat) ||
+			errors.Is(err, services.ErrSignatureMismatch) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid signature",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to verify signature",
+		})
+		return
+	}
+
+	// Find the roles of the wallet
+	normalizedAddress := h.authService.NormalizeWalletAddress(req.WalletAddress)
+	identity, err := h.identityService.Resolve(c.Request.Context(), normalizedAddress, time.Now())
+	if err != nil {
+		if errors.Is(err, services.ErrNoActiveRole) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Wallet has no active account",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get account",
+		})
+		return
+	}
+
+	// Update last login, failures don't fail the login
+	if identity.User != nil {
+		_ = h.userRepo.UpdateLastLogin(identity.User.ID)
+	}
+	if identity.Admin != nil {
+		_ = h.adminRepo.UpdateLastLogin(c.Request.Context(), identity.Admin.ID)
+	}
+
+	refreshToken, err := h.tokenService.IssueRefreshToken(c.Request.Context(), services.TokenAudienceSession, normalizedAddress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate token",
+		})
+		return
+	}
+
+	resp, err := h.newSessionResponse(identity, refreshToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate token",
+		})
+		return
+	}
+
+	h.auditor.Record(c.Request.Context(), sessionLoginEntry(identity))
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Refresh exchanges a refresh token for a new session. The roles are resolved again, so a
+// wallet that lost a role gets a token without it.
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req request.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	session, refreshToken, err := h.tokenService.RotateRefreshToken(c.Request.Context(), services.TokenAudienceSession, req.RefreshToken)
+	if err != nil {
+		respondRefreshError(c, err)
+		return
+	}
+
+	identity, err := h.identityService.Resolve(c.Request.Context(), session.Subject, session.IssuedAt)
+	if err != nil {
+		if errors.Is(err, services.ErrNoActiveRole) {
+			_ = h.tokenService.RevokeRefreshToken(c.Request.Context(), services.TokenAudienceSession, session.Subject, refreshToken)
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Account is no longer available",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to get account",
+		})
+		return
+	}
+
+	resp, err := h.newSessionResponse(identity, refreshToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to generate token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   resp,
+	})
+}
+
+// Logout revokes the session token of the request and the refresh token in the body, if any
+func (h *SessionHandler) Logout(c *gin.Context) {
+	walletAddress, _ := middleware.GetWalletAddress(c)
+	logout(c, h.tokenService, services.TokenAudienceSession, walletAddress)
+}
+
+func (h *SessionHandler) newSessionResponse(identity *services.WalletIdentity, refreshToken string) (*SessionResponse, error) {
+	token, err := h.sessionJwtUtil.GenerateToken(identity.SessionClaims())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SessionResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(h.sessionJwtUtil.TTL().Seconds()),
+		Roles:        identity.Roles,
+		User:         identity.User,
+		Farmer:       identity.Farmer,
+	}
+	if identity.Admin != nil {
+		resp.Admin = &response.AdminInfo{
+			ID:            identity.Admin.ID,
+			WalletAddress: identity.Admin.WalletAddress,
+			Role:          identity.Admin.Role,
+		}
+	}
+	return resp, nil
+}
+
+// sessionLoginEntry records the login against the investor account, or the farmer or admin
+// account of wallets without one
+func sessionLoginEntry(identity *services.WalletIdentity) audit.Entry {
+	entry := audit.Entry{
+		Action: models.AuditActionSessionLogin,
+		After:  map[string]interface{}{"roles": identity.Roles},
+	}
+	switch {
+	case identity.User != nil:
+		entry.EntityType, entry.EntityID = models.AuditEntityTypeUser, identity.User.ID
+		entry.Actor = &audit.Actor{Type: audit.ActorInvestor, ID: identity.User.ID}
+	case identity.HasRole(utils.RoleFarmer):
+		entry.EntityType, entry.EntityID = models.AuditEntityTypeFarmer, identity.Farmer.ID
+		entry.Actor = &audit.Actor{Type: audit.ActorFarmer, ID: identity.Farmer.ID}
+	default:
+		entry.EntityType, entry.EntityID = models.AuditEntityTypeAdmin, identity.Admin.ID
+		entry.Actor = &audit.Actor{Type: audit.ActorAdmin, ID: identity.Admin.ID}
+	}
+	return entry
+}
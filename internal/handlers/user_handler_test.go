@@ -32,6 +32,10 @@ func (m *mockUserRepository) RegenerateWater(userID string) (*models.User, error
 	if m.RegenerateWaterFunc != nil {
 		return m.RegenerateWaterFunc(userID)
 	}
+	// RegenerateWater loads the user first, so fall back to the GetByID stub
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(userID)
+	}
 	return nil, nil
 }
 
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
 	"github.com/ownafarm/ownafarm-backend/internal/utils"
 )
 
@@ -13,18 +15,19 @@ const (
 	ContextKeyAdminID            = "admin_id"
 	ContextKeyAdminWalletAddress = "admin_wallet_address"
 	ContextKeyAdminRole          = "admin_role"
-	ContextKeyIPAddress          = "ip_address"
-	ContextKeyUserAgent          = "user_agent"
 )
 
 // AdminAuthMiddleware handles authentication for admin users
 type AdminAuthMiddleware struct {
-	adminJwtUtil *utils.AdminJWTUtil
+	adminJwtUtil   *utils.AdminJWTUtil
+	sessionJwtUtil *utils.SessionJWTUtil
+	adminRepo      repositories.AdminUserRepository
+	revocations    TokenRevocationChecker
 }
 
 // NewAdminAuthMiddleware creates a new AdminAuthMiddleware instance
-func NewAdminAuthMiddleware(adminJwtUtil *utils.AdminJWTUtil) *AdminAuthMiddleware {
-	return &AdminAuthMiddleware{adminJwtUtil: adminJwtUtil}
+func NewAdminAuthMiddleware(adminJwtUtil *utils.AdminJWTUtil, sessionJwtUtil *utils.SessionJWTUtil, adminRepo repositories.AdminUserRepository, revocations TokenRevocationChecker) *AdminAuthMiddleware {
+	return &AdminAuthMiddleware{adminJwtUtil: adminJwtUtil, sessionJwtUtil: sessionJwtUtil, adminRepo: adminRepo, revocations: revocations}
 }
 
 // AdminAuthRequired returns a middleware that requires admin authentication
@@ -59,31 +62,69 @@ func (m *AdminAuthMiddleware) AdminAuthRequired() gin.HandlerFunc {
 			return
 		}
 
-		// Validate admin token
-		claims, err := m.adminJwtUtil.ValidateToken(tokenString)
-		if err != nil {
-			if errors.Is(err, utils.ErrExpiredToken) {
+		// Session tokens holding the admin role work like admin tokens
+		session, ok := authenticateSession(c, m.sessionJwtUtil, m.revocations, tokenString, utils.RoleAdmin)
+		if !ok {
+			return
+		}
+
+		claims := &utils.AdminClaims{}
+		if session != nil {
+			claims.AdminID = session.AdminID
+			claims.RegisteredClaims = session.RegisteredClaims
+		} else {
+			// Validate admin token
+			var err error
+			claims, err = m.adminJwtUtil.ValidateToken(tokenString)
+			if err != nil {
+				if errors.Is(err, utils.ErrExpiredToken) {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+						"status":  "error",
+						"message": "Token has expired",
+					})
+					return
+				}
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 					"status":  "error",
-					"message": "Token has expired",
+					"message": "Invalid token",
 				})
 				return
 			}
+
+			if !checkNotRevoked(c, m.revocations, &claims.RegisteredClaims) {
+				return
+			}
+		}
+
+		// Tokens outlive admin changes, so check the current account: deactivation revokes
+		// existing tokens and role changes apply immediately
+		admin, err := m.adminRepo.GetByID(c.Request.Context(), claims.AdminID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Admin account not found",
+			})
+			return
+		}
+		if !admin.IsActive {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"status":  "error",
-				"message": "Invalid token",
+				"message": "Admin account is inactive",
+			})
+			return
+		}
+		if admin.TokensRevokedAt != nil && claims.IssuedAt != nil && claims.IssuedAt.Time.Before(admin.TokensRevokedAt.Truncate(time.Second)) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Token has been revoked",
 			})
 			return
 		}
 
 		// Set admin info in context
-		c.Set(ContextKeyAdminID, claims.AdminID)
-		c.Set(ContextKeyAdminWalletAddress, claims.WalletAddress)
-		c.Set(ContextKeyAdminRole, claims.Role)
-
-		// Set request metadata for audit logging
-		c.Set(ContextKeyIPAddress, c.ClientIP())
-		c.Set(ContextKeyUserAgent, c.GetHeader("User-Agent"))
+		c.Set(ContextKeyAdminID, admin.ID)
+		c.Set(ContextKeyAdminWalletAddress, admin.WalletAddress)
+		c.Set(ContextKeyAdminRole, admin.Role)
 
 		c.Next()
 	}
@@ -115,21 +156,3 @@ func GetAdminRole(c *gin.Context) (string, bool) {
 	}
 	return role.(string), true
 }
-
-// GetIPAddress retrieves the client IP address from the context
-func GetIPAddress(c *gin.Context) (string, bool) {
-	ipAddress, exists := c.Get(ContextKeyIPAddress)
-	if !exists {
-		return "", false
-	}
-	return ipAddress.(string), true
-}
-
-// GetUserAgent retrieves the user agent from the context
-func GetUserAgent(c *gin.Context) (string, bool) {
-	userAgent, exists := c.Get(ContextKeyUserAgent)
-	if !exists {
-		return "", false
-	}
-	return userAgent.(string), true
-}
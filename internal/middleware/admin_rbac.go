@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
+
+// RequirePermission returns a middleware that only lets admins through whose role grants all
+// the given permissions. It must run after AdminAuthRequired.
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := GetAdminRole(c)
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Admin role not found in context",
+			})
+			return
+		}
+
+		for _, permission := range permissions {
+			if !models.AdminRoleHasPermission(role, permission) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"status":  "error",
+					"message": "Insufficient permissions",
+					"details": gin.H{
+						"role":     role,
+						"required": permission,
+					},
+				})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminRolePermissionMatrix(t *testing.T) {
+	const (
+		super    = models.AdminRoleSuperAdmin
+		reviewer = models.AdminRoleReviewer
+		finance  = models.AdminRoleFinance
+		readOnly = models.AdminRoleReadOnly
+	)
+
+	tests := []struct {
+		permission models.Permission
+		allowed    []string
+	}{
+		{models.PermissionFarmersRead, []string{super, reviewer, finance, readOnly}},
+		{models.PermissionFarmersReview, []string{super, reviewer}},
+		{models.PermissionFarmersSuspend, []string{super, reviewer}},
+		{models.PermissionFarmersRevealPII, []string{super, finance}},
+		{models.PermissionProfileChangesReview, []string{super, reviewer, finance}},
+		{models.PermissionInvoicesRead, []string{super, reviewer, finance, readOnly}},
+		{models.PermissionInvoicesReview, []string{super, reviewer, finance}},
+		{models.PermissionDataSubjectExport, []string{super}},
+		{models.PermissionDataSubjectErase, []string{super}},
+		{models.PermissionAdminsManage, []string{super}},
+		{models.PermissionAuditLogsRead, []string{super, readOnly}},
+	}
+
+	roles := []string{super, reviewer, finance, readOnly, "admin", ""}
+	for _, tt := range tests {
+		for _, role := range roles {
+			want := false
+			for _, allowed := range tt.allowed {
+				if allowed == role {
+					want = true
+				}
+			}
+			t.Run(string(tt.permission)+"/"+role, func(t *testing.T) {
+				assert.Equal(t, want, models.AdminRoleHasPermission(role, tt.permission))
+			})
+		}
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		role        *string
+		permissions []models.Permission
+		wantStatus  int
+	}{
+		{name: "granted", role: ptr(models.AdminRoleReviewer), permissions: []models.Permission{models.PermissionFarmersReview}, wantStatus: http.StatusOK},
+		{name: "all required", role: ptr(models.AdminRoleReviewer), permissions: []models.Permission{models.PermissionFarmersReview, models.PermissionFarmersRevealPII}, wantStatus: http.StatusForbidden},
+		{name: "denied", role: ptr(models.AdminRoleReadOnly), permissions: []models.Permission{models.PermissionInvoicesReview}, wantStatus: http.StatusForbidden},
+		{name: "legacy role", role: ptr("admin"), permissions: []models.Permission{models.PermissionFarmersRead}, wantStatus: http.StatusForbidden},
+		{name: "no role in context", permissions: []models.Permission{models.PermissionFarmersRead}, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				if tt.role != nil {
+					c.Set(ContextKeyAdminRole, *tt.role)
+				}
+				c.Next()
+			}, RequirePermission(tt.permissions...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
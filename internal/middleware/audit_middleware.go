@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
+
+var auditUUIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// AuditMiddleware makes the actor, IP address and user agent of a request available to the audit
+// recorder, and makes sure every successful mutating request leaves an audit entry
+type AuditMiddleware struct {
+	recorder audit.Recorder
+}
+
+// NewAuditMiddleware creates a new AuditMiddleware instance
+func NewAuditMiddleware(recorder audit.Recorder) *AuditMiddleware {
+	return &AuditMiddleware{recorder: recorder}
+}
+
+// Audit attaches the audit state to the request context. It runs before authentication, the actor
+// is read from the context keys set by the auth middlewares when an entry is recorded. Successful
+// POST, PUT, PATCH and DELETE requests that no service recorded get a generic request entry.
+func (m *AuditMiddleware) Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := audit.NewRequest(c.ClientIP(), c.GetHeader("User-Agent"), func() audit.Actor {
+			return actorFromContext(c)
+		})
+		c.Request = c.Request.WithContext(audit.WithRequest(c.Request.Context(), state))
+
+		c.Next()
+
+		if !isMutatingMethod(c.Request.Method) || state.Recorded() || state.Skipped() {
+			return
+		}
+		status := c.Writer.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return
+		}
+
+		actor := state.Actor()
+		entityID := c.Param("id")
+		if !auditUUIDRegex.MatchString(entityID) {
+			entityID = actor.ID
+		}
+		if entityID == "" {
+			log.Printf("[WARN] Unaudited anonymous request: %s %s", c.Request.Method, c.FullPath())
+			return
+		}
+
+		m.recorder.Record(c.Request.Context(), audit.Entry{
+			Action:     models.AuditActionRequest,
+			EntityType: models.AuditEntityTypeRoute,
+			EntityID:   entityID,
+			After: map[string]interface{}{
+				"method": c.Request.Method,
+				"route":  c.FullPath(),
+				"status": status,
+			},
+		})
+	}
+}
+
+// SkipAudit excludes a mutating route that changes nothing, such as presigning upload URLs, from
+// the generic request entry
+func SkipAudit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if state := audit.FromContext(c.Request.Context()); state != nil {
+			state.Skip()
+		}
+		c.Next()
+	}
+}
+
+// actorFromContext returns the actor authenticated by the admin, farmer or investor middleware
+func actorFromContext(c *gin.Context) audit.Actor {
+	if adminID, ok := GetAdminID(c); ok {
+		return audit.Actor{Type: audit.ActorAdmin, ID: adminID}
+	}
+	if farmerID, ok := GetFarmerID(c); ok {
+		return audit.Actor{Type: audit.ActorFarmer, ID: farmerID}
+	}
+	if userID, ok := GetUserID(c); ok {
+		return audit.Actor{Type: audit.ActorInvestor, ID: userID}
+	}
+	return audit.Actor{}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
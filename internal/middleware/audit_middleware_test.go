@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRecorder records entries like the audit service, marking the request as recorded
+type memoryRecorder struct {
+	entries []audit.Entry
+	actors  []audit.Actor
+}
+
+func (m *memoryRecorder) Record(ctx context.Context, entry audit.Entry) {
+	actor := audit.Actor{Type: audit.ActorSystem}
+	if req := audit.FromContext(ctx); req != nil {
+		actor = req.Actor()
+		req.MarkRecorded()
+	}
+	m.entries = append(m.entries, entry)
+	m.actors = append(m.actors, actor)
+}
+
+func (m *memoryRecorder) RecordRequired(ctx context.Context, entry audit.Entry) error {
+	m.Record(ctx, entry)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		adminID = "11111111-1111-1111-1111-111111111111"
+		farmID  = "22222222-2222-2222-2222-222222222222"
+	)
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		handler   func(recorder *memoryRecorder) gin.HandlerFunc
+		skip      bool
+		wantCount int
+	}{
+		{name: "unrecorded mutation", method: http.MethodPatch, path: "/farms/" + farmID, wantCount: 1},
+		{name: "recorded by the service", method: http.MethodPatch, path: "/farms/" + farmID, handler: func(recorder *memoryRecorder) gin.HandlerFunc {
+			return func(c *gin.Context) {
+				recorder.Record(c.Request.Context(), audit.Entry{Action: models.AuditActionUpdateFarm})
+				c.Status(http.StatusOK)
+			}
+		}, wantCount: 1},
+		{name: "read", method: http.MethodGet, path: "/farms/" + farmID, wantCount: 0},
+		{name: "failed", method: http.MethodPatch, path: "/farms/" + farmID, handler: func(*memoryRecorder) gin.HandlerFunc {
+			return func(c *gin.Context) { c.Status(http.StatusBadRequest) }
+		}, wantCount: 0},
+		{name: "skipped", method: http.MethodPost, path: "/farms/" + farmID, skip: true, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &memoryRecorder{}
+			handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+			if tt.handler != nil {
+				handler = tt.handler(recorder)
+			}
+			handlers := []gin.HandlerFunc{func(c *gin.Context) {
+				c.Set(ContextKeyAdminID, adminID)
+				c.Next()
+			}}
+			if tt.skip {
+				handlers = append(handlers, SkipAudit())
+			}
+
+			router := gin.New()
+			router.Use(NewAuditMiddleware(recorder).Audit())
+			router.Handle(tt.method, "/farms/:id", append(handlers, handler)...)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			require.Len(t, recorder.entries, tt.wantCount)
+			for _, actor := range recorder.actors {
+				assert.Equal(t, audit.Actor{Type: audit.ActorAdmin, ID: adminID}, actor)
+			}
+			if tt.handler == nil && tt.wantCount == 1 {
+				assert.Equal(t, models.AuditActionRequest, recorder.entries[0].Action)
+				assert.Equal(t, farmID, recorder.entries[0].EntityID)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/ownafarm/ownafarm-backend/internal/utils"
 )
 
@@ -14,20 +17,58 @@ const (
 	BearerPrefix        = "Bearer "
 	ContextKeyUserID    = "user_id"
 	ContextKeyWallet    = "wallet_address"
+
+	// ContextKeyTokenID and ContextKeyTokenExpiresAt identify the access token of the request,
+	// for logout
+	ContextKeyTokenID        = "token_id"
+	ContextKeyTokenExpiresAt = "token_expires_at"
+
+	// StreamTicketQueryParam carries a single-use stream ticket for clients that cannot set
+	// headers (EventSource). Access tokens are never accepted in the URL, which ends up in logs.
+	StreamTicketQueryParam = "ticket"
 )
 
+// TokenRevocationChecker reports whether an access token was revoked before it expired
+type TokenRevocationChecker interface {
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// StreamTicketRedeemer exchanges a single-use stream ticket for the investor it was issued to
+type StreamTicketRedeemer interface {
+	RedeemStreamTicket(ctx context.Context, ticket string) (userID, walletAddress string, err error)
+}
+
 type AuthMiddleware struct {
-	jwtUtil *utils.JWTUtil
+	jwtUtil        *utils.JWTUtil
+	sessionJwtUtil *utils.SessionJWTUtil
+	revocations    TokenRevocationChecker
+	streamTickets  StreamTicketRedeemer
 }
 
-func NewAuthMiddleware(jwtUtil *utils.JWTUtil) *AuthMiddleware {
-	return &AuthMiddleware{jwtUtil: jwtUtil}
+func NewAuthMiddleware(jwtUtil *utils.JWTUtil, sessionJwtUtil *utils.SessionJWTUtil, revocations TokenRevocationChecker, streamTickets StreamTicketRedeemer) *AuthMiddleware {
+	return &AuthMiddleware{jwtUtil: jwtUtil, sessionJwtUtil: sessionJwtUtil, revocations: revocations, streamTickets: streamTickets}
 }
 
 func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
+	return m.authenticate(false)
+}
+
+// StreamAuthRequired is AuthRequired for Server-Sent Events endpoints. Browsers cannot set
+// headers on EventSource, so a stream ticket may be passed as the ticket query param instead.
+func (m *AuthMiddleware) StreamAuthRequired() gin.HandlerFunc {
+	return m.authenticate(true)
+}
+
+func (m *AuthMiddleware) authenticate(allowStreamTicket bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader(AuthorizationHeader)
+		if authHeader == "" && allowStreamTicket {
+			if ticket := c.Query(StreamTicketQueryParam); ticket != "" {
+				m.redeemStreamTicket(c, ticket)
+				return
+			}
+		}
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"status":  "error",
@@ -55,6 +96,18 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
+		// Session tokens holding the investor role work like investor tokens
+		session, ok := authenticateSession(c, m.sessionJwtUtil, m.revocations, tokenString, utils.RoleInvestor)
+		if !ok {
+			return
+		}
+		if session != nil {
+			c.Set(ContextKeyUserID, session.UserID)
+			c.Set(ContextKeyWallet, session.WalletAddress)
+			c.Next()
+			return
+		}
+
 		// Validate token
 		claims, err := m.jwtUtil.ValidateToken(tokenString)
 		if err != nil {
@@ -72,6 +125,10 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
+		if !checkNotRevoked(c, m.revocations, &claims.RegisteredClaims) {
+			return
+		}
+
 		// Set user info in context
 		c.Set(ContextKeyUserID, claims.UserID)
 		c.Set(ContextKeyWallet, claims.WalletAddress)
@@ -80,6 +137,22 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	}
 }
 
+// redeemStreamTicket authenticates the request as the investor the stream ticket was issued to
+func (m *AuthMiddleware) redeemStreamTicket(c *gin.Context, ticket string) {
+	userID, walletAddress, err := m.streamTickets.RedeemStreamTicket(c.Request.Context(), ticket)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Invalid or expired stream ticket",
+		})
+		return
+	}
+
+	c.Set(ContextKeyUserID, userID)
+	c.Set(ContextKeyWallet, walletAddress)
+	c.Next()
+}
+
 // GetUserID retrieves the user ID from the context
 func GetUserID(c *gin.Context) (string, bool) {
 	userID, exists := c.Get(ContextKeyUserID)
@@ -97,3 +170,40 @@ func GetWalletAddress(c *gin.Context) (string, bool) {
 	}
 	return wallet.(string), true
 }
+
+// checkNotRevoked aborts the request when its access token is on the denylist, and otherwise
+// stores the token ID for logout
+func checkNotRevoked(c *gin.Context, revocations TokenRevocationChecker, claims *jwt.RegisteredClaims) bool {
+	revoked, err := revocations.IsAccessTokenRevoked(c.Request.Context(), claims.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to verify token",
+		})
+		return false
+	}
+	if revoked {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Token has been revoked",
+		})
+		return false
+	}
+
+	c.Set(ContextKeyTokenID, claims.ID)
+	if claims.ExpiresAt != nil {
+		c.Set(ContextKeyTokenExpiresAt, claims.ExpiresAt.Time)
+	}
+	return true
+}
+
+// GetAccessToken retrieves the ID and expiry of the access token from the context
+func GetAccessToken(c *gin.Context) (string, time.Time, bool) {
+	tokenID, exists := c.Get(ContextKeyTokenID)
+	if !exists {
+		return "", time.Time{}, false
+	}
+	expiresAt, _ := c.Get(ContextKeyTokenExpiresAt)
+	exp, _ := expiresAt.(time.Time)
+	return tokenID.(string), exp, true
+}
@@ -6,28 +6,47 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
 	"github.com/ownafarm/ownafarm-backend/internal/utils"
 )
 
 const (
 	ContextKeyFarmerID     = "farmer_id"
 	ContextKeyFarmerWallet = "farmer_wallet_address"
+	ContextKeyFarmerScope  = "farmer_scope"
 )
 
 // FarmerAuthMiddleware provides authentication for approved farmers
 type FarmerAuthMiddleware struct {
-	jwtUtil *utils.FarmerJWTUtil
+	jwtUtil        *utils.FarmerJWTUtil
+	sessionJwtUtil *utils.SessionJWTUtil
+	farmerRepo     repositories.FarmerRepository
+	revocations    TokenRevocationChecker
 }
 
 // NewFarmerAuthMiddleware creates a new FarmerAuthMiddleware instance
-func NewFarmerAuthMiddleware(jwtUtil *utils.FarmerJWTUtil) *FarmerAuthMiddleware {
+func NewFarmerAuthMiddleware(jwtUtil *utils.FarmerJWTUtil, sessionJwtUtil *utils.SessionJWTUtil, farmerRepo repositories.FarmerRepository, revocations TokenRevocationChecker) *FarmerAuthMiddleware {
 	return &FarmerAuthMiddleware{
-		jwtUtil: jwtUtil,
+		jwtUtil:        jwtUtil,
+		sessionJwtUtil: sessionJwtUtil,
+		farmerRepo:     farmerRepo,
+		revocations:    revocations,
 	}
 }
 
-// FarmerAuthRequired ensures the request has a valid farmer JWT token
+// FarmerAuthRequired ensures the request has a valid full scope farmer JWT token
 func (m *FarmerAuthMiddleware) FarmerAuthRequired() gin.HandlerFunc {
+	return m.authenticate(true)
+}
+
+// FarmerApplicationAuthRequired accepts any farmer token, including the application scope
+// given to farmers that are not approved yet, for the registration/resubmission endpoints
+func (m *FarmerAuthMiddleware) FarmerApplicationAuthRequired() gin.HandlerFunc {
+	return m.authenticate(false)
+}
+
+func (m *FarmerAuthMiddleware) authenticate(requireFullScope bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader(AuthorizationHeader)
@@ -58,19 +77,77 @@ func (m *FarmerAuthMiddleware) FarmerAuthRequired() gin.HandlerFunc {
 			return
 		}
 
-		// Validate farmer token
-		claims, err := m.jwtUtil.ValidateToken(tokenString)
-		if err != nil {
-			if errors.Is(err, utils.ErrExpiredFarmerToken) {
+		// Session tokens holding the farmer role work like farmer tokens
+		session, ok := authenticateSession(c, m.sessionJwtUtil, m.revocations, tokenString, utils.RoleFarmer)
+		if !ok {
+			return
+		}
+
+		claims := &utils.FarmerClaims{}
+		if session != nil {
+			claims.FarmerID = session.FarmerID
+			claims.WalletAddress = session.WalletAddress
+			claims.Scope = session.FarmerScope
+		} else {
+			// Validate farmer token
+			var err error
+			claims, err = m.jwtUtil.ValidateToken(tokenString)
+			if err != nil {
+				if errors.Is(err, utils.ErrExpiredFarmerToken) {
+					c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+						"status":  "error",
+						"message": "Token has expired",
+					})
+					return
+				}
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 					"status":  "error",
-					"message": "Token has expired",
+					"message": "Invalid token",
 				})
 				return
 			}
+
+			if !checkNotRevoked(c, m.revocations, &claims.RegisteredClaims) {
+				return
+			}
+		}
+
+		// Application scope tokens only reach the registration endpoints
+		if requireFullScope && !claims.HasFullScope() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Farmer account is not approved",
+			})
+			return
+		}
+
+		// Tokens outlive status changes, so check the current status (e.g. suspension)
+		status, err := m.farmerRepo.GetStatusByID(claims.FarmerID)
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"status":  "error",
-				"message": "Invalid token",
+				"message": "Farmer account not found",
+			})
+			return
+		}
+		if status == models.FarmerStatusSuspended {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Farmer account is suspended",
+			})
+			return
+		}
+		if status == models.FarmerStatusErased {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Farmer account has been erased",
+			})
+			return
+		}
+		if requireFullScope && status != models.FarmerStatusApproved {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Farmer account is not approved",
 			})
 			return
 		}
@@ -78,6 +155,7 @@ func (m *FarmerAuthMiddleware) FarmerAuthRequired() gin.HandlerFunc {
 		// Set farmer info in context
 		c.Set(ContextKeyFarmerID, claims.FarmerID)
 		c.Set(ContextKeyFarmerWallet, claims.WalletAddress)
+		c.Set(ContextKeyFarmerScope, claims.Scope)
 
 		c.Next()
 	}
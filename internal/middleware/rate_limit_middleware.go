@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+)
+
+// Rate limit response headers (draft-ietf-httpapi-ratelimit-headers)
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+)
+
+// RateLimiter counts the requests of a key in a sliding window
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, policy config.RateLimitPolicy) (allowed bool, remaining int, reset time.Duration, err error)
+}
+
+// RateLimitKeyFunc returns the identifier a request is counted under
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP counts requests per client IP
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByWallet counts requests per authenticated wallet, or per IP without one
+func RateLimitByWallet(c *gin.Context) string {
+	if wallet, ok := GetWalletAddress(c); ok && wallet != "" {
+		return "wallet:" + wallet
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByAccount counts requests per authenticated admin, farmer or investor, or per IP
+// without one
+func RateLimitByAccount(c *gin.Context) string {
+	if actor := actorFromContext(c); actor.ID != "" {
+		return string(actor.Type) + ":" + actor.ID
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitMiddleware limits requests with the route policies of the config
+type RateLimitMiddleware struct {
+	limiter RateLimiter
+	cfg     *config.RateLimitConfig
+}
+
+// NewRateLimitMiddleware creates a new RateLimitMiddleware instance
+func NewRateLimitMiddleware(limiter RateLimiter, cfg *config.RateLimitConfig) *RateLimitMiddleware {
+	return &RateLimitMiddleware{limiter: limiter, cfg: cfg}
+}
+
+// Login limits nonce, login, refresh and logout requests per IP
+func (m *RateLimitMiddleware) Login() gin.HandlerFunc {
+	return m.Limit("login", m.cfg.Login, RateLimitByIP)
+}
+
+// Register limits farmer registrations per IP
+func (m *RateLimitMiddleware) Register() gin.HandlerFunc {
+	return m.Limit("register", m.cfg.Register, RateLimitByIP)
+}
+
+// Presign limits upload URL requests per account, or per IP on public routes
+func (m *RateLimitMiddleware) Presign() gin.HandlerFunc {
+	return m.Limit("presign", m.cfg.Presign, RateLimitByAccount)
+}
+
+// CropSync limits on-chain investment syncs per wallet
+func (m *RateLimitMiddleware) CropSync() gin.HandlerFunc {
+	return m.Limit("crop_sync", m.cfg.CropSync, RateLimitByWallet)
+}
+
+// Authenticated limits authenticated routes per account. Use it after the auth middleware.
+func (m *RateLimitMiddleware) Authenticated() gin.HandlerFunc {
+	return m.Limit("authenticated", m.cfg.Authenticated, RateLimitByAccount)
+}
+
+// Limit counts the requests of each key under the named policy and rejects them with 429 once
+// the policy limit is reached. Every response gets the RateLimit headers. Requests are allowed
+// when the limiter is unavailable, so a Valkey outage does not take the API down.
+func (m *RateLimitMiddleware) Limit(name string, policy config.RateLimitPolicy, key RateLimitKeyFunc) gin.HandlerFunc {
+	if !m.cfg.Enabled || policy.Limit == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	policyHeader := strconv.Itoa(policy.Limit) + ";w=" + strconv.FormatInt(int64(policy.Window.Seconds()), 10)
+	return func(c *gin.Context) {
+		allowed, remaining, reset, err := m.limiter.Allow(c.Request.Context(), name+":"+key(c), policy)
+		if err != nil {
+			log.Printf("[RateLimit] WARNING: %s policy not applied: %v", name, err)
+			c.Next()
+			return
+		}
+
+		// Seconds are rounded up so clients never retry too early
+		resetSeconds := strconv.FormatInt(int64(math.Ceil(reset.Seconds())), 10)
+		c.Header(RateLimitLimitHeader, strconv.Itoa(policy.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(remaining))
+		c.Header(RateLimitResetHeader, resetSeconds)
+		c.Header(RateLimitPolicyHeader, policyHeader)
+
+		if !allowed {
+			c.Header(RetryAfterHeader, resetSeconds)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"status":  "error",
+				"message": "Too many requests, please try again later",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
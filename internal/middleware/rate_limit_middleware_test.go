@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryLimiter counts requests per key in a fixed window that never resets
+type memoryLimiter struct {
+	counts map[string]int
+	keys   []string
+	err    error
+}
+
+func (m *memoryLimiter) Allow(ctx context.Context, key string, policy config.RateLimitPolicy) (bool, int, time.Duration, error) {
+	if m.err != nil {
+		return false, 0, 0, m.err
+	}
+	m.keys = append(m.keys, key)
+	if m.counts[key] >= policy.Limit {
+		return false, 0, 1500 * time.Millisecond, nil
+	}
+	m.counts[key]++
+	return true, policy.Limit - m.counts[key], policy.Window, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.RateLimitConfig{
+		Enabled:       true,
+		Login:         config.RateLimitPolicy{Limit: 2, Window: time.Minute},
+		Authenticated: config.RateLimitPolicy{Limit: 1, Window: time.Minute},
+	}
+	limiter := &memoryLimiter{counts: map[string]int{}}
+	rateLimit := NewRateLimitMiddleware(limiter, cfg)
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.POST("/login", rateLimit.Login(), ok)
+	router.GET("/me", func(c *gin.Context) { c.Set(ContextKeyUserID, c.Query("user")) }, rateLimit.Authenticated(), ok)
+
+	request := func(method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("limits per IP with headers", func(t *testing.T) {
+		w := request(http.MethodPost, "/login")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "60", w.Header().Get(RateLimitResetHeader))
+		assert.Equal(t, "2;w=60", w.Header().Get(RateLimitPolicyHeader))
+
+		assert.Equal(t, http.StatusOK, request(http.MethodPost, "/login").Code)
+
+		w = request(http.MethodPost, "/login")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "2", w.Header().Get(RetryAfterHeader))
+		assert.Equal(t, "login:ip:203.0.113.7", limiter.keys[len(limiter.keys)-1])
+	})
+
+	t.Run("limits per account", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/me?user=a").Code)
+		assert.Equal(t, http.StatusTooManyRequests, request(http.MethodGet, "/me?user=a").Code)
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/me?user=b").Code)
+		assert.Equal(t, "authenticated:investor:b", limiter.keys[len(limiter.keys)-1])
+	})
+
+	t.Run("allows requests when the limiter fails", func(t *testing.T) {
+		limiter.err = errors.New("valkey unavailable")
+		defer func() { limiter.err = nil }()
+
+		w := request(http.MethodPost, "/login")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+	})
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/utils"
+)
+
+const ContextKeySessionRoles = "session_roles"
+
+// SessionAuthMiddleware provides authentication for session tokens, which carry every role of a
+// wallet. The investor, farmer and admin middlewares also accept session tokens holding their role.
+type SessionAuthMiddleware struct {
+	jwtUtil     *utils.SessionJWTUtil
+	revocations TokenRevocationChecker
+}
+
+// NewSessionAuthMiddleware creates a new SessionAuthMiddleware instance
+func NewSessionAuthMiddleware(jwtUtil *utils.SessionJWTUtil, revocations TokenRevocationChecker) *SessionAuthMiddleware {
+	return &SessionAuthMiddleware{jwtUtil: jwtUtil, revocations: revocations}
+}
+
+// SessionAuthRequired ensures the request has a valid session token, whatever its roles
+func (m *SessionAuthMiddleware) SessionAuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get Authorization header
+		authHeader := c.GetHeader(AuthorizationHeader)
+		if authHeader == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Authorization header is required",
+			})
+			return
+		}
+
+		// Check Bearer prefix
+		if !strings.HasPrefix(authHeader, BearerPrefix) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid authorization header format",
+			})
+			return
+		}
+
+		// Extract token
+		tokenString := strings.TrimPrefix(authHeader, BearerPrefix)
+		if tokenString == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Token is required",
+			})
+			return
+		}
+
+		// Validate session token
+		claims, err := m.jwtUtil.ValidateToken(tokenString)
+		if err != nil {
+			if errors.Is(err, utils.ErrExpiredToken) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"status":  "error",
+					"message": "Token has expired",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid token",
+			})
+			return
+		}
+
+		if !checkNotRevoked(c, m.revocations, &claims.RegisteredClaims) {
+			return
+		}
+
+		// Set session info in context
+		c.Set(ContextKeyWallet, claims.WalletAddress)
+		c.Set(ContextKeySessionRoles, claims.Roles)
+
+		c.Next()
+	}
+}
+
+// authenticateSession lets a role middleware accept session tokens. It returns the claims of a
+// valid session token holding the role, nil when the token is not a session token so the
+// middleware validates its own token, and false when the request was aborted.
+func authenticateSession(c *gin.Context, jwtUtil *utils.SessionJWTUtil, revocations TokenRevocationChecker, tokenString, role string) (*utils.SessionClaims, bool) {
+	if jwtUtil == nil {
+		return nil, true
+	}
+
+	claims, err := jwtUtil.ValidateToken(tokenString)
+	if errors.Is(err, utils.ErrInvalidToken) {
+		return nil, true
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Token has expired",
+		})
+		return nil, false
+	}
+
+	if !checkNotRevoked(c, revocations, &claims.RegisteredClaims) {
+		return nil, false
+	}
+	if !claims.HasRole(role) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Token does not grant the " + role + " role",
+		})
+		return nil, false
+	}
+
+	c.Set(ContextKeySessionRoles, claims.Roles)
+	return claims, true
+}
+
+// GetSessionRoles retrieves the roles of the session token from the context
+func GetSessionRoles(c *gin.Context) ([]string, bool) {
+	roles, exists := c.Get(ContextKeySessionRoles)
+	if !exists {
+		return nil, false
+	}
+	return roles.([]string), true
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noRevocations reports every access token as valid
+type noRevocations struct{}
+
+func (noRevocations) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return false, nil
+}
+
+func testJWTConfig(t *testing.T) *config.JWTConfig {
+	t.Helper()
+	keys := func() config.JWTKeyConfig {
+		seed, err := utils.GenerateSigningKey()
+		require.NoError(t, err)
+		return config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:" + seed}}
+	}
+	return &config.JWTConfig{
+		Investor:              keys(),
+		Session:               keys(),
+		KeyGraceMinutes:       60,
+		AccessTokenTTLMinutes: 15,
+	}
+}
+
+func TestAuthRequired_SessionToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := testJWTConfig(t)
+	jwtUtil, err := utils.NewJWTUtil(cfg)
+	require.NoError(t, err)
+	sessionJwtUtil, err := utils.NewSessionJWTUtil(cfg)
+	require.NoError(t, err)
+
+	const walletAddress = "0x1234567890AbcdEF1234567890aBcdef12345678"
+	investorSession, err := sessionJwtUtil.GenerateToken(utils.SessionClaims{
+		WalletAddress: walletAddress,
+		Roles:         []string{utils.RoleInvestor, utils.RoleAdmin},
+		UserID:        "user-uuid-123",
+		AdminID:       "admin-uuid-456",
+	})
+	require.NoError(t, err)
+	farmerSession, err := sessionJwtUtil.GenerateToken(utils.SessionClaims{
+		WalletAddress: walletAddress,
+		Roles:         []string{utils.RoleFarmer},
+		FarmerID:      "farmer-uuid-789",
+		FarmerScope:   utils.FarmerScopeFull,
+	})
+	require.NoError(t, err)
+	investorToken, err := jwtUtil.GenerateToken("user-uuid-123", walletAddress)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/me", NewAuthMiddleware(jwtUtil, sessionJwtUtil, noRevocations{}, nil).AuthRequired(), func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		c.String(http.StatusOK, userID)
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantUserID string
+	}{
+		{name: "session with investor role", token: investorSession, wantStatus: http.StatusOK, wantUserID: "user-uuid-123"},
+		{name: "session without investor role", token: farmerSession, wantStatus: http.StatusForbidden},
+		{name: "investor token", token: investorToken, wantStatus: http.StatusOK, wantUserID: "user-uuid-123"},
+		{name: "invalid token", token: "not-a-token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set(AuthorizationHeader, BearerPrefix+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantUserID != "" {
+				assert.Equal(t, tt.wantUserID, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Achievement requirement type constants
+const (
+	AchievementRequirementLevel           = "level"
+	AchievementRequirementHarvestCount    = "harvest_count"
+	AchievementRequirementInvestmentTotal = "investment_total"
+)
+
+// Achievement represents the achievements table in the database
+type Achievement struct {
+	ID          string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Code        string          `gorm:"type:varchar(50);unique;not null" json:"code"`
+	Name        string          `gorm:"type:varchar(100);not null" json:"name"`
+	Description *string         `gorm:"type:text" json:"description,omitempty"`
+	Icon        *string         `gorm:"type:varchar(50)" json:"icon,omitempty"`
+	XPReward    int             `gorm:"column:xp_reward;default:0" json:"xp_reward"`
+	GoldReward  decimal.Decimal `gorm:"type:decimal(20,8);default:0" json:"gold_reward"`
+
+	// Requirements
+	RequirementType  *string `gorm:"type:varchar(50)" json:"requirement_type,omitempty"`
+	RequirementValue *int    `json:"requirement_value,omitempty"`
+
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName returns the table name for the Achievement model
+func (Achievement) TableName() string {
+	return "achievements"
+}
+
+// UserAchievement represents the user_achievements table in the database
+type UserAchievement struct {
+	ID            string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID        string    `gorm:"type:uuid;not null" json:"user_id"`
+	AchievementID string    `gorm:"type:uuid;not null" json:"achievement_id"`
+	UnlockedAt    time.Time `gorm:"default:now()" json:"unlocked_at"`
+}
+
+// TableName returns the table name for the UserAchievement model
+func (UserAchievement) TableName() string {
+	return "user_achievements"
+}
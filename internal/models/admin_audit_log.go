@@ -5,10 +5,13 @@ import (
 	"time"
 )
 
-// AdminAuditLog represents the admin_audit_logs table in the database
+// AdminAuditLog represents the admin_audit_logs table in the database. Besides admins it records
+// the actions of farmers, investors and the system; AdminID is only set for admin actors.
 type AdminAuditLog struct {
 	ID         string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	AdminID    string          `gorm:"type:uuid;not null" json:"admin_id"`
+	ActorType  string          `gorm:"type:varchar(20);not null;default:admin" json:"actor_type"`
+	ActorID    *string         `gorm:"type:uuid" json:"actor_id,omitempty"`
+	AdminID    *string         `gorm:"type:uuid" json:"admin_id,omitempty"`
 	Action     string          `gorm:"type:varchar(100);not null" json:"action"`
 	EntityType string          `gorm:"type:varchar(50);not null" json:"entity_type"`
 	EntityID   string          `gorm:"type:uuid;not null" json:"entity_id"`
@@ -17,6 +20,11 @@ type AdminAuditLog struct {
 	IPAddress  *string         `gorm:"type:inet" json:"ip_address,omitempty"`
 	UserAgent  *string         `gorm:"type:text" json:"user_agent,omitempty"`
 	CreatedAt  time.Time       `gorm:"default:now()" json:"created_at"`
+
+	// Hash chain, see internal/auditchain. Entries from before the chain have no hash until sealed.
+	Sequence int64   `gorm:"not null;uniqueIndex" json:"sequence"`
+	PrevHash *string `gorm:"type:varchar(64)" json:"prev_hash,omitempty"`
+	Hash     *string `gorm:"type:varchar(64)" json:"hash,omitempty"`
 }
 
 // TableName returns the table name for the AdminAuditLog model
@@ -26,14 +34,57 @@ func (AdminAuditLog) TableName() string {
 
 // Audit log action constants
 const (
-	AuditActionApproveFarmer  = "approve_farmer"
-	AuditActionRejectFarmer   = "reject_farmer"
-	AuditActionApproveInvoice = "approve_invoice"
-	AuditActionRejectInvoice  = "reject_invoice"
+	AuditActionApproveFarmer   = "approve_farmer"
+	AuditActionRejectFarmer    = "reject_farmer"
+	AuditActionClaimFarmer     = "claim_farmer"
+	AuditActionReleaseFarmer   = "release_farmer"
+	AuditActionSuspendFarmer   = "suspend_farmer"
+	AuditActionReinstateFarmer = "reinstate_farmer"
+	AuditActionRevealFarmerPII = "reveal_farmer_pii"
+	AuditActionExportFarmer    = "export_farmer_data"
+	AuditActionEraseFarmer     = "erase_farmer_data"
+	AuditActionExportUser      = "export_user_data"
+	AuditActionEraseUser       = "erase_user_data"
+	AuditActionApproveInvoice  = "approve_invoice"
+	AuditActionRejectInvoice   = "reject_invoice"
+
+	AuditActionApproveProfileChange = "approve_profile_change"
+	AuditActionRejectProfileChange  = "reject_profile_change"
+
+	AuditActionInviteAdmin     = "invite_admin"
+	AuditActionChangeAdminRole = "change_admin_role"
+	AuditActionDeactivateAdmin = "deactivate_admin"
+	AuditActionReactivateAdmin = "reactivate_admin"
+	AuditActionExportAuditLogs = "export_audit_logs"
+	AuditActionAdminLogin      = "admin_login"
+
+	AuditActionFarmerLogin          = "farmer_login"
+	AuditActionRegisterFarmer       = "register_farmer"
+	AuditActionResubmitFarmer       = "resubmit_farmer_application"
+	AuditActionUpdateProfile        = "update_farmer_profile"
+	AuditActionRequestProfileChange = "request_profile_change"
+	AuditActionCreateFarm           = "create_farm"
+	AuditActionUpdateFarm           = "update_farm"
+	AuditActionDeleteFarm           = "delete_farm"
+	AuditActionCreateInvoice        = "create_invoice"
+	AuditActionUpdateInvoice        = "update_invoice"
+	AuditActionWithdrawInvoice      = "withdraw_invoice"
+
+	AuditActionInvestorLogin = "investor_login"
+	AuditActionSessionLogin  = "session_login"
+
+	// AuditActionRequest is recorded for successful mutating requests no service recorded
+	AuditActionRequest = "request"
 )
 
 // Audit log entity type constants
 const (
 	AuditEntityTypeFarmer  = "farmer"
 	AuditEntityTypeInvoice = "invoice"
+	AuditEntityTypeUser    = "user"
+	AuditEntityTypeAdmin   = "admin_user"
+
+	AuditEntityTypeProfileChange = "farmer_profile_change"
+	AuditEntityTypeFarm          = "farm"
+	AuditEntityTypeRoute         = "route"
 )
@@ -8,14 +8,98 @@ import (
 type AdminUser struct {
 	ID            string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	WalletAddress string     `gorm:"type:varchar(42);uniqueIndex;not null" json:"wallet_address"`
-	Role          string     `gorm:"type:varchar(50);default:admin" json:"role"`
+	Role          string     `gorm:"type:varchar(50);not null;default:read_only" json:"role"`
 	IsActive      bool       `gorm:"default:true" json:"is_active"`
+	InvitedBy     *string    `gorm:"type:uuid" json:"invited_by,omitempty"`
 	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
-	CreatedAt     time.Time  `gorm:"default:now()" json:"created_at"`
-	UpdatedAt     time.Time  `gorm:"default:now()" json:"updated_at"`
+
+	// Deactivation
+	DeactivatedAt      *time.Time `json:"deactivated_at,omitempty"`
+	DeactivatedBy      *string    `gorm:"type:uuid" json:"deactivated_by,omitempty"`
+	DeactivationReason *string    `gorm:"type:text" json:"deactivation_reason,omitempty"`
+	TokensRevokedAt    *time.Time `json:"-"` // Tokens issued before this time are rejected
+
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
 }
 
 // TableName returns the table name for the AdminUser model
 func (AdminUser) TableName() string {
 	return "admin_users"
 }
+
+// Admin role constants
+const (
+	AdminRoleSuperAdmin = "super_admin"
+	AdminRoleReviewer   = "reviewer"
+	AdminRoleFinance    = "finance"
+	AdminRoleReadOnly   = "read_only"
+)
+
+// Permission is an action an admin role may perform
+type Permission string
+
+const (
+	PermissionFarmersRead          Permission = "farmers:read"
+	PermissionFarmersReview        Permission = "farmers:review"
+	PermissionFarmersSuspend       Permission = "farmers:suspend"
+	PermissionFarmersRevealPII     Permission = "farmers:reveal_pii"
+	PermissionProfileChangesReview Permission = "profile_changes:review"
+	PermissionInvoicesRead         Permission = "invoices:read"
+	PermissionInvoicesReview       Permission = "invoices:review"
+	PermissionDataSubjectExport    Permission = "data_subject:export"
+	PermissionDataSubjectErase     Permission = "data_subject:erase"
+	PermissionAdminsManage         Permission = "admins:manage"
+	PermissionAuditLogsRead        Permission = "audit_logs:read"
+)
+
+// adminRolePermissions lists the permissions granted to each role. super_admin is granted
+// everything and is not listed.
+var adminRolePermissions = map[string][]Permission{
+	AdminRoleReviewer: {
+		PermissionFarmersRead,
+		PermissionFarmersReview,
+		PermissionFarmersSuspend,
+		PermissionProfileChangesReview,
+		PermissionInvoicesRead,
+		PermissionInvoicesReview,
+	},
+	AdminRoleFinance: {
+		PermissionFarmersRead,
+		PermissionFarmersRevealPII,
+		PermissionProfileChangesReview,
+		PermissionInvoicesRead,
+		PermissionInvoicesReview,
+	},
+	AdminRoleReadOnly: {
+		PermissionFarmersRead,
+		PermissionInvoicesRead,
+		PermissionAuditLogsRead,
+	},
+}
+
+// IsValidAdminRole reports whether role is one of the known admin roles
+func IsValidAdminRole(role string) bool {
+	if role == AdminRoleSuperAdmin {
+		return true
+	}
+	_, ok := adminRolePermissions[role]
+	return ok
+}
+
+// AdminRoles lists the known admin roles
+var AdminRoles = []string{AdminRoleSuperAdmin, AdminRoleReviewer, AdminRoleFinance, AdminRoleReadOnly}
+
+// AdminRoleHasPermission reports whether an admin with the role may perform the action.
+// Unknown roles have no permissions.
+func AdminRoleHasPermission(role string, permission Permission) bool {
+	if role == AdminRoleSuperAdmin {
+		return true
+	}
+	for _, granted := range adminRolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AuditLogAnchor represents the audit_log_anchors table in the database. It records the Merkle
+// root of a range of the audit log hash chain and the transaction that published it on-chain.
+type AuditLogAnchor struct {
+	ID           string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FromSequence int64      `gorm:"not null" json:"from_sequence"`
+	ToSequence   int64      `gorm:"not null" json:"to_sequence"`
+	MerkleRoot   string     `gorm:"type:varchar(64);not null" json:"merkle_root"`
+	TxHash       *string    `gorm:"type:varchar(66)" json:"tx_hash,omitempty"`
+	AnchoredAt   *time.Time `json:"anchored_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName returns the table name for the AuditLogAnchor model
+func (AuditLogAnchor) TableName() string {
+	return "audit_log_anchors"
+}
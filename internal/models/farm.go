@@ -8,8 +8,8 @@ import (
 
 // Farm represents the farms table in the database
 type Farm struct {
-	ID        string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	FarmerID  string `gorm:"type:uuid;not null" json:"farmer_id"`
+	ID       string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FarmerID string `gorm:"type:uuid;not null" json:"farmer_id"`
 
 	// Basic Info
 	Name        string  `gorm:"type:varchar(200);not null" json:"name"`
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/lib/pq"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
+	"gorm.io/gorm"
 )
 
 // FarmerStatus represents the status of a farmer registration
@@ -15,8 +17,29 @@ const (
 	FarmerStatusApproved    FarmerStatus = "approved"
 	FarmerStatusRejected    FarmerStatus = "rejected"
 	FarmerStatusSuspended   FarmerStatus = "suspended"
+	FarmerStatusErased      FarmerStatus = "erased"
 )
 
+// farmerStatusTransitions lists the statuses a farmer can move to from each status.
+// Erasure is possible from every status and cannot be undone.
+var farmerStatusTransitions = map[FarmerStatus][]FarmerStatus{
+	FarmerStatusPending:     {FarmerStatusUnderReview, FarmerStatusApproved, FarmerStatusRejected, FarmerStatusErased},
+	FarmerStatusUnderReview: {FarmerStatusPending, FarmerStatusApproved, FarmerStatusRejected, FarmerStatusErased},
+	FarmerStatusApproved:    {FarmerStatusSuspended, FarmerStatusErased},
+	FarmerStatusSuspended:   {FarmerStatusApproved, FarmerStatusErased},
+	FarmerStatusRejected:    {FarmerStatusPending, FarmerStatusErased},
+}
+
+// CanTransitionTo reports whether a farmer in this status can move to the next status
+func (s FarmerStatus) CanTransitionTo(next FarmerStatus) bool {
+	for _, allowed := range farmerStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 // BusinessType represents the type of business
 type BusinessType string
 
@@ -39,8 +62,8 @@ type Farmer struct {
 	FullName    string    `gorm:"type:varchar(100);not null" json:"full_name"`
 	Email       string    `gorm:"type:varchar(255);not null" json:"email"`
 	PhoneNumber string    `gorm:"type:varchar(20);not null" json:"phone_number"`
-	IDNumber    string    `gorm:"type:varchar(20);not null" json:"id_number"`
-	DateOfBirth time.Time `gorm:"type:date;not null" json:"date_of_birth"`
+	IDNumber    string    `gorm:"type:text;not null;serializer:pii" json:"id_number"`
+	DateOfBirth time.Time `gorm:"type:text;not null;serializer:pii" json:"date_of_birth"`
 	Address     string    `gorm:"type:text;not null" json:"address"`
 	Province    string    `gorm:"type:varchar(100);not null" json:"province"`
 	City        string    `gorm:"type:varchar(100);not null" json:"city"`
@@ -50,18 +73,33 @@ type Farmer struct {
 	// Step 2: Business Info
 	BusinessName      *string        `gorm:"type:varchar(200)" json:"business_name,omitempty"`
 	BusinessType      BusinessType   `gorm:"type:business_type;not null" json:"business_type"`
-	NPWP              *string        `gorm:"type:varchar(30)" json:"npwp,omitempty"`
+	NPWP              *string        `gorm:"type:text;serializer:pii" json:"npwp,omitempty"`
 	BankName          string         `gorm:"type:varchar(100);not null" json:"bank_name"`
-	BankAccountNumber string         `gorm:"type:varchar(30);not null" json:"bank_account_number"`
+	BankAccountNumber string         `gorm:"type:text;not null;serializer:pii" json:"bank_account_number"`
 	BankAccountName   string         `gorm:"type:varchar(100);not null" json:"bank_account_name"`
 	YearsOfExperience int            `gorm:"default:0" json:"years_of_experience"`
 	CropsExpertise    pq.StringArray `gorm:"type:text[]" json:"crops_expertise"`
 
+	// Blind index of IDNumber for duplicate checks, maintained by BeforeSave
+	IDNumberIndex *string `gorm:"column:id_number_bidx;type:varchar(64)" json:"-"`
+
+	// Preferences
+	PreferredLanguage string `gorm:"type:varchar(5);not null;default:id" json:"preferred_language"`
+
 	// Admin
-	ReviewedBy      *string    `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedBy      *string    `gorm:"type:uuid" json:"reviewed_by,omitempty"` // Reviewer, also set while the application is claimed (under_review)
 	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
 	RejectionReason *string    `gorm:"type:text" json:"rejection_reason,omitempty"`
 
+	// Suspension
+	SuspendedBy      *string    `gorm:"type:uuid" json:"suspended_by,omitempty"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason *string    `gorm:"type:text" json:"suspension_reason,omitempty"`
+
+	// Erasure
+	ErasedBy *string    `gorm:"type:uuid" json:"erased_by,omitempty"`
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+
 	// Timestamps
 	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
 	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
@@ -70,7 +108,24 @@ type Farmer struct {
 	Documents []FarmerDocument `gorm:"foreignKey:FarmerID" json:"documents,omitempty"`
 }
 
+// FarmerPIIFields are the JSON names of the farmer fields encrypted at rest. They are also
+// encrypted when copied into JSON columns and masked in admin responses.
+var FarmerPIIFields = []string{"id_number", "date_of_birth", "npwp", "bank_account_number"}
+
 // TableName returns the table name for the Farmer model
 func (Farmer) TableName() string {
 	return "farmers"
 }
+
+// BeforeSave keeps the blind index in sync with the encrypted NIK
+func (f *Farmer) BeforeSave(tx *gorm.DB) error {
+	index, err := pii.BlindIndex("id_number", f.IDNumber)
+	if err != nil {
+		return err
+	}
+	f.IDNumberIndex = nil
+	if index != "" {
+		f.IDNumberIndex = &index
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// FarmerNotificationEvent represents a review outcome or invoice event the farmer is told about
+type FarmerNotificationEvent string
+
+const (
+	FarmerNotificationEventFarmerApproved  FarmerNotificationEvent = "farmer_approved"
+	FarmerNotificationEventFarmerRejected  FarmerNotificationEvent = "farmer_rejected"
+	FarmerNotificationEventInvoiceApproved FarmerNotificationEvent = "invoice_approved"
+	FarmerNotificationEventInvoiceRejected FarmerNotificationEvent = "invoice_rejected"
+
+	FarmerNotificationEventInvoiceFundingFailed FarmerNotificationEvent = "invoice_funding_failed"
+
+	FarmerNotificationEventProfileChangeApproved FarmerNotificationEvent = "profile_change_approved"
+	FarmerNotificationEventProfileChangeRejected FarmerNotificationEvent = "profile_change_rejected"
+)
+
+// FarmerNotificationChannel represents an outbound channel used to reach farmers
+type FarmerNotificationChannel string
+
+const (
+	FarmerNotificationChannelEmail    FarmerNotificationChannel = "email"
+	FarmerNotificationChannelSMS      FarmerNotificationChannel = "sms"
+	FarmerNotificationChannelWhatsApp FarmerNotificationChannel = "whatsapp"
+)
+
+// DeliveryStatus represents the state of a single notification delivery
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending DeliveryStatus = "pending"
+	DeliveryStatusSent    DeliveryStatus = "sent"
+	DeliveryStatusFailed  DeliveryStatus = "failed"
+)
+
+// Notification language constants
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+// FarmerNotificationDelivery represents the farmer_notification_deliveries table in the database
+type FarmerNotificationDelivery struct {
+	ID       string                    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FarmerID string                    `gorm:"type:uuid;not null" json:"farmer_id"`
+	Event    FarmerNotificationEvent   `gorm:"type:varchar(50);not null" json:"event"`
+	Channel  FarmerNotificationChannel `gorm:"type:varchar(20);not null" json:"channel"`
+
+	// Rendered message
+	Recipient string  `gorm:"type:varchar(255);not null" json:"recipient"`
+	Language  string  `gorm:"type:varchar(5);not null" json:"language"`
+	Subject   *string `gorm:"type:varchar(255)" json:"subject,omitempty"`
+	Body      string  `gorm:"type:text;not null" json:"body"`
+
+	// Delivery state
+	Status        DeliveryStatus `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	Attempts      int            `gorm:"not null;default:0" json:"attempts"`
+	LastError     *string        `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time      `gorm:"not null;default:now()" json:"next_attempt_at"`
+	SentAt        *time.Time     `json:"sent_at,omitempty"`
+
+	// Timestamps
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+// TableName returns the table name for the FarmerNotificationDelivery model
+func (FarmerNotificationDelivery) TableName() string {
+	return "farmer_notification_deliveries"
+}
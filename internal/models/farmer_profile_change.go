@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProfileChangeStatus represents the state of a farmer profile change
+type ProfileChangeStatus string
+
+const (
+	ProfileChangeStatusApplied  ProfileChangeStatus = "applied"
+	ProfileChangeStatusPending  ProfileChangeStatus = "pending"
+	ProfileChangeStatusApproved ProfileChangeStatus = "approved"
+	ProfileChangeStatusRejected ProfileChangeStatus = "rejected"
+)
+
+// FarmerProfileChange represents the farmer_profile_changes table in the database.
+// Every profile edit is recorded here; sensitive edits wait for admin review.
+type FarmerProfileChange struct {
+	ID             string              `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FarmerID       string              `gorm:"type:uuid;not null" json:"farmer_id"`
+	OldValues      json.RawMessage     `gorm:"type:jsonb;not null" json:"old_values"`
+	NewValues      json.RawMessage     `gorm:"type:jsonb;not null" json:"new_values"`
+	RequiresReview bool                `gorm:"not null;default:false" json:"requires_review"`
+	Status         ProfileChangeStatus `gorm:"type:varchar(20);not null" json:"status"`
+
+	// Admin review
+	ReviewedBy      *string    `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	RejectionReason *string    `gorm:"type:text" json:"rejection_reason,omitempty"`
+
+	// Timestamps
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+
+	// Relations
+	Farmer Farmer `gorm:"foreignKey:FarmerID" json:"farmer,omitempty"`
+}
+
+// TableName returns the table name for the FarmerProfileChange model
+func (FarmerProfileChange) TableName() string {
+	return "farmer_profile_changes"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SubmissionStatus represents the review decision of a farmer submission
+type SubmissionStatus string
+
+const (
+	SubmissionStatusPending  SubmissionStatus = "pending"
+	SubmissionStatusApproved SubmissionStatus = "approved"
+	SubmissionStatusRejected SubmissionStatus = "rejected"
+)
+
+// FarmerSubmission represents the farmer_submissions table in the database.
+// Each registration or resubmission is kept with the review decision it received.
+type FarmerSubmission struct {
+	ID               string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FarmerID         string          `gorm:"type:uuid;not null" json:"farmer_id"`
+	SubmissionNumber int             `gorm:"not null" json:"submission_number"`
+	Snapshot         json.RawMessage `gorm:"type:jsonb;not null" json:"snapshot"`
+
+	// Review decision
+	Status          SubmissionStatus `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	ReviewedBy      *string          `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time       `json:"reviewed_at,omitempty"`
+	RejectionReason *string          `gorm:"type:text" json:"rejection_reason,omitempty"`
+
+	SubmittedAt time.Time `gorm:"default:now()" json:"submitted_at"`
+}
+
+// TableName returns the table name for the FarmerSubmission model
+func (FarmerSubmission) TableName() string {
+	return "farmer_submissions"
+}
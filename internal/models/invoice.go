@@ -6,15 +6,63 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-// InvoiceStatus represents the status of an invoice
+// InvoiceStatus represents the lifecycle status of an invoice
 type InvoiceStatus string
 
 const (
 	InvoiceStatusPending  InvoiceStatus = "pending"
-	InvoiceStatusApproved InvoiceStatus = "approved"
 	InvoiceStatusRejected InvoiceStatus = "rejected"
+
+	// InvoiceStatusWithdrawn is set when the farmer withdraws a pending invoice before review
+	InvoiceStatusWithdrawn InvoiceStatus = "withdrawn"
+
+	// InvoiceStatusFunding is set on approval; the invoice is listed for investors
+	InvoiceStatusFunding InvoiceStatus = "funding"
+	// InvoiceStatusFunded is set once the funding target is reached
+	InvoiceStatusFunded InvoiceStatus = "funded"
+	// InvoiceStatusFundingFailed is set when the funding deadline passes before the target is reached
+	InvoiceStatusFundingFailed InvoiceStatus = "funding_failed"
+	// InvoiceStatusGrowing is set when the funding window closes and the crop cycle starts
+	InvoiceStatusGrowing InvoiceStatus = "growing"
+	// InvoiceStatusMatured is set on the maturity date, when the farmer is due to repay
+	InvoiceStatusMatured InvoiceStatus = "matured"
+	// InvoiceStatusCompleted is set when the contract marks the invoice repaid
+	InvoiceStatusCompleted InvoiceStatus = "completed"
+	// InvoiceStatusDefaulted is set when a matured invoice is not repaid within the grace period
+	InvoiceStatusDefaulted InvoiceStatus = "defaulted"
 )
 
+// invoiceStatusTransitions lists the statuses an invoice can move to from each status.
+// Rejected, withdrawn, funding failed and completed invoices are final.
+var invoiceStatusTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceStatusPending:   {InvoiceStatusFunding, InvoiceStatusRejected, InvoiceStatusWithdrawn},
+	InvoiceStatusFunding:   {InvoiceStatusFunded, InvoiceStatusFundingFailed},
+	InvoiceStatusFunded:    {InvoiceStatusGrowing, InvoiceStatusCompleted},
+	InvoiceStatusGrowing:   {InvoiceStatusMatured, InvoiceStatusCompleted},
+	InvoiceStatusMatured:   {InvoiceStatusCompleted, InvoiceStatusDefaulted},
+	InvoiceStatusDefaulted: {InvoiceStatusCompleted},
+}
+
+// CanTransitionTo reports whether an invoice in this status can move to the next status
+func (s InvoiceStatus) CanTransitionTo(next InvoiceStatus) bool {
+	for _, allowed := range invoiceStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveInvoiceStatuses are the statuses after approval that still move on their own,
+// driven by time and chain data
+var ActiveInvoiceStatuses = []InvoiceStatus{
+	InvoiceStatusFunding,
+	InvoiceStatusFunded,
+	InvoiceStatusGrowing,
+	InvoiceStatusMatured,
+	InvoiceStatusDefaulted,
+}
+
 // Invoice represents the invoices table in the database
 type Invoice struct {
 	ID     string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -46,6 +94,7 @@ type Invoice struct {
 
 	// Dates
 	ApprovedAt      *time.Time `json:"approved_at,omitempty"`
+	WithdrawnAt     *time.Time `json:"withdrawn_at,omitempty"`
 	FundingDeadline *time.Time `json:"funding_deadline,omitempty"`
 	MaturityDate    *time.Time `json:"maturity_date,omitempty"`
 
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// InvoiceStatusTransition represents the invoice_status_transitions table in the database.
+// Every status change of an invoice is logged with its cause.
+type InvoiceStatusTransition struct {
+	ID         string        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	InvoiceID  string        `gorm:"type:uuid;not null" json:"invoice_id"`
+	FromStatus InvoiceStatus `gorm:"type:invoice_status;not null" json:"from_status"`
+	ToStatus   InvoiceStatus `gorm:"type:invoice_status;not null" json:"to_status"`
+	Reason     string        `gorm:"type:text;not null" json:"reason"`
+	ActorType  string        `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *string       `gorm:"type:uuid" json:"actor_id,omitempty"`
+	CreatedAt  time.Time     `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName returns the table name for the InvoiceStatusTransition model
+func (InvoiceStatusTransition) TableName() string {
+	return "invoice_status_transitions"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// InvoiceVersion represents the invoice_versions table in the database.
+// The submitted invoice is version 1; every edit while pending adds a version with the
+// fields it changed, so the reviewer sees what changed since submission.
+type InvoiceVersion struct {
+	ID            string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	InvoiceID     string          `gorm:"type:uuid;not null" json:"invoice_id"`
+	VersionNumber int             `gorm:"not null" json:"version_number"`
+	Snapshot      json.RawMessage `gorm:"type:jsonb;not null" json:"snapshot"`
+	OldValues     json.RawMessage `gorm:"type:jsonb;not null;default:'{}'" json:"old_values"`
+	NewValues     json.RawMessage `gorm:"type:jsonb;not null;default:'{}'" json:"new_values"`
+	EditedBy      *string         `gorm:"type:uuid" json:"edited_by,omitempty"`
+	CreatedAt     time.Time       `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName returns the table name for the InvoiceVersion model
+func (InvoiceVersion) TableName() string {
+	return "invoice_versions"
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NotificationType represents the event that produced an investor notification
+type NotificationType string
+
+const (
+	NotificationTypeCropReady           NotificationType = "crop_ready"
+	NotificationTypeHarvestSynced       NotificationType = "harvest_synced"
+	NotificationTypeLevelUp             NotificationType = "level_up"
+	NotificationTypeAchievementUnlocked NotificationType = "achievement_unlocked"
+	NotificationTypeInvoiceFullyFunded  NotificationType = "invoice_fully_funded"
+)
+
+// Notification represents the notifications table in the database
+type Notification struct {
+	ID     string           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID string           `gorm:"type:uuid;not null" json:"user_id"`
+	Type   NotificationType `gorm:"type:varchar(50);not null" json:"type"`
+	Title  string           `gorm:"type:varchar(255);not null" json:"title"`
+	Body   string           `gorm:"type:text;not null" json:"body"`
+	Data   json.RawMessage  `gorm:"type:jsonb" json:"data,omitempty"`
+
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName returns the table name for the Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
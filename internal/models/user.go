@@ -18,6 +18,10 @@ type User struct {
 	WaterPoints int        `gorm:"default:100" json:"water_points"`
 	LastRegenAt *time.Time `gorm:"default:now()" json:"last_regen_at,omitempty"`
 
+	// Erasure (personal data anonymized, investments kept)
+	ErasedBy *string    `gorm:"type:uuid" json:"erased_by,omitempty"`
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+
 	// Timestamps
 	LastLoginAt *time.Time `gorm:"" json:"last_login_at,omitempty"`
 	CreatedAt   time.Time  `gorm:"default:now()" json:"created_at"`
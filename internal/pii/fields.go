@@ -0,0 +1,76 @@
+package pii
+
+import (
+	"fmt"
+	"strings"
+)
+
+// visibleSuffix is the number of trailing characters left readable by Mask
+const visibleSuffix = 4
+
+// Mask hides all but the last four characters of a value
+func Mask(value string) string {
+	runes := []rune(value)
+	if len(runes) <= visibleSuffix {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-visibleSuffix) + string(runes[len(runes)-visibleSuffix:])
+}
+
+// MaskDate keeps only the year of a YYYY-MM-DD date
+func MaskDate(value string) string {
+	if len(value) < 4 {
+		return Mask(value)
+	}
+	return value[:4] + "-**-**"
+}
+
+// SealFields encrypts the non-empty string values of the given fields in a decoded JSON object,
+// for PII that is copied into JSON columns such as snapshots and change records
+func SealFields(values map[string]interface{}, fields ...string) error {
+	for _, field := range fields {
+		value, ok := values[field].(string)
+		if !ok || value == "" || IsEncrypted(value) {
+			continue
+		}
+		encrypted, err := Encrypt(value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", field, err)
+		}
+		values[field] = encrypted
+	}
+	return nil
+}
+
+// OpenFields decrypts the values encrypted by SealFields
+func OpenFields(values map[string]interface{}, fields ...string) error {
+	for _, field := range fields {
+		value, ok := values[field].(string)
+		if !ok || !IsEncrypted(value) {
+			continue
+		}
+		plaintext, err := Decrypt(value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", field, err)
+		}
+		values[field] = plaintext
+	}
+	return nil
+}
+
+// MaskFields replaces the values of the given fields with their masked form. Encrypted values
+// that cannot be decrypted are fully masked.
+func MaskFields(values map[string]interface{}, fields ...string) {
+	for _, field := range fields {
+		value, ok := values[field].(string)
+		if !ok || value == "" {
+			continue
+		}
+		plaintext, err := Decrypt(value)
+		if err != nil {
+			values[field] = "********"
+			continue
+		}
+		values[field] = Mask(plaintext)
+	}
+}
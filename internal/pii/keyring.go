@@ -0,0 +1,256 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+)
+
+// prefix marks an encrypted value: enc:v1:<key id>:<wrapped data key>:<ciphertext>
+const prefix = "enc:v1:"
+
+const keySize = 32
+
+var (
+	ErrKeyringNotConfigured = errors.New("pii: keyring is not configured")
+	ErrUnknownKey           = errors.New("pii: value was encrypted with an unknown key")
+	ErrMalformedValue       = errors.New("pii: malformed encrypted value")
+)
+
+var encoding = base64.RawStdEncoding
+
+// Keyring encrypts values with envelope encryption: every value gets a random AES-256-GCM data
+// key, which is stored next to the ciphertext wrapped by a key encryption key from config. Old
+// key encryption keys stay available for decryption until all values are rotated.
+type Keyring struct {
+	activeID string
+	keks     map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// NewKeyring builds a keyring from config. Keys are listed as id:base64 pairs of 32 bytes.
+func NewKeyring(cfg *config.PIIConfig) (*Keyring, error) {
+	if cfg.ActiveKeyID == "" {
+		return nil, errors.New("pii: active key id is required")
+	}
+
+	keks := make(map[string]cipher.AEAD, len(cfg.Keys))
+	for _, entry := range cfg.Keys {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("pii: key entry %q must be id:base64", id)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("pii: key %s: %w", id, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		keks[id] = aead
+	}
+	if _, ok := keks[cfg.ActiveKeyID]; !ok {
+		return nil, fmt.Errorf("pii: active key %s is not listed in the keys", cfg.ActiveKeyID)
+	}
+
+	indexKey, err := decodeKey(cfg.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("pii: blind index key: %w", err)
+	}
+
+	return &Keyring{activeID: cfg.ActiveKeyID, keks: keks, indexKey: indexKey}, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.New("must be base64")
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("must be %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("pii: failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce, returning nonce || ciphertext
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("pii: failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open reverses seal
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformedValue
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// ActiveKeyID returns the id of the key used for new values
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt encrypts a value with a fresh data key wrapped by the active key
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("pii: failed to generate data key: %w", err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	// The key id is bound to the wrapped key so it cannot be swapped for another
+	wrappedKey, err := seal(k.keks[k.activeID], dataKey, []byte(k.activeID))
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataAEAD, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.activeID + ":" + encoding.EncodeToString(wrappedKey) + ":" + encoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt. Values without the encryption prefix are
+// returned unchanged, so rows written before encryption was enabled stay readable.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformedValue
+	}
+	keyID := parts[0]
+	kek, ok := k.keks[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	wrappedKey, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+	ciphertext, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformedValue
+	}
+
+	dataKey, err := open(kek, wrappedKey, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("pii: failed to unwrap data key: %w", err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("pii: failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// IsCurrent reports whether a value is encrypted with the active key. Empty values need no
+// encryption and are always current.
+func (k *Keyring) IsCurrent(value string) bool {
+	return value == "" || strings.HasPrefix(value, prefix+k.activeID+":")
+}
+
+// BlindIndex returns a keyed hash of a value for equality lookups on encrypted columns. The field
+// name is part of the hash so equal values in different columns do not match. Separators and
+// case are ignored; an empty value has no index.
+func (k *Keyring) BlindIndex(field, value string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, value)
+	if normalized == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(field + ":" + normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether a stored value carries the encryption prefix
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault sets the keyring used by the GORM serializer and the package level helpers
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default returns the keyring set with SetDefault
+func Default() (*Keyring, error) {
+	k := defaultKeyring.Load()
+	if k == nil {
+		return nil, ErrKeyringNotConfigured
+	}
+	return k, nil
+}
+
+// Encrypt encrypts a value with the default keyring
+func Encrypt(plaintext string) (string, error) {
+	k, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return k.Encrypt(plaintext)
+}
+
+// Decrypt decrypts a value with the default keyring
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	k, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return k.Decrypt(value)
+}
+
+// BlindIndex computes a blind index with the default keyring
+func BlindIndex(field, value string) (string, error) {
+	k, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return k.BlindIndex(field, value), nil
+}
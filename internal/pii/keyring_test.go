@@ -0,0 +1,92 @@
+package pii
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune(b)), keySize)))
+}
+
+func newTestKeyring(t *testing.T, activeID string, keys ...string) *Keyring {
+	t.Helper()
+	k, err := NewKeyring(&config.PIIConfig{ActiveKeyID: activeID, Keys: keys, BlindIndexKey: testKey('i')})
+	require.NoError(t, err)
+	return k
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k := newTestKeyring(t, "1", "1:"+testKey('a'))
+
+	encrypted, err := k.Encrypt("3201011506850001")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:v1:1:"))
+	assert.NotContains(t, encrypted, "3201011506850001")
+
+	again, err := k.Encrypt("3201011506850001")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "every value gets its own data key and nonce")
+
+	decrypted, err := k.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "3201011506850001", decrypted)
+
+	// Rows written before encryption are returned as they are
+	legacy, err := k.Decrypt("1234567890")
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", legacy)
+
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+	_, err = k.Decrypt(tampered)
+	assert.Error(t, err)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldKeyring := newTestKeyring(t, "1", "1:"+testKey('a'))
+	encrypted, err := oldKeyring.Encrypt("1234567890")
+	require.NoError(t, err)
+
+	rotated := newTestKeyring(t, "2", "1:"+testKey('a'), "2:"+testKey('b'))
+	assert.False(t, rotated.IsCurrent(encrypted))
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", decrypted)
+
+	reencrypted, err := rotated.Encrypt(decrypted)
+	require.NoError(t, err)
+	assert.True(t, rotated.IsCurrent(reencrypted))
+
+	// Once the old key is dropped its values can no longer be read
+	withoutOld := newTestKeyring(t, "2", "2:"+testKey('b'))
+	_, err = withoutOld.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewKeyring_RejectsInvalidConfig(t *testing.T) {
+	_, err := NewKeyring(&config.PIIConfig{ActiveKeyID: "2", Keys: []string{"1:" + testKey('a')}, BlindIndexKey: testKey('i')})
+	assert.Error(t, err)
+	_, err = NewKeyring(&config.PIIConfig{ActiveKeyID: "1", Keys: []string{"1:c2hvcnQ="}, BlindIndexKey: testKey('i')})
+	assert.Error(t, err)
+	_, err = NewKeyring(&config.PIIConfig{ActiveKeyID: "1", Keys: []string{"1:" + testKey('a')}})
+	assert.Error(t, err)
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	k := newTestKeyring(t, "1", "1:"+testKey('a'))
+
+	assert.Equal(t, k.BlindIndex("npwp", "12.345.678.9-123.000"), k.BlindIndex("npwp", "123456789123000"))
+	assert.NotEqual(t, k.BlindIndex("npwp", "123456789123000"), k.BlindIndex("id_number", "123456789123000"))
+	assert.Empty(t, k.BlindIndex("npwp", ""))
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "************0001", Mask("3201011506850001"))
+	assert.Equal(t, "***", Mask("123"))
+	assert.Equal(t, "1985-**-**", MaskDate("1985-06-15"))
+}
@@ -0,0 +1,100 @@
+package pii
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// dateLayout is how time.Time fields are stored before encryption
+const dateLayout = "2006-01-02"
+
+func init() {
+	schema.RegisterSerializer("pii", Serializer{})
+}
+
+// Serializer encrypts string, *string and date (time.Time) fields with the default keyring.
+// Use it with `gorm:"serializer:pii"` on text columns.
+type Serializer struct{}
+
+// Scan implements schema.SerializerInterface
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType).Elem()
+
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+		field.ReflectValueOf(ctx, dst).Set(fieldValue)
+		return nil
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	case time.Time:
+		// Rows still in a date column before the column was converted
+		stored = v.Format(dateLayout)
+	default:
+		return fmt.Errorf("pii: unsupported database value %T for %s", dbValue, field.Name)
+	}
+
+	plaintext, err := Decrypt(stored)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+	}
+	if err := setString(fieldValue, plaintext); err != nil {
+		return fmt.Errorf("failed to scan %s: %w", field.Name, err)
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var plaintext string
+	switch v := fieldValue.(type) {
+	case string:
+		plaintext = v
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		plaintext = *v
+	case time.Time:
+		if !v.IsZero() {
+			plaintext = v.Format(dateLayout)
+		}
+	default:
+		return nil, fmt.Errorf("pii: unsupported field type %T for %s", fieldValue, field.Name)
+	}
+
+	if plaintext == "" {
+		return "", nil
+	}
+	return Encrypt(plaintext)
+}
+
+// setString assigns a decrypted value to a string, *string or time.Time
+func setString(v reflect.Value, plaintext string) error {
+	switch v.Interface().(type) {
+	case string:
+		v.SetString(plaintext)
+	case *string:
+		v.Set(reflect.ValueOf(&plaintext))
+	case time.Time:
+		if plaintext == "" {
+			return nil
+		}
+		t, err := time.Parse(dateLayout, plaintext)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
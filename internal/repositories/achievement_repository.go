@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AchievementRepository defines the interface for achievement data access
+type AchievementRepository interface {
+	UnlockEligible(userID string) ([]models.Achievement, error)
+}
+
+type achievementRepository struct {
+	db *gorm.DB
+}
+
+// NewAchievementRepository creates a new AchievementRepository instance
+func NewAchievementRepository(db *gorm.DB) AchievementRepository {
+	return &achievementRepository{db: db}
+}
+
+// UnlockEligible unlocks every achievement whose requirement the user currently meets
+// and returns only the achievements that were newly unlocked by this call.
+// Achievement rewards (xp_reward, gold_reward) are not granted here.
+func (r *achievementRepository) UnlockEligible(userID string) ([]models.Achievement, error) {
+	var candidates []models.Achievement
+	if err := r.db.
+		Where("requirement_type IS NOT NULL AND requirement_value IS NOT NULL").
+		Where("id NOT IN (SELECT achievement_id FROM user_achievements WHERE user_id = ?)", userID).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// Gather the user's current progress
+	var user models.User
+	if err := r.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	var harvestCount int64
+	if err := r.db.Model(&models.Investment{}).
+		Where("user_id = ? AND is_harvested = ?", userID, true).
+		Count(&harvestCount).Error; err != nil {
+		return nil, err
+	}
+
+	var investmentTotal decimal.Decimal
+	if err := r.db.Model(&models.Investment{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&investmentTotal).Error; err != nil {
+		return nil, err
+	}
+
+	var unlocked []models.Achievement
+	for _, achievement := range candidates {
+		required := int64(*achievement.RequirementValue)
+
+		met := false
+		switch *achievement.RequirementType {
+		case models.AchievementRequirementLevel:
+			met = int64(user.Level) >= required
+		case models.AchievementRequirementHarvestCount:
+			met = harvestCount >= required
+		case models.AchievementRequirementInvestmentTotal:
+			met = investmentTotal.GreaterThanOrEqual(decimal.NewFromInt(required))
+		}
+		if !met {
+			continue
+		}
+
+		// The unique (user_id, achievement_id) index guarantees a single unlock under concurrency
+		result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.UserAchievement{
+			UserID:        userID,
+			AchievementID: achievement.ID,
+		})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+
+	return unlocked, nil
+}
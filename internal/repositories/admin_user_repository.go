@@ -8,8 +8,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// AdminUserFilter contains filter options for listing admin users
+type AdminUserFilter struct {
+	Role     string // Filter by role
+	IsActive *bool  // Only active (true) or only deactivated (false) admins
+	Page     int    // Current page (1-indexed)
+	Limit    int    // Items per page
+}
+
 type AdminUserRepository interface {
+	GetByID(ctx context.Context, id string) (*models.AdminUser, error)
 	GetByWalletAddress(ctx context.Context, walletAddress string) (*models.AdminUser, error)
+	GetAll(ctx context.Context, filter AdminUserFilter) ([]models.AdminUser, int64, error)
+	CountActiveByRole(ctx context.Context, role string) (int64, error)
+	Create(ctx context.Context, admin *models.AdminUser) error
+	Update(ctx context.Context, admin *models.AdminUser) error
 	UpdateLastLogin(ctx context.Context, adminID string) error
 }
 
@@ -21,6 +34,15 @@ func NewAdminUserRepository(db *gorm.DB) AdminUserRepository {
 	return &adminUserRepository{db: db}
 }
 
+// GetByID retrieves an admin user by ID
+func (r *adminUserRepository) GetByID(ctx context.Context, id string) (*models.AdminUser, error) {
+	var admin models.AdminUser
+	if err := r.db.WithContext(ctx).First(&admin, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &admin, nil
+}
+
 // GetByWalletAddress retrieves an admin user by their wallet address
 // Uses case-insensitive comparison since wallet addresses may be stored in different formats
 func (r *adminUserRepository) GetByWalletAddress(ctx context.Context, walletAddress string) (*models.AdminUser, error) {
@@ -40,3 +62,65 @@ func (r *adminUserRepository) UpdateLastLogin(ctx context.Context, adminID strin
 		Where("id = ?", adminID).
 		Update("last_login_at", &now).Error
 }
+
+// GetAll retrieves admin users, oldest first, with pagination
+func (r *adminUserRepository) GetAll(ctx context.Context, filter AdminUserFilter) ([]models.AdminUser, int64, error) {
+	var admins []models.AdminUser
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&models.AdminUser{})
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+
+	// Get total count before pagination
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	if err := query.
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(filter.Limit).
+		Find(&admins).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return admins, totalCount, nil
+}
+
+// CountActiveByRole counts the active admins with a role
+func (r *adminUserRepository) CountActiveByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.AdminUser{}).
+		Where("role = ? AND is_active = ?", role, true).
+		Count(&count).Error
+	return count, err
+}
+
+// Create creates a new admin user record
+func (r *adminUserRepository) Create(ctx context.Context, admin *models.AdminUser) error {
+	return r.db.WithContext(ctx).Create(admin).Error
+}
+
+// Update saves an admin user record
+func (r *adminUserRepository) Update(ctx context.Context, admin *models.AdminUser) error {
+	admin.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(admin).Error
+}
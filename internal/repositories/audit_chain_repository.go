@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ownafarm/ownafarm-backend/internal/auditchain"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditChainRepository defines the interface for verifying and anchoring the audit log hash chain
+type AuditChainRepository interface {
+	ListBySequence(afterSequence int64, limit int) ([]models.AdminAuditLog, error)
+	SealPending() (int, error)
+	GetAnchors() ([]models.AuditLogAnchor, error)
+	CreateAnchor(anchor *models.AuditLogAnchor) error
+	UpdateAnchor(anchor *models.AuditLogAnchor) error
+}
+
+type auditChainRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditChainRepository creates a new AuditChainRepository instance
+func NewAuditChainRepository(db *gorm.DB) AuditChainRepository {
+	return &auditChainRepository{db: db}
+}
+
+// ListBySequence retrieves chain entries in sequence order, starting after a sequence
+func (r *auditChainRepository) ListBySequence(afterSequence int64, limit int) ([]models.AdminAuditLog, error) {
+	var logs []models.AdminAuditLog
+	err := r.db.Where("sequence > ?", afterSequence).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// SealPending hashes the entries written before the chain existed
+func (r *auditChainRepository) SealPending() (int, error) {
+	var sealed int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+			return fmt.Errorf("failed to lock audit log chain: %w", err)
+		}
+		var err error
+		sealed, err = sealAuditLogs(tx)
+		return err
+	})
+	return sealed, err
+}
+
+// GetAnchors retrieves all anchors, oldest first
+func (r *auditChainRepository) GetAnchors() ([]models.AuditLogAnchor, error) {
+	var anchors []models.AuditLogAnchor
+	if err := r.db.Order("to_sequence ASC").Find(&anchors).Error; err != nil {
+		return nil, err
+	}
+	return anchors, nil
+}
+
+// CreateAnchor creates a new anchor record
+func (r *auditChainRepository) CreateAnchor(anchor *models.AuditLogAnchor) error {
+	return r.db.Create(anchor).Error
+}
+
+// UpdateAnchor updates an anchor record
+func (r *auditChainRepository) UpdateAnchor(anchor *models.AuditLogAnchor) error {
+	return r.db.Save(anchor).Error
+}
+
+// sealAuditLogs links the unsealed entries to the chain in sequence order. The caller holds the
+// chain lock. Unsealed entries are only expected before the first sealed one.
+func sealAuditLogs(tx *gorm.DB) (int, error) {
+	prevHash := auditchain.GenesisHash
+	var sealed int
+	for {
+		var logs []models.AdminAuditLog
+		if err := tx.Where("hash IS NULL").Order("sequence ASC").Limit(500).Find(&logs).Error; err != nil {
+			return sealed, err
+		}
+		if len(logs) == 0 {
+			return sealed, nil
+		}
+
+		if sealed == 0 {
+			var previous models.AdminAuditLog
+			err := tx.Where("sequence < ?", logs[0].Sequence).Order("sequence DESC").Take(&previous).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return sealed, err
+			}
+			if err == nil && previous.Hash != nil {
+				prevHash = *previous.Hash
+			}
+		}
+
+		for i := range logs {
+			hash, err := auditchain.Hash(&logs[i], prevHash)
+			if err != nil {
+				return sealed, fmt.Errorf("failed to hash audit log %s: %w", logs[i].ID, err)
+			}
+			err = tx.Model(&logs[i]).UpdateColumns(map[string]interface{}{
+				"prev_hash": prevHash,
+				"hash":      hash,
+			}).Error
+			if err != nil {
+				return sealed, err
+			}
+			prevHash = hash
+			sealed++
+		}
+	}
+}
@@ -1,13 +1,44 @@
 package repositories
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ownafarm/ownafarm-backend/internal/auditchain"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
 	"gorm.io/gorm"
 )
 
+// auditChainLockKey is the Postgres advisory lock that serializes writes to the audit log chain
+const auditChainLockKey = 7_302_114_001
+
+// AuditLogCursor is the position after the last entry of a page
+type AuditLogCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// AuditLogFilter contains filter options for querying audit logs
+type AuditLogFilter struct {
+	ActorType  string
+	ActorID    string
+	AdminID    string
+	Actions    []string
+	EntityType string
+	EntityID   string
+	From       *time.Time // Inclusive
+	To         *time.Time // Exclusive
+	Ascending  bool       // Oldest first (timelines), newest first otherwise
+	After      *AuditLogCursor
+	Limit      int
+}
+
 // AuditLogRepository defines the interface for audit log data access
 type AuditLogRepository interface {
 	Create(log *models.AdminAuditLog) error
+	Query(filter AuditLogFilter) ([]models.AdminAuditLog, error)
 }
 
 type auditLogRepository struct {
@@ -19,7 +50,101 @@ func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
 	return &auditLogRepository{db: db}
 }
 
-// Create creates a new audit log record
+// Create appends an audit log record to the hash chain. Writers take an advisory lock so every
+// entry links to the one written right before it.
 func (r *auditLogRepository) Create(log *models.AdminAuditLog) error {
-	return r.db.Create(log).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+			return fmt.Errorf("failed to lock audit log chain: %w", err)
+		}
+
+		var last models.AdminAuditLog
+		prevHash := auditchain.GenesisHash
+		err := tx.Order("sequence DESC").Take(&last).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+		case err != nil:
+			return err
+		case last.Hash == nil:
+			// Entries written before the chain existed are sealed by the first write
+			if _, err := sealAuditLogs(tx); err != nil {
+				return err
+			}
+			if err := tx.Order("sequence DESC").Take(&last).Error; err != nil {
+				return err
+			}
+			prevHash = *last.Hash
+		default:
+			prevHash = *last.Hash
+		}
+
+		// The hash covers every column, so they are all set here instead of by database defaults
+		if log.ID == "" {
+			log.ID = uuid.NewString()
+		}
+		log.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		log.Sequence = last.Sequence + 1
+		hash, err := auditchain.Hash(log, prevHash)
+		if err != nil {
+			return fmt.Errorf("failed to hash audit log: %w", err)
+		}
+		log.PrevHash = &prevHash
+		log.Hash = &hash
+
+		return tx.Create(log).Error
+	})
+}
+
+// Query retrieves audit logs with keyset pagination on (created_at, id), so pages stay stable
+// while new entries are written
+func (r *auditLogRepository) Query(filter AuditLogFilter) ([]models.AdminAuditLog, error) {
+	var logs []models.AdminAuditLog
+
+	query := r.db.Model(&models.AdminAuditLog{})
+	if filter.ActorType != "" {
+		query = query.Where("actor_type = ?", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.AdminID != "" {
+		query = query.Where("admin_id = ?", filter.AdminID)
+	}
+	if len(filter.Actions) > 0 {
+		query = query.Where("action IN ?", filter.Actions)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	order := "created_at DESC, id DESC"
+	if filter.Ascending {
+		order = "created_at ASC, id ASC"
+	}
+	if filter.After != nil {
+		// created_at is a timestamp without time zone, compare it without a zone conversion
+		position := filter.After.CreatedAt.Format("2006-01-02 15:04:05.999999")
+		if filter.Ascending {
+			query = query.Where("(created_at, id) > (?::timestamp, ?::uuid)", position, filter.After.ID)
+		} else {
+			query = query.Where("(created_at, id) < (?::timestamp, ?::uuid)", position, filter.After.ID)
+		}
+	}
+
+	if filter.Limit < 1 {
+		filter.Limit = 50
+	}
+	if err := query.Order(order).Limit(filter.Limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
 }
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErasedPlaceholder replaces free text personal data that has to stay NOT NULL after erasure
+const ErasedPlaceholder = "[erased]"
+
+// userActivityTables are the game tables without a model that reference users(id)
+var userActivityTables = []string{"daily_rewards", "gold_transactions", "xp_logs", "water_logs", "leaderboard_snapshots"}
+
+// FarmerDataExport contains every record tied to a farmer
+type FarmerDataExport struct {
+	Farmer         *models.Farmer
+	Farms          []models.Farm
+	Invoices       []models.Invoice
+	Submissions    []models.FarmerSubmission
+	ProfileChanges []models.FarmerProfileChange
+	Notifications  []models.FarmerNotificationDelivery
+	AuditLogs      []models.AdminAuditLog
+}
+
+// UserDataExport contains every record tied to an investor
+type UserDataExport struct {
+	User          *models.User
+	Investments   []models.Investment
+	Notifications []models.Notification
+	Achievements  []models.UserAchievement
+	Activity      map[string][]map[string]interface{} // Rows of the game tables keyed by table name
+	AuditLogs     []models.AdminAuditLog
+}
+
+// DataSubjectRepository defines the data access for data subject requests (export and erasure)
+type DataSubjectRepository interface {
+	GetFarmerData(farmerID string) (*FarmerDataExport, error)
+	GetUserData(userID string) (*UserDataExport, error)
+	HasActiveInvestments(userID string) (bool, error)
+	EraseFarmer(farmer *models.Farmer) error
+	EraseUser(user *models.User) error
+}
+
+type dataSubjectRepository struct {
+	db *gorm.DB
+}
+
+// NewDataSubjectRepository creates a new DataSubjectRepository instance
+func NewDataSubjectRepository(db *gorm.DB) DataSubjectRepository {
+	return &dataSubjectRepository{db: db}
+}
+
+// GetFarmerData loads the farmer with documents and all related records
+func (r *dataSubjectRepository) GetFarmerData(farmerID string) (*FarmerDataExport, error) {
+	var farmer models.Farmer
+	if err := r.db.Preload("Documents").First(&farmer, "id = ?", farmerID).Error; err != nil {
+		return nil, err
+	}
+	data := &FarmerDataExport{Farmer: &farmer}
+
+	if err := r.db.Where("farmer_id = ?", farmerID).Order("created_at").Find(&data.Farms).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.
+		Where("farm_id IN (?)", r.db.Model(&models.Farm{}).Select("id").Where("farmer_id = ?", farmerID)).
+		Order("created_at").
+		Find(&data.Invoices).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("farmer_id = ?", farmerID).Order("submission_number").Find(&data.Submissions).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("farmer_id = ?", farmerID).Order("created_at").Find(&data.ProfileChanges).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("farmer_id = ?", farmerID).Order("created_at").Find(&data.Notifications).Error; err != nil {
+		return nil, err
+	}
+
+	// Audit entries about the farmer, its invoices and its profile changes
+	entityIDs := []string{farmerID}
+	for _, invoice := range data.Invoices {
+		entityIDs = append(entityIDs, invoice.ID)
+	}
+	for _, change := range data.ProfileChanges {
+		entityIDs = append(entityIDs, change.ID)
+	}
+	if err := r.db.Where("entity_id IN ?", entityIDs).Order("created_at").Find(&data.AuditLogs).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetUserData loads the investor with all related records
+func (r *dataSubjectRepository) GetUserData(userID string) (*UserDataExport, error) {
+	var user models.User
+	if err := r.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	data := &UserDataExport{User: &user, Activity: make(map[string][]map[string]interface{})}
+
+	if err := r.db.Where("user_id = ?", userID).Order("invested_at").Find(&data.Investments).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&data.Notifications).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("user_id = ?", userID).Order("unlocked_at").Find(&data.Achievements).Error; err != nil {
+		return nil, err
+	}
+	for _, table := range userActivityTables {
+		var rows []map[string]interface{}
+		if err := r.db.Table(table).Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		data.Activity[table] = rows
+	}
+	if err := r.db.
+		Where("entity_type = ? AND entity_id = ?", models.AuditEntityTypeUser, userID).
+		Order("created_at").
+		Find(&data.AuditLogs).Error; err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// HasActiveInvestments reports whether the investor still has investments that were not harvested
+func (r *dataSubjectRepository) HasActiveInvestments(userID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Investment{}).
+		Where("user_id = ? AND is_harvested = ?", userID, false).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// EraseFarmer saves the anonymized farmer and removes the personal data copied into other
+// tables in one transaction. Farms, invoices, invoice files and audit logs are kept.
+func (r *dataSubjectRepository) EraseFarmer(farmer *models.Farmer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		farmer.UpdatedAt = time.Now()
+		if err := tx.Omit("Documents").Save(farmer).Error; err != nil {
+			return err
+		}
+
+		if err := tx.
+			Where("farmer_id = ? AND document_type <> ?", farmer.ID, models.DocumentTypeInvoiceFile).
+			Delete(&models.FarmerDocument{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.FarmerSubmission{}).
+			Where("farmer_id = ?", farmer.ID).
+			UpdateColumn("snapshot", gorm.Expr("'{}'::jsonb")).Error; err != nil {
+			return err
+		}
+
+		// Pending changes can no longer be reviewed, the profile they would change is gone
+		now := time.Now()
+		if err := tx.Model(&models.FarmerProfileChange{}).
+			Where("farmer_id = ? AND status = ?", farmer.ID, models.ProfileChangeStatusPending).
+			Updates(map[string]interface{}{
+				"status":           models.ProfileChangeStatusRejected,
+				"rejection_reason": "farmer data erased",
+				"reviewed_at":      now,
+				"updated_at":       now,
+			}).Error; err != nil {
+			return err
+		}
+
+		// Keep which fields changed, drop the values
+		if err := tx.Model(&models.FarmerProfileChange{}).
+			Where("farmer_id = ?", farmer.ID).
+			UpdateColumns(map[string]interface{}{
+				"old_values": gorm.Expr("COALESCE((SELECT jsonb_object_agg(key, null) FROM jsonb_object_keys(old_values) key), '{}'::jsonb)"),
+				"new_values": gorm.Expr("COALESCE((SELECT jsonb_object_agg(key, null) FROM jsonb_object_keys(new_values) key), '{}'::jsonb)"),
+			}).Error; err != nil {
+			return err
+		}
+
+		// Messages that were not sent yet must not reach the old contact details
+		if err := tx.Model(&models.FarmerNotificationDelivery{}).
+			Where("farmer_id = ? AND status = ?", farmer.ID, models.DeliveryStatusPending).
+			Updates(map[string]interface{}{"status": models.DeliveryStatusFailed, "last_error": "recipient erased"}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.FarmerNotificationDelivery{}).
+			Where("farmer_id = ?", farmer.ID).
+			Updates(map[string]interface{}{"recipient": ErasedPlaceholder, "subject": nil, "body": ErasedPlaceholder}).Error
+	})
+}
+
+// EraseUser clears the profile of an investor and deletes the notifications addressed to them.
+// The wallet address, investments and game history are kept for the financial records.
+func (r *dataSubjectRepository) EraseUser(user *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"name":       nil,
+			"email":      nil,
+			"avatar":     nil,
+			"erased_at":  user.ErasedAt,
+			"erased_by":  user.ErasedBy,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		user.Name, user.Email, user.Avatar = nil, nil, nil
+
+		return tx.Where("user_id = ?", user.ID).Delete(&models.Notification{}).Error
+	})
+}
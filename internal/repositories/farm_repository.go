@@ -137,11 +137,18 @@ func (r *farmRepository) SoftDelete(id string) error {
 	return r.db.Model(&models.Farm{}).Where("id = ?", id).Update("is_active", false).Error
 }
 
-// HasActiveInvoices checks if a farm has any invoices that are not rejected
+// HasActiveInvoices checks if a farm has any invoices that have not reached a final status
 func (r *farmRepository) HasActiveInvoices(farmID string) (bool, error) {
+	finalStatuses := []models.InvoiceStatus{
+		models.InvoiceStatusRejected,
+		models.InvoiceStatusWithdrawn,
+		models.InvoiceStatusFundingFailed,
+		models.InvoiceStatusCompleted,
+	}
+
 	var count int64
 	err := r.db.Model(&models.Invoice{}).
-		Where("farm_id = ? AND status != ?", farmID, models.InvoiceStatusRejected).
+		Where("farm_id = ? AND status NOT IN ?", farmID, finalStatuses).
 		Count(&count).Error
 	if err != nil {
 		return false, err
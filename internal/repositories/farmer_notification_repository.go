@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FarmerNotificationRepository defines the interface for farmer notification delivery data access
+type FarmerNotificationRepository interface {
+	Create(delivery *models.FarmerNotificationDelivery) error
+	Update(delivery *models.FarmerNotificationDelivery) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.FarmerNotificationDelivery, error)
+	GetAllByFarmerID(farmerID string) ([]models.FarmerNotificationDelivery, error)
+}
+
+type farmerNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewFarmerNotificationRepository creates a new FarmerNotificationRepository instance
+func NewFarmerNotificationRepository(db *gorm.DB) FarmerNotificationRepository {
+	return &farmerNotificationRepository{db: db}
+}
+
+// Create creates a new delivery record
+func (r *farmerNotificationRepository) Create(delivery *models.FarmerNotificationDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// Update saves the delivery state after an attempt
+func (r *farmerNotificationRepository) Update(delivery *models.FarmerNotificationDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	return r.db.Save(delivery).Error
+}
+
+// ClaimDue locks pending deliveries whose next attempt is due and pushes their
+// next_attempt_at forward by the lease, so other replicas skip them while they are sent
+func (r *farmerNotificationRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.FarmerNotificationDelivery, error) {
+	var deliveries []models.FarmerNotificationDelivery
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.ID
+		}
+		return tx.Model(&models.FarmerNotificationDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetAllByFarmerID retrieves all deliveries for a farmer, newest first
+func (r *farmerNotificationRepository) GetAllByFarmerID(farmerID string) ([]models.FarmerNotificationDelivery, error) {
+	var deliveries []models.FarmerNotificationDelivery
+	err := r.db.Where("farmer_id = ?", farmerID).
+		Order("created_at DESC").
+		Find(&deliveries).Error
+	return deliveries, err
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrIDNumberTaken is returned when applying a change would give a farmer a NIK that another
+// farmer already registered
+var ErrIDNumberTaken = errors.New("NIK already registered by another farmer")
+
+// ErrFarmerErased is returned when applying a change to a farmer whose personal data was erased
+var ErrFarmerErased = errors.New("farmer data was erased")
+
+// ProfileChangeFilter contains filter options for listing farmer profile changes
+type ProfileChangeFilter struct {
+	FarmerID       string // Filter by farmer ID
+	Status         string // Filter by status (applied, pending, approved, rejected)
+	RequiresReview *bool  // Only sensitive (true) or only direct (false) changes
+	Page           int    // Current page (1-indexed)
+	Limit          int    // Items per page
+}
+
+// FarmerProfileChangeRepository defines the interface for farmer profile change data access
+type FarmerProfileChangeRepository interface {
+	Create(change *models.FarmerProfileChange) error
+	GetByID(id string) (*models.FarmerProfileChange, error)
+	GetPendingByFarmerID(farmerID string) (*models.FarmerProfileChange, error)
+	GetAll(filter ProfileChangeFilter) ([]models.FarmerProfileChange, int64, error)
+	Apply(farmer *models.Farmer, change *models.FarmerProfileChange) error
+	Update(change *models.FarmerProfileChange) error
+}
+
+type farmerProfileChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewFarmerProfileChangeRepository creates a new FarmerProfileChangeRepository instance
+func NewFarmerProfileChangeRepository(db *gorm.DB) FarmerProfileChangeRepository {
+	return &farmerProfileChangeRepository{db: db}
+}
+
+// Create creates a new profile change record
+func (r *farmerProfileChangeRepository) Create(change *models.FarmerProfileChange) error {
+	return r.db.Omit("Farmer").Create(change).Error
+}
+
+// GetByID retrieves a profile change by ID with the farmer relation
+func (r *farmerProfileChangeRepository) GetByID(id string) (*models.FarmerProfileChange, error) {
+	var change models.FarmerProfileChange
+	if err := r.db.Preload("Farmer").First(&change, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// GetPendingByFarmerID retrieves the change of a farmer that is waiting for review
+func (r *farmerProfileChangeRepository) GetPendingByFarmerID(farmerID string) (*models.FarmerProfileChange, error) {
+	var change models.FarmerProfileChange
+	if err := r.db.First(&change, "farmer_id = ? AND status = ?", farmerID, models.ProfileChangeStatusPending).Error; err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// GetAll retrieves profile changes, newest first, with pagination
+func (r *farmerProfileChangeRepository) GetAll(filter ProfileChangeFilter) ([]models.FarmerProfileChange, int64, error) {
+	var changes []models.FarmerProfileChange
+	var totalCount int64
+
+	query := r.db.Model(&models.FarmerProfileChange{})
+	if filter.FarmerID != "" {
+		query = query.Where("farmer_id = ?", filter.FarmerID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.RequiresReview != nil {
+		query = query.Where("requires_review = ?", *filter.RequiresReview)
+	}
+
+	// Get total count before pagination
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	if err := query.
+		Preload("Farmer").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(filter.Limit).
+		Find(&changes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return changes, totalCount, nil
+}
+
+// Apply saves the farmer and the change record in one transaction, so the audit
+// trail never disagrees with the profile. A new NIK is checked for uniqueness again inside
+// the transaction, since another farmer may have registered it while the change was pending.
+// Returns ErrFarmerErased when the farmer's data was erased meanwhile.
+func (r *farmerProfileChangeRepository) Apply(farmer *models.Farmer, change *models.FarmerProfileChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The farmer row stays locked until commit, so an erasure cannot slip in between
+		var current models.Farmer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			First(&current, "id = ?", farmer.ID).Error; err != nil {
+			return err
+		}
+		if current.Status == models.FarmerStatusErased {
+			return ErrFarmerErased
+		}
+
+		if err := checkIDNumberAvailable(tx, farmer); err != nil {
+			return err
+		}
+
+		farmer.UpdatedAt = time.Now()
+		if err := tx.Omit("Documents").Save(farmer).Error; err != nil {
+			return err
+		}
+		change.UpdatedAt = time.Now()
+		return tx.Omit("Farmer").Save(change).Error
+	})
+}
+
+// checkIDNumberAvailable returns ErrIDNumberTaken when the farmer's NIK changed and another
+// farmer holds it. Changes to the same NIK are serialized with a transaction lock on its
+// blind index, so two approvals cannot both pass the check.
+func checkIDNumberAvailable(tx *gorm.DB, farmer *models.Farmer) error {
+	index, err := pii.BlindIndex("id_number", farmer.IDNumber)
+	if err != nil || index == "" {
+		return err
+	}
+
+	var current models.Farmer
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "id_number_bidx").
+		First(&current, "id = ?", farmer.ID).Error; err != nil {
+		return err
+	}
+	if current.IDNumberIndex != nil && *current.IDNumberIndex == index {
+		return nil
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "farmer_id_number:"+index).Error; err != nil {
+		return err
+	}
+	var count int64
+	if err := tx.Model(&models.Farmer{}).
+		Where("id_number_bidx = ? AND id <> ?", index, farmer.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrIDNumberTaken
+	}
+	return nil
+}
+
+// Update saves a profile change record
+func (r *farmerProfileChangeRepository) Update(change *models.FarmerProfileChange) error {
+	change.UpdatedAt = time.Now()
+	return r.db.Omit("Farmer").Save(change).Error
+}
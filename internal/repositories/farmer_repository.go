@@ -1,10 +1,18 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
 	"gorm.io/gorm"
 )
 
+// ErrFarmerStatusChanged is returned when a farmer left the status (or claim) a status change
+// starts from, because another admin moved it first
+var ErrFarmerStatusChanged = errors.New("farmer status changed")
+
 // FarmerFilter contains filter options for listing farmers
 type FarmerFilter struct {
 	Status    []string // Filter by status (pending, under_review, approved, rejected, suspended)
@@ -19,14 +27,19 @@ type FarmerFilter struct {
 type FarmerRepository interface {
 	Create(farmer *models.Farmer) error
 	GetByID(id string) (*models.Farmer, error)
+	GetStatusByID(id string) (models.FarmerStatus, error)
 	GetByUserID(userID string) (*models.Farmer, error)
 	GetByEmail(email string) (*models.Farmer, error)
 	GetByWalletAddress(walletAddress string) (*models.Farmer, error)
 	ExistsByEmailOrPhone(email, phone string) (bool, error)
+	ExistsByPhoneExcludingID(phone, excludeID string) (bool, error)
+	ExistsByEmailOrPhoneExcludingID(email, phone, excludeID string) (bool, error)
 	ExistsByWalletAddress(walletAddress string) (bool, error)
+	ExistsByIDNumber(idNumber, excludeID string) (bool, error)
 	CreateDocuments(documents []models.FarmerDocument) error
 	GetAllWithPagination(filter FarmerFilter) ([]models.Farmer, int64, error)
-	Update(farmer *models.Farmer) error
+	UpdateStatus(farmer *models.Farmer, from models.FarmerStatus, fromReviewedBy *string) error
+	LinkUser(farmerID, userID string) error
 }
 
 type farmerRepository struct {
@@ -52,6 +65,15 @@ func (r *farmerRepository) GetByID(id string) (*models.Farmer, error) {
 	return &farmer, nil
 }
 
+// GetStatusByID retrieves only the status of a farmer
+func (r *farmerRepository) GetStatusByID(id string) (models.FarmerStatus, error) {
+	var farmer models.Farmer
+	if err := r.db.Select("status").First(&farmer, "id = ?", id).Error; err != nil {
+		return "", err
+	}
+	return farmer.Status, nil
+}
+
 // GetByEmail retrieves a farmer by email
 func (r *farmerRepository) GetByEmail(email string) (*models.Farmer, error) {
 	var farmer models.Farmer
@@ -73,6 +95,50 @@ func (r *farmerRepository) ExistsByEmailOrPhone(email, phone string) (bool, erro
 	return count > 0, nil
 }
 
+// ExistsByPhoneExcludingID checks if another farmer already uses the given phone number
+func (r *farmerRepository) ExistsByPhoneExcludingID(phone, excludeID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Farmer{}).
+		Where("phone_number = ? AND id <> ?", phone, excludeID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ExistsByEmailOrPhoneExcludingID checks if another farmer already uses the given email or phone
+func (r *farmerRepository) ExistsByEmailOrPhoneExcludingID(email, phone, excludeID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Farmer{}).
+		Where("(email = ? OR phone_number = ?) AND id <> ?", email, phone, excludeID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ExistsByIDNumber checks if another farmer already registered the given NIK, using its blind
+// index since the column itself is encrypted. Pass an empty excludeID to check all farmers.
+func (r *farmerRepository) ExistsByIDNumber(idNumber, excludeID string) (bool, error) {
+	index, err := pii.BlindIndex("id_number", idNumber)
+	if err != nil {
+		return false, err
+	}
+
+	query := r.db.Model(&models.Farmer{}).Where("id_number_bidx = ?", index)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // ExistsByWalletAddress checks if a farmer with the given wallet address already exists
 func (r *farmerRepository) ExistsByWalletAddress(walletAddress string) (bool, error) {
 	var count int64
@@ -166,9 +232,41 @@ func (r *farmerRepository) GetAllWithPagination(filter FarmerFilter) ([]models.F
 	return farmers, totalCount, nil
 }
 
-// Update updates an existing farmer record
-func (r *farmerRepository) Update(farmer *models.Farmer) error {
-	return r.db.Save(farmer).Error
+// UpdateStatus saves the status, review and suspension columns of a farmer if it is still in
+// the from status and claimed by fromReviewedBy. Returns ErrFarmerStatusChanged otherwise, so
+// two admins cannot both claim or decide the same application.
+func (r *farmerRepository) UpdateStatus(farmer *models.Farmer, from models.FarmerStatus, fromReviewedBy *string) error {
+	query := r.db.Model(&models.Farmer{}).Where("id = ? AND status = ?", farmer.ID, from)
+	if fromReviewedBy == nil {
+		query = query.Where("reviewed_by IS NULL")
+	} else {
+		query = query.Where("reviewed_by = ?", *fromReviewedBy)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":            farmer.Status,
+		"reviewed_by":       farmer.ReviewedBy,
+		"reviewed_at":       farmer.ReviewedAt,
+		"rejection_reason":  farmer.RejectionReason,
+		"suspended_by":      farmer.SuspendedBy,
+		"suspended_at":      farmer.SuspendedAt,
+		"suspension_reason": farmer.SuspensionReason,
+		"updated_at":        time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFarmerStatusChanged
+	}
+	return nil
+}
+
+// LinkUser links a farmer to the investor account of the same wallet, unless already linked
+func (r *farmerRepository) LinkUser(farmerID, userID string) error {
+	return r.db.Model(&models.Farmer{}).
+		Where("id = ? AND user_id IS NULL", farmerID).
+		Update("user_id", userID).Error
 }
 
 // GetByUserID retrieves a farmer by user ID
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// FarmerSubmissionRepository defines the interface for farmer submission history data access
+type FarmerSubmissionRepository interface {
+	Create(submission *models.FarmerSubmission) error
+	GetLatestByFarmerID(farmerID string) (*models.FarmerSubmission, error)
+	GetAllByFarmerID(farmerID string) ([]models.FarmerSubmission, error)
+	Update(submission *models.FarmerSubmission) error
+	Resubmit(farmer *models.Farmer, documents []models.FarmerDocument, submission *models.FarmerSubmission) error
+}
+
+type farmerSubmissionRepository struct {
+	db *gorm.DB
+}
+
+// NewFarmerSubmissionRepository creates a new FarmerSubmissionRepository instance
+func NewFarmerSubmissionRepository(db *gorm.DB) FarmerSubmissionRepository {
+	return &farmerSubmissionRepository{db: db}
+}
+
+// Create creates a submission with the next submission number for the farmer
+func (r *farmerSubmissionRepository) Create(submission *models.FarmerSubmission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createSubmission(tx, submission)
+	})
+}
+
+// GetLatestByFarmerID retrieves the most recent submission of a farmer
+func (r *farmerSubmissionRepository) GetLatestByFarmerID(farmerID string) (*models.FarmerSubmission, error) {
+	var submission models.FarmerSubmission
+	if err := r.db.Where("farmer_id = ?", farmerID).
+		Order("submission_number DESC").
+		First(&submission).Error; err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// GetAllByFarmerID retrieves every submission of a farmer, newest first
+func (r *farmerSubmissionRepository) GetAllByFarmerID(farmerID string) ([]models.FarmerSubmission, error) {
+	var submissions []models.FarmerSubmission
+	err := r.db.Where("farmer_id = ?", farmerID).
+		Order("submission_number DESC").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+// Update saves the review decision of a submission
+func (r *farmerSubmissionRepository) Update(submission *models.FarmerSubmission) error {
+	return r.db.Save(submission).Error
+}
+
+// Resubmit saves the corrected farmer, replaces their documents and records the new
+// submission in one transaction
+func (r *farmerSubmissionRepository) Resubmit(farmer *models.Farmer, documents []models.FarmerDocument, submission *models.FarmerSubmission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		farmer.UpdatedAt = time.Now()
+		if err := tx.Omit("Documents").Save(farmer).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("farmer_id = ?", farmer.ID).Delete(&models.FarmerDocument{}).Error; err != nil {
+			return err
+		}
+		if len(documents) > 0 {
+			if err := tx.Create(&documents).Error; err != nil {
+				return err
+			}
+		}
+		return createSubmission(tx, submission)
+	})
+}
+
+// createSubmission numbers the submission after the farmer's latest one. The row lock on
+// the farmer serialises concurrent submissions; the unique index is the final guard.
+func createSubmission(tx *gorm.DB, submission *models.FarmerSubmission) error {
+	if err := tx.Exec("SELECT 1 FROM farmers WHERE id = ? FOR UPDATE", submission.FarmerID).Error; err != nil {
+		return err
+	}
+
+	var latest int
+	if err := tx.Model(&models.FarmerSubmission{}).
+		Where("farmer_id = ?", submission.FarmerID).
+		Select("COALESCE(MAX(submission_number), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	submission.SubmissionNumber = latest + 1
+	return tx.Create(submission).Error
+}
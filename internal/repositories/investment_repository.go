@@ -26,8 +26,10 @@ type InvestmentRepository interface {
 	GetByUserIDAndOnchainID(userID string, onchainID int64) (*models.Investment, error)
 	GetAllByUserID(filter InvestmentFilter) ([]models.Investment, int64, error)
 	Update(investment *models.Investment) error
-	UpdateProgress(id string, progress int, status models.CropStatus) error
+	UpdateProgress(id string, progress int) error
+	MarkReady(id string) (bool, error)
 	IncrementWaterCount(id string) error
+	GetUserIDsByInvoiceID(invoiceID string) ([]string, error)
 }
 
 type investmentRepository struct {
@@ -161,17 +163,32 @@ func (r *investmentRepository) Update(investment *models.Investment) error {
 	return r.db.Save(investment).Error
 }
 
-// UpdateProgress updates the progress and status of an investment
-func (r *investmentRepository) UpdateProgress(id string, progress int, status models.CropStatus) error {
+// UpdateProgress updates the progress of an investment that is still growing
+func (r *investmentRepository) UpdateProgress(id string, progress int) error {
 	return r.db.Model(&models.Investment{}).
-		Where("id = ?", id).
+		Where("id = ? AND status = ?", id, models.CropStatusGrowing).
 		Updates(map[string]interface{}{
 			"progress":   progress,
-			"status":     status,
 			"updated_at": time.Now(),
 		}).Error
 }
 
+// MarkReady moves a growing investment to ready and reports whether this call made the
+// transition, so that concurrent refreshes of the same crop act on it only once
+func (r *investmentRepository) MarkReady(id string) (bool, error) {
+	result := r.db.Model(&models.Investment{}).
+		Where("id = ? AND status = ?", id, models.CropStatusGrowing).
+		Updates(map[string]interface{}{
+			"progress":   100,
+			"status":     models.CropStatusReady,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // IncrementWaterCount increments the water count for an investment
 func (r *investmentRepository) IncrementWaterCount(id string) error {
 	now := time.Now()
@@ -183,3 +200,15 @@ func (r *investmentRepository) IncrementWaterCount(id string) error {
 			"updated_at":      now,
 		}).Error
 }
+
+// GetUserIDsByInvoiceID retrieves the distinct investors of an invoice
+func (r *investmentRepository) GetUserIDsByInvoiceID(invoiceID string) ([]string, error) {
+	var userIDs []string
+	if err := r.db.Model(&models.Investment{}).
+		Where("invoice_id = ?", invoiceID).
+		Distinct().
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
@@ -1,22 +1,29 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"github.com/ownafarm/ownafarm-backend/internal/models"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
+// ErrInvoiceNotPending is returned when an invoice can no longer be edited, because it was
+// reviewed, withdrawn or submitted on chain
+var ErrInvoiceNotPending = errors.New("invoice is not pending")
+
 // InvoiceFilter contains filter options for listing invoices
 type InvoiceFilter struct {
 	FarmID                   string   // Filter by farm ID
 	FarmerID                 string   // Filter by farmer ID (via farm relation)
-	Status                   []string // Filter by status (pending, approved, rejected)
+	Status                   []string // Filter by status (see models.InvoiceStatus)
 	Page                     int      // Current page (1-indexed)
 	Limit                    int      // Items per page
 	SortBy                   string   // Field to sort (created_at, name, target_fund, yield_percent, duration_days)
 	SortOrder                string   // asc or desc
 	Search                   string   // Search by name
-	IsAvailableForInvestment bool     // Filter for marketplace (approved + not fully funded)
+	IsAvailableForInvestment bool     // Filter for marketplace (funding + not fully funded + before the deadline)
 	MinTargetFund            *float64 // Min target fund filter
 	MaxTargetFund            *float64 // Max target fund filter
 	MinYield                 *float64 // Min yield percent filter
@@ -38,8 +45,10 @@ type InvoiceRepository interface {
 	GetAllByFarmerID(farmerID string, filter InvoiceFilter) ([]models.Invoice, int64, error)
 	GetAllWithPagination(filter InvoiceFilter) ([]models.Invoice, int64, error)
 	GetAvailableForInvestment(filter InvoiceFilter) ([]models.Invoice, int64, error)
+	GetAllByStatus(statuses []models.InvoiceStatus) ([]models.Invoice, error)
 	Update(invoice *models.Invoice) error
 	UpdateFundingTotals(invoiceID string) error
+	HasPendingPayoutsByFarmerID(farmerID string) (bool, error)
 }
 
 type invoiceRepository struct {
@@ -275,18 +284,33 @@ func (r *invoiceRepository) UpdateFundingTotals(invoiceID string) error {
 		}).Error
 }
 
-// GetAvailableForInvestment retrieves approved and available invoices for marketplace
+// GetAllByStatus retrieves every invoice in one of the statuses, oldest first
+func (r *invoiceRepository) GetAllByStatus(statuses []models.InvoiceStatus) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	err := r.db.Where("status IN ?", statuses).
+		Order("created_at ASC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+// GetAvailableForInvestment retrieves invoices open for funding for the marketplace
 func (r *invoiceRepository) GetAvailableForInvestment(filter InvoiceFilter) ([]models.Invoice, int64, error) {
 	var invoices []models.Invoice
 	var totalCount int64
 
-	// Base query with JOIN to farms for location and land area filters
+	// Base query with JOIN to farms for location and land area filters,
+	// and to farmers to hide invoices of farmers that are not approved (e.g. suspended)
 	query := r.db.Model(&models.Invoice{}).
-		Joins("LEFT JOIN farms ON farms.id = invoices.farm_id")
+		Joins("LEFT JOIN farms ON farms.id = invoices.farm_id").
+		Joins("JOIN farmers ON farmers.id = farms.farmer_id")
 
-	// Marketplace base filters: approved and not fully funded
-	query = query.Where("invoices.status = ?", "approved").
-		Where("invoices.is_fully_funded = ?", false)
+	// Marketplace base filters: open for funding, not fully funded and before the funding
+	// deadline, from an approved farmer. Expired invoices are hidden before the lifecycle
+	// worker moves them to funding_failed.
+	query = query.Where("invoices.status = ?", models.InvoiceStatusFunding).
+		Where("invoices.is_fully_funded = ?", false).
+		Where("invoices.funding_deadline > ?", time.Now()).
+		Where("farmers.status = ?", "approved")
 
 	// Apply range filters for target_fund
 	if filter.MinTargetFund != nil {
@@ -385,3 +409,25 @@ func (r *invoiceRepository) GetAvailableForInvestment(filter InvoiceFilter) ([]m
 
 	return invoices, totalCount, nil
 }
+
+// HasPendingPayoutsByFarmerID reports whether the farmer has funded invoices still in their
+// lifecycle whose investments have not all been harvested, i.e. money is still moving to or
+// from the farmer
+func (r *invoiceRepository) HasPendingPayoutsByFarmerID(farmerID string) (bool, error) {
+	var exists bool
+	err := r.db.Raw(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM invoices
+			JOIN farms ON farms.id = invoices.farm_id
+			WHERE farms.farmer_id = ?
+			  AND invoices.status IN ?
+			  AND invoices.total_funded > 0
+			  AND EXISTS (
+				SELECT 1 FROM investments
+				WHERE investments.invoice_id = invoices.id
+				  AND investments.is_harvested = false
+			  )
+		)`, farmerID, models.ActiveInvoiceStatuses).Scan(&exists).Error
+	return exists, err
+}
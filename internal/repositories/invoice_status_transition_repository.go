@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrInvoiceStatusChanged is returned when an invoice left the status a transition starts
+// from, because another request or the lifecycle worker moved it first
+var ErrInvoiceStatusChanged = errors.New("invoice status changed")
+
+// InvoiceStatusTransitionRepository defines the interface for invoice status changes and their log
+type InvoiceStatusTransitionRepository interface {
+	Apply(transition *models.InvoiceStatusTransition, updates map[string]interface{}) error
+	GetAllByInvoiceID(invoiceID string) ([]models.InvoiceStatusTransition, error)
+}
+
+type invoiceStatusTransitionRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceStatusTransitionRepository creates a new InvoiceStatusTransitionRepository instance
+func NewInvoiceStatusTransitionRepository(db *gorm.DB) InvoiceStatusTransitionRepository {
+	return &invoiceStatusTransitionRepository{db: db}
+}
+
+// Apply moves an invoice from the transition's from status to its to status, together with
+// the other column updates, and logs the transition in one transaction. Returns
+// ErrInvoiceStatusChanged when the invoice is no longer in the from status.
+func (r *invoiceStatusTransitionRepository) Apply(transition *models.InvoiceStatusTransition, updates map[string]interface{}) error {
+	columns := map[string]interface{}{
+		"status":     transition.ToStatus,
+		"updated_at": time.Now(),
+	}
+	for column, value := range updates {
+		columns[column] = value
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Invoice{}).
+			Where("id = ? AND status = ?", transition.InvoiceID, transition.FromStatus).
+			Updates(columns)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvoiceStatusChanged
+		}
+		return tx.Create(transition).Error
+	})
+}
+
+// GetAllByInvoiceID retrieves the status history of an invoice, oldest first
+func (r *invoiceStatusTransitionRepository) GetAllByInvoiceID(invoiceID string) ([]models.InvoiceStatusTransition, error) {
+	var transitions []models.InvoiceStatusTransition
+	err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("created_at ASC").
+		Find(&transitions).Error
+	return transitions, err
+}
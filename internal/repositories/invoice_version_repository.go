@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InvoiceVersionRepository defines the interface for invoice version history data access
+type InvoiceVersionRepository interface {
+	Create(version *models.InvoiceVersion) error
+	CreateInvoice(invoice *models.Invoice, version *models.InvoiceVersion) error
+	GetAllByInvoiceID(invoiceID string) ([]models.InvoiceVersion, error)
+	Edit(invoice *models.Invoice, version *models.InvoiceVersion) error
+}
+
+type invoiceVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceVersionRepository creates a new InvoiceVersionRepository instance
+func NewInvoiceVersionRepository(db *gorm.DB) InvoiceVersionRepository {
+	return &invoiceVersionRepository{db: db}
+}
+
+// Create creates a version with the next version number for the invoice
+func (r *invoiceVersionRepository) Create(version *models.InvoiceVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createInvoiceVersion(tx, version)
+	})
+}
+
+// CreateInvoice creates a submitted invoice and records it as its first version in one
+// transaction, so an invoice never exists without its history
+func (r *invoiceVersionRepository) CreateInvoice(invoice *models.Invoice, version *models.InvoiceVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Farm").Create(invoice).Error; err != nil {
+			return err
+		}
+		version.InvoiceID = invoice.ID
+		return createInvoiceVersion(tx, version)
+	})
+}
+
+// GetAllByInvoiceID retrieves every version of an invoice, newest first
+func (r *invoiceVersionRepository) GetAllByInvoiceID(invoiceID string) ([]models.InvoiceVersion, error) {
+	var versions []models.InvoiceVersion
+	err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("version_number DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+// Edit saves the edited invoice and records its new version in one transaction. Returns
+// ErrInvoiceNotPending when the invoice was reviewed, withdrawn or put on chain meanwhile.
+func (r *invoiceVersionRepository) Edit(invoice *models.Invoice, version *models.InvoiceVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status", "token_id").
+			First(&current, "id = ?", invoice.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != models.InvoiceStatusPending || current.TokenID != nil {
+			return ErrInvoiceNotPending
+		}
+
+		invoice.UpdatedAt = time.Now()
+		if err := tx.Omit("Farm").Save(invoice).Error; err != nil {
+			return err
+		}
+		return createInvoiceVersion(tx, version)
+	})
+}
+
+// createInvoiceVersion numbers the version after the invoice's latest one. The row lock on
+// the invoice serialises concurrent edits; the unique index is the final guard.
+func createInvoiceVersion(tx *gorm.DB, version *models.InvoiceVersion) error {
+	if err := tx.Exec("SELECT 1 FROM invoices WHERE id = ? FOR UPDATE", version.InvoiceID).Error; err != nil {
+		return err
+	}
+
+	var latest int
+	if err := tx.Model(&models.InvoiceVersion{}).
+		Where("invoice_id = ?", version.InvoiceID).
+		Select("COALESCE(MAX(version_number), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	version.VersionNumber = latest + 1
+	return tx.Create(version).Error
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// NotificationFilter contains filter options for listing notifications
+type NotificationFilter struct {
+	UserID     string // Filter by user ID (required)
+	UnreadOnly bool   // Only return notifications that have not been read
+	Type       string // Filter by notification type
+	Page       int    // Current page (1-indexed)
+	Limit      int    // Items per page
+}
+
+// NotificationRepository defines the interface for notification data access
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	GetAllByUserID(filter NotificationFilter) ([]models.Notification, int64, error)
+	CountUnread(userID string) (int64, error)
+	MarkAsRead(id, userID string) error
+	MarkAllAsRead(userID string) (int64, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository instance
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create creates a new notification record
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// GetAllByUserID retrieves notifications for a user, newest first, with pagination
+func (r *notificationRepository) GetAllByUserID(filter NotificationFilter) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var totalCount int64
+
+	query := r.db.Model(&models.Notification{}).
+		Where("user_id = ?", filter.UserID)
+
+	if filter.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	// Get total count before pagination
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	offset := (filter.Page - 1) * filter.Limit
+	if err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(filter.Limit).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, totalCount, nil
+}
+
+// CountUnread counts unread notifications for a user
+func (r *notificationRepository) CountUnread(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkAsRead marks a single notification as read with ownership check
+// Returns gorm.ErrRecordNotFound if the notification does not belong to the user
+func (r *notificationRepository) MarkAsRead(id, userID string) error {
+	var notification models.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return err
+	}
+
+	// Already read, keep the original read_at
+	if notification.ReadAt != nil {
+		return nil
+	}
+
+	return r.db.Model(&models.Notification{}).
+		Where("id = ?", id).
+		Update("read_at", time.Now()).Error
+}
+
+// MarkAllAsRead marks all unread notifications of a user as read
+// Returns the number of notifications updated
+func (r *notificationRepository) MarkAllAsRead(userID string) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
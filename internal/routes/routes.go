@@ -6,22 +6,33 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ownafarm/ownafarm-backend/internal/handlers"
 	"github.com/ownafarm/ownafarm-backend/internal/middleware"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
 )
 
 func SetupRoutes(
 	router *gin.Engine,
 	userHandler *handlers.UserHandler,
 	authHandler *handlers.AuthHandler,
+	sessionHandler *handlers.SessionHandler,
 	farmerHandler *handlers.FarmerHandler,
 	farmerAuthHandler *handlers.FarmerAuthHandler,
 	adminAuthHandler *handlers.AdminAuthHandler,
+	jwksHandler *handlers.JWKSHandler,
 	farmHandler *handlers.FarmHandler,
 	invoiceHandler *handlers.InvoiceHandler,
 	investmentHandler *handlers.InvestmentHandler,
 	leaderboardHandler *handlers.LeaderboardHandler,
+	notificationHandler *handlers.NotificationHandler,
+	eventStreamHandler *handlers.EventStreamHandler,
+	farmerProfileHandler *handlers.FarmerProfileHandler,
+	dataSubjectHandler *handlers.DataSubjectHandler,
+	adminUserHandler *handlers.AdminUserHandler,
+	auditLogHandler *handlers.AuditLogHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	adminAuthMiddleware *middleware.AdminAuthMiddleware,
 	farmerAuthMiddleware *middleware.FarmerAuthMiddleware,
+	sessionAuthMiddleware *middleware.SessionAuthMiddleware,
+	rateLimitMiddleware *middleware.RateLimitMiddleware,
 ) {
 	router.GET("/", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, gin.H{
@@ -29,23 +40,39 @@ func SetupRoutes(
 		})
 	})
 
+	// Public keys of the token signing keys, for services verifying OwnaFarm tokens
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	// Auth routes (public)
 	auth := router.Group("/auth")
+	auth.Use(rateLimitMiddleware.Login())
 	{
 		auth.GET("/nonce", authHandler.GetNonce)
 		auth.POST("/login", authHandler.Login)
+		auth.POST("/refresh", middleware.SkipAudit(), authHandler.Refresh)
+		auth.POST("/logout", authMiddleware.AuthRequired(), authHandler.Logout)
+	}
+
+	// Session routes (public), one login for every role of a wallet
+	session := router.Group("/session")
+	session.Use(rateLimitMiddleware.Login())
+	{
+		session.GET("/nonce", sessionHandler.GetNonce)
+		session.POST("/login", sessionHandler.Login)
+		session.POST("/refresh", middleware.SkipAudit(), sessionHandler.Refresh)
+		session.POST("/logout", sessionAuthMiddleware.SessionAuthRequired(), sessionHandler.Logout)
 	}
 
 	// Farmer routes (public)
 	farmers := router.Group("/farmers")
 	{
-		farmers.POST("/register", farmerHandler.Register)
-		farmers.POST("/documents/presign", farmerHandler.GetPresignedURLs)
+		farmers.POST("/register", rateLimitMiddleware.Register(), farmerHandler.Register)
+		farmers.POST("/documents/presign", middleware.SkipAudit(), rateLimitMiddleware.Presign(), farmerHandler.GetPresignedURLs)
 	}
 
 	// Protected routes (investor auth)
 	protected := router.Group("/")
-	protected.Use(authMiddleware.AuthRequired())
+	protected.Use(authMiddleware.AuthRequired(), rateLimitMiddleware.Authenticated())
 	{
 		protected.GET("/users/:id", userHandler.GetByID)
 		// Farmer document download (requires auth)
@@ -55,7 +82,7 @@ func SetupRoutes(
 		protected.GET("/marketplace/invoices", invoiceHandler.ListMarketplace)
 
 		// Crop/Investment routes
-		protected.POST("/crops/sync", investmentHandler.SyncInvestments)
+		protected.POST("/crops/sync", rateLimitMiddleware.CropSync(), investmentHandler.SyncInvestments)
 		protected.GET("/crops", investmentHandler.ListCrops)
 		protected.GET("/crops/:id", investmentHandler.GetCrop)
 		protected.POST("/crops/:id/water", investmentHandler.WaterCrop)
@@ -63,45 +90,103 @@ func SetupRoutes(
 
 		// Leaderboard route
 		protected.GET("/leaderboard", leaderboardHandler.GetLeaderboard)
+
+		// Notification routes
+		protected.GET("/me/notifications", notificationHandler.List)
+		protected.GET("/me/notifications/unread-count", notificationHandler.GetUnreadCount)
+		protected.PATCH("/me/notifications/read-all", notificationHandler.MarkAllAsRead)
+		protected.PATCH("/me/notifications/:id/read", notificationHandler.MarkAsRead)
+	}
+
+	// Live event stream (investor auth; EventSource opens the stream with a ticket from /events/tickets)
+	events := router.Group("/events")
+	{
+		events.POST("/tickets", authMiddleware.AuthRequired(), rateLimitMiddleware.Authenticated(), eventStreamHandler.IssueTicket)
+		events.GET("/stream", authMiddleware.StreamAuthRequired(), rateLimitMiddleware.Authenticated(), eventStreamHandler.Stream)
 	}
 
 	// Admin auth routes (public)
 	adminAuth := router.Group("/admin/auth")
+	adminAuth.Use(rateLimitMiddleware.Login())
 	{
 		adminAuth.GET("/nonce", adminAuthHandler.GetNonce)
 		adminAuth.POST("/login", adminAuthHandler.Login)
+		adminAuth.POST("/refresh", middleware.SkipAudit(), adminAuthHandler.Refresh)
+		adminAuth.POST("/logout", adminAuthMiddleware.AdminAuthRequired(), adminAuthHandler.Logout)
 	}
 
-	// Admin routes (requires admin auth)
+	// Admin routes (requires admin auth, each route checks the permission of the admin role)
 	admin := router.Group("/admin")
-	admin.Use(adminAuthMiddleware.AdminAuthRequired())
+	admin.Use(adminAuthMiddleware.AdminAuthRequired(), rateLimitMiddleware.Authenticated())
 	{
 		// Farmer management
-		admin.GET("/farmers", farmerHandler.GetListForAdmin)
-		admin.GET("/farmers/:id", farmerHandler.GetDetailForAdmin)
-		admin.PATCH("/farmers/:id/approve", farmerHandler.ApproveFarmer)
-		admin.PATCH("/farmers/:id/reject", farmerHandler.RejectFarmer)
+		admin.GET("/farmers", middleware.RequirePermission(models.PermissionFarmersRead), farmerHandler.GetListForAdmin)
+		admin.GET("/farmers/:id", middleware.RequirePermission(models.PermissionFarmersRead), farmerHandler.GetDetailForAdmin)
+		admin.PATCH("/farmers/:id/approve", middleware.RequirePermission(models.PermissionFarmersReview), farmerHandler.ApproveFarmer)
+		admin.PATCH("/farmers/:id/reject", middleware.RequirePermission(models.PermissionFarmersReview), farmerHandler.RejectFarmer)
+		admin.PATCH("/farmers/:id/claim", middleware.RequirePermission(models.PermissionFarmersReview), farmerHandler.ClaimFarmer)
+		admin.PATCH("/farmers/:id/release", middleware.RequirePermission(models.PermissionFarmersReview), farmerHandler.ReleaseFarmer)
+		admin.PATCH("/farmers/:id/suspend", middleware.RequirePermission(models.PermissionFarmersSuspend), farmerHandler.SuspendFarmer)
+		admin.PATCH("/farmers/:id/reinstate", middleware.RequirePermission(models.PermissionFarmersSuspend), farmerHandler.ReinstateFarmer)
+		admin.POST("/farmers/:id/reveal", middleware.RequirePermission(models.PermissionFarmersRevealPII), farmerHandler.RevealPII)
+
+		// Data subject requests (export and erasure of personal data)
+		admin.GET("/farmers/:id/export", middleware.RequirePermission(models.PermissionDataSubjectExport), dataSubjectHandler.ExportFarmer)
+		admin.POST("/farmers/:id/erase", middleware.RequirePermission(models.PermissionDataSubjectErase), dataSubjectHandler.EraseFarmer)
+		admin.GET("/investors/:id/export", middleware.RequirePermission(models.PermissionDataSubjectExport), dataSubjectHandler.ExportUser)
+		admin.POST("/investors/:id/erase", middleware.RequirePermission(models.PermissionDataSubjectErase), dataSubjectHandler.EraseUser)
+
+		// Farmer profile change requests (sensitive fields)
+		admin.GET("/farmer-change-requests", middleware.RequirePermission(models.PermissionFarmersRead), farmerProfileHandler.ListForAdmin)
+		admin.PATCH("/farmer-change-requests/:id/approve", middleware.RequirePermission(models.PermissionProfileChangesReview), farmerProfileHandler.ApproveChange)
+		admin.PATCH("/farmer-change-requests/:id/reject", middleware.RequirePermission(models.PermissionProfileChangesReview), farmerProfileHandler.RejectChange)
 
 		// Invoice management
-		admin.GET("/invoices", invoiceHandler.ListForAdmin)
-		admin.GET("/invoices/:id", invoiceHandler.GetByIDForAdmin)
-		admin.PATCH("/invoices/:id/approve", invoiceHandler.ApproveInvoice)
-		admin.PATCH("/invoices/:id/reject", invoiceHandler.RejectInvoice)
+		admin.GET("/invoices", middleware.RequirePermission(models.PermissionInvoicesRead), invoiceHandler.ListForAdmin)
+		admin.GET("/invoices/:id", middleware.RequirePermission(models.PermissionInvoicesRead), invoiceHandler.GetByIDForAdmin)
+		admin.PATCH("/invoices/:id/approve", middleware.RequirePermission(models.PermissionInvoicesReview), invoiceHandler.ApproveInvoice)
+		admin.PATCH("/invoices/:id/reject", middleware.RequirePermission(models.PermissionInvoicesReview), invoiceHandler.RejectInvoice)
+
+		// Admin user management
+		admin.GET("/users", middleware.RequirePermission(models.PermissionAdminsManage), adminUserHandler.List)
+		admin.POST("/users", middleware.RequirePermission(models.PermissionAdminsManage), adminUserHandler.Invite)
+		admin.PATCH("/users/:id/role", middleware.RequirePermission(models.PermissionAdminsManage), adminUserHandler.ChangeRole)
+		admin.PATCH("/users/:id/deactivate", middleware.RequirePermission(models.PermissionAdminsManage), adminUserHandler.Deactivate)
+		admin.PATCH("/users/:id/reactivate", middleware.RequirePermission(models.PermissionAdminsManage), adminUserHandler.Reactivate)
+
+		// Audit logs
+		admin.GET("/audit-logs", middleware.RequirePermission(models.PermissionAuditLogsRead), auditLogHandler.List)
+		admin.GET("/audit-logs/export", middleware.RequirePermission(models.PermissionAuditLogsRead), auditLogHandler.Export)
+		admin.GET("/audit-logs/entities/:type/:id", middleware.RequirePermission(models.PermissionAuditLogsRead), auditLogHandler.Timeline)
 	}
 
 	// Farmer auth routes (public)
 	farmerAuth := router.Group("/farmer/auth")
+	farmerAuth.Use(rateLimitMiddleware.Login())
 	{
 		farmerAuth.GET("/nonce", farmerAuthHandler.GetNonce)
 		farmerAuth.POST("/login", farmerAuthHandler.Login)
+		farmerAuth.POST("/refresh", middleware.SkipAudit(), farmerAuthHandler.Refresh)
+		// Any farmer token can log out, application scope included
+		farmerAuth.POST("/logout", farmerAuthMiddleware.FarmerApplicationAuthRequired(), farmerAuthHandler.Logout)
+	}
+
+	// Farmer application routes (pending, under review and rejected farmers can log in here)
+	farmerApplication := router.Group("/farmer/application")
+	farmerApplication.Use(farmerAuthMiddleware.FarmerApplicationAuthRequired(), rateLimitMiddleware.Authenticated())
+	{
+		farmerApplication.GET("", farmerHandler.GetApplication)
+		farmerApplication.PUT("", farmerHandler.Resubmit)
 	}
 
 	// Farmer routes (requires farmer auth - approved farmers only)
 	farmer := router.Group("/farmer")
-	farmer.Use(farmerAuthMiddleware.FarmerAuthRequired())
+	farmer.Use(farmerAuthMiddleware.FarmerAuthRequired(), rateLimitMiddleware.Authenticated())
 	{
 		// Get current farmer data
 		farmer.GET("/me", farmerHandler.GetMe)
+		farmer.PATCH("/me", farmerProfileHandler.UpdateMe)
+		farmer.GET("/me/change-requests", farmerProfileHandler.ListMyChanges)
 
 		// Farm management
 		farmer.POST("/farms", farmHandler.Create)
@@ -114,6 +199,8 @@ func SetupRoutes(
 		farmer.POST("/invoices", invoiceHandler.Create)
 		farmer.GET("/invoices", invoiceHandler.List)
 		farmer.GET("/invoices/:id", invoiceHandler.GetByID)
-		farmer.POST("/invoices/image/presign", invoiceHandler.GetPresignedImageURL)
+		farmer.PUT("/invoices/:id", invoiceHandler.Update)
+		farmer.DELETE("/invoices/:id", invoiceHandler.Withdraw)
+		farmer.POST("/invoices/image/presign", middleware.SkipAudit(), rateLimitMiddleware.Presign(), invoiceHandler.GetPresignedImageURL)
 	}
 }
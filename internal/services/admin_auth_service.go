@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
 	"github.com/ownafarm/ownafarm-backend/internal/repositories"
 	"github.com/ownafarm/ownafarm-backend/internal/utils"
@@ -25,8 +26,10 @@ var (
 
 // AdminLoginResult contains the result of a successful admin login
 type AdminLoginResult struct {
-	Token string
-	Admin *models.AdminUser
+	Token        string
+	RefreshToken string
+	ExpiresIn    int64 // Access token lifetime in seconds
+	Admin        *models.AdminUser
 }
 
 // RateLimitInfo contains rate limit information for error responses
@@ -47,6 +50,8 @@ type AdminAuthService struct {
 	adminJwtUtil     *utils.AdminJWTUtil
 	authService      *AuthService
 	valkeyClient     valkey.Client
+	tokenService     TokenServiceInterface
+	auditor          audit.Recorder
 	nonceTTL         time.Duration
 }
 
@@ -56,6 +61,8 @@ func NewAdminAuthService(
 	adminJwtUtil *utils.AdminJWTUtil,
 	authService *AuthService,
 	valkeyClient valkey.Client,
+	tokenService TokenServiceInterface,
+	auditor audit.Recorder,
 	nonceTTLMinutes int,
 ) *AdminAuthService {
 	return &AdminAuthService{
@@ -64,6 +71,8 @@ func NewAdminAuthService(
 		adminJwtUtil:     adminJwtUtil,
 		authService:      authService,
 		valkeyClient:     valkeyClient,
+		tokenService:     tokenService,
+		auditor:          auditor,
 		nonceTTL:         time.Duration(nonceTTLMinutes) * time.Minute,
 	}
 }
@@ -87,8 +96,9 @@ func (s *AdminAuthService) buildSignMessage(nonce string) string {
 	return fmt.Sprintf("Sign this message to login to OwnaFarm Admin.\n\nNonce: %s", nonce)
 }
 
-// GetNonce generates and stores a nonce for admin wallet authentication
-func (s *AdminAuthService) GetNonce(ctx context.Context, walletAddress string) (*AdminNonceResult, error) {
+// GetNonce generates and stores a nonce for admin wallet authentication. The origin is the
+// Origin header of the request and picks the domain of the sign-in message.
+func (s *AdminAuthService) GetNonce(ctx context.Context, walletAddress, origin string) (*AdminNonceResult, error) {
 	// Normalize wallet address
 	normalizedAddress := s.authService.NormalizeWalletAddress(walletAddress)
 
@@ -98,6 +108,17 @@ func (s *AdminAuthService) GetNonce(ctx context.Context, walletAddress string) (
 		return nil, err
 	}
 
+	signMessage, err := s.authService.BuildLoginMessage(LoginMessageRequest{
+		WalletAddress: normalizedAddress,
+		Nonce:         nonce,
+		Statement:     LoginStatementAdmin,
+		Origin:        origin,
+		LegacyMessage: s.buildSignMessage(nonce),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Store nonce in Valkey with TTL
 	key := s.adminNonceKey(normalizedAddress)
 	cmd := s.valkeyClient.B().Set().Key(key).Value(nonce).Ex(s.nonceTTL).Build()
@@ -107,7 +128,7 @@ func (s *AdminAuthService) GetNonce(ctx context.Context, walletAddress string) (
 
 	return &AdminNonceResult{
 		Nonce:       nonce,
-		SignMessage: s.buildSignMessage(nonce),
+		SignMessage: signMessage,
 	}, nil
 }
 
@@ -139,9 +160,10 @@ func (s *AdminAuthService) validateAndDeleteNonce(ctx context.Context, walletAdd
 	return nil
 }
 
-// Login authenticates an admin user with wallet signature
+// Login authenticates an admin user with wallet signature. The message is the signed SIWE
+// message, empty for EIP-712 logins.
 // Returns AdminLoginResult on success, or an error with optional RateLimitInfo
-func (s *AdminAuthService) Login(ctx context.Context, walletAddress, signature, nonce string) (*AdminLoginResult, *RateLimitInfo, error) {
+func (s *AdminAuthService) Login(ctx context.Context, walletAddress, signature, nonce, message, origin string) (*AdminLoginResult, *RateLimitInfo, error) {
 	// Normalize wallet address
 	normalizedAddress := s.authService.NormalizeWalletAddress(walletAddress)
 
@@ -164,8 +186,14 @@ func (s *AdminAuthService) Login(ctx context.Context, walletAddress, signature,
 	}
 
 	// 3. Verify signature
-	signMessage := s.buildSignMessage(nonce)
-	if err := s.authService.VerifySignature(normalizedAddress, signature, signMessage); err != nil {
+	if err := s.authService.VerifyLogin(ctx, LoginProof{
+		WalletAddress: normalizedAddress,
+		Signature:     signature,
+		Nonce:         nonce,
+		Message:       message,
+		Origin:        origin,
+		LegacyMessage: s.buildSignMessage(nonce),
+	}); err != nil {
 		return nil, &RateLimitInfo{Remaining: remaining}, ErrInvalidCredentials
 	}
 
@@ -195,14 +223,89 @@ func (s *AdminAuthService) Login(ctx context.Context, walletAddress, signature,
 		// Log the error but don't fail the login
 	}
 
-	// 8. Generate JWT token
-	token, err := s.adminJwtUtil.GenerateToken(admin.ID, admin.WalletAddress, admin.Role)
+	// 8. Generate JWT and refresh tokens
+	result, err := s.issueTokens(ctx, admin, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// 9. Record the login, the request has no authenticated admin yet
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionAdminLogin,
+		EntityType: models.AuditEntityTypeAdmin,
+		EntityID:   admin.ID,
+		Actor:      &audit.Actor{Type: audit.ActorAdmin, ID: admin.ID},
+	})
+
+	return result, nil, nil
+}
+
+// Refresh exchanges a refresh token for new tokens carrying the current role of the admin.
+// Deactivated admins and sessions from before a token revocation cannot refresh.
+func (s *AdminAuthService) Refresh(ctx context.Context, refreshToken string) (*AdminLoginResult, error) {
+	session, newRefreshToken, err := s.tokenService.RotateRefreshToken(ctx, TokenAudienceAdmin, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	admin, err := s.adminRepo.GetByID(ctx, session.Subject)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		admin = nil
+	}
+
+	switch {
+	case admin == nil:
+		err = ErrAdminNotFound
+	case !admin.IsActive:
+		err = ErrAccountInactive
+	case admin.TokensRevokedAt != nil && session.IssuedAt.Before(admin.TokensRevokedAt.Truncate(time.Second)):
+		err = ErrInvalidRefreshToken
+	}
+	if err != nil {
+		_ = s.tokenService.RevokeRefreshToken(ctx, TokenAudienceAdmin, session.Subject, newRefreshToken)
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, admin, newRefreshToken)
+}
+
+// Logout revokes an access token and, when given, the refresh token of the admin's session.
+// Unknown refresh tokens are ignored so logging out twice succeeds.
+func (s *AdminAuthService) Logout(ctx context.Context, adminID, tokenID string, expiresAt time.Time, refreshToken string) error {
+	if err := s.tokenService.RevokeAccessToken(ctx, tokenID, expiresAt); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	if err := s.tokenService.RevokeRefreshToken(ctx, TokenAudienceAdmin, adminID, refreshToken); err != nil && !errors.Is(err, ErrInvalidRefreshToken) {
+		return err
+	}
+	return nil
+}
+
+// issueTokens generates an access token for the admin, with a new refresh token family unless
+// the refresh token of a rotation is given
+func (s *AdminAuthService) issueTokens(ctx context.Context, admin *models.AdminUser, refreshToken string) (*AdminLoginResult, error) {
+	token, err := s.adminJwtUtil.GenerateToken(admin.ID, admin.WalletAddress, admin.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if refreshToken == "" {
+		refreshToken, err = s.tokenService.IssueRefreshToken(ctx, TokenAudienceAdmin, admin.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &AdminLoginResult{
-		Token: token,
-		Admin: admin,
-	}, nil, nil
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.adminJwtUtil.TTL().Seconds()),
+		Admin:        admin,
+	}, nil
 }
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Errors for AdminUserService
+var (
+	ErrAdminAlreadyExists   = errors.New("admin with this wallet address already exists")
+	ErrInvalidAdminRole     = errors.New("invalid admin role")
+	ErrCannotModifySelf     = errors.New("admins cannot change their own role or status")
+	ErrLastSuperAdmin       = errors.New("the last active super admin cannot be demoted or deactivated")
+	ErrAdminAlreadyActive   = errors.New("admin is already active")
+	ErrAdminAlreadyInactive = errors.New("admin is already inactive")
+)
+
+// AdminUserServiceInterface defines the interface for managing admin users
+type AdminUserServiceInterface interface {
+	List(ctx context.Context, req *request.ListAdminUsersRequest) (*response.ListAdminUsersResponse, error)
+	Invite(ctx context.Context, actorID string, req *request.InviteAdminRequest) (*response.AdminUserResponse, error)
+	ChangeRole(ctx context.Context, actorID, adminID, role string) (*response.AdminUserResponse, error)
+	Deactivate(ctx context.Context, actorID, adminID string, reason *string) (*response.AdminUserResponse, error)
+	Reactivate(ctx context.Context, actorID, adminID string) (*response.AdminUserResponse, error)
+}
+
+// AdminUserService implements AdminUserServiceInterface
+type AdminUserService struct {
+	adminRepo repositories.AdminUserRepository
+	auditor   audit.Recorder
+}
+
+// NewAdminUserService creates a new AdminUserService instance
+func NewAdminUserService(adminRepo repositories.AdminUserRepository, auditor audit.Recorder) *AdminUserService {
+	return &AdminUserService{
+		adminRepo: adminRepo,
+		auditor:   auditor,
+	}
+}
+
+// List retrieves a paginated list of admin users
+func (s *AdminUserService) List(ctx context.Context, req *request.ListAdminUsersRequest) (*response.ListAdminUsersResponse, error) {
+	filter := repositories.AdminUserFilter{
+		Role:     req.Role,
+		IsActive: req.IsActive,
+		Page:     req.Page,
+		Limit:    req.Limit,
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+
+	admins, totalCount, err := s.adminRepo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin users: %w", err)
+	}
+
+	items := make([]response.AdminUserResponse, 0, len(admins))
+	for i := range admins {
+		items = append(items, *toAdminUserResponse(&admins[i]))
+	}
+
+	return &response.ListAdminUsersResponse{
+		Admins: items,
+		Pagination: response.PaginationMeta{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			TotalItems: totalCount,
+			TotalPages: int(math.Ceil(float64(totalCount) / float64(filter.Limit))),
+		},
+	}, nil
+}
+
+// Invite creates an active admin for a wallet address. The admin logs in with a wallet
+// signature like any other admin.
+func (s *AdminUserService) Invite(ctx context.Context, actorID string, req *request.InviteAdminRequest) (*response.AdminUserResponse, error) {
+	if !walletAddressRegex.MatchString(req.WalletAddress) {
+		return nil, ErrInvalidWalletAddress
+	}
+	if !models.IsValidAdminRole(req.Role) {
+		return nil, ErrInvalidAdminRole
+	}
+	walletAddress := strings.ToLower(req.WalletAddress)
+
+	if _, err := s.adminRepo.GetByWalletAddress(ctx, walletAddress); err == nil {
+		return nil, ErrAdminAlreadyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check admin: %w", err)
+	}
+
+	admin := &models.AdminUser{
+		WalletAddress: walletAddress,
+		Role:          req.Role,
+		IsActive:      true,
+		InvitedBy:     &actorID,
+	}
+	if err := s.adminRepo.Create(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	s.record(ctx, models.AuditActionInviteAdmin, admin.ID, nil, map[string]interface{}{
+		"wallet_address": admin.WalletAddress,
+		"role":           admin.Role,
+	})
+
+	return toAdminUserResponse(admin), nil
+}
+
+// ChangeRole changes the role of an admin. The new permissions apply to the next request.
+func (s *AdminUserService) ChangeRole(ctx context.Context, actorID, adminID, role string) (*response.AdminUserResponse, error) {
+	if !models.IsValidAdminRole(role) {
+		return nil, ErrInvalidAdminRole
+	}
+
+	admin, err := s.getManagedAdmin(ctx, actorID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin.Role == role {
+		return toAdminUserResponse(admin), nil
+	}
+	if err := s.ensureOtherSuperAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+
+	oldRole := admin.Role
+	admin.Role = role
+	if err := s.adminRepo.Update(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	s.record(ctx, models.AuditActionChangeAdminRole, admin.ID,
+		map[string]interface{}{"role": oldRole},
+		map[string]interface{}{"role": admin.Role})
+
+	return toAdminUserResponse(admin), nil
+}
+
+// Deactivate disables an admin and revokes every admin JWT issued to them so far
+func (s *AdminUserService) Deactivate(ctx context.Context, actorID, adminID string, reason *string) (*response.AdminUserResponse, error) {
+	admin, err := s.getManagedAdmin(ctx, actorID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if !admin.IsActive {
+		return nil, ErrAdminAlreadyInactive
+	}
+	if err := s.ensureOtherSuperAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	admin.IsActive = false
+	admin.DeactivatedAt = &now
+	admin.DeactivatedBy = &actorID
+	admin.DeactivationReason = reason
+	admin.TokensRevokedAt = &now
+	if err := s.adminRepo.Update(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	newValues := map[string]interface{}{"is_active": false}
+	if reason != nil {
+		newValues["deactivation_reason"] = *reason
+	}
+	s.record(ctx, models.AuditActionDeactivateAdmin, admin.ID,
+		map[string]interface{}{"is_active": true},
+		newValues)
+
+	return toAdminUserResponse(admin), nil
+}
+
+// Reactivate enables a deactivated admin. Tokens revoked by the deactivation stay revoked,
+// the admin has to log in again.
+func (s *AdminUserService) Reactivate(ctx context.Context, actorID, adminID string) (*response.AdminUserResponse, error) {
+	admin, err := s.getManagedAdmin(ctx, actorID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin.IsActive {
+		return nil, ErrAdminAlreadyActive
+	}
+
+	admin.IsActive = true
+	admin.DeactivatedAt = nil
+	admin.DeactivatedBy = nil
+	admin.DeactivationReason = nil
+	if err := s.adminRepo.Update(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	s.record(ctx, models.AuditActionReactivateAdmin, admin.ID,
+		map[string]interface{}{"is_active": false},
+		map[string]interface{}{"is_active": true})
+
+	return toAdminUserResponse(admin), nil
+}
+
+// getManagedAdmin loads the admin to change. Admins cannot change themselves, so a super admin
+// cannot lock themselves out by accident.
+func (s *AdminUserService) getManagedAdmin(ctx context.Context, actorID, adminID string) (*models.AdminUser, error) {
+	if actorID == adminID {
+		return nil, ErrCannotModifySelf
+	}
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAdminNotFound
+		}
+		return nil, fmt.Errorf("failed to get admin: %w", err)
+	}
+	return admin, nil
+}
+
+// ensureOtherSuperAdmin keeps at least one active super admin when a super admin loses the role
+// or is deactivated
+func (s *AdminUserService) ensureOtherSuperAdmin(ctx context.Context, admin *models.AdminUser) error {
+	if admin.Role != models.AdminRoleSuperAdmin || !admin.IsActive {
+		return nil
+	}
+	count, err := s.adminRepo.CountActiveByRole(ctx, models.AdminRoleSuperAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to count super admins: %w", err)
+	}
+	if count <= 1 {
+		return ErrLastSuperAdmin
+	}
+	return nil
+}
+
+// record creates an audit log entry for a change to an admin user
+func (s *AdminUserService) record(ctx context.Context, action, adminID string, oldValues, newValues map[string]interface{}) {
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     action,
+		EntityType: models.AuditEntityTypeAdmin,
+		EntityID:   adminID,
+		Before:     oldValues,
+		After:      newValues,
+	})
+}
+
+// toAdminUserResponse converts an admin user model to its response
+func toAdminUserResponse(admin *models.AdminUser) *response.AdminUserResponse {
+	return &response.AdminUserResponse{
+		ID:                 admin.ID,
+		WalletAddress:      admin.WalletAddress,
+		Role:               admin.Role,
+		IsActive:           admin.IsActive,
+		InvitedBy:          admin.InvitedBy,
+		LastLoginAt:        admin.LastLoginAt,
+		DeactivatedAt:      admin.DeactivatedAt,
+		DeactivatedBy:      admin.DeactivatedBy,
+		DeactivationReason: admin.DeactivationReason,
+		CreatedAt:          admin.CreatedAt,
+	}
+}
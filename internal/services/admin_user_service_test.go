@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryAdminRepo is an AdminUserRepository backed by a map of admins
+type memoryAdminRepo struct {
+	admins map[string]*models.AdminUser
+}
+
+func (m *memoryAdminRepo) GetByID(ctx context.Context, id string) (*models.AdminUser, error) {
+	admin, ok := m.admins[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *admin
+	return &copied, nil
+}
+
+func (m *memoryAdminRepo) GetByWalletAddress(ctx context.Context, walletAddress string) (*models.AdminUser, error) {
+	for _, admin := range m.admins {
+		if admin.WalletAddress == walletAddress {
+			return admin, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memoryAdminRepo) GetAll(ctx context.Context, filter repositories.AdminUserFilter) ([]models.AdminUser, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *memoryAdminRepo) CountActiveByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	for _, admin := range m.admins {
+		if admin.Role == role && admin.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryAdminRepo) Create(ctx context.Context, admin *models.AdminUser) error {
+	admin.ID = "admin-new"
+	m.admins[admin.ID] = admin
+	return nil
+}
+
+func (m *memoryAdminRepo) Update(ctx context.Context, admin *models.AdminUser) error {
+	m.admins[admin.ID] = admin
+	return nil
+}
+
+func (m *memoryAdminRepo) UpdateLastLogin(ctx context.Context, adminID string) error {
+	return nil
+}
+
+type memoryAuditLogRepo struct {
+	logs []models.AdminAuditLog
+}
+
+func (m *memoryAuditLogRepo) Create(log *models.AdminAuditLog) error {
+	m.logs = append(m.logs, *log)
+	return nil
+}
+
+func (m *memoryAuditLogRepo) Query(filter repositories.AuditLogFilter) ([]models.AdminAuditLog, error) {
+	return nil, nil
+}
+
+func TestAdminUserService_Deactivate(t *testing.T) {
+	ctx := context.Background()
+	newService := func(admins ...models.AdminUser) (*AdminUserService, *memoryAdminRepo, *memoryAuditLogRepo) {
+		repo := &memoryAdminRepo{admins: map[string]*models.AdminUser{}}
+		for i := range admins {
+			repo.admins[admins[i].ID] = &admins[i]
+		}
+		audit := &memoryAuditLogRepo{}
+		return NewAdminUserService(repo, NewAuditService(audit)), repo, audit
+	}
+
+	t.Run("revokes tokens and audits", func(t *testing.T) {
+		service, repo, audit := newService(
+			models.AdminUser{ID: "super", Role: models.AdminRoleSuperAdmin, IsActive: true},
+			models.AdminUser{ID: "reviewer", Role: models.AdminRoleReviewer, IsActive: true},
+		)
+		reason := "left the company"
+
+		resp, err := service.Deactivate(ctx, "super", "reviewer", &reason)
+		require.NoError(t, err)
+		assert.False(t, resp.IsActive)
+		assert.NotNil(t, repo.admins["reviewer"].TokensRevokedAt)
+		require.Len(t, audit.logs, 1)
+		assert.Equal(t, models.AuditActionDeactivateAdmin, audit.logs[0].Action)
+		assert.Equal(t, "system", audit.logs[0].ActorType)
+		assert.JSONEq(t, `{"is_active":false,"deactivation_reason":"left the company"}`, string(audit.logs[0].NewValues))
+
+		_, err = service.Deactivate(ctx, "super", "reviewer", nil)
+		assert.ErrorIs(t, err, ErrAdminAlreadyInactive)
+	})
+
+	t.Run("cannot deactivate self", func(t *testing.T) {
+		service, _, _ := newService(models.AdminUser{ID: "super", Role: models.AdminRoleSuperAdmin, IsActive: true})
+		_, err := service.Deactivate(ctx, "super", "super", nil)
+		assert.ErrorIs(t, err, ErrCannotModifySelf)
+	})
+
+	t.Run("keeps the last super admin", func(t *testing.T) {
+		service, _, _ := newService(
+			models.AdminUser{ID: "super", Role: models.AdminRoleSuperAdmin, IsActive: true},
+			models.AdminUser{ID: "other", Role: models.AdminRoleSuperAdmin, IsActive: false},
+		)
+		_, err := service.ChangeRole(ctx, "other", "super", models.AdminRoleReadOnly)
+		assert.ErrorIs(t, err, ErrLastSuperAdmin)
+		_, err = service.Deactivate(ctx, "other", "super", nil)
+		assert.ErrorIs(t, err, ErrLastSuperAdmin)
+	})
+}
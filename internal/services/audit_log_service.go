@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+)
+
+// Errors for AuditLogService
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidDateRange = errors.New("invalid date range, expected RFC3339 or YYYY-MM-DD with from before to")
+)
+
+const (
+	defaultAuditLogLimit = 50
+	auditExportBatchSize = 500
+)
+
+// auditLogCSVHeader is the column order of CSV exports
+var auditLogCSVHeader = []string{"id", "created_at", "actor_type", "actor_id", "admin_id", "action", "entity_type", "entity_id", "old_values", "new_values", "ip_address", "user_agent", "sequence", "prev_hash", "hash"}
+
+// AuditLogServiceInterface defines the interface for reading audit logs
+type AuditLogServiceInterface interface {
+	List(ctx context.Context, req *request.ListAuditLogsRequest) (*response.ListAuditLogsResponse, error)
+	Timeline(ctx context.Context, entityType, entityID string, req *request.AuditLogTimelineRequest) (*response.AuditLogTimelineResponse, error)
+	Export(ctx context.Context, adminID string, req *request.ExportAuditLogsRequest) (*AuditLogExport, error)
+}
+
+// AuditLogService implements AuditLogServiceInterface
+type AuditLogService struct {
+	auditLogRepo repositories.AuditLogRepository
+	auditor      audit.Recorder
+}
+
+// NewAuditLogService creates a new AuditLogService instance
+func NewAuditLogService(auditLogRepo repositories.AuditLogRepository, auditor audit.Recorder) *AuditLogService {
+	return &AuditLogService{auditLogRepo: auditLogRepo, auditor: auditor}
+}
+
+// List retrieves a page of audit logs, newest first
+func (s *AuditLogService) List(ctx context.Context, req *request.ListAuditLogsRequest) (*response.ListAuditLogsResponse, error) {
+	filter, err := toAuditLogFilter(&req.AuditLogQuery)
+	if err != nil {
+		return nil, err
+	}
+	if filter.After, err = decodeAuditCursor(req.Cursor); err != nil {
+		return nil, err
+	}
+
+	logs, nextCursor, err := s.page(filter, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &response.ListAuditLogsResponse{Logs: logs, NextCursor: nextCursor}, nil
+}
+
+// Timeline retrieves every action recorded on one entity, oldest first
+func (s *AuditLogService) Timeline(ctx context.Context, entityType, entityID string, req *request.AuditLogTimelineRequest) (*response.AuditLogTimelineResponse, error) {
+	after, err := decodeAuditCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	filter := repositories.AuditLogFilter{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Ascending:  true,
+		After:      after,
+	}
+
+	logs, nextCursor, err := s.page(filter, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &response.AuditLogTimelineResponse{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Logs:       logs,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// page loads one page and the cursor of the next one. One extra row is read to know whether
+// there is a next page.
+func (s *AuditLogService) page(filter repositories.AuditLogFilter, limit int) ([]response.AuditLogResponse, *string, error) {
+	if limit < 1 {
+		limit = defaultAuditLogLimit
+	}
+	filter.Limit = limit + 1
+
+	entries, err := s.auditLogRepo.Query(filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	var nextCursor *string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		cursor := encodeAuditCursor(&entries[limit-1])
+		nextCursor = &cursor
+	}
+
+	logs := make([]response.AuditLogResponse, 0, len(entries))
+	for i := range entries {
+		logs = append(logs, toAuditLogResponse(&entries[i]))
+	}
+	return logs, nextCursor, nil
+}
+
+// AuditLogExport streams the audit logs matching a filter as CSV or JSON
+type AuditLogExport struct {
+	Format string
+
+	filter       repositories.AuditLogFilter
+	auditLogRepo repositories.AuditLogRepository
+	onDone       func(rows int)
+}
+
+// Export validates the filter and returns an export to stream. The export itself is audit logged
+// once it has been written.
+func (s *AuditLogService) Export(ctx context.Context, adminID string, req *request.ExportAuditLogsRequest) (*AuditLogExport, error) {
+	filter, err := toAuditLogFilter(&req.AuditLogQuery)
+	if err != nil {
+		return nil, err
+	}
+	format := req.Format
+	if format == "" {
+		format = "csv"
+	}
+
+	return &AuditLogExport{
+		Format:       format,
+		filter:       filter,
+		auditLogRepo: s.auditLogRepo,
+		onDone: func(rows int) {
+			s.auditor.Record(ctx, audit.Entry{
+				Action:     models.AuditActionExportAuditLogs,
+				EntityType: models.AuditEntityTypeAdmin,
+				EntityID:   adminID,
+				After:      map[string]interface{}{"filter": req.AuditLogQuery, "format": format, "rows": rows},
+			})
+		},
+	}, nil
+}
+
+// ContentType returns the MIME type of the export
+func (e *AuditLogExport) ContentType() string {
+	if e.Format == "json" {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// FileName returns the suggested file name of the export
+func (e *AuditLogExport) FileName() string {
+	return fmt.Sprintf("audit-logs-%s.%s", time.Now().UTC().Format("20060102-150405"), e.Format)
+}
+
+// Write streams all matching entries, newest first, reading them in batches
+func (e *AuditLogExport) Write(ctx context.Context, w io.Writer) error {
+	var csvWriter *csv.Writer
+	if e.Format == "json" {
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+	} else {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(auditLogCSVHeader); err != nil {
+			return err
+		}
+	}
+
+	rows := 0
+	filter := e.filter
+	filter.Limit = auditExportBatchSize
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entries, err := e.auditLogRepo.Query(filter)
+		if err != nil {
+			return fmt.Errorf("failed to get audit logs: %w", err)
+		}
+
+		for i := range entries {
+			if csvWriter != nil {
+				err = csvWriter.Write(auditLogCSVRecord(&entries[i]))
+			} else {
+				err = writeAuditLogJSON(w, &entries[i], rows == 0)
+			}
+			if err != nil {
+				return err
+			}
+			rows++
+		}
+
+		if len(entries) < filter.Limit {
+			break
+		}
+		last := entries[len(entries)-1]
+		filter.After = &repositories.AuditLogCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return err
+		}
+	} else if _, err := io.WriteString(w, "]\n"); err != nil {
+		return err
+	}
+
+	e.onDone(rows)
+	return nil
+}
+
+// writeAuditLogJSON writes one entry of the JSON array
+func writeAuditLogJSON(w io.Writer, entry *models.AdminAuditLog, first bool) error {
+	data, err := json.Marshal(toAuditLogResponse(entry))
+	if err != nil {
+		return err
+	}
+	if !first {
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// auditLogCSVRecord converts an entry to a CSV row in auditLogCSVHeader order
+func auditLogCSVRecord(entry *models.AdminAuditLog) []string {
+	var actorID, adminID, ipAddress, userAgent, prevHash, hash string
+	if entry.ActorID != nil {
+		actorID = *entry.ActorID
+	}
+	if entry.AdminID != nil {
+		adminID = *entry.AdminID
+	}
+	if entry.PrevHash != nil {
+		prevHash = *entry.PrevHash
+	}
+	if entry.Hash != nil {
+		hash = *entry.Hash
+	}
+	if entry.IPAddress != nil {
+		ipAddress = *entry.IPAddress
+	}
+	if entry.UserAgent != nil {
+		userAgent = *entry.UserAgent
+	}
+	return []string{
+		entry.ID,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.ActorType,
+		actorID,
+		adminID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		string(entry.OldValues),
+		string(entry.NewValues),
+		ipAddress,
+		userAgent,
+		strconv.FormatInt(entry.Sequence, 10),
+		prevHash,
+		hash,
+	}
+}
+
+// toAuditLogFilter converts the query parameters to a repository filter
+func toAuditLogFilter(query *request.AuditLogQuery) (repositories.AuditLogFilter, error) {
+	filter := repositories.AuditLogFilter{
+		ActorType:  query.ActorType,
+		ActorID:    query.ActorID,
+		AdminID:    query.AdminID,
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+	}
+	for _, action := range query.Action {
+		for _, part := range strings.Split(action, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				filter.Actions = append(filter.Actions, part)
+			}
+		}
+	}
+
+	if query.From != "" {
+		from, _, err := parseAuditTime(query.From)
+		if err != nil {
+			return filter, ErrInvalidDateRange
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, dateOnly, err := parseAuditTime(query.To)
+		if err != nil {
+			return filter, ErrInvalidDateRange
+		}
+		// A date includes the whole day
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, ErrInvalidDateRange
+	}
+	return filter, nil
+}
+
+// parseAuditTime parses an RFC3339 time or a YYYY-MM-DD date
+func parseAuditTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// encodeAuditCursor returns the opaque cursor pointing after an entry
+func encodeAuditCursor(entry *models.AdminAuditLog) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(entry.CreatedAt.Format(time.RFC3339Nano) + "|" + entry.ID))
+}
+
+// decodeAuditCursor reverses encodeAuditCursor, an empty cursor starts at the first page
+func decodeAuditCursor(cursor string) (*repositories.AuditLogCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repositories.AuditLogCursor{CreatedAt: t, ID: id}, nil
+}
+
+// toAuditLogResponse converts an audit log model to its response
+func toAuditLogResponse(entry *models.AdminAuditLog) response.AuditLogResponse {
+	return response.AuditLogResponse{
+		ID:         entry.ID,
+		ActorType:  entry.ActorType,
+		ActorID:    entry.ActorID,
+		AdminID:    entry.AdminID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		OldValues:  entry.OldValues,
+		NewValues:  entry.NewValues,
+		IPAddress:  entry.IPAddress,
+		UserAgent:  entry.UserAgent,
+		CreatedAt:  entry.CreatedAt,
+		Sequence:   entry.Sequence,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+}
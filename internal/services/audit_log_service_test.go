@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditCursorRoundTrip(t *testing.T) {
+	entry := &models.AdminAuditLog{
+		ID:        "7f1c2b9e-3a41-4d7e-9a57-2f0c5d1e8b64",
+		CreatedAt: time.Date(2026, 3, 4, 5, 6, 7, 123456000, time.UTC),
+	}
+
+	cursor, err := decodeAuditCursor(encodeAuditCursor(entry))
+	require.NoError(t, err)
+	assert.Equal(t, entry.ID, cursor.ID)
+	assert.True(t, entry.CreatedAt.Equal(cursor.CreatedAt))
+
+	empty, err := decodeAuditCursor("")
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	for _, invalid := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "eHxpZA"} {
+		_, err := decodeAuditCursor(invalid)
+		assert.ErrorIs(t, err, ErrInvalidCursor, invalid)
+	}
+}
+
+func TestToAuditLogFilter(t *testing.T) {
+	t.Run("date only to includes the whole day", func(t *testing.T) {
+		filter, err := toAuditLogFilter(&request.AuditLogQuery{From: "2026-03-01", To: "2026-03-31"})
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+		assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), *filter.To)
+	})
+
+	t.Run("rfc3339 to is exclusive", func(t *testing.T) {
+		filter, err := toAuditLogFilter(&request.AuditLogQuery{To: "2026-03-31T12:00:00Z"})
+		require.NoError(t, err)
+		assert.Nil(t, filter.From)
+		assert.Equal(t, time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC), *filter.To)
+	})
+
+	t.Run("splits comma separated actions", func(t *testing.T) {
+		filter, err := toAuditLogFilter(&request.AuditLogQuery{Action: []string{"approve_invoice, reject_invoice", "suspend_farmer"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"approve_invoice", "reject_invoice", "suspend_farmer"}, filter.Actions)
+	})
+
+	t.Run("rejects invalid ranges", func(t *testing.T) {
+		for _, query := range []request.AuditLogQuery{
+			{From: "yesterday"},
+			{To: "2026-13-01"},
+			{From: "2026-03-02", To: "2026-03-01"},
+		} {
+			_, err := toAuditLogFilter(&query)
+			assert.ErrorIs(t, err, ErrInvalidDateRange)
+		}
+	})
+}
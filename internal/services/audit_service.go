@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+)
+
+// AuditService records audit entries with the actor and origin of the current request
+type AuditService struct {
+	auditLogRepo repositories.AuditLogRepository
+}
+
+// NewAuditService creates a new AuditService instance
+func NewAuditService(auditLogRepo repositories.AuditLogRepository) *AuditService {
+	return &AuditService{auditLogRepo: auditLogRepo}
+}
+
+// Record stores an audit entry. The actor comes from the entry, then from the request, and is the
+// system outside of requests.
+func (s *AuditService) Record(ctx context.Context, entry audit.Entry) {
+	// Log error but don't fail the main operation
+	if err := s.RecordRequired(ctx, entry); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
+
+// RecordRequired stores an audit entry like Record and returns the failure
+func (s *AuditService) RecordRequired(ctx context.Context, entry audit.Entry) error {
+	req := audit.FromContext(ctx)
+
+	actor := audit.Actor{Type: audit.ActorSystem}
+	if entry.Actor != nil {
+		actor = *entry.Actor
+	} else if req != nil {
+		if requestActor := req.Actor(); requestActor.Type != "" {
+			actor = requestActor
+		}
+	}
+
+	auditLog := &models.AdminAuditLog{
+		ActorType:  string(actor.Type),
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+	}
+	if actor.ID != "" {
+		actorID := actor.ID
+		auditLog.ActorID = &actorID
+		if actor.Type == audit.ActorAdmin {
+			auditLog.AdminID = &actorID
+		}
+	}
+
+	oldValues, newValues := audit.Diff(entry.Before, entry.After)
+	if oldValues != nil {
+		auditLog.OldValues, _ = json.Marshal(oldValues)
+	}
+	if newValues != nil {
+		auditLog.NewValues, _ = json.Marshal(newValues)
+	}
+
+	if req != nil {
+		if req.IPAddress != "" {
+			auditLog.IPAddress = &req.IPAddress
+		}
+		if req.UserAgent != "" {
+			auditLog.UserAgent = &req.UserAgent
+		}
+		req.MarkRecorded()
+	}
+
+	if err := s.auditLogRepo.Create(auditLog); err != nil {
+		return fmt.Errorf("failed to create audit log: %w", err)
+	}
+	return nil
+}
@@ -1,55 +1,259 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math/big"
+	"net/url"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/siwe"
 )
 
 var (
 	ErrInvalidSignature    = errors.New("invalid signature")
 	ErrInvalidWalletFormat = errors.New("invalid wallet address format")
 	ErrSignatureMismatch   = errors.New("signature does not match wallet address")
+	ErrInvalidLoginMessage = errors.New("invalid sign-in message")
+	ErrOriginNotAllowed    = errors.New("origin is not allowed to sign in")
 )
 
+// Login message formats
+const (
+	LoginMessageSIWE   = "siwe"
+	LoginMessageEIP712 = "eip712"
+)
+
+// Statements shown by the wallet when signing in to each app
+const (
+	LoginStatementInvestor = "Sign in to OwnaFarm."
+	LoginStatementFarmer   = "Sign in to OwnaFarm as Farmer."
+	LoginStatementAdmin    = "Sign in to OwnaFarm Admin."
+)
+
+// LoginMessageRequest holds what goes into the message a wallet signs to log in
+type LoginMessageRequest struct {
+	WalletAddress string
+	Nonce         string
+	Statement     string // Shown by the wallet in the SIWE prompt
+	Origin        string // Origin header of the request, empty outside browsers
+	LegacyMessage string // EIP-712 login text for the nonce
+}
+
+// LoginProof is a signed login message
+type LoginProof struct {
+	WalletAddress string
+	Signature     string
+	Nonce         string
+	Message       string // Signed SIWE message, empty for EIP-712 logins
+	Origin        string
+	LegacyMessage string
+}
+
 // AuthServiceInterface defines the interface for authentication operations
 type AuthServiceInterface interface {
 	VerifySignature(walletAddress, signature, message string) error
 	NormalizeWalletAddress(walletAddress string) string
+	BuildLoginMessage(req LoginMessageRequest) (string, error)
+	VerifyLogin(ctx context.Context, proof LoginProof) error
 }
 
 type AuthService struct {
-	eip712Name    string
-	eip712Version string
-	eip712ChainID *big.Int
+	blockchainService BlockchainService
+	loginMessage      string
+	siweDomains       []string
+	siweChainID       int64
+	siweTTL           time.Duration
+	eip712Name        string
+	eip712Version     string
+	eip712ChainID     *big.Int
 }
 
-func NewAuthService(cfg *config.AuthConfig) *AuthService {
+// NewAuthService creates an AuthService. Without a blockchain service only signatures of
+// externally owned accounts are accepted.
+func NewAuthService(cfg *config.AuthConfig, blockchainService BlockchainService) *AuthService {
 	return &AuthService{
-		eip712Name:    cfg.EIP712Name,
-		eip712Version: cfg.EIP712Version,
-		eip712ChainID: big.NewInt(cfg.EIP712ChainID),
+		blockchainService: blockchainService,
+		loginMessage:      cfg.LoginMessage,
+		siweDomains:       cfg.SIWEDomains,
+		siweChainID:       cfg.SIWEChainID,
+		siweTTL:           time.Duration(cfg.NonceTTLMinutes) * time.Minute,
+		eip712Name:        cfg.EIP712Name,
+		eip712Version:     cfg.EIP712Version,
+		eip712ChainID:     big.NewInt(cfg.EIP712ChainID),
+	}
+}
+
+// BuildLoginMessage returns the message the wallet signs for a nonce: a SIWE message for the site
+// of the request, or the legacy EIP-712 login text when configured.
+func (s *AuthService) BuildLoginMessage(req LoginMessageRequest) (string, error) {
+	if s.loginMessage == LoginMessageEIP712 {
+		return req.LegacyMessage, nil
+	}
+
+	domain, uri, err := s.siweSite(req.Origin)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	expirationTime := now.Add(s.siweTTL)
+	message := &siwe.Message{
+		Domain:         domain,
+		Address:        s.NormalizeWalletAddress(req.WalletAddress),
+		Statement:      req.Statement,
+		URI:            uri,
+		Version:        siwe.Version,
+		ChainID:        s.siweChainID,
+		Nonce:          req.Nonce,
+		IssuedAt:       now,
+		ExpirationTime: &expirationTime,
+	}
+	return message.String(), nil
+}
+
+// VerifyLogin checks a signed login. SIWE messages are always accepted; without one the legacy
+// EIP-712 signature of the login text is only accepted when configured.
+func (s *AuthService) VerifyLogin(ctx context.Context, proof LoginProof) error {
+	if proof.Message == "" {
+		if s.loginMessage != LoginMessageEIP712 {
+			return fmt.Errorf("%w: message is required", ErrInvalidLoginMessage)
+		}
+		return s.verifyTypedData(ctx, proof.WalletAddress, proof.Signature, proof.LegacyMessage)
+	}
+	if !common.IsHexAddress(proof.WalletAddress) {
+		return ErrInvalidWalletFormat
+	}
+
+	message, err := siwe.Parse(proof.Message)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLoginMessage, err)
+	}
+
+	// A message for another site was signed there and replayed here
+	if !s.siweDomainAllowed(message.Domain) {
+		return fmt.Errorf("%w: domain %s is not allowed", ErrInvalidLoginMessage, message.Domain)
+	}
+	if uri, err := url.Parse(message.URI); err != nil || uri.Host != message.Domain {
+		return fmt.Errorf("%w: uri does not match domain", ErrInvalidLoginMessage)
+	}
+	if proof.Origin != "" {
+		if origin, err := url.Parse(proof.Origin); err != nil || origin.Host != message.Domain {
+			return fmt.Errorf("%w: origin does not match domain", ErrInvalidLoginMessage)
+		}
+	}
+
+	if err := message.Verify(siwe.VerifyOptions{
+		Address: proof.WalletAddress,
+		ChainID: s.siweChainID,
+		Nonce:   proof.Nonce,
+		Now:     time.Now(),
+	}); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLoginMessage, err)
+	}
+
+	// SIWE messages are signed with personal_sign (EIP-191)
+	return s.verifyHash(ctx, proof.WalletAddress, proof.Signature, accounts.TextHash([]byte(proof.Message)))
+}
+
+// siweSite returns the domain and URI of the site that asks for a signature. Requests without an
+// Origin (mobile apps, scripts) get the default domain.
+func (s *AuthService) siweSite(origin string) (string, string, error) {
+	if origin == "" {
+		if len(s.siweDomains) == 0 {
+			return "", "", ErrOriginNotAllowed
+		}
+		return s.siweDomains[0], "https://" + s.siweDomains[0], nil
 	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || !s.siweDomainAllowed(u.Host) {
+		return "", "", ErrOriginNotAllowed
+	}
+	return u.Host, u.Scheme + "://" + u.Host, nil
+}
+
+func (s *AuthService) siweDomainAllowed(domain string) bool {
+	for _, allowed := range s.siweDomains {
+		if strings.EqualFold(allowed, domain) {
+			return true
+		}
+	}
+	return false
 }
 
+// VerifySignature verifies an EIP-712 signature of the legacy login text
 func (s *AuthService) VerifySignature(walletAddress, signature, message string) error {
+	return s.verifyTypedData(context.Background(), walletAddress, signature, message)
+}
+
+func (s *AuthService) verifyTypedData(ctx context.Context, walletAddress, signature, message string) error {
 	// Validate wallet address format
 	if !common.IsHexAddress(walletAddress) {
 		return ErrInvalidWalletFormat
 	}
 
+	// Build EIP-712 typed data hash
+	typedDataHash, err := s.buildTypedDataHash(message)
+	if err != nil {
+		return fmt.Errorf("failed to build typed data hash: %w", err)
+	}
+
+	return s.verifyHash(ctx, walletAddress, signature, typedDataHash)
+}
+
+// verifyHash checks that the signature of the hash was made by the wallet. Smart contract
+// wallets (Safe, account abstraction) have no key to recover, so when recovery fails the wallet
+// itself is asked through EIP-1271, or EIP-6492 if it is not deployed yet. Only wallets with
+// code or EIP-6492 signatures are asked; for accounts with a key the failed recovery is final.
+// A node failure during the contract check fails the login instead of erroring, so it cannot
+// be told apart from a wrong signature by the caller.
+func (s *AuthService) verifyHash(ctx context.Context, walletAddress, signature string, hash []byte) error {
 	// Decode signature
 	sigBytes := common.FromHex(signature)
-	if len(sigBytes) != 65 {
+
+	err := s.verifyECDSA(walletAddress, sigBytes, hash)
+	if err == nil || s.blockchainService == nil {
+		return err
+	}
+
+	if !bytes.HasSuffix(sigBytes, erc6492MagicSuffix) {
+		isContract, codeErr := s.blockchainService.IsContract(ctx, walletAddress)
+		if codeErr != nil {
+			log.Printf("[Auth] WARNING: failed to check contract wallet %s: %v", walletAddress, codeErr)
+			return ErrSignatureMismatch
+		}
+		if !isContract {
+			return err
+		}
+	}
+
+	valid, contractErr := s.blockchainService.IsValidSignature(ctx, walletAddress, [32]byte(hash), sigBytes)
+	if contractErr != nil {
+		log.Printf("[Auth] WARNING: failed to verify contract wallet signature of %s: %v", walletAddress, contractErr)
+		return ErrSignatureMismatch
+	}
+	if !valid {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// verifyECDSA checks that the signature of the hash was made by the key of the wallet
+func (s *AuthService) verifyECDSA(walletAddress string, signature, hash []byte) error {
+	if len(signature) != 65 {
 		return ErrInvalidSignature
 	}
+	sigBytes := append([]byte{}, signature...)
 
 	// Adjust recovery id (v) for Ethereum signatures
 	// Ethereum uses v = 27 or 28, but crypto.Ecrecover expects v = 0 or 1
@@ -57,14 +261,8 @@ func (s *AuthService) VerifySignature(walletAddress, signature, message string)
 		sigBytes[64] -= 27
 	}
 
-	// Build EIP-712 typed data hash
-	typedDataHash, err := s.buildTypedDataHash(message)
-	if err != nil {
-		return fmt.Errorf("failed to build typed data hash: %w", err)
-	}
-
 	// Recover public key from signature
-	pubKey, err := crypto.Ecrecover(typedDataHash, sigBytes)
+	pubKey, err := crypto.Ecrecover(hash, sigBytes)
 	if err != nil {
 		return ErrInvalidSignature
 	}
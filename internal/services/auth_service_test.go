@@ -1,9 +1,13 @@
 package services
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
 	"github.com/stretchr/testify/assert"
@@ -15,7 +19,7 @@ func TestAuthService_VerifySignature_InvalidWalletFormat(t *testing.T) {
 		EIP712Version: "1",
 		EIP712ChainID: 5003,
 	}
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, nil)
 
 	// Test with invalid wallet address format
 	err := authService.VerifySignature("invalid-wallet", "0x123", "test message")
@@ -28,7 +32,7 @@ func TestAuthService_VerifySignature_InvalidSignatureLength(t *testing.T) {
 		EIP712Version: "1",
 		EIP712ChainID: 5003,
 	}
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, nil)
 
 	walletAddress := "0x1234567890abcdef1234567890abcdef12345678"
 
@@ -47,7 +51,7 @@ func TestAuthService_NormalizeWalletAddress(t *testing.T) {
 		EIP712Version: "1",
 		EIP712ChainID: 5003,
 	}
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, nil)
 
 	tests := []struct {
 		name     string
@@ -85,7 +89,7 @@ func TestAuthService_BuildTypedDataHash(t *testing.T) {
 		EIP712Version: "1",
 		EIP712ChainID: 5003,
 	}
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, nil)
 
 	// Test that buildTypedDataHash returns consistent results
 	message := "Sign this message to login to OwnaFarm.\n\nNonce: abc123"
@@ -112,7 +116,7 @@ func TestAuthService_VerifySignature_Success(t *testing.T) {
 		EIP712Version: "1",
 		EIP712ChainID: 5003,
 	}
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, nil)
 
 	// Generate a new private key for testing
 	privateKey, err := crypto.GenerateKey()
@@ -149,7 +153,7 @@ func TestAuthService_VerifySignature_WrongWalletAddress(t *testing.T) {
 		EIP712Version: "1",
 		EIP712ChainID: 5003,
 	}
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, nil)
 
 	// Generate a private key for signing
 	privateKey, err := crypto.GenerateKey()
@@ -175,3 +179,84 @@ func TestAuthService_VerifySignature_WrongWalletAddress(t *testing.T) {
 	err = authService.VerifySignature(wrongWalletAddress, signatureHex, message)
 	assert.ErrorIs(t, err, ErrSignatureMismatch)
 }
+
+func TestAuthService_VerifyLogin_SIWE(t *testing.T) {
+	cfg := &config.AuthConfig{
+		NonceTTLMinutes: 5,
+		LoginMessage:    LoginMessageSIWE,
+		SIWEDomains:     []string{"app.ownafarm.com", "localhost:3000"},
+		SIWEChainID:     5000,
+	}
+	authService := NewAuthService(cfg, nil)
+	ctx := context.Background()
+
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	walletAddress := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	nonce := "a1b2c3d4e5f67890"
+
+	sign := func(message string) string {
+		signature, err := crypto.Sign(accounts.TextHash([]byte(message)), privateKey)
+		if err != nil {
+			t.Fatalf("failed to sign message: %v", err)
+		}
+		signature[64] += 27
+		return hexutil.Encode(signature)
+	}
+
+	message, err := authService.BuildLoginMessage(LoginMessageRequest{
+		WalletAddress: walletAddress,
+		Nonce:         nonce,
+		Statement:     LoginStatementInvestor,
+		Origin:        "https://app.ownafarm.com",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, message, "app.ownafarm.com wants you to sign in with your Ethereum account:\n"+walletAddress)
+	assert.Contains(t, message, "Chain ID: 5000")
+
+	proof := LoginProof{
+		WalletAddress: walletAddress,
+		Signature:     sign(message),
+		Nonce:         nonce,
+		Message:       message,
+		Origin:        "https://app.ownafarm.com",
+	}
+	assert.NoError(t, authService.VerifyLogin(ctx, proof))
+
+	// Signed on another of our sites but sent from this one
+	replayed := proof
+	replayed.Origin = "http://localhost:3000"
+	assert.ErrorIs(t, authService.VerifyLogin(ctx, replayed), ErrInvalidLoginMessage)
+
+	// A message for a nonce other than the one being redeemed
+	otherNonce := proof
+	otherNonce.Nonce = "0000000000000000"
+	assert.ErrorIs(t, authService.VerifyLogin(ctx, otherNonce), ErrInvalidLoginMessage)
+
+	// A message edited after signing
+	edited := proof
+	edited.Message = strings.Replace(message, "Sign in to OwnaFarm.", "Sign in.", 1)
+	assert.ErrorIs(t, authService.VerifyLogin(ctx, edited), ErrSignatureMismatch)
+
+	// A message for a site that is not ours
+	phishing := strings.Replace(message, "app.ownafarm.com", "ownafarm.phish", 2)
+	assert.ErrorIs(t, authService.VerifyLogin(ctx, LoginProof{
+		WalletAddress: walletAddress,
+		Signature:     sign(phishing),
+		Nonce:         nonce,
+		Message:       phishing,
+	}), ErrInvalidLoginMessage)
+
+	// EIP-712 logins without a message are only accepted in eip712 mode
+	proof.Message = ""
+	assert.ErrorIs(t, authService.VerifyLogin(ctx, proof), ErrInvalidLoginMessage)
+
+	_, err = authService.BuildLoginMessage(LoginMessageRequest{
+		WalletAddress: walletAddress,
+		Nonce:         nonce,
+		Origin:        "https://ownafarm.phish",
+	})
+	assert.ErrorIs(t, err, ErrOriginNotAllowed)
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -10,6 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
 )
@@ -27,8 +31,17 @@ type BlockchainService interface {
 	GetInvestmentCount(ctx context.Context, investor string) (uint64, error)
 	GetInvestment(ctx context.Context, investor string, investmentId uint64) (*OnchainInvestment, error)
 	GetInvoiceByTokenID(ctx context.Context, tokenId uint64) (*OnchainInvoice, error)
+	AnchorHash(ctx context.Context, hash [32]byte) (string, error)
+	IsValidSignature(ctx context.Context, wallet string, hash [32]byte, signature []byte) (bool, error)
+	IsContract(ctx context.Context, address string) (bool, error)
 }
 
+// ErrAnchorKeyNotConfigured is returned by AnchorHash when no anchor private key is configured
+var ErrAnchorKeyNotConfigured = errors.New("audit anchor private key is not configured")
+
+// anchorPrefix marks the calldata of audit log anchor transactions
+var anchorPrefix = []byte("ownafarm:audit:")
+
 // OnchainInvoice represents an invoice from the smart contract
 type OnchainInvoice struct {
 	Farmer       common.Address
@@ -41,10 +54,32 @@ type OnchainInvoice struct {
 	OfftakerId   [32]byte
 }
 
+// Invoice statuses of the smart contract
+const (
+	OnchainInvoiceStatusPending uint8 = iota
+	OnchainInvoiceStatusApproved
+	OnchainInvoiceStatusRejected
+	OnchainInvoiceStatusFunded
+	OnchainInvoiceStatusCompleted
+)
+
+// ethClient is the part of the node client used by the service
+type ethClient interface {
+	ethereum.ChainIDReader
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+	ethereum.GasPricer
+	ethereum.PendingStateReader
+	ethereum.TransactionSender
+}
+
 type blockchainService struct {
-	client     *ethclient.Client
+	client     ethClient
 	nftAddress common.Address
 	abi        abi.ABI
+	erc1271ABI abi.ABI
+	anchorKey  *ecdsa.PrivateKey
 }
 
 // OwnaFarmNFT ABI (minimal for reading investments)
@@ -102,16 +137,34 @@ func NewBlockchainService(cfg *config.BlockchainConfig) (BlockchainService, erro
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Mantle RPC: %w", err)
 	}
+	return newBlockchainService(client, cfg)
+}
 
+func newBlockchainService(client ethClient, cfg *config.BlockchainConfig) (*blockchainService, error) {
 	parsedABI, err := abi.JSON(strings.NewReader(OwnaFarmNFTABI))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse OwnaFarmNFT ABI: %w", err)
 	}
 
+	parsedERC1271ABI, err := abi.JSON(strings.NewReader(ERC1271ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC-1271 ABI: %w", err)
+	}
+
+	var anchorKey *ecdsa.PrivateKey
+	if cfg.AnchorKey != "" {
+		anchorKey, err = crypto.HexToECDSA(strings.TrimPrefix(cfg.AnchorKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse audit anchor private key: %w", err)
+		}
+	}
+
 	return &blockchainService{
 		client:     client,
 		nftAddress: common.HexToAddress(cfg.OwnaFarmNFTAddr),
 		abi:        parsedABI,
+		erc1271ABI: parsedERC1271ABI,
+		anchorKey:  anchorKey,
 	}, nil
 }
 
@@ -253,3 +306,48 @@ func (s *blockchainService) GetInvoiceByTokenID(ctx context.Context, tokenId uin
 		OfftakerId:   unpacked[7].([32]byte),
 	}, nil
 }
+
+// AnchorHash publishes a hash on-chain as the calldata of a zero value transaction from the
+// anchor account to itself, and returns the transaction hash
+func (s *blockchainService) AnchorHash(ctx context.Context, hash [32]byte) (string, error) {
+	if s.anchorKey == nil {
+		return "", ErrAnchorKeyNotConfigured
+	}
+	from := crypto.PubkeyToAddress(s.anchorKey.PublicKey)
+	data := append(append([]byte{}, anchorPrefix...), hash[:]...)
+
+	chainID, err := s.client.ChainID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	nonce, err := s.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get gas price: %w", err)
+	}
+	gas, err := s.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &from, Data: data})
+	if err != nil {
+		return "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gas,
+		To:       &from,
+		Value:    big.NewInt(0),
+		Data:     data,
+	})
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), s.anchorKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign anchor transaction: %w", err)
+	}
+	if err := s.client.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("failed to send anchor transaction: %w", err)
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ERC1271ABI is the signature validation interface of smart contract wallets (EIP-1271)
+const ERC1271ABI = `[
+	{
+		"inputs": [
+			{"internalType": "bytes32", "name": "hash", "type": "bytes32"},
+			{"internalType": "bytes", "name": "signature", "type": "bytes"}
+		],
+		"name": "isValidSignature",
+		"outputs": [{"internalType": "bytes4", "name": "magicValue", "type": "bytes4"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// erc1271MagicValue is returned by isValidSignature for a valid signature
+var erc1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+// erc6492MagicSuffix ends the signatures of wallets that are not deployed yet (EIP-6492).
+// Such a signature is abi.encode(factory, factoryCalldata, signature) followed by the suffix.
+var erc6492MagicSuffix = common.FromHex("0x6492649264926492649264926492649264926492649264926492649264926492")
+
+var erc6492Arguments = abi.Arguments{
+	{Type: mustNewABIType("address")},
+	{Type: mustNewABIType("bytes")},
+	{Type: mustNewABIType("bytes")},
+}
+
+func mustNewABIType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// IsValidSignature asks a smart contract wallet whether it signed the hash (EIP-1271). Signatures
+// of counterfactual wallets (EIP-6492) are checked by deploying the wallet through its factory in
+// the same call, without sending a transaction. Addresses without code are never valid.
+func (s *blockchainService) IsValidSignature(ctx context.Context, wallet string, hash [32]byte, signature []byte) (bool, error) {
+	var factory common.Address
+	var factoryCalldata []byte
+	if bytes.HasSuffix(signature, erc6492MagicSuffix) {
+		unpacked, err := erc6492Arguments.Unpack(signature[:len(signature)-len(erc6492MagicSuffix)])
+		if err != nil {
+			return false, nil
+		}
+		factory = unpacked[0].(common.Address)
+		factoryCalldata = unpacked[1].([]byte)
+		signature = unpacked[2].([]byte)
+	}
+
+	calldata, err := s.erc1271ABI.Pack("isValidSignature", hash, signature)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack isValidSignature call: %w", err)
+	}
+
+	// The validator runs as contract creation code, so the call needs no deployed helper contract
+	result, err := s.client.CallContract(ctx, ethereum.CallMsg{
+		Data: signatureValidatorCode(factory, factoryCalldata, common.HexToAddress(wallet), calldata),
+	}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call isValidSignature: %w", err)
+	}
+
+	return len(result) == 32 && bytes.Equal(result[:4], erc1271MagicValue), nil
+}
+
+// IsContract reports whether code is deployed at the address, i.e. whether it is a smart
+// contract wallet rather than an account with a key
+func (s *blockchainService) IsContract(ctx context.Context, address string) (bool, error) {
+	code, err := s.client.CodeAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code: %w", err)
+	}
+	return len(code) > 0, nil
+}
+
+// signatureValidatorCode returns creation code that calls the factory with its calldata when a
+// factory is given, then static calls the wallet with the isValidSignature calldata and returns
+// the 32 byte result, or nothing when the wallet call fails or returns something else. A failing
+// factory call is ignored since the wallet may already be deployed.
+func signatureValidatorCode(factory common.Address, factoryCalldata []byte, wallet common.Address, calldata []byte) []byte {
+	const codeSize = 104
+
+	var code []byte
+	push1 := func(v byte) { code = append(code, 0x60, v) }
+	push2 := func(v int) { code = binary.BigEndian.AppendUint16(append(code, 0x61), uint16(v)) }
+	push20 := func(a common.Address) { code = append(append(code, 0x73), a.Bytes()...) }
+	op := func(ops ...byte) { code = append(code, ops...) }
+
+	// Copy the factory calldata to memory and call the factory
+	push2(len(factoryCalldata))
+	push2(codeSize)
+	push1(0)
+	op(0x39) // CODECOPY
+	push1(0)
+	push1(0)
+	push2(len(factoryCalldata))
+	push1(0)
+	push1(0)
+	push20(factory)
+	op(0x5a, 0xf1, 0x50) // GAS CALL POP
+
+	// Copy the isValidSignature calldata to memory and static call the wallet
+	push2(len(calldata))
+	push2(codeSize + len(factoryCalldata))
+	push1(0)
+	op(0x39) // CODECOPY
+	push1(32)
+	push1(0)
+	push2(len(calldata))
+	push1(0)
+	push20(wallet)
+	op(0x5a, 0xfa) // GAS STATICCALL
+
+	// Return the result when the call succeeded with 32 bytes
+	op(0x3d) // RETURNDATASIZE
+	push1(32)
+	op(0x14, 0x16) // EQ AND
+	push1(codeSize - 6)
+	op(0x57) // JUMPI
+	push1(0)
+	push1(0)
+	op(0xf3) // RETURN
+	op(0x5b) // JUMPDEST
+	push1(32)
+	push1(0)
+	op(0xf3) // RETURN
+
+	if len(code) != codeSize {
+		panic("signature validator code size changed")
+	}
+	return append(append(code, factoryCalldata...), calldata...)
+}
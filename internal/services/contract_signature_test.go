@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// simulatedChain is a simulated backend with a funded account for deploying contracts
+type simulatedChain struct {
+	t        *testing.T
+	backend  *simulated.Backend
+	deployer *ecdsa.PrivateKey
+}
+
+func newSimulatedChain(t *testing.T) *simulatedChain {
+	deployer, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		crypto.PubkeyToAddress(deployer.PublicKey): {Balance: big.NewInt(params.Ether)},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	return &simulatedChain{t: t, backend: backend, deployer: deployer}
+}
+
+// deploy sends a contract creation transaction and returns the contract address
+func (c *simulatedChain) deploy(initCode []byte) common.Address {
+	ctx := context.Background()
+	client := c.backend.Client()
+	from := crypto.PubkeyToAddress(c.deployer.PublicKey)
+
+	nonce, err := client.PendingNonceAt(ctx, from)
+	require.NoError(c.t, err)
+	chainID, err := client.ChainID(ctx)
+	require.NoError(c.t, err)
+
+	tx := types.NewContractCreation(nonce, big.NewInt(0), 1_000_000, big.NewInt(params.GWei), initCode)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), c.deployer)
+	require.NoError(c.t, err)
+	require.NoError(c.t, client.SendTransaction(ctx, signedTx))
+	c.backend.Commit()
+
+	return crypto.CreateAddress(from, nonce)
+}
+
+// mockWalletInitCode returns creation code of an EIP-1271 wallet that accepts ECDSA signatures
+// of its owner, like a Safe with a single owner
+func mockWalletInitCode(owner common.Address) []byte {
+	magicValue := new(big.Int).Lsh(new(big.Int).SetBytes(erc1271MagicValue), 224)
+
+	runtime := program.New().
+		// ecrecover(hash, v, r, s) with the 65 byte signature at calldata offset 100
+		Push(4).Op(vm.CALLDATALOAD).Push(0).Op(vm.MSTORE).
+		Push(164).Op(vm.CALLDATALOAD).Push(248).Op(vm.SHR).Push(32).Op(vm.MSTORE).
+		Push(100).Op(vm.CALLDATALOAD).Push(64).Op(vm.MSTORE).
+		Push(132).Op(vm.CALLDATALOAD).Push(96).Op(vm.MSTORE).
+		StaticCall(nil, 1, 0, 128, 0, 32).Op(vm.POP).
+		// Return the magic value when the signer is the owner, zero otherwise
+		Push(0).Op(vm.MLOAD).Push(owner).Op(vm.EQ).
+		Push(magicValue).Op(vm.MUL).Push(0).Op(vm.MSTORE).
+		Return(0, 32).
+		Bytes()
+
+	return program.New().ReturnViaCodeCopy(runtime).Bytes()
+}
+
+// mockFactoryInitCode returns creation code of a factory that deploys the wallet with CREATE2
+// on any call
+func mockFactoryInitCode(walletInitCode []byte) []byte {
+	runtime := program.New().Create2(walletInitCode, 0).Op(vm.POP, vm.STOP).Bytes()
+	return program.New().ReturnViaCodeCopy(runtime).Bytes()
+}
+
+func signHash(t *testing.T, hash []byte, key *ecdsa.PrivateKey) []byte {
+	signature, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+	signature[64] += 27
+	return signature
+}
+
+func TestBlockchainService_IsValidSignature_ERC1271(t *testing.T) {
+	chain := newSimulatedChain(t)
+	service, err := newBlockchainService(chain.backend.Client(), &config.BlockchainConfig{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	owner, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := chain.deploy(mockWalletInitCode(crypto.PubkeyToAddress(owner.PublicKey)))
+
+	hash := crypto.Keccak256Hash([]byte("Sign in to OwnaFarm."))
+
+	valid, err := service.IsValidSignature(ctx, wallet.Hex(), hash, signHash(t, hash[:], owner))
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = service.IsValidSignature(ctx, wallet.Hex(), hash, signHash(t, hash[:], other))
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	// Accounts without code cannot validate signatures
+	valid, err = service.IsValidSignature(ctx, crypto.PubkeyToAddress(owner.PublicKey).Hex(), hash, signHash(t, hash[:], owner))
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestBlockchainService_IsValidSignature_ERC6492(t *testing.T) {
+	chain := newSimulatedChain(t)
+	service, err := newBlockchainService(chain.backend.Client(), &config.BlockchainConfig{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	owner, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	walletInitCode := mockWalletInitCode(crypto.PubkeyToAddress(owner.PublicKey))
+	factory := chain.deploy(mockFactoryInitCode(walletInitCode))
+	wallet := crypto.CreateAddress2(factory, [32]byte{}, crypto.Keccak256(walletInitCode))
+
+	hash := crypto.Keccak256Hash([]byte("Sign in to OwnaFarm."))
+	innerSignature := signHash(t, hash[:], owner)
+	wrapped, err := erc6492Arguments.Pack(factory, []byte{0x01}, innerSignature)
+	require.NoError(t, err)
+	signature := append(wrapped, erc6492MagicSuffix...)
+
+	valid, err := service.IsValidSignature(ctx, wallet.Hex(), hash, signature)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Checking the signature does not deploy the wallet
+	code, err := chain.backend.Client().CodeAt(ctx, wallet, nil)
+	require.NoError(t, err)
+	assert.Empty(t, code)
+
+	// Without the factory the undeployed wallet cannot validate
+	valid, err = service.IsValidSignature(ctx, wallet.Hex(), hash, innerSignature)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	// Once deployed, wrapped signatures keep validating
+	chain.deploy(program.New().Call(nil, factory, 0, 0, 0, 0, 0).Op(vm.STOP).Bytes())
+
+	valid, err = service.IsValidSignature(ctx, wallet.Hex(), hash, signature)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = service.IsValidSignature(ctx, wallet.Hex(), hash, innerSignature)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestAuthService_VerifyLogin_SmartContractWallet(t *testing.T) {
+	chain := newSimulatedChain(t)
+	blockchainService, err := newBlockchainService(chain.backend.Client(), &config.BlockchainConfig{})
+	require.NoError(t, err)
+	authService := NewAuthService(&config.AuthConfig{
+		NonceTTLMinutes: 5,
+		LoginMessage:    LoginMessageSIWE,
+		SIWEDomains:     []string{"app.ownafarm.com"},
+		SIWEChainID:     5000,
+	}, blockchainService)
+	ctx := context.Background()
+
+	owner, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := chain.deploy(mockWalletInitCode(crypto.PubkeyToAddress(owner.PublicKey)))
+
+	nonce := "a1b2c3d4e5f67890"
+	message, err := authService.BuildLoginMessage(LoginMessageRequest{
+		WalletAddress: wallet.Hex(),
+		Nonce:         nonce,
+		Statement:     LoginStatementInvestor,
+	})
+	require.NoError(t, err)
+
+	proof := LoginProof{
+		WalletAddress: wallet.Hex(),
+		Signature:     hexutil.Encode(signHash(t, accounts.TextHash([]byte(message)), owner)),
+		Nonce:         nonce,
+		Message:       message,
+	}
+	assert.NoError(t, authService.VerifyLogin(ctx, proof))
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	proof.Signature = hexutil.Encode(signHash(t, accounts.TextHash([]byte(message)), other))
+	assert.ErrorIs(t, authService.VerifyLogin(ctx, proof), ErrSignatureMismatch)
+}
+
+// stubSignatureChain answers the contract wallet checks of AuthService and counts them
+type stubSignatureChain struct {
+	BlockchainService
+	isContract     bool
+	codeErr        error
+	validErr       error
+	codeChecks     int
+	signatureCalls int
+}
+
+func (s *stubSignatureChain) IsContract(ctx context.Context, address string) (bool, error) {
+	s.codeChecks++
+	return s.isContract, s.codeErr
+}
+
+func (s *stubSignatureChain) IsValidSignature(ctx context.Context, wallet string, hash [32]byte, signature []byte) (bool, error) {
+	s.signatureCalls++
+	return false, s.validErr
+}
+
+func TestAuthService_VerifyHash_ContractFallback(t *testing.T) {
+	owner, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	wallet := crypto.PubkeyToAddress(owner.PublicKey).Hex()
+	hash := crypto.Keccak256([]byte("Sign in to OwnaFarm."))
+	wrongSignature := hexutil.Encode(signHash(t, hash, other))
+	ctx := context.Background()
+
+	t.Run("accounts without code are not asked", func(t *testing.T) {
+		chain := &stubSignatureChain{}
+		err := NewAuthService(&config.AuthConfig{}, chain).verifyHash(ctx, wallet, wrongSignature, hash)
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+		assert.Equal(t, 1, chain.codeChecks)
+		assert.Zero(t, chain.signatureCalls)
+	})
+
+	t.Run("valid key signatures need no node", func(t *testing.T) {
+		chain := &stubSignatureChain{}
+		err := NewAuthService(&config.AuthConfig{}, chain).verifyHash(ctx, wallet, hexutil.Encode(signHash(t, hash, owner)), hash)
+		assert.NoError(t, err)
+		assert.Zero(t, chain.codeChecks)
+	})
+
+	t.Run("contract wallets are asked", func(t *testing.T) {
+		chain := &stubSignatureChain{isContract: true}
+		err := NewAuthService(&config.AuthConfig{}, chain).verifyHash(ctx, wallet, wrongSignature, hash)
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+		assert.Equal(t, 1, chain.signatureCalls)
+	})
+
+	t.Run("EIP-6492 signatures are asked without a code check", func(t *testing.T) {
+		chain := &stubSignatureChain{}
+		signature := hexutil.Encode(append(common.FromHex(wrongSignature), erc6492MagicSuffix...))
+		err := NewAuthService(&config.AuthConfig{}, chain).verifyHash(ctx, wallet, signature, hash)
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+		assert.Zero(t, chain.codeChecks)
+		assert.Equal(t, 1, chain.signatureCalls)
+	})
+
+	t.Run("node failures fail the login", func(t *testing.T) {
+		chain := &stubSignatureChain{codeErr: errors.New("rpc unavailable")}
+		err := NewAuthService(&config.AuthConfig{}, chain).verifyHash(ctx, wallet, wrongSignature, hash)
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+		chain = &stubSignatureChain{isContract: true, validErr: errors.New("rpc unavailable")}
+		err = NewAuthService(&config.AuthConfig{}, chain).verifyHash(ctx, wallet, wrongSignature, hash)
+		assert.ErrorIs(t, err, ErrSignatureMismatch)
+	})
+}
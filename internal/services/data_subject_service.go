@@ -0,0 +1,407 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Errors for DataSubjectService
+var (
+	ErrUserNotFound             = errors.New("user not found")
+	ErrAlreadyErased            = errors.New("personal data has already been erased")
+	ErrErasurePendingPayouts    = errors.New("farmer cannot be erased while payouts are pending")
+	ErrErasureActiveInvestments = errors.New("investor cannot be erased while investments are not harvested")
+)
+
+// Data subject types used in exports and erasure responses
+const (
+	DataSubjectFarmer = "farmer"
+	DataSubjectUser   = "user"
+)
+
+// DataSubjectServiceInterface defines the interface for data subject requests
+type DataSubjectServiceInterface interface {
+	ExportFarmer(ctx context.Context, farmerID, adminID string) (*DataExport, error)
+	ExportUser(ctx context.Context, userID, adminID string) (*DataExport, error)
+	EraseFarmer(ctx context.Context, farmerID, adminID, reason string) (*response.ErasureResponse, error)
+	EraseUser(ctx context.Context, userID, adminID, reason string) (*response.ErasureResponse, error)
+}
+
+// DataSubjectService implements DataSubjectServiceInterface
+type DataSubjectService struct {
+	dataSubjectRepo repositories.DataSubjectRepository
+	invoiceRepo     repositories.InvoiceRepository
+	storageService  StorageService
+	auditor         audit.Recorder
+}
+
+// NewDataSubjectService creates a new DataSubjectService instance
+func NewDataSubjectService(
+	dataSubjectRepo repositories.DataSubjectRepository,
+	invoiceRepo repositories.InvoiceRepository,
+	storageService StorageService,
+	auditor audit.Recorder,
+) *DataSubjectService {
+	return &DataSubjectService{
+		dataSubjectRepo: dataSubjectRepo,
+		invoiceRepo:     invoiceRepo,
+		storageService:  storageService,
+		auditor:         auditor,
+	}
+}
+
+// DataExport is the content of a data subject export. The JSON files are built up front, the
+// documents are streamed from storage while the zip is written.
+type DataExport struct {
+	SubjectType string
+	SubjectID   string
+	GeneratedAt time.Time
+
+	files     []exportFile
+	documents []models.FarmerDocument
+	storage   StorageService
+}
+
+type exportFile struct {
+	name  string
+	value interface{}
+}
+
+// exportManifest describes the content of an export archive
+type exportManifest struct {
+	SubjectType      string    `json:"subject_type"`
+	SubjectID        string    `json:"subject_id"`
+	GeneratedAt      time.Time `json:"generated_at"`
+	Files            []string  `json:"files"`
+	MissingDocuments []string  `json:"missing_documents,omitempty"`
+}
+
+// FileName returns the suggested file name of the archive
+func (e *DataExport) FileName() string {
+	return fmt.Sprintf("%s-%s-export.zip", e.SubjectType, e.SubjectID)
+}
+
+// WriteZip writes the export as a zip archive. Documents missing from storage are listed in
+// manifest.json instead of failing the export.
+func (e *DataExport) WriteZip(ctx context.Context, w io.Writer) error {
+	archive := zip.NewWriter(w)
+	manifest := exportManifest{SubjectType: e.SubjectType, SubjectID: e.SubjectID, GeneratedAt: e.GeneratedAt}
+
+	for _, file := range e.files {
+		if err := writeZipJSON(archive, file.name, file.value); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, file.name)
+	}
+
+	for _, document := range e.documents {
+		name := fmt.Sprintf("documents/%s-%s", document.DocumentType, document.ID)
+		if document.FileName != nil {
+			name += path.Ext(*document.FileName)
+		}
+		err := e.copyDocument(ctx, archive, name, document.FileURL)
+		if errors.Is(err, ErrObjectNotFound) {
+			manifest.MissingDocuments = append(manifest.MissingDocuments, document.ID)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export document %s: %w", document.ID, err)
+		}
+		manifest.Files = append(manifest.Files, name)
+	}
+
+	if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// copyDocument streams a stored document into the archive
+func (e *DataExport) copyDocument(ctx context.Context, archive *zip.Writer, name, key string) error {
+	body, err := e.storage.Download(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, body)
+	return err
+}
+
+// writeZipJSON adds an indented JSON file to the archive
+func writeZipJSON(archive *zip.Writer, name string, value interface{}) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// ExportFarmer collects everything tied to a farmer: profile, documents, farms, invoices,
+// submissions, profile changes, notifications and audit entries
+func (s *DataSubjectService) ExportFarmer(ctx context.Context, farmerID, adminID string) (*DataExport, error) {
+	data, err := s.dataSubjectRepo.GetFarmerData(farmerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFarmerNotFound
+		}
+		return nil, fmt.Errorf("failed to get farmer data: %w", err)
+	}
+
+	// PII copied into JSON columns is sealed, the subject gets the plaintext
+	for i := range data.Submissions {
+		if data.Submissions[i].Snapshot, err = openJSONFields(data.Submissions[i].Snapshot); err != nil {
+			return nil, err
+		}
+	}
+	for i := range data.ProfileChanges {
+		if data.ProfileChanges[i].OldValues, err = openJSONFields(data.ProfileChanges[i].OldValues); err != nil {
+			return nil, err
+		}
+		if data.ProfileChanges[i].NewValues, err = openJSONFields(data.ProfileChanges[i].NewValues); err != nil {
+			return nil, err
+		}
+	}
+
+	export := &DataExport{
+		SubjectType: DataSubjectFarmer,
+		SubjectID:   farmerID,
+		GeneratedAt: time.Now(),
+		files: []exportFile{
+			{"profile.json", data.Farmer},
+			{"farms.json", data.Farms},
+			{"invoices.json", data.Invoices},
+			{"submissions.json", data.Submissions},
+			{"profile_changes.json", data.ProfileChanges},
+			{"notifications.json", data.Notifications},
+			{"audit_logs.json", data.AuditLogs},
+		},
+		documents: data.Farmer.Documents,
+		storage:   s.storageService,
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionExportFarmer,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmerID,
+		After:      map[string]interface{}{"documents": len(data.Farmer.Documents)},
+	})
+
+	return export, nil
+}
+
+// ExportUser collects everything tied to an investor: profile, investments, notifications,
+// achievements, game activity and audit entries
+func (s *DataSubjectService) ExportUser(ctx context.Context, userID, adminID string) (*DataExport, error) {
+	data, err := s.dataSubjectRepo.GetUserData(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user data: %w", err)
+	}
+
+	export := &DataExport{
+		SubjectType: DataSubjectUser,
+		SubjectID:   userID,
+		GeneratedAt: time.Now(),
+		files: []exportFile{
+			{"profile.json", data.User},
+			{"investments.json", data.Investments},
+			{"notifications.json", data.Notifications},
+			{"achievements.json", data.Achievements},
+			{"activity.json", data.Activity},
+			{"audit_logs.json", data.AuditLogs},
+		},
+		storage: s.storageService,
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionExportUser,
+		EntityType: models.AuditEntityTypeUser,
+		EntityID:   userID,
+	})
+
+	return export, nil
+}
+
+// EraseFarmer anonymizes the personal data of a farmer and deletes the identity documents.
+// Farms, invoices and audit logs are kept; the farmer cannot log in afterwards.
+func (s *DataSubjectService) EraseFarmer(ctx context.Context, farmerID, adminID, reason string) (*response.ErasureResponse, error) {
+	data, err := s.dataSubjectRepo.GetFarmerData(farmerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFarmerNotFound
+		}
+		return nil, fmt.Errorf("failed to get farmer data: %w", err)
+	}
+	farmer := data.Farmer
+
+	if farmer.Status == models.FarmerStatusErased {
+		return nil, ErrAlreadyErased
+	}
+	if !farmer.Status.CanTransitionTo(models.FarmerStatusErased) {
+		return nil, ErrInvalidStatusTransition
+	}
+
+	pending, err := s.invoiceRepo.HasPendingPayoutsByFarmerID(farmerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending payouts: %w", err)
+	}
+	if pending {
+		return nil, ErrErasurePendingPayouts
+	}
+
+	// Invoice files belong to the financial records and stay in storage
+	var erasedDocuments []models.FarmerDocument
+	for _, document := range farmer.Documents {
+		if document.DocumentType != models.DocumentTypeInvoiceFile {
+			erasedDocuments = append(erasedDocuments, document)
+		}
+	}
+
+	oldStatus := string(farmer.Status)
+	now := time.Now()
+	anonymizeFarmer(farmer)
+	farmer.Status = models.FarmerStatusErased
+	farmer.ErasedBy = &adminID
+	farmer.ErasedAt = &now
+
+	if err := s.dataSubjectRepo.EraseFarmer(farmer); err != nil {
+		return nil, fmt.Errorf("failed to erase farmer: %w", err)
+	}
+
+	// Objects are removed after the commit; a leftover object is only unreferenced
+	for _, document := range erasedDocuments {
+		if err := s.storageService.Delete(ctx, document.FileURL); err != nil {
+			fmt.Printf("failed to delete document %s from storage: %v\n", document.ID, err)
+		}
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionEraseFarmer,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmerID,
+		Before:     map[string]interface{}{"status": oldStatus},
+		After: map[string]interface{}{
+			"status":            farmer.Status,
+			"reason":            reason,
+			"deleted_documents": len(erasedDocuments),
+		},
+	})
+
+	return &response.ErasureResponse{
+		SubjectType:      DataSubjectFarmer,
+		SubjectID:        farmerID,
+		ErasedBy:         adminID,
+		ErasedAt:         now,
+		DeletedDocuments: len(erasedDocuments),
+	}, nil
+}
+
+// EraseUser removes the profile data of an investor. The wallet address and investments are
+// kept for the financial records.
+func (s *DataSubjectService) EraseUser(ctx context.Context, userID, adminID, reason string) (*response.ErasureResponse, error) {
+	data, err := s.dataSubjectRepo.GetUserData(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user data: %w", err)
+	}
+	user := data.User
+
+	if user.ErasedAt != nil {
+		return nil, ErrAlreadyErased
+	}
+
+	active, err := s.dataSubjectRepo.HasActiveInvestments(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check investments: %w", err)
+	}
+	if active {
+		return nil, ErrErasureActiveInvestments
+	}
+
+	now := time.Now()
+	user.ErasedBy = &adminID
+	user.ErasedAt = &now
+	if err := s.dataSubjectRepo.EraseUser(user); err != nil {
+		return nil, fmt.Errorf("failed to erase user: %w", err)
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionEraseUser,
+		EntityType: models.AuditEntityTypeUser,
+		EntityID:   userID,
+		After:      map[string]interface{}{"reason": reason},
+	})
+
+	return &response.ErasureResponse{
+		SubjectType: DataSubjectUser,
+		SubjectID:   userID,
+		ErasedBy:    adminID,
+		ErasedAt:    now,
+	}, nil
+}
+
+// anonymizeFarmer replaces the personal data of a farmer. Required columns get a placeholder,
+// optional ones are cleared. The wallet address stays because it identifies the on-chain records.
+func anonymizeFarmer(farmer *models.Farmer) {
+	farmer.FullName = repositories.ErasedPlaceholder
+	farmer.Email = repositories.ErasedPlaceholder
+	farmer.PhoneNumber = repositories.ErasedPlaceholder
+	farmer.IDNumber = ""
+	farmer.DateOfBirth = time.Time{}
+	farmer.Address = repositories.ErasedPlaceholder
+	farmer.Province = repositories.ErasedPlaceholder
+	farmer.City = repositories.ErasedPlaceholder
+	farmer.District = repositories.ErasedPlaceholder
+	farmer.PostalCode = repositories.ErasedPlaceholder
+	farmer.BusinessName = nil
+	farmer.NPWP = nil
+	farmer.BankName = repositories.ErasedPlaceholder
+	farmer.BankAccountNumber = ""
+	farmer.BankAccountName = repositories.ErasedPlaceholder
+	farmer.CropsExpertise = nil
+	farmer.RejectionReason = nil
+	farmer.SuspensionReason = nil
+	farmer.Documents = nil
+}
+
+// openJSONFields decrypts the farmer PII sealed in a JSON column
+func openJSONFields(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("failed to read stored values: %w", err)
+	}
+	if err := pii.OpenFields(values, models.FarmerPIIFields...); err != nil {
+		return nil, err
+	}
+	return json.Marshal(values)
+}
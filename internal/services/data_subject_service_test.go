@@ -0,0 +1,91 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataExport_WriteZip(t *testing.T) {
+	fileName := "ktp.jpg"
+	export := &DataExport{
+		SubjectType: DataSubjectFarmer,
+		SubjectID:   "farmer-1",
+		GeneratedAt: time.Now(),
+		files:       []exportFile{{"profile.json", map[string]string{"full_name": "Budi"}}},
+		documents: []models.FarmerDocument{
+			{ID: "doc-1", DocumentType: models.DocumentTypeKTPPhoto, FileURL: "farmers/documents/a-ktp_photo", FileName: &fileName},
+			{ID: "doc-2", DocumentType: models.DocumentTypeBankStatement, FileURL: "farmers/documents/b-bank_statement"},
+		},
+		storage: &memoryStorage{objects: map[string]memoryObject{
+			"farmers/documents/a-ktp_photo": {body: jpegBytes},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, export.WriteZip(context.Background(), &buf))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	entries := map[string][]byte{}
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		entries[file.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+	}
+
+	assert.Contains(t, string(entries["profile.json"]), `"full_name": "Budi"`)
+	assert.Equal(t, jpegBytes, entries["documents/ktp_photo-doc-1.jpg"])
+
+	var manifest exportManifest
+	require.NoError(t, json.Unmarshal(entries["manifest.json"], &manifest))
+	assert.Equal(t, []string{"profile.json", "documents/ktp_photo-doc-1.jpg"}, manifest.Files)
+	assert.Equal(t, []string{"doc-2"}, manifest.MissingDocuments, "documents missing in storage are listed, not fatal")
+}
+
+func TestOpenJSONFields(t *testing.T) {
+	useTestKeyring(t)
+
+	sealed, err := pii.Encrypt("3273011708900001")
+	require.NoError(t, err)
+	raw, err := json.Marshal(map[string]interface{}{"id_number": sealed, "full_name": "Budi"})
+	require.NoError(t, err)
+
+	opened, err := openJSONFields(raw)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id_number": "3273011708900001", "full_name": "Budi"}`, string(opened))
+}
+
+func TestAnonymizeFarmer_KeepsWalletAndBusinessType(t *testing.T) {
+	npwp := "123456789123000"
+	farmer := &models.Farmer{
+		WalletAddress:     "0x1234567890abcdef1234567890abcdef12345678",
+		FullName:          "Budi",
+		IDNumber:          "3273011708900001",
+		DateOfBirth:       time.Date(1990, time.August, 17, 0, 0, 0, 0, time.UTC),
+		NPWP:              &npwp,
+		BusinessType:      models.BusinessTypeIndividual,
+		BankAccountNumber: "1234567890",
+	}
+
+	anonymizeFarmer(farmer)
+
+	assert.Equal(t, "[erased]", farmer.FullName)
+	assert.Empty(t, farmer.IDNumber)
+	assert.True(t, farmer.DateOfBirth.IsZero())
+	assert.Nil(t, farmer.NPWP)
+	assert.Empty(t, farmer.BankAccountNumber)
+	assert.Equal(t, "0x1234567890abcdef1234567890abcdef12345678", farmer.WalletAddress)
+	assert.Equal(t, models.BusinessTypeIndividual, farmer.BusinessType)
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/validation"
+)
+
+const (
+	// sniffLength is the number of leading bytes read to detect the real file type
+	sniffLength = 512
+
+	megabyte = 1 << 20
+)
+
+// documentPolicy limits what can be uploaded for a document type
+type documentPolicy struct {
+	MaxSize   int64
+	MimeTypes []string
+}
+
+var (
+	imageMimeTypes      = []string{"image/jpeg", "image/png", "image/webp"}
+	imageOrPDFMimeTypes = []string{"image/jpeg", "image/png", "image/webp", "application/pdf"}
+	documentPolicies    = map[models.DocumentType]documentPolicy{
+		models.DocumentTypeKTPPhoto:        {MaxSize: 5 * megabyte, MimeTypes: imageMimeTypes},
+		models.DocumentTypeSelfieWithKTP:   {MaxSize: 5 * megabyte, MimeTypes: imageMimeTypes},
+		models.DocumentTypeNPWPPhoto:       {MaxSize: 5 * megabyte, MimeTypes: imageMimeTypes},
+		models.DocumentTypeBankStatement:   {MaxSize: 10 * megabyte, MimeTypes: imageOrPDFMimeTypes},
+		models.DocumentTypeLandCertificate: {MaxSize: 10 * megabyte, MimeTypes: imageOrPDFMimeTypes},
+		models.DocumentTypeBusinessLicense: {MaxSize: 10 * megabyte, MimeTypes: imageOrPDFMimeTypes},
+		models.DocumentTypeInvoiceFile:     {MaxSize: 10 * megabyte, MimeTypes: imageOrPDFMimeTypes},
+	}
+)
+
+// allows reports whether the policy accepts the MIME type
+func (p documentPolicy) allows(mimeType string) bool {
+	for _, allowed := range p.MimeTypes {
+		if allowed == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyDocuments checks every uploaded document against storage: the object must exist under a
+// key issued for its document type, fit the size limit and have an allowed type by both its
+// stored content type and its magic bytes. The returned documents carry the verified size and
+// MIME type instead of the values claimed by the client. Invalid uploads are returned as
+// validation.Errors.
+func verifyDocuments(ctx context.Context, storage StorageService, docs []request.DocumentRequest) ([]request.DocumentRequest, error) {
+	var fieldErrs validation.Errors
+	verified := make([]request.DocumentRequest, 0, len(docs))
+	seen := make(map[string]bool, len(docs))
+
+	for i, doc := range docs {
+		field := fmt.Sprintf("documents[%d].file_key", i)
+
+		policy, ok := documentPolicies[models.DocumentType(doc.DocumentType)]
+		if !ok {
+			fieldErrs.Add(fmt.Sprintf("documents[%d].document_type", i), "unsupported document type")
+			continue
+		}
+
+		// Keys come from GeneratePresignedURLs, which ends them with the document type
+		if !strings.HasPrefix(doc.FileKey, "farmers/documents/") || !strings.HasSuffix(doc.FileKey, "-"+doc.DocumentType) {
+			fieldErrs.Add(field, "file key was not issued for this document type")
+			continue
+		}
+		if seen[doc.FileKey] {
+			fieldErrs.Add(field, "file is used by another document")
+			continue
+		}
+		seen[doc.FileKey] = true
+
+		info, err := storage.HeadObject(ctx, doc.FileKey)
+		if err != nil {
+			if errors.Is(err, ErrObjectNotFound) {
+				fieldErrs.Add(field, "file has not been uploaded")
+				continue
+			}
+			return nil, fmt.Errorf("failed to read document metadata: %w", err)
+		}
+
+		if info.Size == 0 {
+			fieldErrs.Add(field, "file is empty")
+			continue
+		}
+		if info.Size > policy.MaxSize {
+			fieldErrs.Add(field, fmt.Sprintf("file is larger than %d MB", policy.MaxSize/megabyte))
+			continue
+		}
+
+		// The presigned upload uses a generic content type, so only a specific one is checked
+		storedType := normalizeMimeType(info.ContentType)
+		if storedType != "" && storedType != "application/octet-stream" && !policy.allows(storedType) {
+			fieldErrs.Add(field, fmt.Sprintf("content type %s is not allowed", storedType))
+			continue
+		}
+
+		head, err := storage.ReadRange(ctx, doc.FileKey, 0, sniffLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document content: %w", err)
+		}
+		sniffedType := normalizeMimeType(http.DetectContentType(head))
+		if !policy.allows(sniffedType) {
+			fieldErrs.Add(field, fmt.Sprintf("file content is %s, expected one of %s", sniffedType, strings.Join(policy.MimeTypes, ", ")))
+			continue
+		}
+
+		size := int(info.Size)
+		doc.FileSize = &size
+		doc.MimeType = &sniffedType
+		verified = append(verified, doc)
+	}
+
+	if err := fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+	return verified, nil
+}
+
+// normalizeMimeType strips parameters such as charset from a MIME type
+func normalizeMimeType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
+
+// RequiredDocumentPolicy lists the documents a farmer must submit for their business type
+type RequiredDocumentPolicy struct {
+	requiredForAll     []models.DocumentType
+	requiredByBusiness map[models.BusinessType][]models.DocumentType
+}
+
+// NewRequiredDocumentPolicy builds the policy from config, rejecting unknown document types
+func NewRequiredDocumentPolicy(cfg *config.DocumentPolicyConfig) (*RequiredDocumentPolicy, error) {
+	requiredForAll, err := parseDocumentTypes(cfg.RequiredForAll)
+	if err != nil {
+		return nil, err
+	}
+
+	requiredByBusiness := make(map[models.BusinessType][]models.DocumentType, len(cfg.RequiredByBusiness))
+	for businessType, documentTypes := range cfg.RequiredByBusiness {
+		parsed, err := parseDocumentTypes(documentTypes)
+		if err != nil {
+			return nil, fmt.Errorf("business type %s: %w", businessType, err)
+		}
+		requiredByBusiness[models.BusinessType(businessType)] = parsed
+	}
+
+	return &RequiredDocumentPolicy{
+		requiredForAll:     requiredForAll,
+		requiredByBusiness: requiredByBusiness,
+	}, nil
+}
+
+// parseDocumentTypes converts configured names to document types
+func parseDocumentTypes(names []string) ([]models.DocumentType, error) {
+	documentTypes := make([]models.DocumentType, 0, len(names))
+	for _, name := range names {
+		documentType := models.DocumentType(name)
+		if _, ok := documentPolicies[documentType]; !ok {
+			return nil, fmt.Errorf("unknown document type %q", name)
+		}
+		documentTypes = append(documentTypes, documentType)
+	}
+	return documentTypes, nil
+}
+
+// Required returns the documents required for a business type, without duplicates
+func (p *RequiredDocumentPolicy) Required(businessType models.BusinessType) []models.DocumentType {
+	var required []models.DocumentType
+	seen := make(map[models.DocumentType]bool)
+	for _, documentTypes := range [][]models.DocumentType{p.requiredForAll, p.requiredByBusiness[businessType]} {
+		for _, documentType := range documentTypes {
+			if !seen[documentType] {
+				seen[documentType] = true
+				required = append(required, documentType)
+			}
+		}
+	}
+	return required
+}
+
+// Checklist reports which required documents have been submitted
+func (p *RequiredDocumentPolicy) Checklist(businessType models.BusinessType, submitted []models.DocumentType) []response.DocumentChecklistItem {
+	have := make(map[models.DocumentType]bool, len(submitted))
+	for _, documentType := range submitted {
+		have[documentType] = true
+	}
+
+	required := p.Required(businessType)
+	checklist := make([]response.DocumentChecklistItem, 0, len(required))
+	for _, documentType := range required {
+		checklist = append(checklist, response.DocumentChecklistItem{
+			DocumentType: string(documentType),
+			Submitted:    have[documentType],
+		})
+	}
+	return checklist
+}
+
+// Missing returns the required documents that were not submitted
+func (p *RequiredDocumentPolicy) Missing(businessType models.BusinessType, submitted []models.DocumentType) []models.DocumentType {
+	var missing []models.DocumentType
+	for _, item := range p.Checklist(businessType, submitted) {
+		if !item.Submitted {
+			missing = append(missing, models.DocumentType(item.DocumentType))
+		}
+	}
+	return missing
+}
+
+// checkRequiredDocuments returns a field error for every required document missing from a registration
+func (p *RequiredDocumentPolicy) checkRequiredDocuments(businessType models.BusinessType, docs []request.DocumentRequest) error {
+	submitted := make([]models.DocumentType, 0, len(docs))
+	for _, doc := range docs {
+		submitted = append(submitted, models.DocumentType(doc.DocumentType))
+	}
+
+	var fieldErrs validation.Errors
+	for _, documentType := range p.Missing(businessType, submitted) {
+		fieldErrs.Add("documents", fmt.Sprintf("%s is required for business type %s", documentType, businessType))
+	}
+	return fieldErrs.Err()
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryObject struct {
+	body        []byte
+	size        int64 // defaults to len(body)
+	contentType string
+}
+
+// memoryStorage is a StorageService backed by a map of objects
+type memoryStorage struct {
+	objects map[string]memoryObject
+}
+
+func (m *memoryStorage) Upload(ctx context.Context, key string, body io.Reader, contentType string) error {
+	return nil
+}
+
+func (m *memoryStorage) Download(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, ok := m.objects[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(object.body)), nil
+}
+
+func (m *memoryStorage) GetPresignedUploadURL(ctx context.Context, key string, contentType string, expiry time.Duration) (string, error) {
+	return "https://storage.test/" + key, nil
+}
+
+func (m *memoryStorage) GetPresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "https://storage.test/" + key, nil
+}
+
+func (m *memoryStorage) HeadObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	object, ok := m.objects[key]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	size := object.size
+	if size == 0 {
+		size = int64(len(object.body))
+	}
+	return &ObjectInfo{Size: size, ContentType: object.contentType}, nil
+}
+
+func (m *memoryStorage) ReadRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	body := m.objects[key].body
+	end := offset + length
+	if end > int64(len(body)) {
+		end = int64(len(body))
+	}
+	return body[offset:end], nil
+}
+
+func (m *memoryStorage) Delete(ctx context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+var (
+	jpegBytes = append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, make([]byte, 64)...)
+	pdfBytes  = []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n")
+)
+
+func TestVerifyDocuments_UsesStoredMetadata(t *testing.T) {
+	storage := &memoryStorage{objects: map[string]memoryObject{
+		"farmers/documents/a-ktp_photo":      {body: jpegBytes, contentType: "application/octet-stream"},
+		"farmers/documents/b-bank_statement": {body: pdfBytes, contentType: "application/pdf"},
+	}}
+	claimedSize := 1
+	claimedType := "image/png"
+
+	docs, err := verifyDocuments(context.Background(), storage, []request.DocumentRequest{
+		{DocumentType: "ktp_photo", FileKey: "farmers/documents/a-ktp_photo", FileSize: &claimedSize, MimeType: &claimedType},
+		{DocumentType: "bank_statement", FileKey: "farmers/documents/b-bank_statement"},
+	})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, len(jpegBytes), *docs[0].FileSize)
+	assert.Equal(t, "image/jpeg", *docs[0].MimeType)
+	assert.Equal(t, "application/pdf", *docs[1].MimeType)
+}
+
+func TestVerifyDocuments_ReturnsFieldErrors(t *testing.T) {
+	storage := &memoryStorage{objects: map[string]memoryObject{
+		"farmers/documents/a-ktp_photo":       {body: pdfBytes},                      // PDF not allowed for KTP
+		"farmers/documents/b-selfie_with_ktp": {body: jpegBytes, size: 6 * megabyte}, // over 5 MB
+		"farmers/documents/c-npwp_photo":      {body: []byte("MZ\x90\x00 not an image")},
+	}}
+
+	_, err := verifyDocuments(context.Background(), storage, []request.DocumentRequest{
+		{DocumentType: "ktp_photo", FileKey: "farmers/documents/a-ktp_photo"},
+		{DocumentType: "selfie_with_ktp", FileKey: "farmers/documents/b-selfie_with_ktp"},
+		{DocumentType: "npwp_photo", FileKey: "farmers/documents/c-npwp_photo"},
+		{DocumentType: "bank_statement", FileKey: "farmers/documents/missing-bank_statement"},
+		{DocumentType: "land_certificate", FileKey: "farmers/documents/a-ktp_photo"},
+	})
+
+	var fieldErrs validation.Errors
+	require.ErrorAs(t, err, &fieldErrs)
+
+	fields := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		fields = append(fields, fieldErr.Field)
+	}
+	assert.Equal(t, []string{
+		"documents[0].file_key",
+		"documents[1].file_key",
+		"documents[2].file_key",
+		"documents[3].file_key",
+		"documents[4].file_key",
+	}, fields)
+}
+
+func TestRequiredDocumentPolicy(t *testing.T) {
+	policy, err := NewRequiredDocumentPolicy(&config.DocumentPolicyConfig{
+		RequiredForAll: []string{"ktp_photo", "selfie_with_ktp"},
+		RequiredByBusiness: map[string][]string{
+			"pt": {"business_license", "npwp_photo", "ktp_photo"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.DocumentType{models.DocumentTypeKTPPhoto, models.DocumentTypeSelfieWithKTP}, policy.Required(models.BusinessTypeIndividual))
+	assert.Equal(t, []models.DocumentType{
+		models.DocumentTypeKTPPhoto,
+		models.DocumentTypeSelfieWithKTP,
+		models.DocumentTypeBusinessLicense,
+		models.DocumentTypeNPWPPhoto,
+	}, policy.Required(models.BusinessTypePT))
+
+	submitted := []models.DocumentType{models.DocumentTypeKTPPhoto, models.DocumentTypeSelfieWithKTP, models.DocumentTypeNPWPPhoto}
+	assert.Equal(t, []models.DocumentType{models.DocumentTypeBusinessLicense}, policy.Missing(models.BusinessTypePT, submitted))
+	assert.Empty(t, policy.Missing(models.BusinessTypeIndividual, submitted))
+
+	err = policy.checkRequiredDocuments(models.BusinessTypePT, []request.DocumentRequest{{DocumentType: "ktp_photo"}})
+	var fieldErrs validation.Errors
+	require.ErrorAs(t, err, &fieldErrs)
+	assert.Len(t, fieldErrs, 3)
+
+	_, err = NewRequiredDocumentPolicy(&config.DocumentPolicyConfig{RequiredForAll: []string{"passport"}})
+	assert.Error(t, err)
+}
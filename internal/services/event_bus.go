@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// Live event types pushed to investors over /events/stream
+const (
+	EventTypeCropProgress    = "crop_progress"
+	EventTypeCropStatus      = "crop_status"
+	EventTypeWaterRegen      = "water_regen"
+	EventTypeInvoiceFunding  = "invoice_funding"
+	EventTypeLeaderboardRank = "leaderboard_rank"
+	EventTypeNotification    = "notification"
+)
+
+// Event is a single live update published through the event bus
+type Event struct {
+	Type       string      `json:"type"`
+	Data       interface{} `json:"data"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// UserEventChannel returns the pub/sub channel carrying events for a single investor
+func UserEventChannel(userID string) string {
+	return fmt.Sprintf("events:user:%s", userID)
+}
+
+// InvoiceEventChannel returns the pub/sub channel carrying events for a single invoice
+func InvoiceEventChannel(invoiceID string) string {
+	return fmt.Sprintf("events:invoice:%s", invoiceID)
+}
+
+// EventBus defines the interface for publishing and subscribing to live events
+type EventBus interface {
+	Publish(ctx context.Context, channel string, eventType string, data interface{}) error
+	// Subscribe blocks and calls handler for every event on the given channels until ctx is done
+	Subscribe(ctx context.Context, channels []string, handler func(channel string, event Event)) error
+}
+
+// ValkeyEventBus implements EventBus on top of Valkey pub/sub so that events
+// published by one API replica reach streams held open by every other replica
+type ValkeyEventBus struct {
+	valkey valkey.Client
+}
+
+// NewValkeyEventBus creates a new ValkeyEventBus instance
+func NewValkeyEventBus(valkeyClient valkey.Client) *ValkeyEventBus {
+	return &ValkeyEventBus{valkey: valkeyClient}
+}
+
+// Publish publishes an event to a channel
+func (b *ValkeyEventBus) Publish(ctx context.Context, channel string, eventType string, data interface{}) error {
+	payload, err := json.Marshal(Event{
+		Type:       eventType,
+		Data:       data,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	cmd := b.valkey.B().Publish().Channel(channel).Message(string(payload)).Build()
+	if err := b.valkey.Do(ctx, cmd).Error(); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe listens on the given channels until ctx is cancelled
+func (b *ValkeyEventBus) Subscribe(ctx context.Context, channels []string, handler func(channel string, event Event)) error {
+	cmd := b.valkey.B().Subscribe().Channel(channels...).Build()
+	err := b.valkey.Receive(ctx, cmd, func(msg valkey.PubSubMessage) {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Message), &event); err != nil {
+			log.Printf("[EventBus] WARNING: dropping malformed event on %s: %v", msg.Channel, err)
+			return
+		}
+		handler(msg.Channel, event)
+	})
+	if err != nil && ctx.Err() != nil {
+		// Subscription ended because the stream was closed
+		return nil
+	}
+	return err
+}
+
+// publishEvent publishes an event and only logs failures; live updates are best effort
+func publishEvent(ctx context.Context, bus EventBus, channel, eventType string, data interface{}) {
+	if bus == nil {
+		return
+	}
+	if err := bus.Publish(ctx, channel, eventType, data); err != nil {
+		log.Printf("[EventBus] WARNING: failed to publish %s to %s: %v", eventType, channel, err)
+	}
+}
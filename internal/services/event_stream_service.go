@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+)
+
+// MaxStreamInvoices limits how many invoices a single stream can watch for funding progress
+const MaxStreamInvoices = 20
+
+var (
+	ErrTooManyStreamInvoices = errors.New("too many invoices to watch")
+)
+
+// EventStreamServiceInterface defines the interface for investor live event streams
+type EventStreamServiceInterface interface {
+	// Subscribe blocks and delivers events for the user and the watched invoices until ctx is done
+	Subscribe(ctx context.Context, userID string, invoiceIDs []string, handler func(event Event)) error
+	// Tick advances time-driven state (crop growth, water regeneration, leaderboard ranks)
+	// for a connected user and publishes whatever changed. Every open stream calls it, the
+	// work runs once per tick interval for the user.
+	Tick(ctx context.Context, userID string)
+}
+
+// EventStreamService implements EventStreamServiceInterface
+type EventStreamService struct {
+	eventBus       EventBus
+	locker         Locker
+	userRepo       repositories.UserRepository
+	investmentSvc  InvestmentServiceInterface
+	leaderboardSvc LeaderboardServiceInterface
+	tickLease      time.Duration
+}
+
+// NewEventStreamService creates a new EventStreamService instance. tickInterval is how often
+// each stream calls Tick.
+func NewEventStreamService(
+	eventBus EventBus,
+	locker Locker,
+	userRepo repositories.UserRepository,
+	investmentSvc InvestmentServiceInterface,
+	leaderboardSvc LeaderboardServiceInterface,
+	tickInterval time.Duration,
+) *EventStreamService {
+	return &EventStreamService{
+		eventBus:       eventBus,
+		locker:         locker,
+		userRepo:       userRepo,
+		investmentSvc:  investmentSvc,
+		leaderboardSvc: leaderboardSvc,
+		// Slightly shorter than the interval so that the stream holding the lease is not
+		// locked out of its own next tick by timer jitter
+		tickLease: tickInterval * 9 / 10,
+	}
+}
+
+// Subscribe listens on the user's channel and on the channels of the watched invoices
+func (s *EventStreamService) Subscribe(ctx context.Context, userID string, invoiceIDs []string, handler func(event Event)) error {
+	if len(invoiceIDs) > MaxStreamInvoices {
+		return ErrTooManyStreamInvoices
+	}
+
+	channels := []string{UserEventChannel(userID)}
+	for _, invoiceID := range invoiceIDs {
+		channels = append(channels, InvoiceEventChannel(invoiceID))
+	}
+
+	return s.eventBus.Subscribe(ctx, channels, func(channel string, event Event) {
+		handler(event)
+	})
+}
+
+// Tick refreshes time-driven state for the user. The first stream of the user to tick in an
+// interval takes a lease and does the work; its events reach the user's other tabs and
+// replicas through the bus, so they skip the tick.
+func (s *EventStreamService) Tick(ctx context.Context, userID string) {
+	acquired, err := s.locker.TryLock(ctx, fmt.Sprintf("events:tick:%s", userID), s.tickLease)
+	if err != nil {
+		log.Printf("[EventStream] WARNING: failed to take tick lease for user %s: %v", userID, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	if err := s.investmentSvc.RefreshCropProgress(ctx, userID); err != nil {
+		log.Printf("[EventStream] WARNING: failed to refresh crops for user %s: %v", userID, err)
+	}
+
+	s.tickWater(ctx, userID)
+
+	if err := s.leaderboardSvc.TrackRankChanges(ctx, userID); err != nil {
+		log.Printf("[EventStream] WARNING: failed to track ranks for user %s: %v", userID, err)
+	}
+}
+
+// tickWater regenerates water points and publishes the new balance when it grew
+func (s *EventStreamService) tickWater(ctx context.Context, userID string) {
+	before, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		log.Printf("[EventStream] WARNING: failed to get user %s: %v", userID, err)
+		return
+	}
+
+	after, err := s.userRepo.RegenerateWater(userID)
+	if err != nil {
+		log.Printf("[EventStream] WARNING: failed to regenerate water for user %s: %v", userID, err)
+		return
+	}
+	if after.WaterPoints == before.WaterPoints {
+		return
+	}
+
+	publishEvent(ctx, s.eventBus, UserEventChannel(userID), EventTypeWaterRegen, response.WaterRegenEvent{
+		WaterPoints:    after.WaterPoints,
+		MaxWaterPoints: repositories.MaxWaterPoints,
+		LastRegenAt:    after.LastRegenAt,
+	})
+}
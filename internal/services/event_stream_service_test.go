@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryLocker is an in-process Locker
+type memoryLocker struct {
+	mu     sync.Mutex
+	leases map[string]time.Time
+	now    time.Time
+}
+
+func (l *memoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until, ok := l.leases[key]; ok && l.now.Before(until) {
+		return false, nil
+	}
+	l.leases[key] = l.now.Add(ttl)
+	return true, nil
+}
+
+// countingTickServices counts the time-driven refreshes made for each user
+type countingTickServices struct {
+	InvestmentServiceInterface
+	LeaderboardServiceInterface
+	refreshes map[string]int
+}
+
+func (s *countingTickServices) RefreshCropProgress(ctx context.Context, userID string) error {
+	s.refreshes[userID]++
+	return nil
+}
+
+func (s *countingTickServices) TrackRankChanges(ctx context.Context, userID string) error {
+	return nil
+}
+
+func TestEventStreamTickRunsOncePerUserAndInterval(t *testing.T) {
+	locker := &memoryLocker{leases: map[string]time.Time{}, now: time.Now()}
+	counter := &countingTickServices{refreshes: map[string]int{}}
+	userRepo := &memoryUserRepo{user: models.User{ID: "user-1", WaterPoints: 100}}
+	svc := NewEventStreamService(nil, locker, userRepo, counter, counter, 30*time.Second)
+	ctx := context.Background()
+
+	// Three open tabs of user-1 and one of user-2 tick in the same interval
+	svc.Tick(ctx, "user-1")
+	svc.Tick(ctx, "user-1")
+	svc.Tick(ctx, "user-1")
+	svc.Tick(ctx, "user-2")
+	assert.Equal(t, map[string]int{"user-1": 1, "user-2": 1}, counter.refreshes)
+
+	locker.now = locker.now.Add(30 * time.Second)
+	svc.Tick(ctx, "user-1")
+	svc.Tick(ctx, "user-1")
+	assert.Equal(t, 2, counter.refreshes["user-1"])
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
@@ -31,12 +32,14 @@ type FarmServiceInterface interface {
 // FarmService implements FarmServiceInterface
 type FarmService struct {
 	farmRepo repositories.FarmRepository
+	auditor  audit.Recorder
 }
 
 // NewFarmService creates a new FarmService instance
-func NewFarmService(farmRepo repositories.FarmRepository) *FarmService {
+func NewFarmService(farmRepo repositories.FarmRepository, auditor audit.Recorder) *FarmService {
 	return &FarmService{
 		farmRepo: farmRepo,
+		auditor:  auditor,
 	}
 }
 
@@ -68,7 +71,15 @@ func (s *FarmService) Create(ctx context.Context, farmerID string, req *request.
 		return nil, fmt.Errorf("failed to create farm: %w", err)
 	}
 
-	return s.toFarmResponse(farm), nil
+	resp := s.toFarmResponse(farm)
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionCreateFarm,
+		EntityType: models.AuditEntityTypeFarm,
+		EntityID:   farm.ID,
+		After:      resp,
+	})
+
+	return resp, nil
 }
 
 // GetByID retrieves a farm by ID (with ownership check)
@@ -142,6 +153,7 @@ func (s *FarmService) Update(ctx context.Context, farmerID, farmID string, req *
 	if err != nil {
 		return nil, ErrFarmNotFound
 	}
+	before := s.toFarmResponse(farm)
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -176,7 +188,16 @@ func (s *FarmService) Update(ctx context.Context, farmerID, farmID string, req *
 		return nil, fmt.Errorf("failed to update farm: %w", err)
 	}
 
-	return s.toFarmResponse(farm), nil
+	resp := s.toFarmResponse(farm)
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionUpdateFarm,
+		EntityType: models.AuditEntityTypeFarm,
+		EntityID:   farmID,
+		Before:     before,
+		After:      resp,
+	})
+
+	return resp, nil
 }
 
 // Delete soft deletes a farm
@@ -192,6 +213,14 @@ func (s *FarmService) Delete(ctx context.Context, farmerID, farmID string) error
 		return fmt.Errorf("failed to delete farm: %w", err)
 	}
 
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionDeleteFarm,
+		EntityType: models.AuditEntityTypeFarm,
+		EntityID:   farmID,
+		Before:     map[string]interface{}{"is_active": true},
+		After:      map[string]interface{}{"is_active": false},
+	})
+
 	return nil
 }
 
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+)
+
+const (
+	// farmerNotificationSendTimeout bounds a single delivery attempt
+	farmerNotificationSendTimeout = 30 * time.Second
+	// farmerNotificationClaimLease keeps a claimed delivery away from other workers while it is sent
+	farmerNotificationClaimLease = 2 * time.Minute
+	// farmerNotificationBatchSize is how many due deliveries the retry worker claims per poll
+	farmerNotificationBatchSize = 50
+)
+
+// FarmerNotificationServiceInterface defines the interface for outbound farmer notifications
+type FarmerNotificationServiceInterface interface {
+	// NotifyFarmerReviewed tells the farmer their registration was approved or rejected
+	NotifyFarmerReviewed(ctx context.Context, farmer *models.Farmer)
+	// NotifyInvoiceReviewed tells the farmer one of their invoices was approved or rejected
+	NotifyInvoiceReviewed(ctx context.Context, farmerID string, invoice *models.Invoice)
+	// NotifyInvoiceFundingFailed tells the farmer one of their invoices missed its funding deadline
+	NotifyInvoiceFundingFailed(ctx context.Context, farmerID string, invoice *models.Invoice)
+	// NotifyProfileChangeReviewed tells the farmer a sensitive profile change was approved or rejected
+	NotifyProfileChangeReviewed(ctx context.Context, farmer *models.Farmer, change *models.FarmerProfileChange, fields []string)
+	// ProcessDue retries pending deliveries whose next attempt is due
+	ProcessDue(ctx context.Context) (int, error)
+	// Run polls for due deliveries until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// FarmerNotificationService records every farmer notification as a delivery per channel,
+// sends it right away and retries failures with exponential backoff
+type FarmerNotificationService struct {
+	deliveryRepo repositories.FarmerNotificationRepository
+	farmerRepo   repositories.FarmerRepository
+	transports   []FarmerNotificationTransport
+	maxAttempts  int
+	retryBase    time.Duration
+	now          func() time.Time
+	wg           sync.WaitGroup
+}
+
+// NewFarmerNotificationService creates a new FarmerNotificationService instance
+func NewFarmerNotificationService(
+	deliveryRepo repositories.FarmerNotificationRepository,
+	farmerRepo repositories.FarmerRepository,
+	transports []FarmerNotificationTransport,
+	maxAttempts int,
+	retryBase time.Duration,
+) *FarmerNotificationService {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &FarmerNotificationService{
+		deliveryRepo: deliveryRepo,
+		farmerRepo:   farmerRepo,
+		transports:   transports,
+		maxAttempts:  maxAttempts,
+		retryBase:    retryBase,
+		now:          time.Now,
+	}
+}
+
+// NotifyFarmerReviewed queues the approval or rejection message for a farmer registration
+func (s *FarmerNotificationService) NotifyFarmerReviewed(ctx context.Context, farmer *models.Farmer) {
+	var event models.FarmerNotificationEvent
+	switch farmer.Status {
+	case models.FarmerStatusApproved:
+		event = models.FarmerNotificationEventFarmerApproved
+	case models.FarmerStatusRejected:
+		event = models.FarmerNotificationEventFarmerRejected
+	default:
+		return
+	}
+
+	data := newFarmerMessageData(farmer)
+	if farmer.RejectionReason != nil {
+		data.Reason = *farmer.RejectionReason
+	}
+	s.notify(ctx, farmer, event, data)
+}
+
+// NotifyInvoiceReviewed queues the approval or rejection message for an invoice
+func (s *FarmerNotificationService) NotifyInvoiceReviewed(ctx context.Context, farmerID string, invoice *models.Invoice) {
+	var event models.FarmerNotificationEvent
+	switch invoice.Status {
+	case models.InvoiceStatusFunding:
+		event = models.FarmerNotificationEventInvoiceApproved
+	case models.InvoiceStatusRejected:
+		event = models.FarmerNotificationEventInvoiceRejected
+	default:
+		return
+	}
+
+	farmer, err := s.farmerRepo.GetByID(farmerID)
+	if err != nil {
+		log.Printf("[FarmerNotification] WARNING: failed to get farmer %s: %v", farmerID, err)
+		return
+	}
+
+	data := newFarmerMessageData(farmer)
+	data.InvoiceName = invoice.Name
+	data.TokenID = invoice.TokenID
+	if invoice.RejectionReason != nil {
+		data.Reason = *invoice.RejectionReason
+	}
+	s.notify(ctx, farmer, event, data)
+}
+
+// NotifyInvoiceFundingFailed queues the funding failed message for an invoice
+func (s *FarmerNotificationService) NotifyInvoiceFundingFailed(ctx context.Context, farmerID string, invoice *models.Invoice) {
+	farmer, err := s.farmerRepo.GetByID(farmerID)
+	if err != nil {
+		log.Printf("[FarmerNotification] WARNING: failed to get farmer %s: %v", farmerID, err)
+		return
+	}
+
+	data := newFarmerMessageData(farmer)
+	data.InvoiceName = invoice.Name
+	data.TokenID = invoice.TokenID
+	data.TargetFund = invoice.TargetFund.String()
+	s.notify(ctx, farmer, models.FarmerNotificationEventInvoiceFundingFailed, data)
+}
+
+// NotifyProfileChangeReviewed queues the approval or rejection message for a profile change request
+func (s *FarmerNotificationService) NotifyProfileChangeReviewed(ctx context.Context, farmer *models.Farmer, change *models.FarmerProfileChange, fields []string) {
+	var event models.FarmerNotificationEvent
+	switch change.Status {
+	case models.ProfileChangeStatusApproved:
+		event = models.FarmerNotificationEventProfileChangeApproved
+	case models.ProfileChangeStatusRejected:
+		event = models.FarmerNotificationEventProfileChangeRejected
+	default:
+		return
+	}
+
+	data := newFarmerMessageData(farmer)
+	data.Fields = farmerFieldList(farmer.PreferredLanguage, fields)
+	if change.RejectionReason != nil {
+		data.Reason = *change.RejectionReason
+	}
+	s.notify(ctx, farmer, event, data)
+}
+
+// notify renders and records one delivery per channel, then sends them in the background.
+// Failures never fail the review itself; undelivered messages stay pending for the retry worker.
+func (s *FarmerNotificationService) notify(ctx context.Context, farmer *models.Farmer, event models.FarmerNotificationEvent, data farmerMessageData) {
+	language, subject, body, err := renderFarmerMessage(event, farmer.PreferredLanguage, data)
+	if err != nil {
+		log.Printf("[FarmerNotification] WARNING: failed to render %s for farmer %s: %v", event, farmer.ID, err)
+		return
+	}
+
+	for _, transport := range s.transports {
+		recipient := farmer.PhoneNumber
+		if transport.Channel() == models.FarmerNotificationChannelEmail {
+			recipient = farmer.Email
+		}
+		if recipient == "" {
+			continue
+		}
+
+		delivery := &models.FarmerNotificationDelivery{
+			FarmerID:      farmer.ID,
+			Event:         event,
+			Channel:       transport.Channel(),
+			Recipient:     recipient,
+			Language:      language,
+			Body:          body,
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: s.now().Add(farmerNotificationClaimLease),
+		}
+		if transport.Channel() == models.FarmerNotificationChannelEmail {
+			delivery.Subject = &subject
+		}
+
+		if err := s.deliveryRepo.Create(delivery); err != nil {
+			log.Printf("[FarmerNotification] WARNING: failed to record %s %s delivery for farmer %s: %v", event, transport.Channel(), farmer.ID, err)
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(transport FarmerNotificationTransport, delivery *models.FarmerNotificationDelivery) {
+			defer s.wg.Done()
+			// Detached from the request so the send is not cancelled when the response is written
+			s.attempt(context.Background(), transport, delivery)
+		}(transport, delivery)
+	}
+}
+
+// ProcessDue claims due pending deliveries and attempts each of them once
+func (s *FarmerNotificationService) ProcessDue(ctx context.Context) (int, error) {
+	deliveries, err := s.deliveryRepo.ClaimDue(s.now(), farmerNotificationClaimLease, farmerNotificationBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due deliveries: %w", err)
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		transport := s.transportFor(delivery.Channel)
+		if transport == nil {
+			s.recordFailure(delivery, fmt.Errorf("%w: %s", ErrUnknownNotificationChannel, delivery.Channel))
+			continue
+		}
+		s.attempt(ctx, transport, delivery)
+	}
+	return len(deliveries), nil
+}
+
+// Run polls for due deliveries until ctx is done
+func (s *FarmerNotificationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ProcessDue(ctx); err != nil {
+				log.Printf("[FarmerNotification] WARNING: retry worker: %v", err)
+			}
+		}
+	}
+}
+
+// Wait blocks until all in-flight first attempts have finished
+func (s *FarmerNotificationService) Wait() {
+	s.wg.Wait()
+}
+
+// attempt sends a delivery once and records the outcome
+func (s *FarmerNotificationService) attempt(ctx context.Context, transport FarmerNotificationTransport, delivery *models.FarmerNotificationDelivery) {
+	sendCtx, cancel := context.WithTimeout(ctx, farmerNotificationSendTimeout)
+	defer cancel()
+
+	msg := FarmerMessage{
+		Recipient: delivery.Recipient,
+		Body:      delivery.Body,
+	}
+	if delivery.Subject != nil {
+		msg.Subject = *delivery.Subject
+	}
+
+	if err := transport.Send(sendCtx, msg); err != nil {
+		s.recordFailure(delivery, err)
+		return
+	}
+
+	now := s.now()
+	delivery.Attempts++
+	delivery.Status = models.DeliveryStatusSent
+	delivery.SentAt = &now
+	delivery.LastError = nil
+	if err := s.deliveryRepo.Update(delivery); err != nil {
+		log.Printf("[FarmerNotification] WARNING: failed to update delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// recordFailure schedules the next attempt with exponential backoff, or marks the
+// delivery failed once the attempts are exhausted
+func (s *FarmerNotificationService) recordFailure(delivery *models.FarmerNotificationDelivery, sendErr error) {
+	delivery.Attempts++
+	errMsg := sendErr.Error()
+	delivery.LastError = &errMsg
+
+	if delivery.Attempts >= s.maxAttempts {
+		delivery.Status = models.DeliveryStatusFailed
+		log.Printf("[FarmerNotification] ERROR: giving up on %s %s delivery %s after %d attempts: %v", delivery.Event, delivery.Channel, delivery.ID, delivery.Attempts, sendErr)
+	} else {
+		delivery.NextAttemptAt = s.now().Add(s.retryBase << (delivery.Attempts - 1))
+	}
+
+	if err := s.deliveryRepo.Update(delivery); err != nil {
+		log.Printf("[FarmerNotification] WARNING: failed to update delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// transportFor returns the configured transport for a channel
+func (s *FarmerNotificationService) transportFor(channel models.FarmerNotificationChannel) FarmerNotificationTransport {
+	for _, transport := range s.transports {
+		if transport.Channel() == channel {
+			return transport
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTransport records sent messages and fails the first failures sends
+type memoryTransport struct {
+	mu       sync.Mutex
+	channel  models.FarmerNotificationChannel
+	failures int
+	sent     []FarmerMessage
+}
+
+func (t *memoryTransport) Channel() models.FarmerNotificationChannel {
+	return t.channel
+}
+
+func (t *memoryTransport) Send(ctx context.Context, msg FarmerMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failures > 0 {
+		t.failures--
+		return errors.New("gateway unavailable")
+	}
+	t.sent = append(t.sent, msg)
+	return nil
+}
+
+// memoryDeliveryRepo is an in-memory FarmerNotificationRepository
+type memoryDeliveryRepo struct {
+	mu         sync.Mutex
+	deliveries map[string]*models.FarmerNotificationDelivery
+	nextID     int
+}
+
+func newMemoryDeliveryRepo() *memoryDeliveryRepo {
+	return &memoryDeliveryRepo{deliveries: map[string]*models.FarmerNotificationDelivery{}}
+}
+
+func (r *memoryDeliveryRepo) Create(delivery *models.FarmerNotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	delivery.ID = fmt.Sprintf("delivery-%d", r.nextID)
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (r *memoryDeliveryRepo) Update(delivery *models.FarmerNotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (r *memoryDeliveryRepo) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.FarmerNotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []models.FarmerNotificationDelivery
+	for _, d := range r.deliveries {
+		if d.Status == models.DeliveryStatusPending && !d.NextAttemptAt.After(now) && len(due) < limit {
+			d.NextAttemptAt = now.Add(lease)
+			due = append(due, *d)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryDeliveryRepo) GetAllByFarmerID(farmerID string) ([]models.FarmerNotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []models.FarmerNotificationDelivery
+	for _, d := range r.deliveries {
+		if d.FarmerID == farmerID {
+			result = append(result, *d)
+		}
+	}
+	return result, nil
+}
+
+// stubFarmerRepo only implements lookups of a single farmer; other methods panic if called
+type stubFarmerRepo struct {
+	repositories.FarmerRepository
+	farmer *models.Farmer
+}
+
+func (r *stubFarmerRepo) GetByID(id string) (*models.Farmer, error) {
+	if r.farmer == nil || r.farmer.ID != id {
+		return nil, errors.New("record not found")
+	}
+	farmer := *r.farmer
+	return &farmer, nil
+}
+
+func (r *stubFarmerRepo) UpdateStatus(farmer *models.Farmer, from models.FarmerStatus, fromReviewedBy *string) error {
+	stored := r.farmer
+	if stored.Status != from || (stored.ReviewedBy == nil) != (fromReviewedBy == nil) ||
+		(fromReviewedBy != nil && *stored.ReviewedBy != *fromReviewedBy) {
+		return repositories.ErrFarmerStatusChanged
+	}
+	r.farmer = farmer
+	return nil
+}
+
+func (r *stubFarmerRepo) GetStatusByID(id string) (models.FarmerStatus, error) {
+	farmer, err := r.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+	return farmer.Status, nil
+}
+
+func testFarmer(status models.FarmerStatus, language string) *models.Farmer {
+	business := "Tani Makmur"
+	return &models.Farmer{
+		ID:                "farmer-1",
+		Status:            status,
+		FullName:          "Budi Santoso",
+		Email:             "budi@example.com",
+		PhoneNumber:       "+6281234567890",
+		BusinessName:      &business,
+		PreferredLanguage: language,
+	}
+}
+
+func TestFarmerNotificationService_NotifyFarmerReviewed_SendsOnEveryChannel(t *testing.T) {
+	repo := newMemoryDeliveryRepo()
+	email := &memoryTransport{channel: models.FarmerNotificationChannelEmail}
+	whatsapp := &memoryTransport{channel: models.FarmerNotificationChannelWhatsApp}
+	svc := NewFarmerNotificationService(repo, &stubFarmerRepo{}, []FarmerNotificationTransport{email, whatsapp}, 3, time.Minute)
+
+	svc.NotifyFarmerReviewed(context.Background(), testFarmer(models.FarmerStatusApproved, models.LanguageIndonesian))
+	svc.Wait()
+
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "budi@example.com", email.sent[0].Recipient)
+	assert.Equal(t, "Pendaftaran OwnaFarm Anda disetujui", email.sent[0].Subject)
+	assert.Contains(t, email.sent[0].Body, "Tani Makmur")
+
+	require.Len(t, whatsapp.sent, 1)
+	assert.Equal(t, "+6281234567890", whatsapp.sent[0].Recipient)
+
+	deliveries, _ := repo.GetAllByFarmerID("farmer-1")
+	require.Len(t, deliveries, 2)
+	for _, d := range deliveries {
+		assert.Equal(t, models.DeliveryStatusSent, d.Status)
+		assert.Equal(t, 1, d.Attempts)
+		assert.Equal(t, models.FarmerNotificationEventFarmerApproved, d.Event)
+		assert.NotNil(t, d.SentAt)
+	}
+}
+
+func TestFarmerNotificationService_NotifyInvoiceReviewed_RendersEnglishRejection(t *testing.T) {
+	repo := newMemoryDeliveryRepo()
+	email := &memoryTransport{channel: models.FarmerNotificationChannelEmail}
+	farmer := testFarmer(models.FarmerStatusApproved, models.LanguageEnglish)
+	svc := NewFarmerNotificationService(repo, &stubFarmerRepo{farmer: farmer}, []FarmerNotificationTransport{email}, 3, time.Minute)
+
+	reason := "Harvest estimate is missing"
+	svc.NotifyInvoiceReviewed(context.Background(), farmer.ID, &models.Invoice{
+		Name:            "Padi Musim Hujan",
+		Status:          models.InvoiceStatusRejected,
+		RejectionReason: &reason,
+	})
+	svc.Wait()
+
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "Invoice Padi Musim Hujan rejected", email.sent[0].Subject)
+	assert.Contains(t, email.sent[0].Body, "Reason: Harvest estimate is missing")
+}
+
+func TestFarmerNotificationService_NotifyInvoiceFundingFailed(t *testing.T) {
+	repo := newMemoryDeliveryRepo()
+	email := &memoryTransport{channel: models.FarmerNotificationChannelEmail}
+	farmer := testFarmer(models.FarmerStatusApproved, models.LanguageIndonesian)
+	svc := NewFarmerNotificationService(repo, &stubFarmerRepo{farmer: farmer}, []FarmerNotificationTransport{email}, 3, time.Minute)
+
+	svc.NotifyInvoiceFundingFailed(context.Background(), farmer.ID, &models.Invoice{
+		Name:       "Cabai Rawit - Batch 1",
+		Status:     models.InvoiceStatusFundingFailed,
+		TargetFund: decimal.NewFromInt(45000000),
+	})
+	svc.Wait()
+
+	require.Len(t, email.sent, 1)
+	assert.Equal(t, "Pendanaan invoice Cabai Rawit - Batch 1 tidak tercapai", email.sent[0].Subject)
+	assert.Contains(t, email.sent[0].Body, "target pendanaan 45000000")
+	for _, d := range repo.deliveries {
+		assert.Equal(t, models.FarmerNotificationEventInvoiceFundingFailed, d.Event)
+	}
+}
+
+func TestFarmerNotificationService_RetriesWithBackoffUntilFailed(t *testing.T) {
+	repo := newMemoryDeliveryRepo()
+	sms := &memoryTransport{channel: models.FarmerNotificationChannelSMS, failures: 3}
+	svc := NewFarmerNotificationService(repo, &stubFarmerRepo{}, []FarmerNotificationTransport{sms}, 3, time.Minute)
+
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	svc.NotifyFarmerReviewed(context.Background(), testFarmer(models.FarmerStatusRejected, models.LanguageIndonesian))
+	svc.Wait()
+
+	deliveries, _ := repo.GetAllByFarmerID("farmer-1")
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, now.Add(time.Minute), deliveries[0].NextAttemptAt)
+	assert.Nil(t, deliveries[0].Subject)
+
+	// Not due yet
+	processed, err := svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, processed)
+
+	// Second attempt fails and doubles the delay
+	now = now.Add(time.Minute)
+	processed, err = svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	deliveries, _ = repo.GetAllByFarmerID("farmer-1")
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, now.Add(2*time.Minute), deliveries[0].NextAttemptAt)
+
+	// Third attempt exhausts the attempts
+	now = now.Add(2 * time.Minute)
+	_, err = svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+	deliveries, _ = repo.GetAllByFarmerID("farmer-1")
+	assert.Equal(t, models.DeliveryStatusFailed, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	require.NotNil(t, deliveries[0].LastError)
+	assert.Equal(t, "gateway unavailable", *deliveries[0].LastError)
+	assert.Empty(t, sms.sent)
+}
+
+func TestRenderFarmerMessage_FallsBackToIndonesian(t *testing.T) {
+	tokenID := int64(42)
+	language, subject, body, err := renderFarmerMessage(models.FarmerNotificationEventInvoiceApproved, "fr", farmerMessageData{
+		FullName:    "Budi",
+		InvoiceName: "Jagung",
+		TokenID:     &tokenID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.LanguageIndonesian, language)
+	assert.Equal(t, "Invoice Jagung disetujui", subject)
+	assert.Contains(t, body, "token ID 42")
+}
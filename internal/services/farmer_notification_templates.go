@@ -0,0 +1,191 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
+
+// farmerMessageData is the data available to farmer notification templates
+type farmerMessageData struct {
+	FullName     string
+	BusinessName string
+	InvoiceName  string
+	TokenID      *int64
+	TargetFund   string
+	Fields       string // Human readable list of changed profile fields
+	Reason       string
+}
+
+// newFarmerMessageData fills the farmer fields, using the full name for individual farmers without a business name
+func newFarmerMessageData(farmer *models.Farmer) farmerMessageData {
+	data := farmerMessageData{
+		FullName:     farmer.FullName,
+		BusinessName: farmer.FullName,
+	}
+	if farmer.BusinessName != nil && *farmer.BusinessName != "" {
+		data.BusinessName = *farmer.BusinessName
+	}
+	return data
+}
+
+// farmerMessageTemplate holds the subject and body of one event in one language
+type farmerMessageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// farmerMessageTemplates maps event -> language -> template
+var farmerMessageTemplates = map[models.FarmerNotificationEvent]map[string]farmerMessageTemplate{
+	models.FarmerNotificationEventFarmerApproved: {
+		models.LanguageIndonesian: mustFarmerTemplate(
+			"Pendaftaran OwnaFarm Anda disetujui",
+			"Halo {{.FullName}},\n\nPendaftaran {{.BusinessName}} sebagai petani OwnaFarm telah disetujui. "+
+				"Anda sekarang dapat masuk dengan wallet Anda, menambahkan lahan, dan mengajukan invoice.\n\nSalam,\nTim OwnaFarm",
+		),
+		models.LanguageEnglish: mustFarmerTemplate(
+			"Your OwnaFarm registration is approved",
+			"Hi {{.FullName}},\n\nThe registration of {{.BusinessName}} as an OwnaFarm farmer has been approved. "+
+				"You can now sign in with your wallet, add farms and submit invoices.\n\nRegards,\nThe OwnaFarm Team",
+		),
+	},
+	models.FarmerNotificationEventFarmerRejected: {
+		models.LanguageIndonesian: mustFarmerTemplate(
+			"Pendaftaran OwnaFarm Anda ditolak",
+			"Halo {{.FullName}},\n\nMohon maaf, pendaftaran {{.BusinessName}} sebagai petani OwnaFarm belum dapat kami setujui."+
+				"{{if .Reason}}\n\nAlasan: {{.Reason}}{{end}}"+
+				"\n\nAnda dapat masuk dengan wallet Anda untuk memperbaiki data dan dokumen lalu mengajukan ulang pendaftaran.\n\nSalam,\nTim OwnaFarm",
+		),
+		models.LanguageEnglish: mustFarmerTemplate(
+			"Your OwnaFarm registration was rejected",
+			"Hi {{.FullName}},\n\nUnfortunately we could not approve the registration of {{.BusinessName}} as an OwnaFarm farmer."+
+				"{{if .Reason}}\n\nReason: {{.Reason}}{{end}}"+
+				"\n\nYou can log in with your wallet to fix your details and documents and resubmit the registration.\n\nRegards,\nThe OwnaFarm Team",
+		),
+	},
+	models.FarmerNotificationEventInvoiceApproved: {
+		models.LanguageIndonesian: mustFarmerTemplate(
+			"Invoice {{.InvoiceName}} disetujui",
+			"Halo {{.FullName}},\n\nInvoice \"{{.InvoiceName}}\" telah disetujui"+
+				"{{if .TokenID}} dengan token ID {{.TokenID}}{{end}} dan kini terbuka untuk pendanaan investor.\n\nSalam,\nTim OwnaFarm",
+		),
+		models.LanguageEnglish: mustFarmerTemplate(
+			"Invoice {{.InvoiceName}} approved",
+			"Hi {{.FullName}},\n\nYour invoice \"{{.InvoiceName}}\" has been approved"+
+				"{{if .TokenID}} with token ID {{.TokenID}}{{end}} and is now open for investor funding.\n\nRegards,\nThe OwnaFarm Team",
+		),
+	},
+	models.FarmerNotificationEventInvoiceRejected: {
+		models.LanguageIndonesian: mustFarmerTemplate(
+			"Invoice {{.InvoiceName}} ditolak",
+			"Halo {{.FullName}},\n\nMohon maaf, invoice \"{{.InvoiceName}}\" belum dapat kami setujui."+
+				"{{if .Reason}}\n\nAlasan: {{.Reason}}{{end}}\n\nSalam,\nTim OwnaFarm",
+		),
+		models.LanguageEnglish: mustFarmerTemplate(
+			"Invoice {{.InvoiceName}} rejected",
+			"Hi {{.FullName}},\n\nUnfortunately your invoice \"{{.InvoiceName}}\" could not be approved."+
+				"{{if .Reason}}\n\nReason: {{.Reason}}{{end}}\n\nRegards,\nThe OwnaFarm Team",
+		),
+	},
+	models.FarmerNotificationEventInvoiceFundingFailed: {
+		models.LanguageIndonesian: mustFarmerTemplate(
+			"Pendanaan invoice {{.InvoiceName}} tidak tercapai",
+			"Halo {{.FullName}},\n\nMohon maaf, invoice \"{{.InvoiceName}}\" tidak mencapai target pendanaan {{.TargetFund}} "+
+				"sebelum batas waktu pendanaan dan telah ditutup. Anda dapat mengajukan invoice baru.\n\nSalam,\nTim OwnaFarm",
+		),
+		models.LanguageEnglish: mustFarmerTemplate(
+			"Funding of invoice {{.InvoiceName}} failed",
+			"Hi {{.FullName}},\n\nUnfortunately your invoice \"{{.InvoiceName}}\" did not reach its funding target of {{.TargetFund}} "+
+				"before the funding deadline and has been closed. You can submit a new invoice.\n\nRegards,\nThe OwnaFarm Team",
+		),
+	},
+	models.FarmerNotificationEventProfileChangeApproved: {
+		models.LanguageIndonesian: mustFarmerTemplate(
+			"Perubahan data profil disetujui",
+			"Halo {{.FullName}},\n\nPerubahan data {{.Fields}} pada profil OwnaFarm Anda telah disetujui dan sudah berlaku.\n\nSalam,\nTim OwnaFarm",
+		),
+		models.LanguageEnglish: mustFarmerTemplate(
+			"Profile change approved",
+			"Hi {{.FullName}},\n\nThe change to your {{.Fields}} on OwnaFarm has been approved and is now in effect.\n\nRegards,\nThe OwnaFarm Team",
+		),
+	},
+	models.FarmerNotificationEventProfileChangeRejected: {
+		models.LanguageIndonesian: mustFarmerTemplate(
+			"Perubahan data profil ditolak",
+			"Halo {{.FullName}},\n\nMohon maaf, perubahan data {{.Fields}} pada profil OwnaFarm Anda belum dapat kami setujui. Data lama tetap berlaku."+
+				"{{if .Reason}}\n\nAlasan: {{.Reason}}{{end}}\n\nSalam,\nTim OwnaFarm",
+		),
+		models.LanguageEnglish: mustFarmerTemplate(
+			"Profile change rejected",
+			"Hi {{.FullName}},\n\nUnfortunately the change to your {{.Fields}} on OwnaFarm could not be approved. Your previous details remain in effect."+
+				"{{if .Reason}}\n\nReason: {{.Reason}}{{end}}\n\nRegards,\nThe OwnaFarm Team",
+		),
+	},
+}
+
+// farmerFieldLabels names the sensitive profile fields in notification messages
+var farmerFieldLabels = map[string]map[string]string{
+	models.LanguageIndonesian: {
+		"id_number":           "NIK",
+		"npwp":                "NPWP",
+		"bank_name":           "nama bank",
+		"bank_account_number": "nomor rekening",
+		"bank_account_name":   "nama pemilik rekening",
+	},
+	models.LanguageEnglish: {
+		"id_number":           "ID number",
+		"npwp":                "tax number (NPWP)",
+		"bank_name":           "bank name",
+		"bank_account_number": "bank account number",
+		"bank_account_name":   "bank account holder name",
+	},
+}
+
+// farmerFieldList joins the labels of the given fields in the farmer's language
+func farmerFieldList(language string, fields []string) string {
+	labels, ok := farmerFieldLabels[language]
+	if !ok {
+		labels = farmerFieldLabels[models.LanguageIndonesian]
+	}
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field
+		if label, ok := labels[field]; ok {
+			names[i] = label
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// mustFarmerTemplate parses a subject/body pair, panicking on a malformed built-in template
+func mustFarmerTemplate(subject, body string) farmerMessageTemplate {
+	return farmerMessageTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// renderFarmerMessage renders the subject and body of an event in the given language,
+// falling back to Indonesian for unsupported languages
+func renderFarmerMessage(event models.FarmerNotificationEvent, language string, data farmerMessageData) (string, string, string, error) {
+	byLanguage, ok := farmerMessageTemplates[event]
+	if !ok {
+		return "", "", "", fmt.Errorf("no template for event %s", event)
+	}
+	tmpl, ok := byLanguage[language]
+	if !ok {
+		language = models.LanguageIndonesian
+		tmpl = byLanguage[language]
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+	return language, subject.String(), body.String(), nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
+
+// FarmerMessage is a rendered notification ready to be handed to a transport
+type FarmerMessage struct {
+	Recipient string // Email address or E.164 phone number, depending on the channel
+	Subject   string // Only used by email
+	Body      string
+}
+
+// FarmerNotificationTransport delivers rendered messages to farmers over one channel
+type FarmerNotificationTransport interface {
+	Channel() models.FarmerNotificationChannel
+	Send(ctx context.Context, msg FarmerMessage) error
+}
+
+// NewFarmerNotificationTransports builds the transports listed in config. A channel
+// without provider credentials falls back to a log stub so local setups keep working.
+func NewFarmerNotificationTransports(cfg *config.FarmerNotificationConfig) ([]FarmerNotificationTransport, error) {
+	timeout := time.Duration(cfg.GatewayTimeoutSec) * time.Second
+
+	var transports []FarmerNotificationTransport
+	for _, name := range cfg.Channels {
+		channel := models.FarmerNotificationChannel(name)
+		switch channel {
+		case models.FarmerNotificationChannelEmail:
+			if cfg.SMTPHost == "" {
+				transports = append(transports, NewLogFarmerNotificationTransport(channel))
+				continue
+			}
+			transports = append(transports, NewSMTPEmailTransport(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom))
+		case models.FarmerNotificationChannelSMS:
+			if cfg.SMSGatewayURL == "" {
+				transports = append(transports, NewLogFarmerNotificationTransport(channel))
+				continue
+			}
+			transports = append(transports, NewHTTPMessageTransport(channel, cfg.SMSGatewayURL, cfg.SMSGatewayToken, timeout))
+		case models.FarmerNotificationChannelWhatsApp:
+			if cfg.WhatsAppURL == "" {
+				transports = append(transports, NewLogFarmerNotificationTransport(channel))
+				continue
+			}
+			transports = append(transports, NewHTTPMessageTransport(channel, cfg.WhatsAppURL, cfg.WhatsAppToken, timeout))
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationChannel, name)
+		}
+	}
+	return transports, nil
+}
+
+// SMTPEmailTransport sends plain text emails through an SMTP relay
+type SMTPEmailTransport struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPEmailTransport creates a new SMTPEmailTransport instance
+func NewSMTPEmailTransport(host, port, username, password, from string) *SMTPEmailTransport {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPEmailTransport{
+		addr: host + ":" + port,
+		auth: auth,
+		from: from,
+	}
+}
+
+// Channel returns the channel served by this transport
+func (t *SMTPEmailTransport) Channel() models.FarmerNotificationChannel {
+	return models.FarmerNotificationChannelEmail
+}
+
+// Send sends the message as a UTF-8 plain text email
+func (t *SMTPEmailTransport) Send(ctx context.Context, msg FarmerMessage) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", t.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.Recipient)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	if err := smtp.SendMail(t.addr, t.auth, envelopeAddress(t.from), []string{msg.Recipient}, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// envelopeAddress extracts the bare address from a "Name <address>" header value
+func envelopeAddress(from string) string {
+	if start, end := strings.Index(from, "<"), strings.Index(from, ">"); start >= 0 && end > start {
+		return from[start+1 : end]
+	}
+	return from
+}
+
+// HTTPMessageTransport posts text messages to an SMS or WhatsApp gateway as
+// {"to": "...", "message": "..."} with a bearer token
+type HTTPMessageTransport struct {
+	channel models.FarmerNotificationChannel
+	url     string
+	token   string
+	client  *http.Client
+}
+
+// NewHTTPMessageTransport creates a new HTTPMessageTransport instance
+func NewHTTPMessageTransport(channel models.FarmerNotificationChannel, url, token string, timeout time.Duration) *HTTPMessageTransport {
+	return &HTTPMessageTransport{
+		channel: channel,
+		url:     url,
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Channel returns the channel served by this transport
+func (t *HTTPMessageTransport) Channel() models.FarmerNotificationChannel {
+	return t.channel
+}
+
+// Send posts the message to the gateway
+func (t *HTTPMessageTransport) Send(ctx context.Context, msg FarmerMessage) error {
+	body, err := json.Marshal(map[string]string{
+		"to":      msg.Recipient,
+		"message": msg.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create gateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s gateway: %w", t.channel, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s gateway returned status %d", t.channel, resp.StatusCode)
+	}
+	return nil
+}
+
+// LogFarmerNotificationTransport is a local stub that logs messages instead of delivering them
+type LogFarmerNotificationTransport struct {
+	channel models.FarmerNotificationChannel
+}
+
+// NewLogFarmerNotificationTransport creates a new LogFarmerNotificationTransport instance
+func NewLogFarmerNotificationTransport(channel models.FarmerNotificationChannel) *LogFarmerNotificationTransport {
+	return &LogFarmerNotificationTransport{channel: channel}
+}
+
+// Channel returns the channel served by this transport
+func (t *LogFarmerNotificationTransport) Channel() models.FarmerNotificationChannel {
+	return t.channel
+}
+
+// Send writes the message to the server log
+func (t *LogFarmerNotificationTransport) Send(ctx context.Context, msg FarmerMessage) error {
+	log.Printf("[FarmerNotification] channel=%s to=%s subject=%q body=%q", t.channel, msg.Recipient, msg.Subject, msg.Body)
+	return nil
+}
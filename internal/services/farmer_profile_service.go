@@ -0,0 +1,536 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/ownafarm/ownafarm-backend/internal/validation"
+	"gorm.io/gorm"
+)
+
+// Errors for FarmerProfileService
+var (
+	ErrProfileChangeNotFound         = errors.New("profile change not found")
+	ErrProfileChangePending          = errors.New("a profile change is already waiting for review")
+	ErrProfileChangeAlreadyProcessed = errors.New("profile change has already been processed")
+	ErrBankAccountLocked             = errors.New("bank account cannot be changed while payouts are pending")
+	ErrPhoneNumberInUse              = errors.New("phone number is already used by another farmer")
+)
+
+// sensitiveProfileFields are applied only after an admin approves the change
+var sensitiveProfileFields = map[string]bool{
+	"id_number":           true,
+	"npwp":                true,
+	"bank_name":           true,
+	"bank_account_number": true,
+	"bank_account_name":   true,
+}
+
+// bankAccountFields are locked while the farmer has pending payouts
+var bankAccountFields = []string{"bank_name", "bank_account_number", "bank_account_name"}
+
+// FarmerProfileServiceInterface defines the interface for farmer profile self-service
+type FarmerProfileServiceInterface interface {
+	UpdateProfile(ctx context.Context, farmerID string, req *request.UpdateFarmerProfileRequest) (*response.UpdateFarmerProfileResponse, error)
+	ListMyChanges(ctx context.Context, farmerID string, req *request.ListProfileChangesRequest) (*response.ListProfileChangesResponse, error)
+
+	// Admin operations
+	ListForAdmin(ctx context.Context, req *request.ListProfileChangesRequest) (*response.ListProfileChangesResponse, error)
+	ApproveChange(ctx context.Context, changeID, adminID string) (*response.FarmerProfileChangeResponse, error)
+	RejectChange(ctx context.Context, changeID, adminID string, reason *string) (*response.FarmerProfileChangeResponse, error)
+}
+
+// FarmerProfileService implements FarmerProfileServiceInterface
+type FarmerProfileService struct {
+	farmerRepo  repositories.FarmerRepository
+	changeRepo  repositories.FarmerProfileChangeRepository
+	invoiceRepo repositories.InvoiceRepository
+	auditor     audit.Recorder
+	notifier    FarmerNotificationServiceInterface
+}
+
+// NewFarmerProfileService creates a new FarmerProfileService instance
+func NewFarmerProfileService(
+	farmerRepo repositories.FarmerRepository,
+	changeRepo repositories.FarmerProfileChangeRepository,
+	invoiceRepo repositories.InvoiceRepository,
+	auditor audit.Recorder,
+	notifier FarmerNotificationServiceInterface,
+) *FarmerProfileService {
+	return &FarmerProfileService{
+		farmerRepo:  farmerRepo,
+		changeRepo:  changeRepo,
+		invoiceRepo: invoiceRepo,
+		auditor:     auditor,
+		notifier:    notifier,
+	}
+}
+
+// UpdateProfile applies non-sensitive fields right away and turns sensitive fields into a
+// change request for admin review. Both are recorded with their old and new values.
+func (s *FarmerProfileService) UpdateProfile(ctx context.Context, farmerID string, req *request.UpdateFarmerProfileRequest) (*response.UpdateFarmerProfileResponse, error) {
+	farmer, err := s.farmerRepo.GetByID(farmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
+	}
+
+	if err := normalizeProfileUpdate(farmer, req); err != nil {
+		return nil, err
+	}
+
+	direct, sensitive, err := diffFarmerProfile(farmer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate everything before applying anything
+	if newPhone, ok := direct.new["phone_number"].(string); ok {
+		inUse, err := s.farmerRepo.ExistsByPhoneExcludingID(newPhone, farmerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check phone number: %w", err)
+		}
+		if inUse {
+			return nil, ErrPhoneNumberInUse
+		}
+	}
+	if newIDNumber, ok := sensitive.new["id_number"].(string); ok {
+		exists, err := s.farmerRepo.ExistsByIDNumber(newIDNumber, farmerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check NIK: %w", err)
+		}
+		if exists {
+			return nil, ErrIDNumberAlreadyExists
+		}
+	}
+	if len(sensitive.new) > 0 {
+		if _, err := s.changeRepo.GetPendingByFarmerID(farmerID); err == nil {
+			return nil, ErrProfileChangePending
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to check pending profile change: %w", err)
+		}
+		if err := s.checkBankAccountLock(farmerID, sensitive.fields()); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &response.UpdateFarmerProfileResponse{Profile: farmer}
+
+	if len(direct.new) > 0 {
+		change, err := direct.toChange(farmerID, models.ProfileChangeStatusApplied, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyFarmerValues(farmer, change.NewValues); err != nil {
+			return nil, err
+		}
+		if err := s.changeRepo.Apply(farmer, change); err != nil {
+			if errors.Is(err, repositories.ErrFarmerErased) {
+				return nil, ErrFarmerNotFound
+			}
+			return nil, fmt.Errorf("failed to update farmer profile: %w", err)
+		}
+		s.recordChange(ctx, models.AuditActionUpdateProfile, change, direct.fields(), nil)
+		resp.AppliedChange = toProfileChangeResponse(change)
+	}
+
+	if len(sensitive.new) > 0 {
+		change, err := sensitive.toChange(farmerID, models.ProfileChangeStatusPending, true)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.changeRepo.Create(change); err != nil {
+			return nil, fmt.Errorf("failed to create profile change request: %w", err)
+		}
+		s.recordChange(ctx, models.AuditActionRequestProfileChange, change, sensitive.fields(), nil)
+		resp.PendingChange = toProfileChangeResponse(change)
+	}
+
+	return resp, nil
+}
+
+// ListMyChanges lists the profile change history of a farmer
+func (s *FarmerProfileService) ListMyChanges(ctx context.Context, farmerID string, req *request.ListProfileChangesRequest) (*response.ListProfileChangesResponse, error) {
+	return s.list(repositories.ProfileChangeFilter{
+		FarmerID: farmerID,
+		Status:   req.Status,
+		Page:     req.Page,
+		Limit:    req.Limit,
+	})
+}
+
+// ListForAdmin lists change requests that need or needed admin review
+func (s *FarmerProfileService) ListForAdmin(ctx context.Context, req *request.ListProfileChangesRequest) (*response.ListProfileChangesResponse, error) {
+	requiresReview := true
+	return s.list(repositories.ProfileChangeFilter{
+		Status:         req.Status,
+		RequiresReview: &requiresReview,
+		Page:           req.Page,
+		Limit:          req.Limit,
+	})
+}
+
+// ApproveChange applies a pending sensitive change to the farmer profile
+func (s *FarmerProfileService) ApproveChange(ctx context.Context, changeID, adminID string) (*response.FarmerProfileChangeResponse, error) {
+	change, err := s.getPendingChange(changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := changeFields(change)
+	if err != nil {
+		return nil, err
+	}
+
+	farmer, err := s.farmerRepo.GetByID(change.FarmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
+	}
+	// Suspended and erased farmers keep their profile as it is
+	if farmer.Status != models.FarmerStatusApproved {
+		return nil, ErrFarmerNotApproved
+	}
+
+	// Payouts may have started since the request was made
+	if err := s.checkBankAccountLock(change.FarmerID, fields); err != nil {
+		return nil, err
+	}
+	if err := applyFarmerValues(farmer, change.NewValues); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	change.Status = models.ProfileChangeStatusApproved
+	change.ReviewedBy = &adminID
+	change.ReviewedAt = &now
+
+	if err := s.changeRepo.Apply(farmer, change); err != nil {
+		if errors.Is(err, repositories.ErrIDNumberTaken) {
+			return nil, ErrIDNumberAlreadyExists
+		}
+		if errors.Is(err, repositories.ErrFarmerErased) {
+			return nil, ErrFarmerNotApproved
+		}
+		return nil, fmt.Errorf("failed to apply profile change: %w", err)
+	}
+
+	s.recordChange(ctx, models.AuditActionApproveProfileChange, change, fields, pendingChangeState)
+	s.notifier.NotifyProfileChangeReviewed(ctx, farmer, change, fields)
+
+	return toProfileChangeResponse(change), nil
+}
+
+// RejectChange rejects a pending sensitive change, leaving the profile untouched
+func (s *FarmerProfileService) RejectChange(ctx context.Context, changeID, adminID string, reason *string) (*response.FarmerProfileChangeResponse, error) {
+	change, err := s.getPendingChange(changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := changeFields(change)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	change.Status = models.ProfileChangeStatusRejected
+	change.ReviewedBy = &adminID
+	change.ReviewedAt = &now
+	change.RejectionReason = reason
+
+	if err := s.changeRepo.Update(change); err != nil {
+		return nil, fmt.Errorf("failed to reject profile change: %w", err)
+	}
+
+	s.recordChange(ctx, models.AuditActionRejectProfileChange, change, fields, pendingChangeState)
+	s.notifier.NotifyProfileChangeReviewed(ctx, &change.Farmer, change, fields)
+
+	return toProfileChangeResponse(change), nil
+}
+
+// getPendingChange loads a change and makes sure it is still waiting for review
+func (s *FarmerProfileService) getPendingChange(changeID string) (*models.FarmerProfileChange, error) {
+	change, err := s.changeRepo.GetByID(changeID)
+	if err != nil {
+		return nil, ErrProfileChangeNotFound
+	}
+	if change.Status != models.ProfileChangeStatusPending {
+		return nil, ErrProfileChangeAlreadyProcessed
+	}
+	return change, nil
+}
+
+// checkBankAccountLock rejects bank account changes while the farmer has pending payouts
+func (s *FarmerProfileService) checkBankAccountLock(farmerID string, fields []string) error {
+	touchesBank := false
+	for _, field := range fields {
+		for _, bankField := range bankAccountFields {
+			if field == bankField {
+				touchesBank = true
+			}
+		}
+	}
+	if !touchesBank {
+		return nil
+	}
+
+	pending, err := s.invoiceRepo.HasPendingPayoutsByFarmerID(farmerID)
+	if err != nil {
+		return fmt.Errorf("failed to check pending payouts: %w", err)
+	}
+	if pending {
+		return ErrBankAccountLocked
+	}
+	return nil
+}
+
+// list builds a paginated change list response
+func (s *FarmerProfileService) list(filter repositories.ProfileChangeFilter) (*response.ListProfileChangesResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+
+	changes, totalCount, err := s.changeRepo.GetAll(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile changes: %w", err)
+	}
+
+	items := make([]response.FarmerProfileChangeResponse, 0, len(changes))
+	for i := range changes {
+		items = append(items, *toProfileChangeResponse(&changes[i]))
+	}
+
+	return &response.ListProfileChangesResponse{
+		Changes: items,
+		Pagination: response.PaginationMeta{
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			TotalItems: totalCount,
+			TotalPages: int(math.Ceil(float64(totalCount) / float64(filter.Limit))),
+		},
+	}, nil
+}
+
+// pendingChangeState is the audit state of a change before it is reviewed
+var pendingChangeState = map[string]interface{}{"status": models.ProfileChangeStatusPending}
+
+// recordChange records a profile change or its review. Only field names are stored here; the
+// values live in farmer_profile_changes so bank and identity numbers are not copied around.
+func (s *FarmerProfileService) recordChange(ctx context.Context, action string, change *models.FarmerProfileChange, fields []string, before interface{}) {
+	after := map[string]interface{}{"status": change.Status, "fields": fields}
+	if change.RejectionReason != nil {
+		after["rejection_reason"] = *change.RejectionReason
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     action,
+		EntityType: models.AuditEntityTypeProfileChange,
+		EntityID:   change.ID,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// profileDiff holds the old and new values of the fields that actually change
+type profileDiff struct {
+	old map[string]interface{}
+	new map[string]interface{}
+}
+
+// fields returns the changed field names in a stable order
+func (d profileDiff) fields() []string {
+	fields := make([]string, 0, len(d.new))
+	for field := range d.new {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// toChange builds the change record for the diff, with PII values encrypted
+func (d profileDiff) toChange(farmerID string, status models.ProfileChangeStatus, requiresReview bool) (*models.FarmerProfileChange, error) {
+	oldSealed, newSealed := copyValues(d.old), copyValues(d.new)
+	if err := pii.SealFields(oldSealed, models.FarmerPIIFields...); err != nil {
+		return nil, err
+	}
+	if err := pii.SealFields(newSealed, models.FarmerPIIFields...); err != nil {
+		return nil, err
+	}
+
+	oldValues, err := json.Marshal(oldSealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal old values: %w", err)
+	}
+	newValues, err := json.Marshal(newSealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal new values: %w", err)
+	}
+	return &models.FarmerProfileChange{
+		FarmerID:       farmerID,
+		OldValues:      oldValues,
+		NewValues:      newValues,
+		RequiresReview: requiresReview,
+		Status:         status,
+	}, nil
+}
+
+// diffFarmerProfile compares the requested values with the current profile using the
+// JSON field names of models.Farmer, and splits the real changes into direct and sensitive
+func diffFarmerProfile(farmer *models.Farmer, req *request.UpdateFarmerProfileRequest) (profileDiff, profileDiff, error) {
+	direct := profileDiff{old: map[string]interface{}{}, new: map[string]interface{}{}}
+	sensitive := profileDiff{old: map[string]interface{}{}, new: map[string]interface{}{}}
+
+	requested, err := toJSONMap(req)
+	if err != nil {
+		return direct, sensitive, err
+	}
+	current, err := toJSONMap(farmer)
+	if err != nil {
+		return direct, sensitive, err
+	}
+
+	for field, newValue := range requested {
+		oldValue := current[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		target := direct
+		if sensitiveProfileFields[field] {
+			target = sensitive
+		}
+		target.old[field] = oldValue
+		target.new[field] = newValue
+	}
+	return direct, sensitive, nil
+}
+
+// copyValues returns a shallow copy of a value map
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values))
+	for field, value := range values {
+		copied[field] = value
+	}
+	return copied
+}
+
+// applyFarmerValues decrypts the recorded new values and writes them onto the farmer
+func applyFarmerValues(farmer *models.Farmer, values json.RawMessage) error {
+	var opened map[string]interface{}
+	if err := json.Unmarshal(values, &opened); err != nil {
+		return fmt.Errorf("failed to read profile values: %w", err)
+	}
+	if err := pii.OpenFields(opened, models.FarmerPIIFields...); err != nil {
+		return err
+	}
+	plain, err := json.Marshal(opened)
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile values: %w", err)
+	}
+	if err := json.Unmarshal(plain, farmer); err != nil {
+		return fmt.Errorf("failed to apply profile values: %w", err)
+	}
+	return nil
+}
+
+// changeFields returns the field names of a recorded change
+func changeFields(change *models.FarmerProfileChange) ([]string, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(change.NewValues, &values); err != nil {
+		return nil, fmt.Errorf("failed to read profile change values: %w", err)
+	}
+	return profileDiff{new: values}.fields(), nil
+}
+
+// toJSONMap round-trips a value through JSON so values compare the way they are stored
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal profile: %w", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal profile: %w", err)
+	}
+	return m, nil
+}
+
+// toProfileChangeResponse converts a profile change model to its response, with PII values masked
+func toProfileChangeResponse(change *models.FarmerProfileChange) *response.FarmerProfileChangeResponse {
+	var oldValues, newValues map[string]interface{}
+	_ = json.Unmarshal(change.OldValues, &oldValues)
+	_ = json.Unmarshal(change.NewValues, &newValues)
+	pii.MaskFields(oldValues, models.FarmerPIIFields...)
+	pii.MaskFields(newValues, models.FarmerPIIFields...)
+
+	return &response.FarmerProfileChangeResponse{
+		ID:              change.ID,
+		FarmerID:        change.FarmerID,
+		FarmerName:      change.Farmer.FullName,
+		Status:          string(change.Status),
+		RequiresReview:  change.RequiresReview,
+		Fields:          profileDiff{new: newValues}.fields(),
+		OldValues:       oldValues,
+		NewValues:       newValues,
+		ReviewedBy:      change.ReviewedBy,
+		ReviewedAt:      change.ReviewedAt,
+		RejectionReason: change.RejectionReason,
+		CreatedAt:       change.CreatedAt,
+	}
+}
+
+// normalizeProfileUpdate validates the Indonesian identity fields present in a profile update
+// against the rest of the profile and rewrites the phone number in E.164 format
+func normalizeProfileUpdate(farmer *models.Farmer, req *request.UpdateFarmerProfileRequest) error {
+	var fieldErrs validation.Errors
+
+	if req.PhoneNumber != nil {
+		phone, err := validation.NormalizePhoneNumber(*req.PhoneNumber)
+		if err != nil {
+			fieldErrs.Add("phone_number", err.Error())
+		} else {
+			req.PhoneNumber = &phone
+		}
+	}
+	if req.IDNumber != nil {
+		if _, err := validation.ValidateNIK(*req.IDNumber, farmer.DateOfBirth); err != nil {
+			fieldErrs.Add("id_number", err.Error())
+		}
+	}
+	if req.NPWP != nil && *req.NPWP != "" {
+		if _, err := validation.NormalizeNPWP(*req.NPWP); err != nil {
+			fieldErrs.Add("npwp", err.Error())
+		}
+	}
+
+	// Province and postal code are checked together, using the stored value for the one not sent
+	if req.Province != nil || req.PostalCode != nil {
+		province, postalCode := farmer.Province, farmer.PostalCode
+		if req.Province != nil {
+			province = *req.Province
+			if err := validation.ValidateProvince(province); err != nil {
+				fieldErrs.Add("province", err.Error())
+			}
+		}
+		if req.PostalCode != nil {
+			postalCode = *req.PostalCode
+		}
+		if err := validation.ValidatePostalCode(postalCode, province); err != nil {
+			fieldErrs.Add("postal_code", err.Error())
+		}
+	}
+	return fieldErrs.Err()
+}
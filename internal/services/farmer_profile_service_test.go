@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTestKeyring sets a default PII keyring for code paths that encrypt values
+func useTestKeyring(t *testing.T) {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	keyring, err := pii.NewKeyring(&config.PIIConfig{ActiveKeyID: "test", Keys: []string{"test:" + key}, BlindIndexKey: key})
+	require.NoError(t, err)
+	pii.SetDefault(keyring)
+}
+
+func TestDiffFarmerProfile_SplitsDirectAndSensitiveChanges(t *testing.T) {
+	useTestKeyring(t)
+
+	farmer := &models.Farmer{
+		ID:                "farmer-1",
+		PhoneNumber:       "+6281111111111",
+		City:              "Bandung",
+		BankName:          "Bank BCA",
+		BankAccountNumber: "1234567890",
+		CropsExpertise:    pq.StringArray{"padi"},
+	}
+
+	city := "Bandung" // unchanged, must not be recorded
+	phone := "+6282222222222"
+	bankAccount := "0987654321"
+	crops := []string{"padi", "jagung"}
+	direct, sensitive, err := diffFarmerProfile(farmer, &request.UpdateFarmerProfileRequest{
+		City:              &city,
+		PhoneNumber:       &phone,
+		CropsExpertise:    &crops,
+		BankAccountNumber: &bankAccount,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"crops_expertise", "phone_number"}, direct.fields())
+	assert.Equal(t, "+6281111111111", direct.old["phone_number"])
+	assert.Equal(t, []string{"bank_account_number"}, sensitive.fields())
+	assert.Equal(t, "1234567890", sensitive.old["bank_account_number"])
+
+	change, err := sensitive.toChange(farmer.ID, models.ProfileChangeStatusPending, true)
+	require.NoError(t, err)
+	assert.NotContains(t, string(change.NewValues), "0987654321", "PII is encrypted in change records")
+	assert.Equal(t, "******4321", toProfileChangeResponse(change).NewValues["bank_account_number"])
+	require.NoError(t, applyFarmerValues(farmer, change.NewValues))
+	assert.Equal(t, "0987654321", farmer.BankAccountNumber)
+	assert.Equal(t, "Bank BCA", farmer.BankName)
+	assert.Equal(t, "farmer-1", farmer.ID)
+}
+
+// conflictingChangeRepo holds one pending change and fails Apply with err, the way the
+// repository does when the NIK was registered or the farmer erased while the change was pending
+type conflictingChangeRepo struct {
+	repositories.FarmerProfileChangeRepository
+	change *models.FarmerProfileChange
+	err    error
+}
+
+func (r *conflictingChangeRepo) GetByID(id string) (*models.FarmerProfileChange, error) {
+	return r.change, nil
+}
+
+func (r *conflictingChangeRepo) Apply(farmer *models.Farmer, change *models.FarmerProfileChange) error {
+	return r.err
+}
+
+func TestApproveChange_RejectsNIKTakenMeanwhile(t *testing.T) {
+	useTestKeyring(t)
+
+	farmer := testFarmer(models.FarmerStatusApproved, "id")
+	farmer.IDNumber = "3201010101010001"
+	newIDNumber := "3201010101010002"
+	_, sensitive, err := diffFarmerProfile(farmer, &request.UpdateFarmerProfileRequest{IDNumber: &newIDNumber})
+	require.NoError(t, err)
+	change, err := sensitive.toChange(farmer.ID, models.ProfileChangeStatusPending, true)
+	require.NoError(t, err)
+
+	svc := NewFarmerProfileService(&stubFarmerRepo{farmer: farmer}, &conflictingChangeRepo{change: change, err: repositories.ErrIDNumberTaken}, nil, nil, nil)
+	_, err = svc.ApproveChange(context.Background(), "change-1", "admin-1")
+	assert.ErrorIs(t, err, ErrIDNumberAlreadyExists)
+}
+
+func TestApproveChange_RefusesFarmersNotApproved(t *testing.T) {
+	useTestKeyring(t)
+
+	newIDNumber := "3201010101010002"
+	pendingChange := func(farmer *models.Farmer) *models.FarmerProfileChange {
+		farmer.IDNumber = "3201010101010001"
+		_, sensitive, err := diffFarmerProfile(farmer, &request.UpdateFarmerProfileRequest{IDNumber: &newIDNumber})
+		require.NoError(t, err)
+		change, err := sensitive.toChange(farmer.ID, models.ProfileChangeStatusPending, true)
+		require.NoError(t, err)
+		return change
+	}
+
+	// Neither apply nor notification happen for these farmers
+	for _, status := range []models.FarmerStatus{models.FarmerStatusSuspended, models.FarmerStatusErased} {
+		t.Run(string(status), func(t *testing.T) {
+			farmer := testFarmer(status, "id")
+			svc := NewFarmerProfileService(&stubFarmerRepo{farmer: farmer}, &conflictingChangeRepo{change: pendingChange(farmer)}, nil, nil, nil)
+			_, err := svc.ApproveChange(context.Background(), "change-1", "admin-1")
+			assert.ErrorIs(t, err, ErrFarmerNotApproved)
+		})
+	}
+
+	t.Run("erased while approving", func(t *testing.T) {
+		farmer := testFarmer(models.FarmerStatusApproved, "id")
+		changeRepo := &conflictingChangeRepo{change: pendingChange(farmer), err: repositories.ErrFarmerErased}
+		svc := NewFarmerProfileService(&stubFarmerRepo{farmer: farmer}, changeRepo, nil, nil, nil)
+		_, err := svc.ApproveChange(context.Background(), "change-1", "admin-1")
+		assert.ErrorIs(t, err, ErrFarmerNotApproved)
+	})
+}
@@ -11,10 +11,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/pii"
 	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/ownafarm/ownafarm-backend/internal/validation"
 )
 
 // Errors for FarmerService
@@ -27,6 +30,13 @@ var (
 	ErrDocumentNotFound           = errors.New("document not found")
 	ErrInvalidStatusTransition    = errors.New("invalid status transition")
 	ErrFarmerAlreadyProcessed     = errors.New("farmer has already been processed")
+	ErrFarmerRejectedResubmit     = errors.New("farmer registration was rejected, log in to resubmit")
+	ErrFarmerNotRejected          = errors.New("only rejected registrations can be resubmitted")
+	ErrWalletAddressMismatch      = errors.New("wallet address does not match the logged-in farmer")
+	ErrFarmerClaimedByOther       = errors.New("farmer application is claimed by another admin")
+	ErrFarmerNotApproved          = errors.New("farmer is not approved")
+	ErrRequiredDocumentsMissing   = errors.New("farmer is missing required documents")
+	ErrIDNumberAlreadyExists      = errors.New("farmer with this NIK already exists")
 )
 
 // walletAddressRegex validates Ethereum wallet address format (0x + 40 hex chars)
@@ -39,28 +49,46 @@ type FarmerServiceInterface interface {
 	GetDocumentDownloadURL(ctx context.Context, farmerID, documentID string) (*response.DocumentDownloadURLResponse, error)
 	GetListForAdmin(ctx context.Context, req *request.ListFarmerRequest) (*response.ListFarmerResponse, error)
 	GetDetailForAdmin(ctx context.Context, farmerID string) (*response.FarmerDetailResponse, error)
-	ApproveFarmer(ctx context.Context, farmerID, adminID, ipAddress, userAgent string) (*response.FarmerStatusUpdateResponse, error)
-	RejectFarmer(ctx context.Context, farmerID, adminID string, reason *string, ipAddress, userAgent string) (*response.FarmerStatusUpdateResponse, error)
+	ApproveFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error)
+	RejectFarmer(ctx context.Context, farmerID, adminID string, reason *string) (*response.FarmerStatusUpdateResponse, error)
+	ClaimFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error)
+	ReleaseFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error)
+	SuspendFarmer(ctx context.Context, farmerID, adminID, reason string) (*response.FarmerStatusUpdateResponse, error)
+	ReinstateFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error)
+	RevealPII(ctx context.Context, farmerID, adminID string, req *request.RevealFarmerPIIRequest) (*response.FarmerPIIResponse, error)
 	GetByID(ctx context.Context, farmerID string) (*models.Farmer, error)
+
+	// Registration follow-up (application scope)
+	GetApplication(ctx context.Context, farmerID string) (*response.FarmerApplicationResponse, error)
+	Resubmit(ctx context.Context, farmerID string, req *request.RegisterFarmerRequest) (*response.FarmerApplicationResponse, error)
 }
 
 // FarmerService implements FarmerServiceInterface
 type FarmerService struct {
 	farmerRepo     repositories.FarmerRepository
 	storageService StorageService
-	auditLogRepo   repositories.AuditLogRepository
+	auditor        audit.Recorder
+	submissionRepo repositories.FarmerSubmissionRepository
+	documentPolicy *RequiredDocumentPolicy
+	notifier       FarmerNotificationServiceInterface
 }
 
 // NewFarmerService creates a new FarmerService instance
 func NewFarmerService(
 	farmerRepo repositories.FarmerRepository,
 	storageService StorageService,
-	auditLogRepo repositories.AuditLogRepository,
+	auditor audit.Recorder,
+	submissionRepo repositories.FarmerSubmissionRepository,
+	documentPolicy *RequiredDocumentPolicy,
+	notifier FarmerNotificationServiceInterface,
 ) *FarmerService {
 	return &FarmerService{
 		farmerRepo:     farmerRepo,
 		storageService: storageService,
-		auditLogRepo:   auditLogRepo,
+		auditor:        auditor,
+		submissionRepo: submissionRepo,
+		documentPolicy: documentPolicy,
+		notifier:       notifier,
 	}
 }
 
@@ -72,12 +100,21 @@ func (s *FarmerService) Register(ctx context.Context, req *request.RegisterFarme
 		return nil, ErrInvalidWalletAddress
 	}
 
+	// Validate identity data and normalize the phone number before the uniqueness checks
+	if err := normalizeRegistration(req); err != nil {
+		return nil, err
+	}
+
 	// Check if wallet address already exists
 	walletExists, err := s.farmerRepo.ExistsByWalletAddress(walletAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing wallet address: %w", err)
 	}
 	if walletExists {
+		// Rejected farmers fix and resubmit their existing registration instead
+		if existing, err := s.farmerRepo.GetByWalletAddress(walletAddress); err == nil && existing.Status == models.FarmerStatusRejected {
+			return nil, ErrFarmerRejectedResubmit
+		}
 		return nil, ErrWalletAddressAlreadyExists
 	}
 
@@ -93,46 +130,33 @@ func (s *FarmerService) Register(ctx context.Context, req *request.RegisterFarme
 		return nil, ErrFarmerAlreadyExists
 	}
 
-	// Parse date of birth
-	dob, err := time.Parse("2006-01-02", req.PersonalInfo.DateOfBirth)
+	// NIKs are encrypted, so they are compared through their blind index
+	idNumberExists, err := s.farmerRepo.ExistsByIDNumber(req.PersonalInfo.IDNumber, "")
 	if err != nil {
-		return nil, ErrInvalidDateFormat
+		return nil, fmt.Errorf("failed to check existing NIK: %w", err)
+	}
+	if idNumberExists {
+		return nil, ErrIDNumberAlreadyExists
 	}
 
-	// Build farmer model
-	farmer := &models.Farmer{
-		Status:        models.FarmerStatusPending,
-		WalletAddress: walletAddress,
-
-		// Personal Info
-		FullName:    req.PersonalInfo.FullName,
-		Email:       req.PersonalInfo.Email,
-		PhoneNumber: req.PersonalInfo.PhoneNumber,
-		IDNumber:    req.PersonalInfo.IDNumber,
-		DateOfBirth: dob,
-		Address:     req.PersonalInfo.Address,
-		Province:    req.PersonalInfo.Province,
-		City:        req.PersonalInfo.City,
-		District:    req.PersonalInfo.District,
-		PostalCode:  req.PersonalInfo.PostalCode,
-
-		// Business Info
-		BusinessName:      req.BusinessInfo.BusinessName,
-		BusinessType:      models.BusinessType(req.BusinessInfo.BusinessType),
-		NPWP:              req.BusinessInfo.NPWP,
-		BankName:          req.BusinessInfo.BankName,
-		BankAccountNumber: req.BusinessInfo.BankAccountNumber,
-		BankAccountName:   req.BusinessInfo.BankAccountName,
-		YearsOfExperience: 0,
-		CropsExpertise:    pq.StringArray{},
+	// Verify the documents required for the business type and the uploads in storage
+	// before creating anything
+	if err := s.documentPolicy.checkRequiredDocuments(models.BusinessType(req.BusinessInfo.BusinessType), req.Documents); err != nil {
+		return nil, err
+	}
+	verifiedDocs, err := verifyDocuments(ctx, s.storageService, req.Documents)
+	if err != nil {
+		return nil, err
 	}
 
-	// Set optional fields
-	if req.BusinessInfo.YearsOfExperience != nil {
-		farmer.YearsOfExperience = *req.BusinessInfo.YearsOfExperience
+	// Build farmer model
+	farmer := &models.Farmer{
+		Status:            models.FarmerStatusPending,
+		WalletAddress:     walletAddress,
+		PreferredLanguage: models.LanguageIndonesian,
 	}
-	if len(req.BusinessInfo.CropsExpertise) > 0 {
-		farmer.CropsExpertise = pq.StringArray(req.BusinessInfo.CropsExpertise)
+	if err := applyRegistration(farmer, req); err != nil {
+		return nil, err
 	}
 
 	// Create farmer record
@@ -141,24 +165,30 @@ func (s *FarmerService) Register(ctx context.Context, req *request.RegisterFarme
 	}
 
 	// Build and create document records
-	// Store only the file_key in FileURL column (not a public URL)
-	documents := make([]models.FarmerDocument, 0, len(req.Documents))
-	for _, doc := range req.Documents {
-		documents = append(documents, models.FarmerDocument{
-			FarmerID:     farmer.ID,
-			DocumentType: models.DocumentType(doc.DocumentType),
-			FileURL:      doc.FileKey, // Store file_key directly, not a public URL
-			FileName:     doc.FileName,
-			FileSize:     doc.FileSize,
-			MimeType:     doc.MimeType,
-		})
-	}
-
+	documents := buildFarmerDocuments(farmer.ID, verifiedDocs)
 	if err := s.farmerRepo.CreateDocuments(documents); err != nil {
 		return nil, fmt.Errorf("failed to create farmer documents: %w", err)
 	}
 
 	farmer.Documents = documents
+
+	// Record the registration as submission 1
+	submission, err := newFarmerSubmission(farmer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.submissionRepo.Create(submission); err != nil {
+		return nil, fmt.Errorf("failed to record farmer submission: %w", err)
+	}
+
+	// Registration is public, the new farmer is the actor. Identity data stays out of the log.
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionRegisterFarmer,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmer.ID,
+		After:      map[string]interface{}{"status": farmer.Status, "business_type": farmer.BusinessType},
+		Actor:      &audit.Actor{Type: audit.ActorFarmer, ID: farmer.ID},
+	})
 	return farmer, nil
 }
 
@@ -315,6 +345,26 @@ func (s *FarmerService) GetDetailForAdmin(ctx context.Context, farmerID string)
 		})
 	}
 
+	// Required documents for the business type
+	checklist := s.documentPolicy.Checklist(farmer.BusinessType, submittedDocumentTypes(farmer.Documents))
+	documentsComplete := true
+	for _, item := range checklist {
+		documentsComplete = documentsComplete && item.Submitted
+	}
+
+	// Submission history (registration and resubmissions)
+	submissions, err := s.submissionRepo.GetAllByFarmerID(farmerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farmer submissions: %w", err)
+	}
+
+	// Identity and bank numbers are masked; admins use RevealPII to see them
+	var npwp *string
+	if farmer.NPWP != nil {
+		masked := pii.Mask(*farmer.NPWP)
+		npwp = &masked
+	}
+
 	// Format dates
 	var reviewedAt *string
 	if farmer.ReviewedAt != nil {
@@ -335,8 +385,8 @@ func (s *FarmerService) GetDetailForAdmin(ctx context.Context, farmerID string)
 		FullName:          farmer.FullName,
 		Email:             farmer.Email,
 		PhoneNumber:       farmer.PhoneNumber,
-		IDNumber:          farmer.IDNumber,
-		DateOfBirth:       farmer.DateOfBirth.Format("2006-01-02"),
+		IDNumber:          pii.Mask(farmer.IDNumber),
+		DateOfBirth:       pii.MaskDate(farmer.DateOfBirth.Format("2006-01-02")),
 		Address:           farmer.Address,
 		Province:          farmer.Province,
 		City:              farmer.City,
@@ -344,13 +394,16 @@ func (s *FarmerService) GetDetailForAdmin(ctx context.Context, farmerID string)
 		PostalCode:        farmer.PostalCode,
 		BusinessName:      farmer.BusinessName,
 		BusinessType:      string(farmer.BusinessType),
-		NPWP:              farmer.NPWP,
+		NPWP:              npwp,
 		BankName:          farmer.BankName,
-		BankAccountNumber: farmer.BankAccountNumber,
+		BankAccountNumber: pii.Mask(farmer.BankAccountNumber),
 		BankAccountName:   farmer.BankAccountName,
 		YearsOfExperience: farmer.YearsOfExperience,
 		CropsExpertise:    cropsExpertise,
 		Documents:         documents,
+		DocumentChecklist: checklist,
+		DocumentsComplete: documentsComplete,
+		Submissions:       toSubmissionItems(submissions),
 		ReviewedBy:        farmer.ReviewedBy,
 		ReviewedAt:        reviewedAt,
 		RejectionReason:   farmer.RejectionReason,
@@ -358,21 +411,69 @@ func (s *FarmerService) GetDetailForAdmin(ctx context.Context, farmerID string)
 	}, nil
 }
 
+// RevealPII returns the unmasked identity and bank values requested by an admin. Every reveal is
+// audit logged with the fields and the reason, never the values; nothing is revealed when the
+// audit log cannot be written.
+func (s *FarmerService) RevealPII(ctx context.Context, farmerID, adminID string, req *request.RevealFarmerPIIRequest) (*response.FarmerPIIResponse, error) {
+	farmer, err := s.farmerRepo.GetByID(farmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
+	}
+
+	values := make(map[string]string, len(req.Fields))
+	for _, field := range req.Fields {
+		switch field {
+		case "id_number":
+			values[field] = farmer.IDNumber
+		case "date_of_birth":
+			values[field] = farmer.DateOfBirth.Format("2006-01-02")
+		case "npwp":
+			if farmer.NPWP != nil {
+				values[field] = *farmer.NPWP
+			}
+		case "bank_account_number":
+			values[field] = farmer.BankAccountNumber
+		}
+	}
+
+	now := time.Now()
+	if err := s.auditor.RecordRequired(ctx, audit.Entry{
+		Action:     models.AuditActionRevealFarmerPII,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmerID,
+		After:      map[string]interface{}{"fields": req.Fields, "reason": req.Reason},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to record PII reveal: %w", err)
+	}
+
+	return &response.FarmerPIIResponse{
+		FarmerID:   farmerID,
+		Values:     values,
+		RevealedBy: adminID,
+		RevealedAt: now,
+	}, nil
+}
+
 // ApproveFarmer approves a farmer registration
-func (s *FarmerService) ApproveFarmer(ctx context.Context, farmerID, adminID, ipAddress, userAgent string) (*response.FarmerStatusUpdateResponse, error) {
+func (s *FarmerService) ApproveFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
 	// Get farmer
 	farmer, err := s.farmerRepo.GetByID(farmerID)
 	if err != nil {
 		return nil, ErrFarmerNotFound
 	}
 
-	// Validate status transition (only pending can be approved)
-	if farmer.Status != models.FarmerStatusPending {
-		return nil, ErrFarmerAlreadyProcessed
+	// Validate status transition (only pending or claimed applications can be approved)
+	if err := checkReviewable(farmer, adminID); err != nil {
+		return nil, err
+	}
+
+	// The policy may have changed since registration, so check the documents again
+	if missing := s.documentPolicy.Missing(farmer.BusinessType, submittedDocumentTypes(farmer.Documents)); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrRequiredDocumentsMissing, missing)
 	}
 
-	// Store old status for audit
-	oldStatus := string(farmer.Status)
+	// Store the review state for audit
+	before := toFarmerReviewState(farmer)
 
 	// Update farmer status
 	now := time.Now()
@@ -380,12 +481,27 @@ func (s *FarmerService) ApproveFarmer(ctx context.Context, farmerID, adminID, ip
 	farmer.ReviewedBy = &adminID
 	farmer.ReviewedAt = &now
 
-	if err := s.farmerRepo.Update(farmer); err != nil {
+	if err := s.farmerRepo.UpdateStatus(farmer, before.Status, before.ReviewedBy); err != nil {
+		if errors.Is(err, repositories.ErrFarmerStatusChanged) {
+			// Claimed or decided by another admin meanwhile
+			return nil, ErrFarmerAlreadyProcessed
+		}
 		return nil, fmt.Errorf("failed to update farmer: %w", err)
 	}
 
-	// Create audit log
-	s.createAuditLog(adminID, models.AuditActionApproveFarmer, models.AuditEntityTypeFarmer, farmerID, oldStatus, string(farmer.Status), nil, ipAddress, userAgent)
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionApproveFarmer,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmerID,
+		Before:     before,
+		After:      toFarmerReviewState(farmer),
+	})
+
+	// Record the decision on the submission that was reviewed
+	s.recordDecision(farmer)
+
+	// Tell the farmer about the outcome
+	s.notifier.NotifyFarmerReviewed(ctx, farmer)
 
 	return &response.FarmerStatusUpdateResponse{
 		FarmerID:   farmer.ID,
@@ -397,20 +513,20 @@ func (s *FarmerService) ApproveFarmer(ctx context.Context, farmerID, adminID, ip
 }
 
 // RejectFarmer rejects a farmer registration
-func (s *FarmerService) RejectFarmer(ctx context.Context, farmerID, adminID string, reason *string, ipAddress, userAgent string) (*response.FarmerStatusUpdateResponse, error) {
+func (s *FarmerService) RejectFarmer(ctx context.Context, farmerID, adminID string, reason *string) (*response.FarmerStatusUpdateResponse, error) {
 	// Get farmer
 	farmer, err := s.farmerRepo.GetByID(farmerID)
 	if err != nil {
 		return nil, ErrFarmerNotFound
 	}
 
-	// Validate status transition (only pending can be rejected)
-	if farmer.Status != models.FarmerStatusPending {
-		return nil, ErrFarmerAlreadyProcessed
+	// Validate status transition (only pending or claimed applications can be rejected)
+	if err := checkReviewable(farmer, adminID); err != nil {
+		return nil, err
 	}
 
-	// Store old status for audit
-	oldStatus := string(farmer.Status)
+	// Store the review state for audit
+	before := toFarmerReviewState(farmer)
 
 	// Update farmer status
 	now := time.Now()
@@ -419,12 +535,27 @@ func (s *FarmerService) RejectFarmer(ctx context.Context, farmerID, adminID stri
 	farmer.ReviewedAt = &now
 	farmer.RejectionReason = reason
 
-	if err := s.farmerRepo.Update(farmer); err != nil {
+	if err := s.farmerRepo.UpdateStatus(farmer, before.Status, before.ReviewedBy); err != nil {
+		if errors.Is(err, repositories.ErrFarmerStatusChanged) {
+			// Claimed or decided by another admin meanwhile
+			return nil, ErrFarmerAlreadyProcessed
+		}
 		return nil, fmt.Errorf("failed to update farmer: %w", err)
 	}
 
-	// Create audit log
-	s.createAuditLog(adminID, models.AuditActionRejectFarmer, models.AuditEntityTypeFarmer, farmerID, oldStatus, string(farmer.Status), reason, ipAddress, userAgent)
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionRejectFarmer,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmerID,
+		Before:     before,
+		After:      toFarmerReviewState(farmer),
+	})
+
+	// Record the decision on the submission that was reviewed
+	s.recordDecision(farmer)
+
+	// Tell the farmer about the outcome
+	s.notifier.NotifyFarmerReviewed(ctx, farmer)
 
 	return &response.FarmerStatusUpdateResponse{
 		FarmerID:   farmer.ID,
@@ -435,38 +566,147 @@ func (s *FarmerService) RejectFarmer(ctx context.Context, farmerID, adminID stri
 	}, nil
 }
 
-// createAuditLog creates an audit log entry
-func (s *FarmerService) createAuditLog(adminID, action, entityType, entityID, oldStatus, newStatus string, reason *string, ipAddress, userAgent string) {
-	oldValues, _ := json.Marshal(map[string]interface{}{"status": oldStatus})
-	newValuesMap := map[string]interface{}{"status": newStatus}
-	if reason != nil {
-		newValuesMap["rejection_reason"] = *reason
+// ClaimFarmer moves a pending application to under_review, assigned to the claiming admin
+func (s *FarmerService) ClaimFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
+	return s.transition(ctx, farmerID, adminID, models.FarmerStatusPending, models.FarmerStatusUnderReview, models.AuditActionClaimFarmer, func(farmer *models.Farmer) error {
+		farmer.ReviewedBy = &adminID
+		return nil
+	})
+}
+
+// ReleaseFarmer returns a claimed application to the pending queue. Rejected registrations
+// go back to the queue only by resubmitting.
+func (s *FarmerService) ReleaseFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
+	return s.transition(ctx, farmerID, adminID, models.FarmerStatusUnderReview, models.FarmerStatusPending, models.AuditActionReleaseFarmer, func(farmer *models.Farmer) error {
+		// Only the claiming admin can release the application
+		if farmer.ReviewedBy != nil && *farmer.ReviewedBy != adminID {
+			return ErrFarmerClaimedByOther
+		}
+		farmer.ReviewedBy = nil
+		return nil
+	})
+}
+
+// SuspendFarmer suspends an approved farmer, blocking their access and hiding their invoices
+func (s *FarmerService) SuspendFarmer(ctx context.Context, farmerID, adminID, reason string) (*response.FarmerStatusUpdateResponse, error) {
+	return s.transition(ctx, farmerID, adminID, models.FarmerStatusApproved, models.FarmerStatusSuspended, models.AuditActionSuspendFarmer, func(farmer *models.Farmer) error {
+		now := time.Now()
+		farmer.SuspendedBy = &adminID
+		farmer.SuspendedAt = &now
+		farmer.SuspensionReason = &reason
+		return nil
+	})
+}
+
+// ReinstateFarmer lifts the suspension of a farmer. Pending applications are approved through
+// ApproveFarmer, never by reinstating.
+func (s *FarmerService) ReinstateFarmer(ctx context.Context, farmerID, adminID string) (*response.FarmerStatusUpdateResponse, error) {
+	return s.transition(ctx, farmerID, adminID, models.FarmerStatusSuspended, models.FarmerStatusApproved, models.AuditActionReinstateFarmer, func(farmer *models.Farmer) error {
+		farmer.SuspendedBy = nil
+		farmer.SuspendedAt = nil
+		farmer.SuspensionReason = nil
+		return nil
+	})
+}
+
+// transition moves a farmer from the status the action starts from to the next status, applies
+// the status specific changes and records the audit log. Other statuses the state machine
+// allows to move to next are left to their own flows (review, resubmission).
+func (s *FarmerService) transition(ctx context.Context, farmerID, adminID string, from, next models.FarmerStatus, action string, apply func(farmer *models.Farmer) error) (*response.FarmerStatusUpdateResponse, error) {
+	farmer, err := s.farmerRepo.GetByID(farmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
 	}
-	newValues, _ := json.Marshal(newValuesMap)
 
-	var ipAddr *string
-	if ipAddress != "" {
-		ipAddr = &ipAddress
+	if farmer.Status != from || !farmer.Status.CanTransitionTo(next) {
+		return nil, ErrInvalidStatusTransition
 	}
-	var ua *string
-	if userAgent != "" {
-		ua = &userAgent
+
+	// Store the review state for audit
+	before := toFarmerReviewState(farmer)
+
+	if err := apply(farmer); err != nil {
+		return nil, err
 	}
+	farmer.Status = next
 
-	auditLog := &models.AdminAuditLog{
-		AdminID:    adminID,
+	if err := s.farmerRepo.UpdateStatus(farmer, before.Status, before.ReviewedBy); err != nil {
+		if errors.Is(err, repositories.ErrFarmerStatusChanged) {
+			// Moved by another admin meanwhile
+			return nil, ErrInvalidStatusTransition
+		}
+		return nil, fmt.Errorf("failed to update farmer: %w", err)
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
 		Action:     action,
-		EntityType: entityType,
-		EntityID:   entityID,
-		OldValues:  oldValues,
-		NewValues:  newValues,
-		IPAddress:  ipAddr,
-		UserAgent:  ua,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmerID,
+		Before:     before,
+		After:      toFarmerReviewState(farmer),
+	})
+
+	return &response.FarmerStatusUpdateResponse{
+		FarmerID:   farmer.ID,
+		Status:     string(farmer.Status),
+		ReviewedBy: adminID,
+		ReviewedAt: time.Now(),
+		Reason:     farmer.SuspensionReason,
+	}, nil
+}
+
+// checkReviewable ensures a farmer application can be approved or rejected by the admin.
+// Claimed (under_review) applications can only be decided by the admin who claimed them.
+func checkReviewable(farmer *models.Farmer, adminID string) error {
+	switch farmer.Status {
+	case models.FarmerStatusPending:
+		return nil
+	case models.FarmerStatusUnderReview:
+		if farmer.ReviewedBy != nil && *farmer.ReviewedBy != adminID {
+			return ErrFarmerClaimedByOther
+		}
+		return nil
+	default:
+		return ErrFarmerAlreadyProcessed
 	}
+}
+
+// recordDecision stores the review outcome on the farmer's latest submission
+func (s *FarmerService) recordDecision(farmer *models.Farmer) {
+	submission, err := s.submissionRepo.GetLatestByFarmerID(farmer.ID)
+	if err != nil {
+		fmt.Printf("failed to get farmer submission: %v\n", err)
+		return
+	}
+
+	submission.Status = models.SubmissionStatusApproved
+	if farmer.Status == models.FarmerStatusRejected {
+		submission.Status = models.SubmissionStatusRejected
+	}
+	submission.ReviewedBy = farmer.ReviewedBy
+	submission.ReviewedAt = farmer.ReviewedAt
+	submission.RejectionReason = farmer.RejectionReason
 
 	// Log error but don't fail the main operation
-	if err := s.auditLogRepo.Create(auditLog); err != nil {
-		fmt.Printf("failed to create audit log: %v\n", err)
+	if err := s.submissionRepo.Update(submission); err != nil {
+		fmt.Printf("failed to record farmer submission decision: %v\n", err)
+	}
+}
+
+// farmerReviewState is the part of a farmer recorded in the audit log of status changes
+type farmerReviewState struct {
+	Status           models.FarmerStatus `json:"status"`
+	ReviewedBy       *string             `json:"reviewed_by,omitempty"`
+	RejectionReason  *string             `json:"rejection_reason,omitempty"`
+	SuspensionReason *string             `json:"suspension_reason,omitempty"`
+}
+
+func toFarmerReviewState(farmer *models.Farmer) farmerReviewState {
+	return farmerReviewState{
+		Status:           farmer.Status,
+		ReviewedBy:       farmer.ReviewedBy,
+		RejectionReason:  farmer.RejectionReason,
+		SuspensionReason: farmer.SuspensionReason,
 	}
 }
 
@@ -478,3 +718,242 @@ func (s *FarmerService) GetByID(ctx context.Context, farmerID string) (*models.F
 	}
 	return farmer, nil
 }
+
+// GetApplication returns the registration status, the rejection reason and the submission
+// history of a farmer
+func (s *FarmerService) GetApplication(ctx context.Context, farmerID string) (*response.FarmerApplicationResponse, error) {
+	farmer, err := s.farmerRepo.GetByID(farmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
+	}
+
+	submissions, err := s.submissionRepo.GetAllByFarmerID(farmerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farmer submissions: %w", err)
+	}
+
+	return &response.FarmerApplicationResponse{
+		Status:          string(farmer.Status),
+		RejectionReason: farmer.RejectionReason,
+		ReviewedAt:      farmer.ReviewedAt,
+		CanResubmit:     farmer.Status == models.FarmerStatusRejected,
+		Profile:         farmer,
+		Submissions:     toSubmissionItems(submissions),
+	}, nil
+}
+
+// Resubmit replaces the profile and documents of a rejected farmer with the corrected
+// registration and puts it back in the review queue as a new submission
+func (s *FarmerService) Resubmit(ctx context.Context, farmerID string, req *request.RegisterFarmerRequest) (*response.FarmerApplicationResponse, error) {
+	farmer, err := s.farmerRepo.GetByID(farmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
+	}
+
+	if farmer.Status != models.FarmerStatusRejected {
+		return nil, ErrFarmerNotRejected
+	}
+
+	// The wallet identifies the farmer and cannot be changed by resubmitting
+	if strings.ToLower(req.PersonalInfo.WalletAddress) != strings.ToLower(farmer.WalletAddress) {
+		return nil, ErrWalletAddressMismatch
+	}
+
+	if err := normalizeRegistration(req); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.farmerRepo.ExistsByEmailOrPhoneExcludingID(req.PersonalInfo.Email, req.PersonalInfo.PhoneNumber, farmerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing farmer: %w", err)
+	}
+	if exists {
+		return nil, ErrFarmerAlreadyExists
+	}
+
+	idNumberExists, err := s.farmerRepo.ExistsByIDNumber(req.PersonalInfo.IDNumber, farmerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing NIK: %w", err)
+	}
+	if idNumberExists {
+		return nil, ErrIDNumberAlreadyExists
+	}
+
+	if err := s.documentPolicy.checkRequiredDocuments(models.BusinessType(req.BusinessInfo.BusinessType), req.Documents); err != nil {
+		return nil, err
+	}
+	verifiedDocs, err := verifyDocuments(ctx, s.storageService, req.Documents)
+	if err != nil {
+		return nil, err
+	}
+
+	before := toFarmerReviewState(farmer)
+	if err := applyRegistration(farmer, req); err != nil {
+		return nil, err
+	}
+
+	// Back to the review queue; the previous decision stays in the submission history
+	farmer.Status = models.FarmerStatusPending
+	farmer.ReviewedBy = nil
+	farmer.ReviewedAt = nil
+	farmer.RejectionReason = nil
+	farmer.Documents = buildFarmerDocuments(farmer.ID, verifiedDocs)
+
+	submission, err := newFarmerSubmission(farmer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.submissionRepo.Resubmit(farmer, farmer.Documents, submission); err != nil {
+		return nil, fmt.Errorf("failed to resubmit farmer registration: %w", err)
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionResubmitFarmer,
+		EntityType: models.AuditEntityTypeFarmer,
+		EntityID:   farmerID,
+		Before:     before,
+		After:      toFarmerReviewState(farmer),
+	})
+
+	return s.GetApplication(ctx, farmerID)
+}
+
+// normalizeRegistration validates the Indonesian identity data of a registration (NIK, NPWP,
+// phone number, province and postal code) and rewrites the phone number in E.164 format.
+// Invalid fields are returned together as validation.Errors.
+func normalizeRegistration(req *request.RegisterFarmerRequest) error {
+	dob, err := time.Parse("2006-01-02", req.PersonalInfo.DateOfBirth)
+	if err != nil {
+		return ErrInvalidDateFormat
+	}
+
+	var fieldErrs validation.Errors
+	if _, err := validation.ValidateNIK(req.PersonalInfo.IDNumber, dob); err != nil {
+		fieldErrs.Add("personal_info.id_number", err.Error())
+	}
+	phone, err := validation.NormalizePhoneNumber(req.PersonalInfo.PhoneNumber)
+	if err != nil {
+		fieldErrs.Add("personal_info.phone_number", err.Error())
+	} else {
+		req.PersonalInfo.PhoneNumber = phone
+	}
+	if err := validation.ValidateProvince(req.PersonalInfo.Province); err != nil {
+		fieldErrs.Add("personal_info.province", err.Error())
+	}
+	if err := validation.ValidatePostalCode(req.PersonalInfo.PostalCode, req.PersonalInfo.Province); err != nil {
+		fieldErrs.Add("personal_info.postal_code", err.Error())
+	}
+	if req.BusinessInfo.NPWP != nil && *req.BusinessInfo.NPWP != "" {
+		if _, err := validation.NormalizeNPWP(*req.BusinessInfo.NPWP); err != nil {
+			fieldErrs.Add("business_info.npwp", err.Error())
+		}
+	}
+	return fieldErrs.Err()
+}
+
+// applyRegistration copies the personal and business info of a registration onto the farmer
+func applyRegistration(farmer *models.Farmer, req *request.RegisterFarmerRequest) error {
+	// Parse date of birth
+	dob, err := time.Parse("2006-01-02", req.PersonalInfo.DateOfBirth)
+	if err != nil {
+		return ErrInvalidDateFormat
+	}
+
+	// Personal Info
+	farmer.FullName = req.PersonalInfo.FullName
+	farmer.Email = req.PersonalInfo.Email
+	farmer.PhoneNumber = req.PersonalInfo.PhoneNumber
+	farmer.IDNumber = req.PersonalInfo.IDNumber
+	farmer.DateOfBirth = dob
+	farmer.Address = req.PersonalInfo.Address
+	farmer.Province = req.PersonalInfo.Province
+	farmer.City = req.PersonalInfo.City
+	farmer.District = req.PersonalInfo.District
+	farmer.PostalCode = req.PersonalInfo.PostalCode
+
+	// Business Info
+	farmer.BusinessName = req.BusinessInfo.BusinessName
+	farmer.BusinessType = models.BusinessType(req.BusinessInfo.BusinessType)
+	farmer.NPWP = req.BusinessInfo.NPWP
+	farmer.BankName = req.BusinessInfo.BankName
+	farmer.BankAccountNumber = req.BusinessInfo.BankAccountNumber
+	farmer.BankAccountName = req.BusinessInfo.BankAccountName
+	farmer.YearsOfExperience = 0
+	farmer.CropsExpertise = pq.StringArray{}
+
+	// Set optional fields
+	if req.BusinessInfo.YearsOfExperience != nil {
+		farmer.YearsOfExperience = *req.BusinessInfo.YearsOfExperience
+	}
+	if len(req.BusinessInfo.CropsExpertise) > 0 {
+		farmer.CropsExpertise = pq.StringArray(req.BusinessInfo.CropsExpertise)
+	}
+	if req.PersonalInfo.PreferredLanguage != nil {
+		farmer.PreferredLanguage = *req.PersonalInfo.PreferredLanguage
+	}
+	return nil
+}
+
+// buildFarmerDocuments builds document records from the verified uploads.
+// Only the file_key is stored in the FileURL column (not a public URL).
+func buildFarmerDocuments(farmerID string, docs []request.DocumentRequest) []models.FarmerDocument {
+	documents := make([]models.FarmerDocument, 0, len(docs))
+	for _, doc := range docs {
+		documents = append(documents, models.FarmerDocument{
+			FarmerID:     farmerID,
+			DocumentType: models.DocumentType(doc.DocumentType),
+			FileURL:      doc.FileKey, // Store file_key directly, not a public URL
+			FileName:     doc.FileName,
+			FileSize:     doc.FileSize,
+			MimeType:     doc.MimeType,
+		})
+	}
+	return documents
+}
+
+// submittedDocumentTypes returns the types of the stored documents
+func submittedDocumentTypes(documents []models.FarmerDocument) []models.DocumentType {
+	documentTypes := make([]models.DocumentType, 0, len(documents))
+	for _, doc := range documents {
+		documentTypes = append(documentTypes, doc.DocumentType)
+	}
+	return documentTypes
+}
+
+// newFarmerSubmission snapshots the farmer and their documents as a pending submission.
+// PII fields are encrypted in the snapshot like in the farmers table.
+func newFarmerSubmission(farmer *models.Farmer) (*models.FarmerSubmission, error) {
+	values, err := toJSONMap(farmer)
+	if err != nil {
+		return nil, err
+	}
+	if err := pii.SealFields(values, models.FarmerPIIFields...); err != nil {
+		return nil, err
+	}
+	snapshot, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot farmer submission: %w", err)
+	}
+	return &models.FarmerSubmission{
+		FarmerID: farmer.ID,
+		Snapshot: snapshot,
+		Status:   models.SubmissionStatusPending,
+	}, nil
+}
+
+// toSubmissionItems converts submissions to their response items
+func toSubmissionItems(submissions []models.FarmerSubmission) []response.FarmerSubmissionItem {
+	items := make([]response.FarmerSubmissionItem, 0, len(submissions))
+	for _, submission := range submissions {
+		items = append(items, response.FarmerSubmissionItem{
+			ID:               submission.ID,
+			SubmissionNumber: submission.SubmissionNumber,
+			Status:           string(submission.Status),
+			RejectionReason:  submission.RejectionReason,
+			ReviewedBy:       submission.ReviewedBy,
+			ReviewedAt:       submission.ReviewedAt,
+			SubmittedAt:      submission.SubmittedAt,
+		})
+	}
+	return items
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReviewable(t *testing.T) {
+	claimant := "admin-1"
+
+	tests := []struct {
+		name      string
+		status    models.FarmerStatus
+		claimedBy *string
+		adminID   string
+		wantErr   error
+	}{
+		{name: "pending", status: models.FarmerStatusPending, adminID: "admin-2"},
+		{name: "claimed by same admin", status: models.FarmerStatusUnderReview, claimedBy: &claimant, adminID: claimant},
+		{name: "claimed by another admin", status: models.FarmerStatusUnderReview, claimedBy: &claimant, adminID: "admin-2", wantErr: ErrFarmerClaimedByOther},
+		{name: "already approved", status: models.FarmerStatusApproved, adminID: claimant, wantErr: ErrFarmerAlreadyProcessed},
+		{name: "suspended", status: models.FarmerStatusSuspended, adminID: claimant, wantErr: ErrFarmerAlreadyProcessed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			farmer := &models.Farmer{Status: tt.status, ReviewedBy: tt.claimedBy}
+			assert.ErrorIs(t, checkReviewable(farmer, tt.adminID), tt.wantErr)
+		})
+	}
+}
+
+func TestFarmerStatusTransitions(t *testing.T) {
+	assert.True(t, models.FarmerStatusPending.CanTransitionTo(models.FarmerStatusUnderReview))
+	assert.True(t, models.FarmerStatusApproved.CanTransitionTo(models.FarmerStatusSuspended))
+	assert.True(t, models.FarmerStatusSuspended.CanTransitionTo(models.FarmerStatusApproved))
+	assert.False(t, models.FarmerStatusPending.CanTransitionTo(models.FarmerStatusSuspended))
+	assert.False(t, models.FarmerStatusRejected.CanTransitionTo(models.FarmerStatusApproved))
+	assert.False(t, models.FarmerStatusSuspended.CanTransitionTo(models.FarmerStatusRejected))
+}
+
+func TestReinstateFarmer_OnlySuspended(t *testing.T) {
+	for _, status := range []models.FarmerStatus{models.FarmerStatusPending, models.FarmerStatusUnderReview, models.FarmerStatusRejected, models.FarmerStatusApproved} {
+		t.Run(string(status), func(t *testing.T) {
+			farmerRepo := &stubFarmerRepo{farmer: testFarmer(status, "id")}
+			svc := NewFarmerService(farmerRepo, nil, nopRecorder{}, nil, nil, nil)
+
+			_, err := svc.ReinstateFarmer(context.Background(), farmerRepo.farmer.ID, "admin-1")
+			assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+			assert.Equal(t, status, farmerRepo.farmer.Status)
+		})
+	}
+
+	t.Run(string(models.FarmerStatusSuspended), func(t *testing.T) {
+		farmer := testFarmer(models.FarmerStatusSuspended, "id")
+		reason := "fraud report"
+		farmer.SuspensionReason = &reason
+		farmerRepo := &stubFarmerRepo{farmer: farmer}
+		svc := NewFarmerService(farmerRepo, nil, nopRecorder{}, nil, nil, nil)
+
+		resp, err := svc.ReinstateFarmer(context.Background(), farmer.ID, "admin-1")
+		require.NoError(t, err)
+		assert.Equal(t, string(models.FarmerStatusApproved), resp.Status)
+		assert.Nil(t, farmerRepo.farmer.SuspensionReason)
+	})
+}
+
+func TestReleaseFarmer_OnlyUnderReview(t *testing.T) {
+	claimant := "admin-1"
+	for _, status := range []models.FarmerStatus{models.FarmerStatusPending, models.FarmerStatusRejected, models.FarmerStatusApproved, models.FarmerStatusSuspended} {
+		t.Run(string(status), func(t *testing.T) {
+			farmer := testFarmer(status, "id")
+			farmer.ReviewedBy = &claimant
+			farmerRepo := &stubFarmerRepo{farmer: farmer}
+			svc := NewFarmerService(farmerRepo, nil, nopRecorder{}, nil, nil, nil)
+
+			_, err := svc.ReleaseFarmer(context.Background(), farmer.ID, claimant)
+			assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+			assert.Equal(t, status, farmerRepo.farmer.Status)
+		})
+	}
+
+	t.Run(string(models.FarmerStatusUnderReview), func(t *testing.T) {
+		farmer := testFarmer(models.FarmerStatusUnderReview, "id")
+		farmer.ReviewedBy = &claimant
+		farmerRepo := &stubFarmerRepo{farmer: farmer}
+		svc := NewFarmerService(farmerRepo, nil, nopRecorder{}, nil, nil, nil)
+
+		_, err := svc.ReleaseFarmer(context.Background(), farmer.ID, "admin-2")
+		assert.ErrorIs(t, err, ErrFarmerClaimedByOther)
+
+		resp, err := svc.ReleaseFarmer(context.Background(), farmer.ID, claimant)
+		require.NoError(t, err)
+		assert.Equal(t, string(models.FarmerStatusPending), resp.Status)
+		assert.Nil(t, farmerRepo.farmer.ReviewedBy)
+	})
+}
+
+// staleFarmerRepo serves the farmer as an admin read it before another admin changed it
+type staleFarmerRepo struct {
+	*stubFarmerRepo
+	read models.Farmer
+}
+
+func (r *staleFarmerRepo) GetByID(id string) (*models.Farmer, error) {
+	farmer := r.read
+	return &farmer, nil
+}
+
+func TestFarmerReview_SecondAdminLosesTheRace(t *testing.T) {
+	farmerRepo := &stubFarmerRepo{farmer: testFarmer(models.FarmerStatusPending, "id")}
+	ctx := context.Background()
+
+	// Both admins open the application while it is pending, the first one claims it
+	staleRepo := &staleFarmerRepo{stubFarmerRepo: farmerRepo, read: *farmerRepo.farmer}
+	first := NewFarmerService(farmerRepo, nil, nopRecorder{}, nil, &RequiredDocumentPolicy{}, nil)
+	_, err := first.ClaimFarmer(ctx, "farmer-1", "admin-1")
+	require.NoError(t, err)
+
+	second := NewFarmerService(staleRepo, nil, nopRecorder{}, nil, &RequiredDocumentPolicy{}, nil)
+	_, err = second.ClaimFarmer(ctx, "farmer-1", "admin-2")
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	_, err = second.ApproveFarmer(ctx, "farmer-1", "admin-2")
+	assert.ErrorIs(t, err, ErrFarmerAlreadyProcessed)
+	_, err = second.RejectFarmer(ctx, "farmer-1", "admin-2", nil)
+	assert.ErrorIs(t, err, ErrFarmerAlreadyProcessed)
+
+	assert.Equal(t, models.FarmerStatusUnderReview, farmerRepo.farmer.Status)
+	assert.Equal(t, "admin-1", *farmerRepo.farmer.ReviewedBy)
+}
+
+// failingRecorder cannot write audit entries
+type failingRecorder struct{}
+
+func (failingRecorder) Record(ctx context.Context, entry audit.Entry) {}
+
+func (failingRecorder) RecordRequired(ctx context.Context, entry audit.Entry) error {
+	return errors.New("audit log unavailable")
+}
+
+func TestRevealPII_RequiresAuditEntry(t *testing.T) {
+	farmer := testFarmer(models.FarmerStatusApproved, "id")
+	farmer.IDNumber = "3201010101010001"
+	req := &request.RevealFarmerPIIRequest{Fields: []string{"id_number"}, Reason: "verifikasi rekening"}
+
+	svc := NewFarmerService(&stubFarmerRepo{farmer: farmer}, nil, failingRecorder{}, nil, nil, nil)
+	resp, err := svc.RevealPII(context.Background(), farmer.ID, "admin-1", req)
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+
+	svc = NewFarmerService(&stubFarmerRepo{farmer: farmer}, nil, nopRecorder{}, nil, nil, nil)
+	resp, err = svc.RevealPII(context.Background(), farmer.ID, "admin-1", req)
+	require.NoError(t, err)
+	assert.Equal(t, "3201010101010001", resp.Values["id_number"])
+}
+
+// registeredFarmerRepo is a stubFarmerRepo without other farmers holding the same contact
+// details or NIK
+type registeredFarmerRepo struct {
+	*stubFarmerRepo
+}
+
+func (r *registeredFarmerRepo) ExistsByEmailOrPhoneExcludingID(email, phone, excludeID string) (bool, error) {
+	return false, nil
+}
+
+func (r *registeredFarmerRepo) ExistsByIDNumber(idNumber, excludeID string) (bool, error) {
+	return false, nil
+}
+
+// memorySubmissionRepo keeps the submission history of the farmer in a stubFarmerRepo
+type memorySubmissionRepo struct {
+	repositories.FarmerSubmissionRepository
+	farmerRepo  *stubFarmerRepo
+	submissions []models.FarmerSubmission
+}
+
+func (r *memorySubmissionRepo) GetAllByFarmerID(farmerID string) ([]models.FarmerSubmission, error) {
+	return r.submissions, nil
+}
+
+func (r *memorySubmissionRepo) Resubmit(farmer *models.Farmer, documents []models.FarmerDocument, submission *models.FarmerSubmission) error {
+	submission.SubmissionNumber = len(r.submissions) + 1
+	r.submissions = append(r.submissions, *submission)
+	r.farmerRepo.farmer = farmer
+	return nil
+}
+
+func testResubmission(walletAddress string) *request.RegisterFarmerRequest {
+	return &request.RegisterFarmerRequest{
+		PersonalInfo: request.PersonalInfoRequest{
+			FullName:      "Budi Santoso",
+			Email:         "budi@example.com",
+			PhoneNumber:   "081234567890",
+			IDNumber:      "3273011708900001",
+			DateOfBirth:   "1990-08-17",
+			Address:       "Jl. Merdeka 1",
+			Province:      "Jawa Barat",
+			City:          "Bandung",
+			District:      "Sumur Bandung",
+			PostalCode:    "40115",
+			WalletAddress: walletAddress,
+		},
+		BusinessInfo: request.BusinessInfoRequest{
+			BusinessType:      "individual",
+			BankName:          "Bank BCA",
+			BankAccountNumber: "1234567890",
+			BankAccountName:   "Budi Santoso",
+		},
+		Documents: []request.DocumentRequest{
+			{DocumentType: "ktp_photo", FileKey: "farmers/documents/a-ktp_photo"},
+		},
+	}
+}
+
+func TestResubmit(t *testing.T) {
+	useTestKeyring(t)
+	wallet := "0x71855a33a6f648f05ef719a8a2fae4635aa04fbf"
+	reviewer := "admin-1"
+	reason := "KTP photo is blurry"
+	reviewedAt := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	newService := func(status models.FarmerStatus) (*FarmerService, *stubFarmerRepo, *memorySubmissionRepo) {
+		farmer := testFarmer(status, "id")
+		farmer.WalletAddress = wallet
+		farmer.ReviewedBy = &reviewer
+		farmer.ReviewedAt = &reviewedAt
+		farmer.RejectionReason = &reason
+		farmerRepo := &stubFarmerRepo{farmer: farmer}
+		submissionRepo := &memorySubmissionRepo{farmerRepo: farmerRepo, submissions: []models.FarmerSubmission{
+			{ID: "submission-1", FarmerID: farmer.ID, SubmissionNumber: 1, Status: models.SubmissionStatusRejected, ReviewedBy: &reviewer, ReviewedAt: &reviewedAt, RejectionReason: &reason},
+		}}
+		storage := &memoryStorage{objects: map[string]memoryObject{"farmers/documents/a-ktp_photo": {body: jpegBytes}}}
+		svc := NewFarmerService(&registeredFarmerRepo{farmerRepo}, storage, nopRecorder{}, submissionRepo, &RequiredDocumentPolicy{}, nil)
+		return svc, farmerRepo, submissionRepo
+	}
+
+	t.Run("only rejected registrations", func(t *testing.T) {
+		for _, status := range []models.FarmerStatus{models.FarmerStatusPending, models.FarmerStatusUnderReview, models.FarmerStatusApproved, models.FarmerStatusSuspended} {
+			svc, farmerRepo, submissionRepo := newService(status)
+			_, err := svc.Resubmit(context.Background(), "farmer-1", testResubmission(wallet))
+			assert.ErrorIs(t, err, ErrFarmerNotRejected, status)
+			assert.Equal(t, status, farmerRepo.farmer.Status)
+			assert.Len(t, submissionRepo.submissions, 1)
+		}
+	})
+
+	t.Run("wallet cannot change", func(t *testing.T) {
+		svc, farmerRepo, submissionRepo := newService(models.FarmerStatusRejected)
+		_, err := svc.Resubmit(context.Background(), "farmer-1", testResubmission("0x0000000000000000000000000000000000000001"))
+		assert.ErrorIs(t, err, ErrWalletAddressMismatch)
+		assert.Equal(t, models.FarmerStatusRejected, farmerRepo.farmer.Status)
+		assert.Len(t, submissionRepo.submissions, 1)
+	})
+
+	t.Run("back to the review queue as a new submission", func(t *testing.T) {
+		svc, farmerRepo, submissionRepo := newService(models.FarmerStatusRejected)
+
+		application, err := svc.GetApplication(context.Background(), "farmer-1")
+		require.NoError(t, err)
+		assert.True(t, application.CanResubmit)
+		assert.Equal(t, &reason, application.RejectionReason)
+
+		// The wallet is compared case-insensitively
+		application, err = svc.Resubmit(context.Background(), "farmer-1", testResubmission("0x71855a33a6f648f05EF719A8a2Fae4635aA04FbF"))
+		require.NoError(t, err)
+
+		farmer := farmerRepo.farmer
+		assert.Equal(t, models.FarmerStatusPending, farmer.Status)
+		assert.Nil(t, farmer.ReviewedBy)
+		assert.Nil(t, farmer.ReviewedAt)
+		assert.Nil(t, farmer.RejectionReason)
+		assert.Equal(t, "+6281234567890", farmer.PhoneNumber)
+		require.Len(t, farmer.Documents, 1)
+		assert.Equal(t, "farmers/documents/a-ktp_photo", farmer.Documents[0].FileURL)
+
+		require.Len(t, submissionRepo.submissions, 2)
+		assert.Equal(t, models.SubmissionStatusRejected, submissionRepo.submissions[0].Status)
+		assert.Equal(t, &reason, submissionRepo.submissions[0].RejectionReason)
+		assert.Equal(t, 2, submissionRepo.submissions[1].SubmissionNumber)
+		assert.Equal(t, models.SubmissionStatusPending, submissionRepo.submissions[1].Status)
+		assert.NotContains(t, string(submissionRepo.submissions[1].Snapshot), "3273011708900001", "PII is encrypted in the snapshot")
+
+		assert.Equal(t, string(models.FarmerStatusPending), application.Status)
+		assert.False(t, application.CanResubmit)
+		assert.Nil(t, application.RejectionReason)
+		require.Len(t, application.Submissions, 2)
+		assert.Equal(t, string(models.SubmissionStatusRejected), application.Submissions[0].Status)
+		assert.Equal(t, string(models.SubmissionStatusPending), application.Submissions[1].Status)
+	})
+}
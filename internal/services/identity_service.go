@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/ownafarm/ownafarm-backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+// ErrNoActiveRole is returned when none of the accounts of a wallet can log in
+var ErrNoActiveRole = errors.New("wallet has no active role")
+
+// farmerTokenScopes maps the statuses that can log in to their token scope. Approved farmers get
+// full access. Farmers still in review or rejected get an application scope token to follow up
+// on and resubmit their registration.
+var farmerTokenScopes = map[models.FarmerStatus]string{
+	models.FarmerStatusApproved:    utils.FarmerScopeFull,
+	models.FarmerStatusPending:     utils.FarmerScopeApplication,
+	models.FarmerStatusUnderReview: utils.FarmerScopeApplication,
+	models.FarmerStatusRejected:    utils.FarmerScopeApplication,
+}
+
+// FarmerTokenScope returns the token scope of a farmer status, false when the farmer cannot log in
+func FarmerTokenScope(status models.FarmerStatus) (string, bool) {
+	scope, ok := farmerTokenScopes[status]
+	return scope, ok
+}
+
+// WalletIdentity is the investor, farmer and admin accounts of one wallet. Accounts are nil when
+// the wallet has none; the farmer is set even when its status cannot log in, for its status.
+type WalletIdentity struct {
+	WalletAddress string
+	Roles         []string
+	User          *models.User
+	Farmer        *models.Farmer
+	FarmerScope   string
+	Admin         *models.AdminUser
+}
+
+// HasRole reports whether the wallet holds the role
+func (i *WalletIdentity) HasRole(role string) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+// SessionClaims returns the claims of a session token for the identity
+func (i *WalletIdentity) SessionClaims() utils.SessionClaims {
+	claims := utils.SessionClaims{WalletAddress: i.WalletAddress, Roles: i.Roles}
+	if i.HasRole(utils.RoleInvestor) {
+		claims.UserID = i.User.ID
+	}
+	if i.HasRole(utils.RoleFarmer) {
+		claims.FarmerID = i.Farmer.ID
+		claims.FarmerScope = i.FarmerScope
+	}
+	if i.HasRole(utils.RoleAdmin) {
+		claims.AdminID = i.Admin.ID
+		claims.AdminRole = i.Admin.Role
+	}
+	return claims
+}
+
+// IdentityServiceInterface defines the interface for resolving the roles of a wallet
+type IdentityServiceInterface interface {
+	Resolve(ctx context.Context, walletAddress string, sessionStartedAt time.Time) (*WalletIdentity, error)
+}
+
+// IdentityService finds the accounts a wallet holds across the investor, farmer and admin roles
+type IdentityService struct {
+	userRepo   repositories.UserRepository
+	farmerRepo repositories.FarmerRepository
+	adminRepo  repositories.AdminUserRepository
+}
+
+// NewIdentityService creates a new IdentityService instance
+func NewIdentityService(
+	userRepo repositories.UserRepository,
+	farmerRepo repositories.FarmerRepository,
+	adminRepo repositories.AdminUserRepository,
+) *IdentityService {
+	return &IdentityService{
+		userRepo:   userRepo,
+		farmerRepo: farmerRepo,
+		adminRepo:  adminRepo,
+	}
+}
+
+// Resolve returns the accounts and roles of a normalized wallet address. Every wallet gets an
+// investor account, as on investor login, and a farmer of the same wallet is linked to it.
+// sessionStartedAt is when the session signed in; admins whose tokens were revoked since then
+// lose the admin role. Returns ErrNoActiveRole when no account can log in.
+func (s *IdentityService) Resolve(ctx context.Context, walletAddress string, sessionStartedAt time.Time) (*WalletIdentity, error) {
+	identity := &WalletIdentity{WalletAddress: walletAddress}
+
+	// Investor, erased accounts keep the wallet for their investments but cannot log in again
+	user, err := s.userRepo.GetByWalletAddress(walletAddress)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user = &models.User{WalletAddress: walletAddress}
+		err = s.userRepo.Create(user)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.ErasedAt == nil {
+		identity.User = user
+		identity.Roles = append(identity.Roles, utils.RoleInvestor)
+	}
+
+	// Farmer
+	farmer, err := s.farmerRepo.GetByWalletAddress(walletAddress)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get farmer: %w", err)
+	}
+	if farmer != nil && farmer.Status != models.FarmerStatusErased {
+		identity.Farmer = farmer
+		if scope, ok := FarmerTokenScope(farmer.Status); ok {
+			identity.FarmerScope = scope
+			identity.Roles = append(identity.Roles, utils.RoleFarmer)
+		}
+
+		if farmer.UserID == nil && identity.User != nil {
+			if err := s.farmerRepo.LinkUser(farmer.ID, identity.User.ID); err != nil {
+				// The link is recorded on a later login
+				log.Printf("[Auth] WARNING: failed to link farmer %s to user %s: %v", farmer.ID, identity.User.ID, err)
+			} else {
+				farmer.UserID = &identity.User.ID
+			}
+		}
+	}
+
+	// Admin
+	admin, err := s.adminRepo.GetByWalletAddress(ctx, walletAddress)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get admin: %w", err)
+	}
+	if admin != nil && admin.IsActive &&
+		(admin.TokensRevokedAt == nil || !sessionStartedAt.Before(admin.TokensRevokedAt.Truncate(time.Second))) {
+		identity.Admin = admin
+		identity.Roles = append(identity.Roles, utils.RoleAdmin)
+	}
+
+	if len(identity.Roles) == 0 {
+		return nil, ErrNoActiveRole
+	}
+	return identity, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"math"
 	"time"
@@ -38,14 +39,19 @@ type InvestmentServiceInterface interface {
 	GetCrop(ctx context.Context, userID, cropID string) (*response.CropResponse, error)
 	WaterCrop(ctx context.Context, userID, cropID string) (*response.WaterCropResponse, error)
 	SyncHarvest(ctx context.Context, userID, walletAddress, cropID string) (*response.SyncHarvestResponse, error)
+	RefreshCropProgress(ctx context.Context, userID string) error
 }
 
 // InvestmentService implements InvestmentServiceInterface
 type InvestmentService struct {
-	investmentRepo repositories.InvestmentRepository
-	invoiceRepo    repositories.InvoiceRepository
-	userRepo       repositories.UserRepository
-	blockchainSvc  BlockchainService
+	investmentRepo  repositories.InvestmentRepository
+	invoiceRepo     repositories.InvoiceRepository
+	userRepo        repositories.UserRepository
+	achievementRepo repositories.AchievementRepository
+	blockchainSvc   BlockchainService
+	lifecycleSvc    InvoiceLifecycleServiceInterface
+	notificationSvc NotificationServiceInterface
+	eventBus        EventBus
 }
 
 // NewInvestmentService creates a new InvestmentService instance
@@ -53,13 +59,21 @@ func NewInvestmentService(
 	investmentRepo repositories.InvestmentRepository,
 	invoiceRepo repositories.InvoiceRepository,
 	userRepo repositories.UserRepository,
+	achievementRepo repositories.AchievementRepository,
 	blockchainSvc BlockchainService,
+	lifecycleSvc InvoiceLifecycleServiceInterface,
+	notificationSvc NotificationServiceInterface,
+	eventBus EventBus,
 ) *InvestmentService {
 	return &InvestmentService{
-		investmentRepo: investmentRepo,
-		invoiceRepo:    invoiceRepo,
-		userRepo:       userRepo,
-		blockchainSvc:  blockchainSvc,
+		investmentRepo:  investmentRepo,
+		invoiceRepo:     invoiceRepo,
+		userRepo:        userRepo,
+		achievementRepo: achievementRepo,
+		blockchainSvc:   blockchainSvc,
+		lifecycleSvc:    lifecycleSvc,
+		notificationSvc: notificationSvc,
+		eventBus:        eventBus,
 	}
 }
 
@@ -161,6 +175,8 @@ func (s *InvestmentService) SyncInvestments(ctx context.Context, userID, walletA
 		// Error is logged but doesn't fail the sync - totals can be recalculated later
 		if err := s.invoiceRepo.UpdateFundingTotals(invoice.ID); err != nil {
 			log.Printf("[SyncInvestments] WARNING: failed to update funding totals for invoice %s: %v", invoice.ID, err)
+		} else {
+			s.afterFundingUpdate(ctx, invoice.ID, invoice.IsFullyFunded)
 		}
 
 		// Reload with relations for response
@@ -176,6 +192,10 @@ func (s *InvestmentService) SyncInvestments(ctx context.Context, userID, walletA
 
 	log.Printf("[SyncInvestments] Sync complete. Synced %d new investments for wallet %s", syncedCount, walletAddress)
 
+	if syncedCount > 0 {
+		s.checkAchievements(ctx, userID)
+	}
+
 	return &response.SyncInvestmentsResponse{
 		SyncedCount: syncedCount,
 		NewCrops:    newCrops,
@@ -211,14 +231,8 @@ func (s *InvestmentService) ListCrops(ctx context.Context, userID string, req *r
 	var crops []response.CropResponse
 	for i := range investments {
 		// Update progress for active investments
-		if investments[i].Status != models.CropStatusHarvested {
-			progress, status := s.calculateProgressAndStatus(&investments[i], &investments[i].Invoice)
-			investments[i].Progress = progress
-			investments[i].Status = status
-			// Update in database asynchronously (fire and forget)
-			go func(id string, p int, st models.CropStatus) {
-				_ = s.investmentRepo.UpdateProgress(id, p, st)
-			}(investments[i].ID, progress, status)
+		if err := s.refreshProgress(ctx, userID, &investments[i]); err != nil {
+			log.Printf("[Crops] WARNING: failed to refresh crop %s: %v", investments[i].ID, err)
 		}
 		crops = append(crops, s.toCropResponse(&investments[i]))
 	}
@@ -241,12 +255,9 @@ func (s *InvestmentService) GetCrop(ctx context.Context, userID, cropID string)
 		return nil, err
 	}
 
-	// Update progress if not harvested
-	if investment.Status != models.CropStatusHarvested {
-		progress, status := s.calculateProgressAndStatus(investment, &investment.Invoice)
-		investment.Progress = progress
-		investment.Status = status
-		_ = s.investmentRepo.UpdateProgress(investment.ID, progress, status)
+	// Update progress if still growing
+	if err := s.refreshProgress(ctx, userID, investment); err != nil {
+		log.Printf("[Crops] WARNING: failed to refresh crop %s: %v", investment.ID, err)
 	}
 
 	resp := s.toCropResponse(investment)
@@ -299,6 +310,7 @@ func (s *InvestmentService) WaterCrop(ctx context.Context, userID, cropID string
 		}
 		return nil, err
 	}
+	s.afterXPGain(ctx, userID, user.Level)
 
 	// Reload investment with updated water count
 	investment, err = s.investmentRepo.GetByIDAndUserID(cropID, userID)
@@ -359,6 +371,8 @@ func (s *InvestmentService) SyncHarvest(ctx context.Context, userID, walletAddre
 		if err := s.investmentRepo.Update(investment); err != nil {
 			return nil, err
 		}
+		s.notificationSvc.NotifyHarvestSynced(ctx, userID, investment)
+		publishEvent(ctx, s.eventBus, UserEventChannel(userID), EventTypeCropStatus, s.toCropProgressEvent(investment))
 
 		// Add XP to user profile
 		user, err := s.userRepo.GetByID(userID)
@@ -372,6 +386,7 @@ func (s *InvestmentService) SyncHarvest(ctx context.Context, userID, walletAddre
 		if err != nil {
 			return nil, err
 		}
+		s.afterXPGain(ctx, userID, user.Level)
 		xpGained = HarvestXPGain
 	}
 
@@ -382,6 +397,121 @@ func (s *InvestmentService) SyncHarvest(ctx context.Context, userID, walletAddre
 	}, nil
 }
 
+// RefreshCropProgress recalculates progress of the user's growing crops, persists changes
+// and publishes live events. It is driven by open event streams so that crops keep
+// progressing for the investor without polling /crops.
+func (s *InvestmentService) RefreshCropProgress(ctx context.Context, userID string) error {
+	investments, _, err := s.investmentRepo.GetAllByUserID(repositories.InvestmentFilter{
+		UserID: userID,
+		Status: string(models.CropStatusGrowing),
+		Page:   1,
+		Limit:  100,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get growing crops: %w", err)
+	}
+
+	for i := range investments {
+		if err := s.refreshProgress(ctx, userID, &investments[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshProgress recalculates the progress of a growing investment in place, persists it and
+// publishes changes as live events. The growing -> ready transition is claimed with a conditional
+// update, so the investor is notified once even when several requests refresh the crop at once.
+func (s *InvestmentService) refreshProgress(ctx context.Context, userID string, investment *models.Investment) error {
+	if investment.Status != models.CropStatusGrowing {
+		return nil
+	}
+
+	progress, status := s.calculateProgressAndStatus(investment, &investment.Invoice)
+	if status == models.CropStatusReady {
+		marked, err := s.investmentRepo.MarkReady(investment.ID)
+		if err != nil {
+			return fmt.Errorf("failed to mark crop ready: %w", err)
+		}
+		investment.Progress = progress
+		investment.Status = status
+		if !marked {
+			// Another refresh made the transition and notified the investor
+			return nil
+		}
+		publishEvent(ctx, s.eventBus, UserEventChannel(userID), EventTypeCropStatus, s.toCropProgressEvent(investment))
+		s.notificationSvc.NotifyCropReady(ctx, userID, investment)
+		return nil
+	}
+
+	if progress == investment.Progress {
+		return nil
+	}
+	if err := s.investmentRepo.UpdateProgress(investment.ID, progress); err != nil {
+		return fmt.Errorf("failed to update crop progress: %w", err)
+	}
+	investment.Progress = progress
+	publishEvent(ctx, s.eventBus, UserEventChannel(userID), EventTypeCropProgress, s.toCropProgressEvent(investment))
+	return nil
+}
+
+// afterXPGain emits level-up and achievement notifications after the user's XP changed
+func (s *InvestmentService) afterXPGain(ctx context.Context, userID string, previousLevel int) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		log.Printf("[Notification] WARNING: failed to reload user %s after XP gain: %v", userID, err)
+		return
+	}
+	if user.Level > previousLevel {
+		s.notificationSvc.NotifyLevelUp(ctx, userID, user.Level)
+	}
+	s.checkAchievements(ctx, userID)
+}
+
+// checkAchievements unlocks newly eligible achievements and notifies the user about them
+func (s *InvestmentService) checkAchievements(ctx context.Context, userID string) {
+	unlocked, err := s.achievementRepo.UnlockEligible(userID)
+	if err != nil {
+		log.Printf("[Notification] WARNING: failed to check achievements for user %s: %v", userID, err)
+		return
+	}
+	for _, achievement := range unlocked {
+		s.notificationSvc.NotifyAchievementUnlocked(ctx, userID, achievement.Code, achievement.Name)
+	}
+}
+
+// afterFundingUpdate publishes the new funding progress of an invoice, and once the funding
+// target is reached moves the invoice to funded, which notifies all of its investors
+func (s *InvestmentService) afterFundingUpdate(ctx context.Context, invoiceID string, wasFullyFunded bool) {
+	invoice, err := s.invoiceRepo.GetByID(invoiceID)
+	if err != nil {
+		log.Printf("[SyncInvestments] WARNING: failed to reload invoice %s: %v", invoiceID, err)
+		return
+	}
+
+	fundingProgress := 0.0
+	if invoice.TargetFund.GreaterThan(decimal.Zero) {
+		fundingProgress = invoice.TotalFunded.Div(invoice.TargetFund).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+	publishEvent(ctx, s.eventBus, InvoiceEventChannel(invoice.ID), EventTypeInvoiceFunding, response.InvoiceFundingEvent{
+		InvoiceID:       invoice.ID,
+		TotalFunded:     invoice.TotalFunded.InexactFloat64(),
+		TargetFund:      invoice.TargetFund.InexactFloat64(),
+		FundingProgress: fundingProgress,
+		IsFullyFunded:   invoice.IsFullyFunded,
+	})
+
+	if wasFullyFunded || !invoice.IsFullyFunded {
+		return
+	}
+
+	// Investors are notified by the lifecycle transition to funded, which only one of
+	// concurrent syncs wins
+	if err := s.lifecycleSvc.Advance(ctx, invoiceID); err != nil {
+		log.Printf("[SyncInvestments] WARNING: failed to advance lifecycle of invoice %s: %v", invoiceID, err)
+	}
+}
+
 // calculateProgressAndStatus calculates progress and status based on time
 func (s *InvestmentService) calculateProgressAndStatus(investment *models.Investment, invoice *models.Invoice) (int, models.CropStatus) {
 	if investment.IsHarvested {
@@ -405,6 +535,18 @@ func (s *InvestmentService) findInvoiceByTokenID(tokenID int64) (*models.Invoice
 	return s.invoiceRepo.GetByTokenID(tokenID)
 }
 
+// toCropProgressEvent converts an Investment model to a live crop event payload
+func (s *InvestmentService) toCropProgressEvent(investment *models.Investment) response.CropProgressEvent {
+	crop := s.toCropResponse(investment)
+	return response.CropProgressEvent{
+		CropID:     crop.ID,
+		Progress:   crop.Progress,
+		Status:     crop.Status,
+		DaysLeft:   crop.DaysLeft,
+		CanHarvest: crop.CanHarvest,
+	}
+}
+
 // toCropResponse converts an Investment model to CropResponse
 func (s *InvestmentService) toCropResponse(investment *models.Investment) response.CropResponse {
 	invoice := investment.Invoice
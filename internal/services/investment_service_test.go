@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryInvestmentRepo is an in-memory InvestmentRepository for a single user's crops;
+// methods the tests do not use panic if called
+type memoryInvestmentRepo struct {
+	repositories.InvestmentRepository
+	mu          sync.Mutex
+	investments map[string]*models.Investment
+}
+
+func newMemoryInvestmentRepo(investments ...models.Investment) *memoryInvestmentRepo {
+	r := &memoryInvestmentRepo{investments: map[string]*models.Investment{}}
+	for i := range investments {
+		stored := investments[i]
+		r.investments[stored.ID] = &stored
+	}
+	return r
+}
+
+func (r *memoryInvestmentRepo) GetByIDAndUserID(id, userID string) (*models.Investment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	investment, ok := r.investments[id]
+	if !ok || investment.UserID != userID {
+		return nil, errors.New("record not found")
+	}
+	loaded := *investment
+	return &loaded, nil
+}
+
+func (r *memoryInvestmentRepo) GetAllByUserID(filter repositories.InvestmentFilter) ([]models.Investment, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []models.Investment
+	for _, investment := range r.investments {
+		if investment.UserID == filter.UserID && (filter.Status == "" || string(investment.Status) == filter.Status) {
+			result = append(result, *investment)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (r *memoryInvestmentRepo) GetUserIDsByInvoiceID(invoiceID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seen := map[string]bool{}
+	var userIDs []string
+	for _, investment := range r.investments {
+		if investment.InvoiceID == invoiceID && !seen[investment.UserID] {
+			seen[investment.UserID] = true
+			userIDs = append(userIDs, investment.UserID)
+		}
+	}
+	return userIDs, nil
+}
+
+func (r *memoryInvestmentRepo) UpdateProgress(id string, progress int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if investment := r.investments[id]; investment != nil && investment.Status == models.CropStatusGrowing {
+		investment.Progress = progress
+	}
+	return nil
+}
+
+func (r *memoryInvestmentRepo) MarkReady(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	investment := r.investments[id]
+	if investment == nil || investment.Status != models.CropStatusGrowing {
+		return false, nil
+	}
+	investment.Status = models.CropStatusReady
+	investment.Progress = 100
+	return true, nil
+}
+
+func (r *memoryInvestmentRepo) IncrementWaterCount(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.investments[id].WaterCount++
+	return nil
+}
+
+func (r *memoryInvestmentRepo) get(id string) models.Investment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.investments[id]
+}
+
+// memoryUserRepo keeps one user's game stats and raises the level to nextLevel on any update
+type memoryUserRepo struct {
+	repositories.UserRepository
+	user      models.User
+	nextLevel int
+}
+
+func (r *memoryUserRepo) GetByID(id string) (*models.User, error) {
+	user := r.user
+	return &user, nil
+}
+
+func (r *memoryUserRepo) RegenerateWater(userID string) (*models.User, error) {
+	return r.GetByID(userID)
+}
+
+func (r *memoryUserRepo) UpdateGameStats(userID string, updates map[string]interface{}) error {
+	if xp, ok := updates["xp"].(int); ok {
+		r.user.XP = xp
+	}
+	if water, ok := updates["water_points"].(int); ok {
+		r.user.WaterPoints = water
+	}
+	r.user.Level = r.nextLevel
+	return nil
+}
+
+// stubAchievementRepo unlocks the given achievements on the first check only
+type stubAchievementRepo struct {
+	unlock []models.Achievement
+}
+
+func (r *stubAchievementRepo) UnlockEligible(userID string) ([]models.Achievement, error) {
+	unlocked := r.unlock
+	r.unlock = nil
+	return unlocked, nil
+}
+
+func testCrop(investedAgo time.Duration) models.Investment {
+	return models.Investment{
+		ID:         "crop-1",
+		UserID:     "user-1",
+		InvoiceID:  "invoice-1",
+		InvestedAt: time.Now().Add(-investedAgo),
+		Status:     models.CropStatusGrowing,
+		Invoice:    models.Invoice{ID: "invoice-1", Name: "Padi Sawah", DurationDays: 30},
+	}
+}
+
+func TestRefreshProgressNotifiesCropReadyOnce(t *testing.T) {
+	crop := testCrop(31 * 24 * time.Hour)
+	investmentRepo := newMemoryInvestmentRepo(crop)
+	notificationRepo := &memoryNotificationRepo{}
+	svc := NewInvestmentService(investmentRepo, nil, nil, nil, nil, nil, NewNotificationService(notificationRepo, nil), nil)
+	ctx := context.Background()
+
+	// A page load and an event stream tick both loaded the crop while it was still growing
+	stale := []models.Investment{crop, crop}
+	for i := range stale {
+		require.NoError(t, svc.refreshProgress(ctx, "user-1", &stale[i]))
+		assert.Equal(t, models.CropStatusReady, stale[i].Status)
+	}
+
+	_, err := svc.ListCrops(ctx, "user-1", &request.ListCropsRequest{})
+	require.NoError(t, err)
+	_, err = svc.GetCrop(ctx, "user-1", "crop-1")
+	require.NoError(t, err)
+	require.NoError(t, svc.RefreshCropProgress(ctx, "user-1"))
+
+	assert.Equal(t, []models.NotificationType{models.NotificationTypeCropReady}, notificationRepo.typesFor("user-1"))
+	stored := investmentRepo.get("crop-1")
+	assert.Equal(t, models.CropStatusReady, stored.Status)
+	assert.Equal(t, 100, stored.Progress)
+}
+
+func TestRefreshCropProgressPersistsGrowingProgress(t *testing.T) {
+	investmentRepo := newMemoryInvestmentRepo(testCrop(15 * 24 * time.Hour))
+	notificationRepo := &memoryNotificationRepo{}
+	svc := NewInvestmentService(investmentRepo, nil, nil, nil, nil, nil, NewNotificationService(notificationRepo, nil), nil)
+
+	require.NoError(t, svc.RefreshCropProgress(context.Background(), "user-1"))
+
+	stored := investmentRepo.get("crop-1")
+	assert.Equal(t, models.CropStatusGrowing, stored.Status)
+	assert.Equal(t, 50, stored.Progress)
+	assert.Empty(t, notificationRepo.typesFor("user-1"))
+}
+
+func TestWaterCropNotifiesLevelUpAndAchievements(t *testing.T) {
+	investmentRepo := newMemoryInvestmentRepo(testCrop(time.Hour))
+	userRepo := &memoryUserRepo{user: models.User{ID: "user-1", Level: 1, WaterPoints: 100}, nextLevel: 2}
+	achievementRepo := &stubAchievementRepo{unlock: []models.Achievement{{Code: "first_water", Name: "First Water"}}}
+	notificationRepo := &memoryNotificationRepo{}
+	svc := NewInvestmentService(investmentRepo, nil, userRepo, achievementRepo, nil, nil, NewNotificationService(notificationRepo, nil), nil)
+	ctx := context.Background()
+
+	_, err := svc.WaterCrop(ctx, "user-1", "crop-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.NotificationType{
+		models.NotificationTypeLevelUp,
+		models.NotificationTypeAchievementUnlocked,
+	}, notificationRepo.typesFor("user-1"))
+
+	// Watering again without a new level or achievement notifies nothing more
+	_, err = svc.WaterCrop(ctx, "user-1", "crop-1")
+	require.NoError(t, err)
+	assert.Len(t, notificationRepo.typesFor("user-1"), 2)
+}
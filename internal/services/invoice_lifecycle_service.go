@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+)
+
+// ErrInvalidInvoiceTransition is returned when an invoice cannot move from its status to the requested one
+var ErrInvalidInvoiceTransition = errors.New("invoice cannot move to this status")
+
+// invoiceLifecyclePollLock is the lease that lets a single API replica run each poll
+const invoiceLifecyclePollLock = "invoice_lifecycle:poll"
+
+// InvoiceLifecycleServiceInterface defines the interface for moving approved invoices through
+// funding, the crop cycle and repayment
+type InvoiceLifecycleServiceInterface interface {
+	Advance(ctx context.Context, invoiceID string) error
+	ProcessDue(ctx context.Context) (int, error)
+}
+
+// InvoiceLifecycleService moves invoices to their next status from their dates and the
+// invoice state on chain. Every transition is logged with the system as actor.
+type InvoiceLifecycleService struct {
+	invoiceRepo     repositories.InvoiceRepository
+	transitionRepo  repositories.InvoiceStatusTransitionRepository
+	farmRepo        repositories.FarmRepository
+	investmentRepo  repositories.InvestmentRepository
+	blockchainSvc   BlockchainService
+	notifier        FarmerNotificationServiceInterface
+	notificationSvc NotificationServiceInterface
+	locker          Locker
+	gracePeriod     time.Duration
+	now             func() time.Time
+}
+
+// NewInvoiceLifecycleService creates a new InvoiceLifecycleService instance. Farmers are told
+// through notifier when funding fails, investors through notificationSvc when it succeeds.
+// Matured invoices that are not repaid within gracePeriod default. The locker lets one
+// replica run each poll.
+func NewInvoiceLifecycleService(
+	invoiceRepo repositories.InvoiceRepository,
+	transitionRepo repositories.InvoiceStatusTransitionRepository,
+	farmRepo repositories.FarmRepository,
+	investmentRepo repositories.InvestmentRepository,
+	blockchainSvc BlockchainService,
+	notifier FarmerNotificationServiceInterface,
+	notificationSvc NotificationServiceInterface,
+	locker Locker,
+	gracePeriod time.Duration,
+) *InvoiceLifecycleService {
+	return &InvoiceLifecycleService{
+		invoiceRepo:     invoiceRepo,
+		transitionRepo:  transitionRepo,
+		farmRepo:        farmRepo,
+		investmentRepo:  investmentRepo,
+		blockchainSvc:   blockchainSvc,
+		notifier:        notifier,
+		notificationSvc: notificationSvc,
+		locker:          locker,
+		gracePeriod:     gracePeriod,
+		now:             time.Now,
+	}
+}
+
+// Advance moves an invoice through every transition that is due now, e.g. right after an
+// investment sync reached its funding target
+func (s *InvoiceLifecycleService) Advance(ctx context.Context, invoiceID string) error {
+	invoice, err := s.invoiceRepo.GetByID(invoiceID)
+	if err != nil {
+		return ErrInvoiceNotFound
+	}
+	_, err = s.advance(ctx, invoice)
+	return err
+}
+
+// ProcessDue advances every invoice between approval and a final status and returns the
+// number of transitions made
+func (s *InvoiceLifecycleService) ProcessDue(ctx context.Context) (int, error) {
+	invoices, err := s.invoiceRepo.GetAllByStatus(models.ActiveInvoiceStatuses)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active invoices: %w", err)
+	}
+
+	transitions := 0
+	for i := range invoices {
+		count, err := s.advance(ctx, &invoices[i])
+		if err != nil {
+			log.Printf("[InvoiceLifecycle] WARNING: invoice %s: %v", invoices[i].ID, err)
+		}
+		transitions += count
+	}
+	return transitions, nil
+}
+
+// Run advances due invoices until ctx is done. Every replica runs the worker, only the one
+// holding the poll lease processes a tick.
+func (s *InvoiceLifecycleService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Slightly shorter than the interval so that timer jitter does not skip a poll
+			if _, err := s.poll(ctx, interval*9/10); err != nil {
+				log.Printf("[InvoiceLifecycle] WARNING: lifecycle worker: %v", err)
+			}
+		}
+	}
+}
+
+// poll runs ProcessDue when this replica takes the poll lease for lease, and returns false
+// when another replica holds it
+func (s *InvoiceLifecycleService) poll(ctx context.Context, lease time.Duration) (bool, error) {
+	acquired, err := s.locker.TryLock(ctx, invoiceLifecyclePollLock, lease)
+	if err != nil {
+		return false, fmt.Errorf("failed to take poll lease: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	_, err = s.ProcessDue(ctx)
+	return true, err
+}
+
+// advance applies the due transitions of an invoice one after another and returns how many
+// were made. Steps due from the invoice's dates are applied without the chain, except failing
+// funding, which is final: the chain may show the target reached before the investment sync
+// did. The chain is read at most once, and only when the funding target or a repayment
+// decides the next step. A failed read stops at that point and keeps the transitions already
+// made; a funding failure waits for the next run.
+func (s *InvoiceLifecycleService) advance(ctx context.Context, invoice *models.Invoice) (int, error) {
+	var onchain *OnchainInvoice
+	system := audit.Actor{Type: audit.ActorSystem}
+	transitions := 0
+	for {
+		step, ok := dueInvoiceStep(invoice, s.now(), s.gracePeriod)
+		if chainDecides(invoice, step, ok) {
+			if onchain == nil {
+				var err error
+				onchain, err = s.blockchainSvc.GetInvoiceByTokenID(ctx, uint64(*invoice.TokenID))
+				if err != nil {
+					return transitions, fmt.Errorf("failed to read invoice from chain: %w", err)
+				}
+			}
+			if onchainStep, found := onchainInvoiceStep(invoice, onchain); found {
+				step, ok = onchainStep, true
+			}
+		}
+		if !ok {
+			return transitions, nil
+		}
+
+		from := invoice.Status
+		if err := transitionInvoice(s.transitionRepo, invoice, step.status, step.reason, system, step.updates); err != nil {
+			if errors.Is(err, repositories.ErrInvoiceStatusChanged) {
+				// Moved by a request or another worker meanwhile; the next run picks it up
+				return transitions, nil
+			}
+			return transitions, fmt.Errorf("failed to move invoice to %s: %w", step.status, err)
+		}
+		if maturityDate, ok := step.updates["maturity_date"].(time.Time); ok {
+			invoice.MaturityDate = &maturityDate
+		}
+
+		log.Printf("[InvoiceLifecycle] invoice %s: %s -> %s (%s)", invoice.ID, from, invoice.Status, step.reason)
+		transitions++
+
+		// Only the request or worker whose transition went through notifies, so concurrent
+		// syncs and polls notify once
+		switch invoice.Status {
+		case models.InvoiceStatusFunded:
+			s.notifyFullyFunded(ctx, invoice)
+		case models.InvoiceStatusFundingFailed:
+			s.notifyFundingFailed(ctx, invoice)
+		}
+	}
+}
+
+// notifyFullyFunded tells the investors of the invoice that it reached its funding target
+func (s *InvoiceLifecycleService) notifyFullyFunded(ctx context.Context, invoice *models.Invoice) {
+	userIDs, err := s.investmentRepo.GetUserIDsByInvoiceID(invoice.ID)
+	if err != nil {
+		log.Printf("[InvoiceLifecycle] WARNING: failed to get investors of invoice %s: %v", invoice.ID, err)
+		return
+	}
+	s.notificationSvc.NotifyInvoiceFullyFunded(ctx, userIDs, invoice)
+}
+
+// notifyFundingFailed tells the farmer owning the invoice's farm that funding failed
+func (s *InvoiceLifecycleService) notifyFundingFailed(ctx context.Context, invoice *models.Invoice) {
+	farm, err := s.farmRepo.GetByID(invoice.FarmID)
+	if err != nil {
+		log.Printf("[InvoiceLifecycle] WARNING: failed to get farm of invoice %s: %v", invoice.ID, err)
+		return
+	}
+	s.notifier.NotifyInvoiceFundingFailed(ctx, farm.FarmerID, invoice)
+}
+
+// invoiceStep is the next transition of an invoice and the columns it sets
+type invoiceStep struct {
+	status  models.InvoiceStatus
+	reason  string
+	updates map[string]interface{}
+}
+
+// chainDecides reports whether the chain state is needed for the next step of an invoice: when
+// no step is due from its dates, or before failing its funding
+func chainDecides(invoice *models.Invoice, due invoiceStep, ok bool) bool {
+	if invoice.TokenID == nil || !slices.Contains(models.ActiveInvoiceStatuses, invoice.Status) {
+		return false
+	}
+	return !ok || due.status == models.InvoiceStatusFundingFailed
+}
+
+// dueInvoiceStep returns the transition that is due from the invoice's dates and funding
+// totals alone. A repayment that lands after the grace period moves a defaulted invoice to
+// completed through onchainInvoiceStep.
+func dueInvoiceStep(invoice *models.Invoice, now time.Time, gracePeriod time.Duration) (invoiceStep, bool) {
+	switch invoice.Status {
+	case models.InvoiceStatusFunding:
+		if invoice.IsFullyFunded {
+			return invoiceStep{status: models.InvoiceStatusFunded, reason: "funding target reached"}, true
+		}
+		if invoice.FundingDeadline != nil && !now.Before(*invoice.FundingDeadline) {
+			return invoiceStep{status: models.InvoiceStatusFundingFailed, reason: "funding deadline passed before the target was reached"}, true
+		}
+
+	case models.InvoiceStatusFunded:
+		// The crop cycle starts when the funding window closes
+		if invoice.FundingDeadline == nil || !now.Before(*invoice.FundingDeadline) {
+			step := invoiceStep{status: models.InvoiceStatusGrowing, reason: "funding window closed"}
+			if invoice.MaturityDate == nil {
+				start := now
+				if invoice.FundingDeadline != nil {
+					start = *invoice.FundingDeadline
+				}
+				step.updates = map[string]interface{}{
+					"maturity_date": start.AddDate(0, 0, invoice.DurationDays),
+				}
+			}
+			return step, true
+		}
+
+	case models.InvoiceStatusGrowing:
+		if invoice.MaturityDate != nil && !now.Before(*invoice.MaturityDate) {
+			return invoiceStep{status: models.InvoiceStatusMatured, reason: "maturity date reached"}, true
+		}
+
+	case models.InvoiceStatusMatured:
+		if invoice.MaturityDate != nil && !now.Before(invoice.MaturityDate.Add(gracePeriod)) {
+			return invoiceStep{status: models.InvoiceStatusDefaulted, reason: "not repaid within the grace period"}, true
+		}
+	}
+
+	return invoiceStep{}, false
+}
+
+// onchainInvoiceStep returns the transition decided by the invoice's state on chain: the
+// funding target reached before the next investment sync, or a repayment
+func onchainInvoiceStep(invoice *models.Invoice, onchain *OnchainInvoice) (invoiceStep, bool) {
+	repaid := onchain.Status == OnchainInvoiceStatusCompleted
+
+	switch invoice.Status {
+	case models.InvoiceStatusFunding:
+		if repaid || onchain.Status == OnchainInvoiceStatusFunded {
+			return invoiceStep{status: models.InvoiceStatusFunded, reason: "funding target reached"}, true
+		}
+
+	case models.InvoiceStatusFunded, models.InvoiceStatusGrowing, models.InvoiceStatusMatured:
+		if repaid {
+			return invoiceStep{status: models.InvoiceStatusCompleted, reason: "repaid on chain"}, true
+		}
+
+	case models.InvoiceStatusDefaulted:
+		if repaid {
+			return invoiceStep{status: models.InvoiceStatusCompleted, reason: "repaid on chain after default"}, true
+		}
+	}
+
+	return invoiceStep{}, false
+}
+
+// transitionInvoice moves an invoice to the next status with the other column updates and
+// logs the transition. Returns ErrInvalidInvoiceTransition when the status cannot move there
+// and repositories.ErrInvoiceStatusChanged when the invoice was moved meanwhile.
+func transitionInvoice(
+	transitionRepo repositories.InvoiceStatusTransitionRepository,
+	invoice *models.Invoice,
+	to models.InvoiceStatus,
+	reason string,
+	actor audit.Actor,
+	updates map[string]interface{},
+) error {
+	if !invoice.Status.CanTransitionTo(to) {
+		return ErrInvalidInvoiceTransition
+	}
+
+	transition := &models.InvoiceStatusTransition{
+		InvoiceID:  invoice.ID,
+		FromStatus: invoice.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		ActorType:  string(actor.Type),
+	}
+	if actor.ID != "" {
+		actorID := actor.ID
+		transition.ActorID = &actorID
+	}
+
+	if err := transitionRepo.Apply(transition, updates); err != nil {
+		return err
+	}
+	invoice.Status = to
+	return nil
+}
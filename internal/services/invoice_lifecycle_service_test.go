@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryInvoiceRepo serves invoices by ID and status; other methods panic if called
+type memoryInvoiceRepo struct {
+	repositories.InvoiceRepository
+	invoices []models.Invoice
+}
+
+func (r *memoryInvoiceRepo) GetByID(id string) (*models.Invoice, error) {
+	for i := range r.invoices {
+		if r.invoices[i].ID == id {
+			invoice := r.invoices[i]
+			return &invoice, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+// GetByIDAndFarmerID does not check ownership; the tests use one farmer
+func (r *memoryInvoiceRepo) GetByIDAndFarmerID(id, farmerID string) (*models.Invoice, error) {
+	return r.GetByID(id)
+}
+
+func (r *memoryInvoiceRepo) GetAllByStatus(statuses []models.InvoiceStatus) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	for _, invoice := range r.invoices {
+		for _, status := range statuses {
+			if invoice.Status == status {
+				invoices = append(invoices, invoice)
+			}
+		}
+	}
+	return invoices, nil
+}
+
+// memoryTransitionRepo applies transitions to the invoices of a memoryInvoiceRepo
+type memoryTransitionRepo struct {
+	repositories.InvoiceStatusTransitionRepository
+	invoiceRepo *memoryInvoiceRepo
+	transitions []models.InvoiceStatusTransition
+	updates     []map[string]interface{}
+}
+
+func (r *memoryTransitionRepo) Apply(transition *models.InvoiceStatusTransition, updates map[string]interface{}) error {
+	for i := range r.invoiceRepo.invoices {
+		invoice := &r.invoiceRepo.invoices[i]
+		if invoice.ID != transition.InvoiceID {
+			continue
+		}
+		if invoice.Status != transition.FromStatus {
+			return repositories.ErrInvoiceStatusChanged
+		}
+		invoice.Status = transition.ToStatus
+		if maturityDate, ok := updates["maturity_date"].(time.Time); ok {
+			invoice.MaturityDate = &maturityDate
+		}
+		r.transitions = append(r.transitions, *transition)
+		r.updates = append(r.updates, updates)
+		return nil
+	}
+	return errors.New("record not found")
+}
+
+// stubInvoiceChain serves invoices from the chain by token ID and counts the reads
+type stubInvoiceChain struct {
+	BlockchainService
+	invoices map[uint64]*OnchainInvoice
+	err      error
+	reads    int
+}
+
+func (c *stubInvoiceChain) GetInvoiceByTokenID(ctx context.Context, tokenId uint64) (*OnchainInvoice, error) {
+	c.reads++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.invoices[tokenId], nil
+}
+
+// recordingFarmerNotifier records the invoices farmers were notified about
+type recordingFarmerNotifier struct {
+	FarmerNotificationServiceInterface
+	reviewed      []string
+	fundingFailed []string
+}
+
+func (n *recordingFarmerNotifier) NotifyInvoiceReviewed(ctx context.Context, farmerID string, invoice *models.Invoice) {
+	n.reviewed = append(n.reviewed, farmerID+":"+invoice.ID)
+}
+
+func (n *recordingFarmerNotifier) NotifyInvoiceFundingFailed(ctx context.Context, farmerID string, invoice *models.Invoice) {
+	n.fundingFailed = append(n.fundingFailed, farmerID+":"+invoice.ID)
+}
+
+// recordingInvestorNotifier records the investors told about fully funded invoices
+type recordingInvestorNotifier struct {
+	NotificationServiceInterface
+	fullyFunded []string
+}
+
+func (n *recordingInvestorNotifier) NotifyInvoiceFullyFunded(ctx context.Context, userIDs []string, invoice *models.Invoice) {
+	for _, userID := range userIDs {
+		n.fullyFunded = append(n.fullyFunded, userID+":"+invoice.ID)
+	}
+}
+
+func newTestLifecycleService(now time.Time, chain *stubInvoiceChain, invoices ...models.Invoice) (*InvoiceLifecycleService, *memoryInvoiceRepo, *recordingFarmerNotifier) {
+	invoiceRepo := &memoryInvoiceRepo{invoices: invoices}
+	notifier := &recordingFarmerNotifier{}
+	farmRepo := &stubFarmRepo{farm: &models.Farm{ID: "farm-1", FarmerID: "farmer-1"}}
+	locker := &memoryLocker{leases: map[string]time.Time{}, now: now}
+	svc := NewInvoiceLifecycleService(invoiceRepo, &memoryTransitionRepo{invoiceRepo: invoiceRepo}, farmRepo, newMemoryInvestmentRepo(),
+		chain, notifier, &recordingInvestorNotifier{}, locker, 14*24*time.Hour)
+	svc.now = func() time.Time { return now }
+	return svc, invoiceRepo, notifier
+}
+
+func tokenID(id int64) *int64 { return &id }
+
+func TestNextInvoiceStep(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	grace := 14 * 24 * time.Hour
+	graceOver := now.Add(-grace)
+	onchain := func(status uint8) *OnchainInvoice { return &OnchainInvoice{Status: status} }
+
+	tests := []struct {
+		name    string
+		invoice models.Invoice
+		onchain *OnchainInvoice
+		want    models.InvoiceStatus
+	}{
+		{"funding open", models.Invoice{Status: models.InvoiceStatusFunding, FundingDeadline: &future}, onchain(OnchainInvoiceStatusApproved), ""},
+		{"funding target reached", models.Invoice{Status: models.InvoiceStatusFunding, FundingDeadline: &future, IsFullyFunded: true}, nil, models.InvoiceStatusFunded},
+		{"funded on chain", models.Invoice{Status: models.InvoiceStatusFunding, FundingDeadline: &future}, onchain(OnchainInvoiceStatusFunded), models.InvoiceStatusFunded},
+		{"funding deadline passed", models.Invoice{Status: models.InvoiceStatusFunding, FundingDeadline: &past}, onchain(OnchainInvoiceStatusApproved), models.InvoiceStatusFundingFailed},
+		{"deadline passed before the sync saw the funding", models.Invoice{Status: models.InvoiceStatusFunding, FundingDeadline: &past}, onchain(OnchainInvoiceStatusFunded), models.InvoiceStatusFunded},
+		{"funded until the window closes", models.Invoice{Status: models.InvoiceStatusFunded, FundingDeadline: &future}, nil, ""},
+		{"window closed", models.Invoice{Status: models.InvoiceStatusFunded, FundingDeadline: &past}, nil, models.InvoiceStatusGrowing},
+		{"growing", models.Invoice{Status: models.InvoiceStatusGrowing, MaturityDate: &future}, nil, ""},
+		{"maturity reached", models.Invoice{Status: models.InvoiceStatusGrowing, MaturityDate: &past}, nil, models.InvoiceStatusMatured},
+		{"repaid early", models.Invoice{Status: models.InvoiceStatusGrowing, MaturityDate: &future}, onchain(OnchainInvoiceStatusCompleted), models.InvoiceStatusCompleted},
+		{"maturity before repayment", models.Invoice{Status: models.InvoiceStatusGrowing, MaturityDate: &past}, onchain(OnchainInvoiceStatusCompleted), models.InvoiceStatusMatured},
+		{"within grace period", models.Invoice{Status: models.InvoiceStatusMatured, MaturityDate: &past}, onchain(OnchainInvoiceStatusFunded), ""},
+		{"grace period over", models.Invoice{Status: models.InvoiceStatusMatured, MaturityDate: &graceOver}, onchain(OnchainInvoiceStatusFunded), models.InvoiceStatusDefaulted},
+		{"grace period over without chain", models.Invoice{Status: models.InvoiceStatusMatured, MaturityDate: &graceOver}, nil, models.InvoiceStatusDefaulted},
+		{"repaid after default", models.Invoice{Status: models.InvoiceStatusDefaulted}, onchain(OnchainInvoiceStatusCompleted), models.InvoiceStatusCompleted},
+		{"final", models.Invoice{Status: models.InvoiceStatusCompleted}, onchain(OnchainInvoiceStatusCompleted), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := dueInvoiceStep(&tt.invoice, now, grace)
+			if (!ok || step.status == models.InvoiceStatusFundingFailed) && tt.onchain != nil {
+				if onchainStep, found := onchainInvoiceStep(&tt.invoice, tt.onchain); found {
+					step, ok = onchainStep, true
+				}
+			}
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, step.status)
+			if ok {
+				assert.True(t, tt.invoice.Status.CanTransitionTo(step.status))
+			}
+		})
+	}
+
+	t.Run("maturity counts from the end of the funding window", func(t *testing.T) {
+		invoice := models.Invoice{Status: models.InvoiceStatusFunded, FundingDeadline: &past, DurationDays: 90}
+		step, ok := dueInvoiceStep(&invoice, now, grace)
+		assert.True(t, ok)
+		assert.Equal(t, past.AddDate(0, 0, 90), step.updates["maturity_date"])
+	})
+}
+
+func TestProcessDue_AppliesDateStepsWhenChainIsDown(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	deadlinePassed := now.Add(-time.Hour)
+	graceOver := now.AddDate(0, 0, -15)
+	open := now.Add(time.Hour)
+	chain := &stubInvoiceChain{err: errors.New("rpc unavailable")}
+	svc, invoiceRepo, notifier := newTestLifecycleService(now, chain,
+		models.Invoice{ID: "expired", FarmID: "farm-1", TokenID: tokenID(1), Status: models.InvoiceStatusFunding, FundingDeadline: &deadlinePassed},
+		models.Invoice{ID: "overdue", FarmID: "farm-1", TokenID: tokenID(2), Status: models.InvoiceStatusMatured, MaturityDate: &graceOver},
+		models.Invoice{ID: "open", FarmID: "farm-1", TokenID: tokenID(3), Status: models.InvoiceStatusFunding, FundingDeadline: &open},
+	)
+
+	transitions, err := svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, transitions)
+
+	statuses := map[string]models.InvoiceStatus{}
+	for _, invoice := range invoiceRepo.invoices {
+		statuses[invoice.ID] = invoice.Status
+	}
+	// Funding failures are final, so they wait until the chain confirms the target was missed
+	assert.Equal(t, map[string]models.InvoiceStatus{
+		"expired": models.InvoiceStatusFunding,
+		"overdue": models.InvoiceStatusDefaulted,
+		"open":    models.InvoiceStatusFunding,
+	}, statuses)
+	assert.Empty(t, notifier.fundingFailed)
+	assert.Equal(t, 3, chain.reads)
+}
+
+func TestProcessDue_KeepsFundingReachedOnChainPastDeadline(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(-time.Minute)
+	chain := &stubInvoiceChain{invoices: map[uint64]*OnchainInvoice{1: {Status: OnchainInvoiceStatusFunded}}}
+	svc, invoiceRepo, notifier := newTestLifecycleService(now, chain,
+		models.Invoice{ID: "funded", FarmID: "farm-1", TokenID: tokenID(1), Status: models.InvoiceStatusFunding, FundingDeadline: &deadline, DurationDays: 90},
+	)
+
+	_, err := svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+	// Funded on chain before the deadline: the crop cycle starts instead of failing
+	assert.Equal(t, models.InvoiceStatusGrowing, invoiceRepo.invoices[0].Status)
+	assert.Empty(t, notifier.fundingFailed)
+	assert.Equal(t, 1, chain.reads)
+}
+
+func TestAdvance_ReadsChainOnceAcrossSteps(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	open := now.Add(time.Hour)
+	chain := &stubInvoiceChain{invoices: map[uint64]*OnchainInvoice{1: {Status: OnchainInvoiceStatusCompleted}}}
+	svc, invoiceRepo, _ := newTestLifecycleService(now, chain,
+		models.Invoice{ID: "repaid", FarmID: "farm-1", TokenID: tokenID(1), Status: models.InvoiceStatusFunding, FundingDeadline: &open},
+	)
+
+	require.NoError(t, svc.Advance(context.Background(), "repaid"))
+	assert.Equal(t, models.InvoiceStatusCompleted, invoiceRepo.invoices[0].Status)
+	assert.Equal(t, 1, chain.reads)
+}
+
+func TestInvoiceLifecyclePoll_RunsOnOneReplica(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	locker := &memoryLocker{leases: map[string]time.Time{}, now: now}
+	chain := &stubInvoiceChain{}
+	replicas := make([]*InvoiceLifecycleService, 3)
+	for i := range replicas {
+		replicas[i], _, _ = newTestLifecycleService(now, chain)
+		replicas[i].locker = locker
+	}
+
+	ran := 0
+	for _, replica := range replicas {
+		polled, err := replica.poll(context.Background(), time.Minute)
+		require.NoError(t, err)
+		if polled {
+			ran++
+		}
+	}
+	assert.Equal(t, 1, ran)
+
+	locker.now = now.Add(time.Minute)
+	polled, err := replicas[1].poll(context.Background(), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, polled)
+}
+
+func TestProcessDue_FailsExpiredFundingAndNotifiesOnce(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(-time.Minute)
+	chain := &stubInvoiceChain{invoices: map[uint64]*OnchainInvoice{1: {Status: OnchainInvoiceStatusApproved}}}
+	svc, invoiceRepo, notifier := newTestLifecycleService(now, chain,
+		models.Invoice{ID: "expired", FarmID: "farm-1", TokenID: tokenID(1), Status: models.InvoiceStatusFunding, FundingDeadline: &deadline},
+	)
+
+	transitions, err := svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, transitions)
+	assert.Equal(t, models.InvoiceStatusFundingFailed, invoiceRepo.invoices[0].Status)
+
+	// Later polls and investment syncs leave the failed invoice alone
+	transitions, err = svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, transitions)
+	require.NoError(t, svc.Advance(context.Background(), "expired"))
+
+	assert.Equal(t, []string{"farmer-1:expired"}, notifier.fundingFailed)
+}
+
+func TestAdvance_NotifiesInvestorsOfFullFundingOnce(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	open := now.Add(time.Hour)
+	chain := &stubInvoiceChain{invoices: map[uint64]*OnchainInvoice{1: {Status: OnchainInvoiceStatusFunded}}}
+	svc, invoiceRepo, _ := newTestLifecycleService(now, chain,
+		models.Invoice{ID: "funded", FarmID: "farm-1", TokenID: tokenID(1), Status: models.InvoiceStatusFunding, FundingDeadline: &open, DurationDays: 90},
+	)
+	svc.investmentRepo = newMemoryInvestmentRepo(
+		models.Investment{ID: "investment-1", UserID: "user-1", InvoiceID: "funded"},
+		models.Investment{ID: "investment-2", UserID: "user-2", InvoiceID: "funded"},
+	)
+	investors := svc.notificationSvc.(*recordingInvestorNotifier)
+
+	// Two syncs that loaded the invoice before either moved it; only the first transition
+	// goes through
+	first, err := invoiceRepo.GetByID("funded")
+	require.NoError(t, err)
+	second, err := invoiceRepo.GetByID("funded")
+	require.NoError(t, err)
+	_, err = svc.advance(context.Background(), first)
+	require.NoError(t, err)
+	_, err = svc.advance(context.Background(), second)
+	require.NoError(t, err)
+
+	// Later worker polls leave the funded invoice alone
+	_, err = svc.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, models.InvoiceStatusFunded, invoiceRepo.invoices[0].Status)
+	assert.ElementsMatch(t, []string{"user-1:funded", "user-2:funded"}, investors.fullyFunded)
+}
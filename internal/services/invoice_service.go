@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
 	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
 	"github.com/ownafarm/ownafarm-backend/internal/models"
@@ -20,6 +22,7 @@ var (
 	ErrInvoiceNotFound         = errors.New("invoice not found")
 	ErrInvoiceNotOwned         = errors.New("invoice does not belong to this farmer")
 	ErrInvoiceAlreadyProcessed = errors.New("invoice has already been processed")
+	ErrInvoiceNotEditable      = errors.New("invoice can only be changed while pending and not on chain")
 	ErrFarmNotActive           = errors.New("farm is not active")
 )
 
@@ -27,6 +30,8 @@ var (
 type InvoiceServiceInterface interface {
 	Create(ctx context.Context, farmerID string, req *request.CreateInvoiceRequest) (*response.InvoiceResponse, error)
 	GetByID(ctx context.Context, farmerID, invoiceID string) (*response.InvoiceResponse, error)
+	Update(ctx context.Context, farmerID, invoiceID string, req *request.UpdateInvoiceRequest) (*response.InvoiceResponse, error)
+	Withdraw(ctx context.Context, farmerID, invoiceID string) (*response.InvoiceResponse, error)
 	List(ctx context.Context, farmerID string, req *request.ListInvoiceRequest) (*response.ListInvoiceResponse, error)
 	GeneratePresignedImageURL(ctx context.Context, req *request.PresignInvoiceImageRequest) (*response.PresignInvoiceImageResponse, error)
 
@@ -36,35 +41,61 @@ type InvoiceServiceInterface interface {
 	// Admin operations
 	GetByIDForAdmin(ctx context.Context, invoiceID string) (*response.InvoiceResponse, error)
 	ListForAdmin(ctx context.Context, req *request.ListInvoiceRequest) (*response.ListInvoiceAdminResponse, error)
-	ApproveInvoice(ctx context.Context, invoiceID, adminID string, req *request.ApproveInvoiceRequest, ipAddress, userAgent string) (*response.InvoiceStatusUpdateResponse, error)
-	RejectInvoice(ctx context.Context, invoiceID, adminID string, reason *string, ipAddress, userAgent string) (*response.InvoiceStatusUpdateResponse, error)
+	ApproveInvoice(ctx context.Context, invoiceID, adminID string, req *request.ApproveInvoiceRequest) (*response.InvoiceStatusUpdateResponse, error)
+	RejectInvoice(ctx context.Context, invoiceID, adminID string, reason *string) (*response.InvoiceStatusUpdateResponse, error)
 }
 
 // InvoiceService implements InvoiceServiceInterface
 type InvoiceService struct {
 	invoiceRepo    repositories.InvoiceRepository
+	versionRepo    repositories.InvoiceVersionRepository
+	transitionRepo repositories.InvoiceStatusTransitionRepository
 	farmRepo       repositories.FarmRepository
+	farmerRepo     repositories.FarmerRepository
 	storageService StorageService
-	auditLogRepo   repositories.AuditLogRepository
+	auditor        audit.Recorder
+	notifier       FarmerNotificationServiceInterface
+	fundingWindow  time.Duration
+	now            func() time.Time
 }
 
 // NewInvoiceService creates a new InvoiceService instance
 func NewInvoiceService(
 	invoiceRepo repositories.InvoiceRepository,
+	versionRepo repositories.InvoiceVersionRepository,
+	transitionRepo repositories.InvoiceStatusTransitionRepository,
 	farmRepo repositories.FarmRepository,
+	farmerRepo repositories.FarmerRepository,
 	storageService StorageService,
-	auditLogRepo repositories.AuditLogRepository,
+	auditor audit.Recorder,
+	notifier FarmerNotificationServiceInterface,
+	fundingWindow time.Duration,
 ) *InvoiceService {
 	return &InvoiceService{
 		invoiceRepo:    invoiceRepo,
+		versionRepo:    versionRepo,
+		transitionRepo: transitionRepo,
 		farmRepo:       farmRepo,
+		farmerRepo:     farmerRepo,
 		storageService: storageService,
-		auditLogRepo:   auditLogRepo,
+		auditor:        auditor,
+		notifier:       notifier,
+		fundingWindow:  fundingWindow,
+		now:            time.Now,
 	}
 }
 
 // Create creates a new invoice for a farmer's farm
 func (s *InvoiceService) Create(ctx context.Context, farmerID string, req *request.CreateInvoiceRequest) (*response.InvoiceResponse, error) {
+	// Only approved farmers can raise new invoices
+	status, err := s.farmerRepo.GetStatusByID(farmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
+	}
+	if status != models.FarmerStatusApproved {
+		return nil, ErrFarmerNotApproved
+	}
+
 	// Verify farm ownership and active status
 	farm, err := s.farmRepo.GetByIDAndFarmerID(req.FarmID, farmerID)
 	if err != nil {
@@ -93,10 +124,29 @@ func (s *InvoiceService) Create(ctx context.Context, farmerID string, req *reque
 		invoice.ImageURL = req.ImageKey
 	}
 
-	if err := s.invoiceRepo.Create(invoice); err != nil {
+	// The submitted invoice is recorded as version 1 together with the invoice
+	version, err := newInvoiceVersion(invoice, nil, map[string]interface{}{}, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.versionRepo.CreateInvoice(invoice, version); err != nil {
 		return nil, fmt.Errorf("failed to create invoice: %w", err)
 	}
 
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionCreateInvoice,
+		EntityType: models.AuditEntityTypeInvoice,
+		EntityID:   invoice.ID,
+		After: map[string]interface{}{
+			"farm_id":       invoice.FarmID,
+			"name":          invoice.Name,
+			"target_fund":   invoice.TargetFund,
+			"yield_percent": invoice.YieldPercent,
+			"duration_days": invoice.DurationDays,
+			"status":        invoice.Status,
+		},
+	})
+
 	// Set farm for response
 	invoice.Farm = *farm
 
@@ -119,6 +169,120 @@ func (s *InvoiceService) GetByID(ctx context.Context, farmerID, invoiceID string
 	return s.toInvoiceResponse(invoice), nil
 }
 
+// Update edits a pending invoice that is not on chain yet. Every edit is recorded as a new
+// version with the fields it changed; an edit that changes nothing records no version.
+func (s *InvoiceService) Update(ctx context.Context, farmerID, invoiceID string, req *request.UpdateInvoiceRequest) (*response.InvoiceResponse, error) {
+	// Only approved farmers can change their invoices
+	status, err := s.farmerRepo.GetStatusByID(farmerID)
+	if err != nil {
+		return nil, ErrFarmerNotFound
+	}
+	if status != models.FarmerStatusApproved {
+		return nil, ErrFarmerNotApproved
+	}
+
+	invoice, err := s.invoiceRepo.GetByIDAndFarmerID(invoiceID, farmerID)
+	if err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	if invoice.Status != models.InvoiceStatusPending || invoice.TokenID != nil {
+		return nil, ErrInvoiceNotEditable
+	}
+
+	// Verify farm ownership and active status
+	farm, err := s.farmRepo.GetByIDAndFarmerID(req.FarmID, farmerID)
+	if err != nil {
+		return nil, ErrFarmNotFound
+	}
+	if !farm.IsActive {
+		return nil, ErrFarmNotActive
+	}
+
+	before := toInvoiceContent(invoice)
+
+	invoice.FarmID = req.FarmID
+	invoice.Name = req.Name
+	invoice.Description = req.Description
+	invoice.TargetFund = decimal.NewFromFloat(req.TargetFund)
+	invoice.YieldPercent = decimal.NewFromFloat(req.YieldPercent)
+	invoice.DurationDays = req.DurationDays
+	invoice.OfftakerID = req.OfftakerID
+	if req.ImageKey != nil {
+		invoice.ImageURL = req.ImageKey
+	}
+
+	oldValues, newValues, err := diffInvoiceContent(before, toInvoiceContent(invoice))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(newValues) > 0 {
+		version, err := newInvoiceVersion(invoice, &farmerID, oldValues, newValues)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.versionRepo.Edit(invoice, version); err != nil {
+			if errors.Is(err, repositories.ErrInvoiceNotPending) {
+				return nil, ErrInvoiceNotEditable
+			}
+			return nil, fmt.Errorf("failed to update invoice: %w", err)
+		}
+
+		s.auditor.Record(ctx, audit.Entry{
+			Action:     models.AuditActionUpdateInvoice,
+			EntityType: models.AuditEntityTypeInvoice,
+			EntityID:   invoice.ID,
+			Before:     oldValues,
+			After:      newValues,
+		})
+	}
+
+	invoice.Farm = *farm
+
+	return s.toInvoiceResponse(invoice), nil
+}
+
+// Withdraw withdraws a pending invoice that is not on chain yet, taking it out of the review
+// queue. The invoice and its versions are kept.
+func (s *InvoiceService) Withdraw(ctx context.Context, farmerID, invoiceID string) (*response.InvoiceResponse, error) {
+	invoice, err := s.invoiceRepo.GetByIDAndFarmerID(invoiceID, farmerID)
+	if err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	if invoice.Status != models.InvoiceStatusPending || invoice.TokenID != nil {
+		return nil, ErrInvoiceNotEditable
+	}
+
+	now := s.now()
+	actor := audit.Actor{Type: audit.ActorFarmer, ID: farmerID}
+	updates := map[string]interface{}{"withdrawn_at": now}
+	if err := transitionInvoice(s.transitionRepo, invoice, models.InvoiceStatusWithdrawn, "withdrawn by farmer", actor, updates); err != nil {
+		if errors.Is(err, repositories.ErrInvoiceStatusChanged) {
+			return nil, ErrInvoiceNotEditable
+		}
+		return nil, fmt.Errorf("failed to withdraw invoice: %w", err)
+	}
+
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionWithdrawInvoice,
+		EntityType: models.AuditEntityTypeInvoice,
+		EntityID:   invoice.ID,
+		Before:     map[string]interface{}{"status": models.InvoiceStatusPending},
+		After:      map[string]interface{}{"status": invoice.Status},
+	})
+
+	invoice.WithdrawnAt = &now
+	invoice.UpdatedAt = now
+
+	// Get farm for response
+	farm, _ := s.farmRepo.GetByID(invoice.FarmID)
+	if farm != nil {
+		invoice.Farm = *farm
+	}
+
+	return s.toInvoiceResponse(invoice), nil
+}
+
 // List retrieves all invoices for a farmer with pagination
 func (s *InvoiceService) List(ctx context.Context, farmerID string, req *request.ListInvoiceRequest) (*response.ListInvoiceResponse, error) {
 	// Set defaults
@@ -270,6 +434,7 @@ func (s *InvoiceService) ListAvailableInvoices(ctx context.Context, req *request
 			FundingProgress: fundingProgress,
 			YieldPercent:    inv.YieldPercent,
 			DurationDays:    inv.DurationDays,
+			FundingDeadline: inv.FundingDeadline,
 			MaturityDate:    inv.MaturityDate,
 			FarmID:          inv.FarmID,
 			FarmName:        inv.Farm.Name,
@@ -306,7 +471,33 @@ func (s *InvoiceService) GetByIDForAdmin(ctx context.Context, invoiceID string)
 		return nil, ErrInvoiceNotFound
 	}
 
-	return s.toInvoiceResponse(invoice), nil
+	// Edit history, so the reviewer sees what changed since submission
+	versions, err := s.versionRepo.GetAllByInvoiceID(invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice versions: %w", err)
+	}
+
+	transitions, err := s.transitionRepo.GetAllByInvoiceID(invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice status history: %w", err)
+	}
+
+	resp := s.toInvoiceResponse(invoice)
+	resp.Versions = toInvoiceVersionItems(versions)
+	resp.StatusHistory = toInvoiceStatusTransitionItems(transitions)
+	if len(versions) > 1 {
+		var submitted invoiceContent
+		if err := json.Unmarshal(versions[len(versions)-1].Snapshot, &submitted); err != nil {
+			return nil, fmt.Errorf("failed to read submitted invoice version: %w", err)
+		}
+		oldValues, newValues, err := diffInvoiceContent(submitted, toInvoiceContent(invoice))
+		if err != nil {
+			return nil, err
+		}
+		resp.ChangesSinceSubmission = &response.InvoiceChanges{OldValues: oldValues, NewValues: newValues}
+	}
+
+	return resp, nil
 }
 
 // ListForAdmin retrieves all invoices with pagination (for admin)
@@ -382,7 +573,7 @@ func (s *InvoiceService) ListForAdmin(ctx context.Context, req *request.ListInvo
 }
 
 // ApproveInvoice approves an invoice with blockchain data
-func (s *InvoiceService) ApproveInvoice(ctx context.Context, invoiceID, adminID string, req *request.ApproveInvoiceRequest, ipAddress, userAgent string) (*response.InvoiceStatusUpdateResponse, error) {
+func (s *InvoiceService) ApproveInvoice(ctx context.Context, invoiceID, adminID string, req *request.ApproveInvoiceRequest) (*response.InvoiceStatusUpdateResponse, error) {
 	invoice, err := s.invoiceRepo.GetByID(invoiceID)
 	if err != nil {
 		return nil, ErrInvoiceNotFound
@@ -393,38 +584,63 @@ func (s *InvoiceService) ApproveInvoice(ctx context.Context, invoiceID, adminID
 		return nil, ErrInvoiceAlreadyProcessed
 	}
 
-	// Store old status for audit
-	oldStatus := string(invoice.Status)
-
-	// Update invoice status and blockchain data
-	now := time.Now()
-	invoice.Status = models.InvoiceStatusApproved
+	before := toInvoiceReviewState(invoice)
+
+	// Approval opens the invoice for funding with its blockchain data. The crop cycle starts
+	// when the funding window closes, so the invoice matures DurationDays after the deadline.
+	now := s.now()
+	fundingDeadline := now.Add(s.fundingWindow)
+	maturityDate := fundingDeadline.AddDate(0, 0, invoice.DurationDays)
+	actor := audit.Actor{Type: audit.ActorAdmin, ID: adminID}
+	updates := map[string]interface{}{
+		"reviewed_by":      adminID,
+		"reviewed_at":      now,
+		"approved_at":      now,
+		"funding_deadline": fundingDeadline,
+		"maturity_date":    maturityDate,
+		"token_id":         req.TokenID,
+		"approval_tx_hash": req.ApprovalTxHash,
+	}
+	if err := transitionInvoice(s.transitionRepo, invoice, models.InvoiceStatusFunding, "approved by admin", actor, updates); err != nil {
+		if errors.Is(err, repositories.ErrInvoiceStatusChanged) {
+			return nil, ErrInvoiceAlreadyProcessed
+		}
+		return nil, fmt.Errorf("failed to update invoice: %w", err)
+	}
 	invoice.ReviewedBy = &adminID
 	invoice.ReviewedAt = &now
 	invoice.ApprovedAt = &now
+	invoice.FundingDeadline = &fundingDeadline
+	invoice.MaturityDate = &maturityDate
 	invoice.TokenID = &req.TokenID
 	invoice.ApprovalTxHash = &req.ApprovalTxHash
 
-	if err := s.invoiceRepo.Update(invoice); err != nil {
-		return nil, fmt.Errorf("failed to update invoice: %w", err)
-	}
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionApproveInvoice,
+		EntityType: models.AuditEntityTypeInvoice,
+		EntityID:   invoiceID,
+		Before:     before,
+		After:      toInvoiceReviewState(invoice),
+	})
 
-	// Create audit log
-	s.createAuditLog(adminID, models.AuditActionApproveInvoice, models.AuditEntityTypeInvoice, invoiceID, oldStatus, string(invoice.Status), nil, ipAddress, userAgent)
+	// Tell the farmer about the outcome
+	s.notifyFarmer(ctx, invoice)
 
 	return &response.InvoiceStatusUpdateResponse{
-		InvoiceID:      invoice.ID,
-		Status:         string(invoice.Status),
-		TokenID:        invoice.TokenID,
-		ApprovalTxHash: invoice.ApprovalTxHash,
-		ReviewedBy:     adminID,
-		ReviewedAt:     now,
-		Reason:         nil,
+		InvoiceID:       invoice.ID,
+		Status:          string(invoice.Status),
+		TokenID:         invoice.TokenID,
+		ApprovalTxHash:  invoice.ApprovalTxHash,
+		FundingDeadline: invoice.FundingDeadline,
+		MaturityDate:    invoice.MaturityDate,
+		ReviewedBy:      adminID,
+		ReviewedAt:      now,
+		Reason:          nil,
 	}, nil
 }
 
 // RejectInvoice rejects an invoice
-func (s *InvoiceService) RejectInvoice(ctx context.Context, invoiceID, adminID string, reason *string, ipAddress, userAgent string) (*response.InvoiceStatusUpdateResponse, error) {
+func (s *InvoiceService) RejectInvoice(ctx context.Context, invoiceID, adminID string, reason *string) (*response.InvoiceStatusUpdateResponse, error) {
 	invoice, err := s.invoiceRepo.GetByID(invoiceID)
 	if err != nil {
 		return nil, ErrInvoiceNotFound
@@ -435,22 +651,36 @@ func (s *InvoiceService) RejectInvoice(ctx context.Context, invoiceID, adminID s
 		return nil, ErrInvoiceAlreadyProcessed
 	}
 
-	// Store old status for audit
-	oldStatus := string(invoice.Status)
+	before := toInvoiceReviewState(invoice)
 
 	// Update invoice status
-	now := time.Now()
-	invoice.Status = models.InvoiceStatusRejected
+	now := s.now()
+	actor := audit.Actor{Type: audit.ActorAdmin, ID: adminID}
+	updates := map[string]interface{}{
+		"reviewed_by":      adminID,
+		"reviewed_at":      now,
+		"rejection_reason": reason,
+	}
+	if err := transitionInvoice(s.transitionRepo, invoice, models.InvoiceStatusRejected, "rejected by admin", actor, updates); err != nil {
+		if errors.Is(err, repositories.ErrInvoiceStatusChanged) {
+			return nil, ErrInvoiceAlreadyProcessed
+		}
+		return nil, fmt.Errorf("failed to update invoice: %w", err)
+	}
 	invoice.ReviewedBy = &adminID
 	invoice.ReviewedAt = &now
 	invoice.RejectionReason = reason
 
-	if err := s.invoiceRepo.Update(invoice); err != nil {
-		return nil, fmt.Errorf("failed to update invoice: %w", err)
-	}
+	s.auditor.Record(ctx, audit.Entry{
+		Action:     models.AuditActionRejectInvoice,
+		EntityType: models.AuditEntityTypeInvoice,
+		EntityID:   invoiceID,
+		Before:     before,
+		After:      toInvoiceReviewState(invoice),
+	})
 
-	// Create audit log
-	s.createAuditLog(adminID, models.AuditActionRejectInvoice, models.AuditEntityTypeInvoice, invoiceID, oldStatus, string(invoice.Status), reason, ipAddress, userAgent)
+	// Tell the farmer about the outcome
+	s.notifyFarmer(ctx, invoice)
 
 	return &response.InvoiceStatusUpdateResponse{
 		InvoiceID:  invoice.ID,
@@ -461,39 +691,138 @@ func (s *InvoiceService) RejectInvoice(ctx context.Context, invoiceID, adminID s
 	}, nil
 }
 
-// createAuditLog creates an audit log entry
-func (s *InvoiceService) createAuditLog(adminID, action, entityType, entityID, oldStatus, newStatus string, reason *string, ipAddress, userAgent string) {
-	oldValues, _ := json.Marshal(map[string]interface{}{"status": oldStatus})
-	newValuesMap := map[string]interface{}{"status": newStatus}
-	if reason != nil {
-		newValuesMap["rejection_reason"] = *reason
+// notifyFarmer sends the review outcome of an invoice to the farmer owning its farm
+func (s *InvoiceService) notifyFarmer(ctx context.Context, invoice *models.Invoice) {
+	farm, err := s.farmRepo.GetByID(invoice.FarmID)
+	if err != nil {
+		fmt.Printf("failed to get farm for invoice notification: %v\n", err)
+		return
 	}
-	newValues, _ := json.Marshal(newValuesMap)
+	s.notifier.NotifyInvoiceReviewed(ctx, farm.FarmerID, invoice)
+}
 
-	var ipAddr *string
-	if ipAddress != "" {
-		ipAddr = &ipAddress
+// invoiceReviewState is the part of an invoice recorded in the audit log of review decisions
+type invoiceReviewState struct {
+	Status          models.InvoiceStatus `json:"status"`
+	ReviewedBy      *string              `json:"reviewed_by,omitempty"`
+	RejectionReason *string              `json:"rejection_reason,omitempty"`
+	TokenID         *int64               `json:"token_id,omitempty"`
+	ApprovalTxHash  *string              `json:"approval_tx_hash,omitempty"`
+}
+
+func toInvoiceReviewState(invoice *models.Invoice) invoiceReviewState {
+	return invoiceReviewState{
+		Status:          invoice.Status,
+		ReviewedBy:      invoice.ReviewedBy,
+		RejectionReason: invoice.RejectionReason,
+		TokenID:         invoice.TokenID,
+		ApprovalTxHash:  invoice.ApprovalTxHash,
 	}
-	var ua *string
-	if userAgent != "" {
-		ua = &userAgent
+}
+
+// invoiceContent is the part of an invoice the farmer can edit, snapshotted in every version
+type invoiceContent struct {
+	FarmID       string          `json:"farm_id"`
+	Name         string          `json:"name"`
+	Description  *string         `json:"description"`
+	ImageURL     *string         `json:"image_url"`
+	TargetFund   decimal.Decimal `json:"target_fund"`
+	YieldPercent decimal.Decimal `json:"yield_percent"`
+	DurationDays int             `json:"duration_days"`
+	OfftakerID   *string         `json:"offtaker_id"`
+}
+
+func toInvoiceContent(invoice *models.Invoice) invoiceContent {
+	return invoiceContent{
+		FarmID:       invoice.FarmID,
+		Name:         invoice.Name,
+		Description:  invoice.Description,
+		ImageURL:     invoice.ImageURL,
+		TargetFund:   invoice.TargetFund,
+		YieldPercent: invoice.YieldPercent,
+		DurationDays: invoice.DurationDays,
+		OfftakerID:   invoice.OfftakerID,
 	}
+}
 
-	auditLog := &models.AdminAuditLog{
-		AdminID:    adminID,
-		Action:     action,
-		EntityType: entityType,
-		EntityID:   entityID,
-		OldValues:  oldValues,
-		NewValues:  newValues,
-		IPAddress:  ipAddr,
-		UserAgent:  ua,
+// diffInvoiceContent returns the old and new values of the fields that differ, by their JSON
+// names. Decimals are compared in their canonical form, so 100 and 100.00000000 are equal.
+func diffInvoiceContent(before, after invoiceContent) (map[string]interface{}, map[string]interface{}, error) {
+	oldMap, err := toJSONMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	newMap, err := toJSONMap(after)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Log error but don't fail the main operation
-	if err := s.auditLogRepo.Create(auditLog); err != nil {
-		fmt.Printf("failed to create audit log: %v\n", err)
+	oldValues, newValues := map[string]interface{}{}, map[string]interface{}{}
+	for field, value := range newMap {
+		if reflect.DeepEqual(oldMap[field], value) {
+			continue
+		}
+		oldValues[field] = oldMap[field]
+		newValues[field] = value
+	}
+	return oldValues, newValues, nil
+}
+
+// newInvoiceVersion snapshots the invoice with the fields changed since the previous version
+func newInvoiceVersion(invoice *models.Invoice, editedBy *string, oldValues, newValues map[string]interface{}) (*models.InvoiceVersion, error) {
+	snapshot, err := json.Marshal(toInvoiceContent(invoice))
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot invoice version: %w", err)
+	}
+	oldJSON, err := json.Marshal(oldValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal old values: %w", err)
+	}
+	newJSON, err := json.Marshal(newValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal new values: %w", err)
+	}
+	return &models.InvoiceVersion{
+		InvoiceID: invoice.ID,
+		Snapshot:  snapshot,
+		OldValues: oldJSON,
+		NewValues: newJSON,
+		EditedBy:  editedBy,
+	}, nil
+}
+
+// toInvoiceVersionItems converts invoice versions to their response items
+func toInvoiceVersionItems(versions []models.InvoiceVersion) []response.InvoiceVersionItem {
+	items := make([]response.InvoiceVersionItem, 0, len(versions))
+	for _, version := range versions {
+		item := response.InvoiceVersionItem{
+			ID:            version.ID,
+			VersionNumber: version.VersionNumber,
+			EditedBy:      version.EditedBy,
+			CreatedAt:     version.CreatedAt,
+		}
+		_ = json.Unmarshal(version.Snapshot, &item.Snapshot)
+		_ = json.Unmarshal(version.OldValues, &item.OldValues)
+		_ = json.Unmarshal(version.NewValues, &item.NewValues)
+		items = append(items, item)
+	}
+	return items
+}
+
+// toInvoiceStatusTransitionItems converts status transitions to their response items
+func toInvoiceStatusTransitionItems(transitions []models.InvoiceStatusTransition) []response.InvoiceStatusTransitionItem {
+	items := make([]response.InvoiceStatusTransitionItem, 0, len(transitions))
+	for _, transition := range transitions {
+		items = append(items, response.InvoiceStatusTransitionItem{
+			FromStatus: string(transition.FromStatus),
+			ToStatus:   string(transition.ToStatus),
+			Reason:     transition.Reason,
+			ActorType:  transition.ActorType,
+			ActorID:    transition.ActorID,
+			CreatedAt:  transition.CreatedAt,
+		})
 	}
+	return items
 }
 
 // toInvoiceResponse converts an Invoice model to InvoiceResponse
@@ -517,6 +846,7 @@ func (s *InvoiceService) toInvoiceResponse(invoice *models.Invoice) *response.In
 		ReviewedBy:      invoice.ReviewedBy,
 		ReviewedAt:      invoice.ReviewedAt,
 		ApprovedAt:      invoice.ApprovedAt,
+		WithdrawnAt:     invoice.WithdrawnAt,
 		FundingDeadline: invoice.FundingDeadline,
 		MaturityDate:    invoice.MaturityDate,
 		ApprovalTxHash:  invoice.ApprovalTxHash,
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/audit"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffInvoiceContent(t *testing.T) {
+	description := "Cabai rawit merah"
+	invoice := &models.Invoice{
+		FarmID:       "a1b2c3d4-e5f6-7890-abcd-ef1234567890",
+		Name:         "Cabai Rawit - Batch 1",
+		Description:  &description,
+		TargetFund:   decimal.NewFromInt(45000000),
+		YieldPercent: decimal.RequireFromString("18.5"),
+		DurationDays: 90,
+	}
+
+	// Version 1 as backfilled by the migration, with the decimals as stored in the database
+	var submitted invoiceContent
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"farm_id": "a1b2c3d4-e5f6-7890-abcd-ef1234567890",
+		"name": "Cabai Rawit - Batch 1",
+		"description": "Cabai rawit merah",
+		"image_url": null,
+		"target_fund": "45000000.00000000",
+		"yield_percent": "18.50",
+		"duration_days": 90,
+		"offtaker_id": null
+	}`), &submitted))
+
+	oldValues, newValues, err := diffInvoiceContent(submitted, toInvoiceContent(invoice))
+	require.NoError(t, err)
+	assert.Empty(t, newValues, "unchanged decimals must not show as changes")
+
+	invoice.TargetFund = decimal.NewFromInt(50000000)
+	invoice.Description = nil
+	oldValues, newValues, err = diffInvoiceContent(submitted, toInvoiceContent(invoice))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"target_fund": "45000000", "description": "Cabai rawit merah"}, oldValues)
+	assert.Equal(t, map[string]interface{}{"target_fund": "50000000", "description": nil}, newValues)
+}
+
+// nopRecorder drops audit entries
+type nopRecorder struct{}
+
+func (nopRecorder) Record(ctx context.Context, entry audit.Entry) {}
+
+func (nopRecorder) RecordRequired(ctx context.Context, entry audit.Entry) error { return nil }
+
+// stubFarmRepo only implements lookups of a single farm; other methods panic if called
+type stubFarmRepo struct {
+	repositories.FarmRepository
+	farm *models.Farm
+}
+
+func (r *stubFarmRepo) GetByID(id string) (*models.Farm, error) {
+	if r.farm == nil || r.farm.ID != id {
+		return nil, errors.New("record not found")
+	}
+	return r.farm, nil
+}
+
+func (r *stubFarmRepo) GetByIDAndFarmerID(id, farmerID string) (*models.Farm, error) {
+	farm, err := r.GetByID(id)
+	if err != nil || farm.FarmerID != farmerID {
+		return nil, errors.New("record not found")
+	}
+	return farm, nil
+}
+
+// memoryInvoiceVersionRepo stores invoices created or edited with their versions; other
+// methods panic if called
+type memoryInvoiceVersionRepo struct {
+	repositories.InvoiceVersionRepository
+	err      error
+	invoices []models.Invoice
+	versions []models.InvoiceVersion
+}
+
+func (r *memoryInvoiceVersionRepo) CreateInvoice(invoice *models.Invoice, version *models.InvoiceVersion) error {
+	if r.err != nil {
+		return r.err
+	}
+	invoice.ID = "invoice-1"
+	version.InvoiceID = invoice.ID
+	version.VersionNumber = 1
+	r.invoices = append(r.invoices, *invoice)
+	r.versions = append(r.versions, *version)
+	return nil
+}
+
+func (r *memoryInvoiceVersionRepo) Edit(invoice *models.Invoice, version *models.InvoiceVersion) error {
+	if r.err != nil {
+		return r.err
+	}
+	version.InvoiceID = invoice.ID
+	version.VersionNumber = len(r.versions) + 2
+	r.invoices = append(r.invoices, *invoice)
+	r.versions = append(r.versions, *version)
+	return nil
+}
+
+func TestInvoiceServiceCreate_RecordsFirstVersionWithInvoice(t *testing.T) {
+	farmer := testFarmer(models.FarmerStatusApproved, "id")
+	farmRepo := &stubFarmRepo{farm: &models.Farm{ID: "farm-1", FarmerID: farmer.ID, Name: "Kebun Cabai", IsActive: true}}
+	req := &request.CreateInvoiceRequest{FarmID: "farm-1", Name: "Cabai Rawit", TargetFund: 5000, YieldPercent: 12, DurationDays: 90}
+
+	// Invoices are only written together with their version; the invoice repository is unused
+	versionRepo := &memoryInvoiceVersionRepo{}
+	svc := NewInvoiceService(nil, versionRepo, nil, farmRepo, &stubFarmerRepo{farmer: farmer}, nil, nopRecorder{}, nil, 0)
+
+	resp, err := svc.Create(context.Background(), farmer.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, "invoice-1", resp.ID)
+	require.Len(t, versionRepo.invoices, 1)
+	require.Len(t, versionRepo.versions, 1)
+	assert.Equal(t, models.InvoiceStatusPending, versionRepo.invoices[0].Status)
+	assert.Equal(t, "invoice-1", versionRepo.versions[0].InvoiceID)
+	assert.Nil(t, versionRepo.versions[0].EditedBy)
+
+	var snapshot invoiceContent
+	require.NoError(t, json.Unmarshal(versionRepo.versions[0].Snapshot, &snapshot))
+	assert.Equal(t, "Cabai Rawit", snapshot.Name)
+	assert.Equal(t, "farm-1", snapshot.FarmID)
+	assert.True(t, snapshot.TargetFund.Equal(decimal.NewFromInt(5000)))
+	assert.Equal(t, 90, snapshot.DurationDays)
+
+	// A failed write leaves neither the invoice nor a version behind
+	failing := &memoryInvoiceVersionRepo{err: errors.New("connection reset")}
+	svc = NewInvoiceService(nil, failing, nil, farmRepo, &stubFarmerRepo{farmer: farmer}, nil, nopRecorder{}, nil, 0)
+	_, err = svc.Create(context.Background(), farmer.ID, req)
+	assert.Error(t, err)
+	assert.Empty(t, failing.invoices)
+	assert.Empty(t, failing.versions)
+}
+
+func TestApproveInvoice_SetsFundingDeadlineAndMaturityDate(t *testing.T) {
+	approvedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	approve := func(t *testing.T, windowDays int) *models.Invoice {
+		invoiceRepo := &memoryInvoiceRepo{invoices: []models.Invoice{
+			{ID: "invoice-1", FarmID: "farm-1", Status: models.InvoiceStatusPending, DurationDays: 90},
+		}}
+		transitionRepo := &memoryTransitionRepo{invoiceRepo: invoiceRepo}
+		farmRepo := &stubFarmRepo{farm: &models.Farm{ID: "farm-1", FarmerID: "farmer-1"}}
+		notifier := &recordingFarmerNotifier{}
+		svc := NewInvoiceService(invoiceRepo, nil, transitionRepo, farmRepo, nil, nil, nopRecorder{}, notifier, time.Duration(windowDays)*24*time.Hour)
+		svc.now = func() time.Time { return approvedAt }
+
+		resp, err := svc.ApproveInvoice(context.Background(), "invoice-1", "admin-1", &request.ApproveInvoiceRequest{
+			TokenID:        7,
+			ApprovalTxHash: "0x" + strings.Repeat("ab", 32),
+		})
+		require.NoError(t, err)
+		require.Len(t, transitionRepo.updates, 1)
+		assert.Equal(t, *resp.FundingDeadline, transitionRepo.updates[0]["funding_deadline"])
+		assert.Equal(t, *resp.MaturityDate, transitionRepo.updates[0]["maturity_date"])
+		assert.Equal(t, []string{"farmer-1:invoice-1"}, notifier.reviewed)
+		return &models.Invoice{FundingDeadline: resp.FundingDeadline, MaturityDate: resp.MaturityDate}
+	}
+
+	t.Run("window from approval, crop cycle from the deadline", func(t *testing.T) {
+		dates := approve(t, 21)
+		assert.Equal(t, approvedAt.AddDate(0, 0, 21), *dates.FundingDeadline)
+		assert.Equal(t, approvedAt.AddDate(0, 0, 21+90), *dates.MaturityDate)
+	})
+
+	// Migration 000026 backfilled invoices approved before deadlines existed; they must get
+	// the dates approval gives with the default window
+	t.Run("same dates as the migration backfill", func(t *testing.T) {
+		migration, err := os.ReadFile("../../migrations/000026_backfill_invoice_funding_deadlines.up.sql")
+		require.NoError(t, err)
+		sql := string(migration)
+
+		window := regexp.MustCompile(`funding_deadline = COALESCE\(approved_at, reviewed_at, created_at\) \+ INTERVAL '(\d+) days'`).FindStringSubmatch(sql)
+		require.NotNil(t, window, "backfill of funding_deadline not found")
+		windowDays, err := strconv.Atoi(window[1])
+		require.NoError(t, err)
+		assert.Equal(t, config.DefaultInvoiceFundingWindowDays, windowDays)
+		assert.Contains(t, sql, "maturity_date = funding_deadline + make_interval(days => duration_days)")
+
+		dates := approve(t, config.DefaultInvoiceFundingWindowDays)
+		backfilledDeadline := approvedAt.AddDate(0, 0, windowDays)
+		assert.Equal(t, backfilledDeadline, *dates.FundingDeadline)
+		assert.Equal(t, backfilledDeadline.AddDate(0, 0, 90), *dates.MaturityDate)
+	})
+}
+
+func TestInvoiceServiceUpdate_RecordsVersionsOnlyForPendingChanges(t *testing.T) {
+	farmer := testFarmer(models.FarmerStatusApproved, "id")
+	farmRepo := &stubFarmRepo{farm: &models.Farm{ID: "farm-1", FarmerID: farmer.ID, Name: "Kebun Cabai", IsActive: true}}
+	pending := models.Invoice{
+		ID: "invoice-1", FarmID: "farm-1", Name: "Cabai Rawit", Status: models.InvoiceStatusPending,
+		TargetFund: decimal.NewFromInt(5000), YieldPercent: decimal.NewFromInt(12), DurationDays: 90,
+	}
+	unchanged := &request.UpdateInvoiceRequest{FarmID: "farm-1", Name: "Cabai Rawit", TargetFund: 5000, YieldPercent: 12, DurationDays: 90}
+	update := func(invoice models.Invoice, versionRepo *memoryInvoiceVersionRepo, req *request.UpdateInvoiceRequest) error {
+		invoiceRepo := &memoryInvoiceRepo{invoices: []models.Invoice{invoice}}
+		svc := NewInvoiceService(invoiceRepo, versionRepo, nil, farmRepo, &stubFarmerRepo{farmer: farmer}, nil, nopRecorder{}, nil, 0)
+		_, err := svc.Update(context.Background(), farmer.ID, invoice.ID, req)
+		return err
+	}
+
+	t.Run("edit records a version", func(t *testing.T) {
+		versionRepo := &memoryInvoiceVersionRepo{}
+		edited := *unchanged
+		edited.TargetFund = 7500
+		require.NoError(t, update(pending, versionRepo, &edited))
+		require.Len(t, versionRepo.versions, 1)
+		assert.Equal(t, &farmer.ID, versionRepo.versions[0].EditedBy)
+		assert.True(t, versionRepo.invoices[0].TargetFund.Equal(decimal.NewFromInt(7500)))
+	})
+
+	t.Run("edit without changes records no version", func(t *testing.T) {
+		versionRepo := &memoryInvoiceVersionRepo{}
+		require.NoError(t, update(pending, versionRepo, unchanged))
+		assert.Empty(t, versionRepo.versions)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		funding := pending
+		funding.Status = models.InvoiceStatusFunding
+		versionRepo := &memoryInvoiceVersionRepo{}
+		assert.ErrorIs(t, update(funding, versionRepo, unchanged), ErrInvoiceNotEditable)
+		assert.Empty(t, versionRepo.versions)
+	})
+
+	t.Run("on chain", func(t *testing.T) {
+		onchain := pending
+		onchain.TokenID = tokenID(7)
+		versionRepo := &memoryInvoiceVersionRepo{}
+		assert.ErrorIs(t, update(onchain, versionRepo, unchanged), ErrInvoiceNotEditable)
+		assert.Empty(t, versionRepo.versions)
+	})
+
+	t.Run("reviewed while editing", func(t *testing.T) {
+		edited := *unchanged
+		edited.Name = "Cabai Merah"
+		versionRepo := &memoryInvoiceVersionRepo{err: repositories.ErrInvoiceNotPending}
+		assert.ErrorIs(t, update(pending, versionRepo, &edited), ErrInvoiceNotEditable)
+	})
+}
+
+func TestInvoiceServiceWithdraw_OnlyPendingInvoicesOffChain(t *testing.T) {
+	farmRepo := &stubFarmRepo{farm: &models.Farm{ID: "farm-1", FarmerID: "farmer-1"}}
+	pending := models.Invoice{ID: "invoice-1", FarmID: "farm-1", Status: models.InvoiceStatusPending}
+	// stored is the invoice the transition is applied to, which a review may have moved on
+	// since the service loaded it
+	withdraw := func(loaded, stored models.Invoice) (*memoryTransitionRepo, error) {
+		transitionRepo := &memoryTransitionRepo{invoiceRepo: &memoryInvoiceRepo{invoices: []models.Invoice{stored}}}
+		svc := NewInvoiceService(&memoryInvoiceRepo{invoices: []models.Invoice{loaded}}, nil, transitionRepo, farmRepo, nil, nil, nopRecorder{}, nil, 0)
+		_, err := svc.Withdraw(context.Background(), "farmer-1", loaded.ID)
+		return transitionRepo, err
+	}
+
+	t.Run("pending", func(t *testing.T) {
+		transitionRepo, err := withdraw(pending, pending)
+		require.NoError(t, err)
+		require.Len(t, transitionRepo.transitions, 1)
+		assert.Equal(t, models.InvoiceStatusWithdrawn, transitionRepo.invoiceRepo.invoices[0].Status)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		rejected := pending
+		rejected.Status = models.InvoiceStatusRejected
+		transitionRepo, err := withdraw(rejected, rejected)
+		assert.ErrorIs(t, err, ErrInvoiceNotEditable)
+		assert.Empty(t, transitionRepo.transitions)
+	})
+
+	t.Run("on chain", func(t *testing.T) {
+		onchain := pending
+		onchain.TokenID = tokenID(7)
+		transitionRepo, err := withdraw(onchain, onchain)
+		assert.ErrorIs(t, err, ErrInvoiceNotEditable)
+		assert.Empty(t, transitionRepo.transitions)
+	})
+
+	t.Run("approved while withdrawing", func(t *testing.T) {
+		approved := pending
+		approved.Status = models.InvoiceStatusFunding
+		transitionRepo, err := withdraw(pending, approved)
+		assert.ErrorIs(t, err, ErrInvoiceNotEditable)
+		assert.Empty(t, transitionRepo.transitions)
+		assert.Equal(t, models.InvoiceStatusFunding, transitionRepo.invoiceRepo.invoices[0].Status)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
@@ -14,25 +15,33 @@ import (
 const (
 	// LeaderboardCacheTTL is the TTL for leaderboard cache
 	LeaderboardCacheTTL = 5 * time.Minute
+	// LeaderboardRankTTL is how long the last known rank of a user is remembered for change detection
+	LeaderboardRankTTL = 24 * time.Hour
 )
 
+// leaderboardTypes lists every leaderboard a user can be ranked on
+var leaderboardTypes = []string{"xp", "wealth", "profit"}
+
 // LeaderboardServiceInterface defines the interface for leaderboard operations
 type LeaderboardServiceInterface interface {
 	GetLeaderboard(ctx context.Context, userID string, leaderboardType string, limit int) (*response.LeaderboardResponse, error)
+	TrackRankChanges(ctx context.Context, userID string) error
 }
 
 // LeaderboardService handles leaderboard business logic
 type LeaderboardService struct {
 	repo     repositories.LeaderboardRepository
 	valkey   valkey.Client
+	eventBus EventBus
 	cacheTTL time.Duration
 }
 
 // NewLeaderboardService creates a new LeaderboardService instance
-func NewLeaderboardService(repo repositories.LeaderboardRepository, valkeyClient valkey.Client) *LeaderboardService {
+func NewLeaderboardService(repo repositories.LeaderboardRepository, valkeyClient valkey.Client, eventBus EventBus) *LeaderboardService {
 	return &LeaderboardService{
 		repo:     repo,
 		valkey:   valkeyClient,
+		eventBus: eventBus,
 		cacheTTL: LeaderboardCacheTTL,
 	}
 }
@@ -101,6 +110,50 @@ func (s *LeaderboardService) GetLeaderboard(ctx context.Context, userID string,
 	return resp, nil
 }
 
+// TrackRankChanges compares the user's current rank on every leaderboard with the last
+// known rank and publishes a live event for each leaderboard where it changed
+func (s *LeaderboardService) TrackRankChanges(ctx context.Context, userID string) error {
+	for _, leaderboardType := range leaderboardTypes {
+		entry, err := s.getUserRank(userID, leaderboardType)
+		if err != nil || entry == nil {
+			// User is not ranked on this leaderboard (e.g. no investments yet)
+			continue
+		}
+
+		key := s.rankKey(leaderboardType, userID)
+		previousRank, err := s.valkey.Do(ctx, s.valkey.B().Get().Key(key).Build()).AsInt64()
+		if err != nil && !valkey.IsValkeyNil(err) {
+			return fmt.Errorf("failed to get previous rank: %w", err)
+		}
+		if previousRank == int64(entry.Rank) {
+			continue
+		}
+
+		cmd := s.valkey.B().Set().Key(key).Value(strconv.Itoa(entry.Rank)).Ex(LeaderboardRankTTL).Build()
+		if err := s.valkey.Do(ctx, cmd).Error(); err != nil {
+			return fmt.Errorf("failed to store rank: %w", err)
+		}
+
+		// The first observation only establishes a baseline
+		if previousRank == 0 {
+			continue
+		}
+
+		publishEvent(ctx, s.eventBus, UserEventChannel(userID), EventTypeLeaderboardRank, response.LeaderboardRankEvent{
+			Type:         leaderboardType,
+			Rank:         entry.Rank,
+			PreviousRank: int(previousRank),
+			Score:        entry.Score.InexactFloat64(),
+		})
+	}
+	return nil
+}
+
+// rankKey generates the key storing the last known rank of a user
+func (s *LeaderboardService) rankKey(leaderboardType, userID string) string {
+	return fmt.Sprintf("leaderboard:rank:%s:%s", leaderboardType, userID)
+}
+
 // cacheKey generates cache key for leaderboard
 func (s *LeaderboardService) cacheKey(leaderboardType string, limit int) string {
 	return fmt.Sprintf("leaderboard:%s:%d", leaderboardType, limit)
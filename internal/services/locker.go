@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// Locker hands out leases on keys so that work shared by several connections or API replicas
+// runs only once per lease
+type Locker interface {
+	// TryLock takes the lease on key for ttl and reports false when someone else holds it
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// ValkeyLocker implements Locker with SET NX. Leases are not released, they expire after ttl.
+type ValkeyLocker struct {
+	client valkey.Client
+}
+
+// NewValkeyLocker creates a new ValkeyLocker instance
+func NewValkeyLocker(client valkey.Client) *ValkeyLocker {
+	return &ValkeyLocker{client: client}
+}
+
+// TryLock takes the lease on key for ttl
+func (l *ValkeyLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	cmd := l.client.B().Set().Key(fmt.Sprintf("lock:%s", key)).Value("1").Nx().Px(ttl).Build()
+	if err := l.client.Do(ctx, cmd).Error(); err != nil {
+		if valkey.IsValkeyNil(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to take lock %s: %w", key, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+)
+
+const (
+	// NotificationChannelWebhook delivers notifications as signed HTTP POST requests
+	NotificationChannelWebhook = "webhook"
+	// NotificationChannelStream pushes notifications to the investor's open /events/stream connections
+	NotificationChannelStream = "stream"
+	// NotificationChannelLog is a local stub that only writes notifications to the server log
+	NotificationChannelLog = "log"
+
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body
+	WebhookSignatureHeader = "X-OwnaFarm-Signature"
+)
+
+var (
+	ErrUnknownNotificationChannel = errors.New("unknown notification channel")
+	ErrWebhookURLNotConfigured    = errors.New("notification webhook URL is not configured")
+)
+
+// NotificationChannel delivers a stored notification to an external destination.
+// The in-app channel is the notifications table itself and is always enabled.
+type NotificationChannel interface {
+	Name() string
+	Send(ctx context.Context, notification *models.Notification) error
+}
+
+// NewNotificationChannels builds the external channels listed in config
+func NewNotificationChannels(cfg *config.NotificationConfig, eventBus EventBus) ([]NotificationChannel, error) {
+	var channels []NotificationChannel
+	for _, name := range cfg.Channels {
+		switch name {
+		case NotificationChannelWebhook:
+			if cfg.WebhookURL == "" {
+				return nil, ErrWebhookURLNotConfigured
+			}
+			channels = append(channels, NewWebhookNotificationChannel(cfg.WebhookURL, cfg.WebhookSecret, time.Duration(cfg.WebhookTimeoutSeconds)*time.Second))
+		case NotificationChannelStream:
+			channels = append(channels, NewStreamNotificationChannel(eventBus))
+		case NotificationChannelLog:
+			channels = append(channels, NewLogNotificationChannel())
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationChannel, name)
+		}
+	}
+	return channels, nil
+}
+
+// WebhookNotificationChannel posts notifications as JSON to a webhook endpoint,
+// e.g. an email/push relay. The body is signed with HMAC-SHA256 when a secret is set.
+type WebhookNotificationChannel struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookNotificationChannel creates a new WebhookNotificationChannel instance
+func NewWebhookNotificationChannel(url, secret string, timeout time.Duration) *WebhookNotificationChannel {
+	return &WebhookNotificationChannel{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the channel name
+func (c *WebhookNotificationChannel) Name() string {
+	return NotificationChannelWebhook
+}
+
+// Send posts the notification to the webhook endpoint
+func (c *WebhookNotificationChannel) Send(ctx context.Context, notification *models.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// StreamNotificationChannel publishes notifications as live events on the user's channel
+type StreamNotificationChannel struct {
+	eventBus EventBus
+}
+
+// NewStreamNotificationChannel creates a new StreamNotificationChannel instance
+func NewStreamNotificationChannel(eventBus EventBus) *StreamNotificationChannel {
+	return &StreamNotificationChannel{eventBus: eventBus}
+}
+
+// Name returns the channel name
+func (c *StreamNotificationChannel) Name() string {
+	return NotificationChannelStream
+}
+
+// Send publishes the notification to the user's event channel
+func (c *StreamNotificationChannel) Send(ctx context.Context, notification *models.Notification) error {
+	return c.eventBus.Publish(ctx, UserEventChannel(notification.UserID), EventTypeNotification, notification)
+}
+
+// LogNotificationChannel is a local stub channel that logs notifications instead of delivering them
+type LogNotificationChannel struct{}
+
+// NewLogNotificationChannel creates a new LogNotificationChannel instance
+func NewLogNotificationChannel() *LogNotificationChannel {
+	return &LogNotificationChannel{}
+}
+
+// Name returns the channel name
+func (c *LogNotificationChannel) Name() string {
+	return NotificationChannelLog
+}
+
+// Send writes the notification to the server log
+func (c *LogNotificationChannel) Send(ctx context.Context, notification *models.Notification) error {
+	log.Printf("[Notification] user=%s type=%s title=%q body=%q", notification.UserID, notification.Type, notification.Title, notification.Body)
+	return nil
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/dto/request"
+	"github.com/ownafarm/ownafarm-backend/internal/dto/response"
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// notificationDeliveryTimeout bounds a single external channel delivery
+const notificationDeliveryTimeout = 10 * time.Second
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// NotificationServiceInterface defines the interface for investor notification operations
+type NotificationServiceInterface interface {
+	// Investor endpoints
+	List(ctx context.Context, userID string, req *request.ListNotificationsRequest) (*response.ListNotificationsResponse, error)
+	GetUnreadCount(ctx context.Context, userID string) (*response.UnreadNotificationCountResponse, error)
+	MarkAsRead(ctx context.Context, userID, notificationID string) error
+	MarkAllAsRead(ctx context.Context, userID string) (int64, error)
+
+	// Event producers. Failures are logged and never returned so that a
+	// notification problem cannot break the game action that produced it.
+	NotifyCropReady(ctx context.Context, userID string, investment *models.Investment)
+	NotifyHarvestSynced(ctx context.Context, userID string, investment *models.Investment)
+	NotifyLevelUp(ctx context.Context, userID string, level int)
+	NotifyAchievementUnlocked(ctx context.Context, userID, achievementCode, achievementName string)
+	NotifyInvoiceFullyFunded(ctx context.Context, userIDs []string, invoice *models.Invoice)
+}
+
+// NotificationService implements NotificationServiceInterface
+type NotificationService struct {
+	notificationRepo repositories.NotificationRepository
+	channels         []NotificationChannel
+}
+
+// NewNotificationService creates a new NotificationService instance
+func NewNotificationService(notificationRepo repositories.NotificationRepository, channels []NotificationChannel) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		channels:         channels,
+	}
+}
+
+// List retrieves notifications for the user together with the unread counter
+func (s *NotificationService) List(ctx context.Context, userID string, req *request.ListNotificationsRequest) (*response.ListNotificationsResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 20
+	}
+
+	notifications, totalCount, err := s.notificationRepo.GetAllByUserID(repositories.NotificationFilter{
+		UserID:     userID,
+		UnreadOnly: req.UnreadOnly,
+		Type:       req.Type,
+		Page:       page,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	unreadCount, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	return &response.ListNotificationsResponse{
+		Notifications: notifications,
+		UnreadCount:   unreadCount,
+		TotalCount:    totalCount,
+		Page:          page,
+		Limit:         limit,
+	}, nil
+}
+
+// GetUnreadCount returns the number of unread notifications for the user
+func (s *NotificationService) GetUnreadCount(ctx context.Context, userID string) (*response.UnreadNotificationCountResponse, error) {
+	count, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return &response.UnreadNotificationCountResponse{UnreadCount: count}, nil
+}
+
+// MarkAsRead marks a single notification owned by the user as read
+func (s *NotificationService) MarkAsRead(ctx context.Context, userID, notificationID string) error {
+	if err := s.notificationRepo.MarkAsRead(notificationID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotificationNotFound
+		}
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllAsRead marks every unread notification of the user as read
+func (s *NotificationService) MarkAllAsRead(ctx context.Context, userID string) (int64, error) {
+	updated, err := s.notificationRepo.MarkAllAsRead(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return updated, nil
+}
+
+// NotifyCropReady notifies the investor that a crop reached 100% and can be harvested
+func (s *NotificationService) NotifyCropReady(ctx context.Context, userID string, investment *models.Investment) {
+	s.notify(ctx, userID, models.NotificationTypeCropReady,
+		"Your crop is ready to harvest",
+		fmt.Sprintf("%s has fully grown. Harvest it to collect your yield.", investment.Invoice.Name),
+		map[string]interface{}{
+			"crop_id":    investment.ID,
+			"invoice_id": investment.InvoiceID,
+		},
+	)
+}
+
+// NotifyHarvestSynced notifies the investor that a harvest was confirmed on-chain
+func (s *NotificationService) NotifyHarvestSynced(ctx context.Context, userID string, investment *models.Investment) {
+	harvestAmount := decimal.Zero
+	if investment.HarvestAmount != nil {
+		harvestAmount = *investment.HarvestAmount
+	}
+	s.notify(ctx, userID, models.NotificationTypeHarvestSynced,
+		"Harvest confirmed",
+		fmt.Sprintf("Your harvest of %s has been confirmed: %s GOLD received.", investment.Invoice.Name, harvestAmount.StringFixed(2)),
+		map[string]interface{}{
+			"crop_id":        investment.ID,
+			"invoice_id":     investment.InvoiceID,
+			"harvest_amount": harvestAmount.String(),
+			"xp_gained":      HarvestXPGain,
+		},
+	)
+}
+
+// NotifyLevelUp notifies the investor that they reached a new level
+func (s *NotificationService) NotifyLevelUp(ctx context.Context, userID string, level int) {
+	s.notify(ctx, userID, models.NotificationTypeLevelUp,
+		"Level up!",
+		fmt.Sprintf("Congratulations, you reached level %d.", level),
+		map[string]interface{}{
+			"level": level,
+		},
+	)
+}
+
+// NotifyAchievementUnlocked notifies the investor that an achievement was unlocked
+func (s *NotificationService) NotifyAchievementUnlocked(ctx context.Context, userID, achievementCode, achievementName string) {
+	s.notify(ctx, userID, models.NotificationTypeAchievementUnlocked,
+		"Achievement unlocked",
+		fmt.Sprintf("You unlocked the %q achievement.", achievementName),
+		map[string]interface{}{
+			"achievement_code": achievementCode,
+		},
+	)
+}
+
+// NotifyInvoiceFullyFunded notifies every investor of an invoice that its funding target was reached
+func (s *NotificationService) NotifyInvoiceFullyFunded(ctx context.Context, userIDs []string, invoice *models.Invoice) {
+	for _, userID := range userIDs {
+		s.notify(ctx, userID, models.NotificationTypeInvoiceFullyFunded,
+			"Invoice fully funded",
+			fmt.Sprintf("%s reached its funding target. Your crop is now growing.", invoice.Name),
+			map[string]interface{}{
+				"invoice_id": invoice.ID,
+			},
+		)
+	}
+}
+
+// notify stores the in-app notification and fans it out to the external channels
+func (s *NotificationService) notify(ctx context.Context, userID string, notificationType models.NotificationType, title, body string, data map[string]interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("[Notification] WARNING: failed to marshal %s payload for user %s: %v", notificationType, userID, err)
+		payload = nil
+	}
+
+	notification := &models.Notification{
+		UserID: userID,
+		Type:   notificationType,
+		Title:  title,
+		Body:   body,
+		Data:   payload,
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("[Notification] WARNING: failed to store %s notification for user %s: %v", notificationType, userID, err)
+		return
+	}
+
+	if len(s.channels) == 0 {
+		return
+	}
+
+	// External delivery must not block or outlive the request that produced the event
+	go func(n *models.Notification) {
+		for _, channel := range s.channels {
+			deliveryCtx, cancel := context.WithTimeout(context.Background(), notificationDeliveryTimeout)
+			if err := channel.Send(deliveryCtx, n); err != nil {
+				log.Printf("[Notification] WARNING: %s delivery failed for notification %s: %v", channel.Name(), n.ID, err)
+			}
+			cancel()
+		}
+	}(notification)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ownafarm/ownafarm-backend/internal/models"
+	"github.com/ownafarm/ownafarm-backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryNotificationRepo is an in-memory NotificationRepository
+type memoryNotificationRepo struct {
+	mu            sync.Mutex
+	notifications []models.Notification
+}
+
+func (r *memoryNotificationRepo) Create(notification *models.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notification.ID = fmt.Sprintf("notification-%d", len(r.notifications)+1)
+	r.notifications = append(r.notifications, *notification)
+	return nil
+}
+
+func (r *memoryNotificationRepo) GetAllByUserID(filter repositories.NotificationFilter) ([]models.Notification, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []models.Notification
+	for _, n := range r.notifications {
+		if n.UserID == filter.UserID && (filter.Type == "" || string(n.Type) == filter.Type) && (!filter.UnreadOnly || n.ReadAt == nil) {
+			result = append(result, n)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (r *memoryNotificationRepo) CountUnread(userID string) (int64, error) {
+	_, count, err := r.GetAllByUserID(repositories.NotificationFilter{UserID: userID, UnreadOnly: true})
+	return count, err
+}
+
+func (r *memoryNotificationRepo) MarkAsRead(id, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for i := range r.notifications {
+		if r.notifications[i].ID == id && r.notifications[i].UserID == userID {
+			r.notifications[i].ReadAt = &now
+			return nil
+		}
+	}
+	return fmt.Errorf("record not found")
+}
+
+func (r *memoryNotificationRepo) MarkAllAsRead(userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	var updated int64
+	for i := range r.notifications {
+		if r.notifications[i].UserID == userID && r.notifications[i].ReadAt == nil {
+			r.notifications[i].ReadAt = &now
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// typesFor returns the types of the user's notifications in the order they were stored
+func (r *memoryNotificationRepo) typesFor(userID string) []models.NotificationType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []models.NotificationType
+	for _, n := range r.notifications {
+		if n.UserID == userID {
+			types = append(types, n.Type)
+		}
+	}
+	return types
+}
+
+// memoryChannel hands every sent notification to the test
+type memoryChannel struct {
+	sent chan *models.Notification
+}
+
+func (c *memoryChannel) Name() string {
+	return "memory"
+}
+
+func (c *memoryChannel) Send(ctx context.Context, notification *models.Notification) error {
+	c.sent <- notification
+	return nil
+}
+
+func TestNotificationServiceNotify(t *testing.T) {
+	repo := &memoryNotificationRepo{}
+	channel := &memoryChannel{sent: make(chan *models.Notification, 1)}
+	svc := NewNotificationService(repo, []NotificationChannel{channel})
+
+	investment := &models.Investment{ID: "crop-1", InvoiceID: "invoice-1", Invoice: models.Invoice{Name: "Padi Sawah"}}
+	svc.NotifyCropReady(context.Background(), "user-1", investment)
+
+	require.Len(t, repo.notifications, 1)
+	stored := repo.notifications[0]
+	assert.Equal(t, "user-1", stored.UserID)
+	assert.Equal(t, models.NotificationTypeCropReady, stored.Type)
+	assert.Contains(t, stored.Body, "Padi Sawah")
+
+	var data map[string]string
+	require.NoError(t, json.Unmarshal(stored.Data, &data))
+	assert.Equal(t, "crop-1", data["crop_id"])
+	assert.Equal(t, "invoice-1", data["invoice_id"])
+
+	select {
+	case sent := <-channel.sent:
+		assert.Equal(t, stored.ID, sent.ID)
+	case <-time.After(time.Second):
+		t.Fatal("notification was not delivered to the channel")
+	}
+}
+
+func TestNotificationServiceNotifyInvoiceFullyFunded(t *testing.T) {
+	repo := &memoryNotificationRepo{}
+	svc := NewNotificationService(repo, nil)
+
+	svc.NotifyInvoiceFullyFunded(context.Background(), []string{"user-1", "user-2"}, &models.Invoice{ID: "invoice-1", Name: "Padi Sawah"})
+
+	assert.Equal(t, []models.NotificationType{models.NotificationTypeInvoiceFullyFunded}, repo.typesFor("user-1"))
+	assert.Equal(t, []models.NotificationType{models.NotificationTypeInvoiceFullyFunded}, repo.typesFor("user-2"))
+}
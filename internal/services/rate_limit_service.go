@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
 	"github.com/valkey-io/valkey-go"
 )
 
@@ -15,6 +18,31 @@ const (
 	RateLimitWindowMinutes = 15
 )
 
+// slidingWindowScript keeps the request times of a key in a sorted set. It drops the requests
+// that left the window and adds this one when the window is under the limit, in one atomic step
+// so concurrent requests cannot overshoot. Returns whether the request was allowed, the requests
+// left and the milliseconds until the oldest request leaves the window.
+var slidingWindowScript = valkey.NewLuaScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[4])
+  redis.call('PEXPIRE', KEYS[1], window)
+  count = count + 1
+  allowed = 1
+end
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+  reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, math.max(limit - count, 0), reset}
+`)
+
 type RateLimitService struct {
 	client valkey.Client
 	window time.Duration
@@ -85,3 +113,27 @@ func (s *RateLimitService) ResetRateLimit(ctx context.Context, identifier string
 	}
 	return nil
 }
+
+// Allow counts a request of key against a sliding window policy, used by the rate limit middleware
+// Returns:
+//   - allowed: true if the request is allowed
+//   - remaining: number of requests left in the window
+//   - reset: time until the oldest request leaves the window and frees a request
+//   - error: any error that occurred
+func (s *RateLimitService) Allow(ctx context.Context, key string, policy config.RateLimitPolicy) (allowed bool, remaining int, reset time.Duration, err error) {
+	now := time.Now().UnixMilli()
+	result, err := slidingWindowScript.Exec(ctx, s.client, []string{"ratelimit:" + key}, []string{
+		strconv.FormatInt(now, 10),
+		strconv.FormatInt(policy.Window.Milliseconds(), 10),
+		strconv.Itoa(policy.Limit),
+		strconv.FormatInt(now, 10) + "-" + uuid.NewString(),
+	}).AsIntSlice()
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if len(result) != 3 {
+		return false, 0, 0, fmt.Errorf("failed to check rate limit: unexpected script result %v", result)
+	}
+
+	return result[0] == 1, int(result[1]), time.Duration(result[2]) * time.Millisecond, nil
+}
@@ -2,15 +2,27 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
 )
 
+// ErrObjectNotFound is returned when a storage object does not exist
+var ErrObjectNotFound = errors.New("storage object not found")
+
+// ObjectInfo contains the metadata of a stored object
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
 // StorageService interface untuk abstraksi storage operations
 type StorageService interface {
 	// Upload file ke storage
@@ -24,6 +36,15 @@ type StorageService interface {
 
 	// GetPresignedDownloadURL generates presigned URL untuk download
 	GetPresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+
+	// HeadObject membaca ukuran dan content type object tanpa mengunduh isinya
+	HeadObject(ctx context.Context, key string) (*ObjectInfo, error)
+
+	// ReadRange membaca sebagian isi object, mulai dari offset sepanjang length byte
+	ReadRange(ctx context.Context, key string, offset, length int64) ([]byte, error)
+
+	// Delete menghapus object dari storage, object yang tidak ada tidak dianggap error
+	Delete(ctx context.Context, key string) error
 }
 
 // R2StorageService implementasi StorageService untuk Cloudflare R2
@@ -80,7 +101,7 @@ func (s *R2StorageService) Download(ctx context.Context, key string) (io.ReadClo
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		return nil, err
+		return nil, mapStorageError(err)
 	}
 	return output.Body, nil
 }
@@ -109,3 +130,54 @@ func (s *R2StorageService) GetPresignedDownloadURL(ctx context.Context, key stri
 	}
 	return presignedReq.URL, nil
 }
+
+// HeadObject returns the size and content type of an object in R2
+func (s *R2StorageService) HeadObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, mapStorageError(err)
+	}
+	return &ObjectInfo{
+		Size:        aws.ToInt64(output.ContentLength),
+		ContentType: aws.ToString(output.ContentType),
+	}, nil
+}
+
+// ReadRange reads a byte range of an object in R2
+func (s *R2StorageService) ReadRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, mapStorageError(err)
+	}
+	defer output.Body.Close()
+	return io.ReadAll(io.LimitReader(output.Body, length))
+}
+
+// Delete removes an object from R2
+func (s *R2StorageService) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return mapStorageError(err)
+}
+
+// mapStorageError converts missing object errors to ErrObjectNotFound
+func mapStorageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var notFound *types.NotFound
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &notFound) || errors.As(err, &noSuchKey) {
+		return ErrObjectNotFound
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// StreamTicketTTL is how long a stream ticket can be redeemed after it was issued
+const StreamTicketTTL = 30 * time.Second
+
+var (
+	ErrInvalidStreamTicket = errors.New("invalid or expired stream ticket")
+)
+
+// StreamTicketServiceInterface defines the interface for event stream tickets
+type StreamTicketServiceInterface interface {
+	IssueStreamTicket(ctx context.Context, userID, walletAddress string) (string, time.Time, error)
+	RedeemStreamTicket(ctx context.Context, ticket string) (userID, walletAddress string, err error)
+}
+
+// StreamTicketService issues short-lived, single-use tickets for /events/stream. EventSource
+// cannot send headers, so the stream is opened with a ticket in the URL instead of the access
+// token; a ticket that ends up in access logs is already used or expired. Only the SHA-256 of
+// a ticket is stored.
+type StreamTicketService struct {
+	client valkey.Client
+}
+
+// NewStreamTicketService creates a new StreamTicketService instance
+func NewStreamTicketService(client valkey.Client) *StreamTicketService {
+	return &StreamTicketService{client: client}
+}
+
+func (s *StreamTicketService) ticketKey(ticket string) string {
+	sum := sha256.Sum256([]byte(ticket))
+	return fmt.Sprintf("stream_ticket:%s", hex.EncodeToString(sum[:]))
+}
+
+// IssueStreamTicket creates a ticket for the investor and returns it with its expiry
+func (s *StreamTicketService) IssueStreamTicket(ctx context.Context, userID, walletAddress string) (string, time.Time, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate stream ticket: %w", err)
+	}
+	ticket := hex.EncodeToString(bytes)
+	expiresAt := time.Now().Add(StreamTicketTTL)
+
+	cmd := s.client.B().Set().Key(s.ticketKey(ticket)).Value(userID + "|" + walletAddress).Ex(StreamTicketTTL).Build()
+	if err := s.client.Do(ctx, cmd).Error(); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store stream ticket in valkey: %w", err)
+	}
+	return ticket, expiresAt, nil
+}
+
+// RedeemStreamTicket deletes the ticket and returns the investor it was issued to
+func (s *StreamTicketService) RedeemStreamTicket(ctx context.Context, ticket string) (string, string, error) {
+	cmd := s.client.B().Getdel().Key(s.ticketKey(ticket)).Build()
+	value, err := s.client.Do(ctx, cmd).ToString()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return "", "", ErrInvalidStreamTicket
+		}
+		return "", "", fmt.Errorf("failed to redeem stream ticket: %w", err)
+	}
+
+	userID, walletAddress, ok := strings.Cut(value, "|")
+	if !ok {
+		return "", "", ErrInvalidStreamTicket
+	}
+	return userID, walletAddress, nil
+}
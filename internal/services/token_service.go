@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/ownafarm/ownafarm-backend/internal/utils"
+	"github.com/valkey-io/valkey-go"
+)
+
+// Token audiences. Refresh tokens only work on the refresh endpoint of their own audience.
+const (
+	TokenAudienceInvestor = utils.AudienceInvestor
+	TokenAudienceFarmer   = utils.AudienceFarmer
+	TokenAudienceAdmin    = utils.AudienceAdmin
+	TokenAudienceSession  = utils.AudienceSession
+)
+
+// Errors for TokenService
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+// rotateRefreshTokenScript swaps the current token of a family for a new one. Presenting a token
+// that was already rotated means it leaked, so the whole family is revoked.
+//
+// KEYS[1] family hash, KEYS[2] set of used token hashes
+// ARGV[1] presented token hash, ARGV[2] new token hash, ARGV[3] TTL in seconds
+var rotateRefreshTokenScript = valkey.NewLuaScript(`
+local family = redis.call('HMGET', KEYS[1], 'subject', 'current', 'issued_at')
+if not family[1] then
+  return {'invalid', '', ''}
+end
+if family[2] ~= ARGV[1] then
+  if redis.call('SISMEMBER', KEYS[2], ARGV[1]) == 1 then
+    redis.call('DEL', KEYS[1], KEYS[2])
+    return {'reused', family[1], ''}
+  end
+  return {'invalid', '', ''}
+end
+redis.call('SADD', KEYS[2], ARGV[1])
+redis.call('HSET', KEYS[1], 'current', ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+return {'rotated', family[1], family[3] or ''}
+`)
+
+// RefreshSession is the login a refresh token belongs to
+type RefreshSession struct {
+	Subject  string
+	IssuedAt time.Time // Login time, kept across rotations
+}
+
+// TokenServiceInterface defines the interface for refresh tokens and access token revocation
+type TokenServiceInterface interface {
+	IssueRefreshToken(ctx context.Context, audience, subject string) (string, error)
+	RotateRefreshToken(ctx context.Context, audience, refreshToken string) (*RefreshSession, string, error)
+	RevokeRefreshToken(ctx context.Context, audience, subject, refreshToken string) error
+	RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	RefreshTokenTTL() time.Duration
+}
+
+// TokenService stores refresh token families and the access token denylist in Valkey.
+// A refresh token is "<family id>.<secret>"; only the SHA-256 of the secret is stored. Every
+// refresh replaces the token of the family, the family expires after the refresh TTL without use.
+type TokenService struct {
+	client     valkey.Client
+	refreshTTL time.Duration
+}
+
+// NewTokenService creates a new TokenService instance
+func NewTokenService(client valkey.Client, cfg *config.JWTConfig) *TokenService {
+	return &TokenService{
+		client:     client,
+		refreshTTL: time.Duration(cfg.RefreshTokenTTLHours) * time.Hour,
+	}
+}
+
+func (s *TokenService) familyKey(audience, familyID string) string {
+	return fmt.Sprintf("auth:refresh:%s:%s", audience, familyID)
+}
+
+func (s *TokenService) usedKey(audience, familyID string) string {
+	return fmt.Sprintf("auth:refresh:%s:%s:used", audience, familyID)
+}
+
+func (s *TokenService) denylistKey(tokenID string) string {
+	return fmt.Sprintf("auth:denylist:%s", tokenID)
+}
+
+// RefreshTokenTTL returns how long a refresh token stays valid without being used
+func (s *TokenService) RefreshTokenTTL() time.Duration {
+	return s.refreshTTL
+}
+
+// IssueRefreshToken starts a new token family for a login
+func (s *TokenService) IssueRefreshToken(ctx context.Context, audience, subject string) (string, error) {
+	familyID := uuid.NewString()
+	token, hash, err := newRefreshToken(familyID)
+	if err != nil {
+		return "", err
+	}
+
+	key := s.familyKey(audience, familyID)
+	for _, resp := range s.client.DoMulti(ctx,
+		s.client.B().Hset().Key(key).FieldValue().FieldValue("subject", subject).FieldValue("current", hash).
+			FieldValue("issued_at", strconv.FormatInt(time.Now().Unix(), 10)).Build(),
+		s.client.B().Expire().Key(key).Seconds(int64(s.refreshTTL.Seconds())).Build(),
+	) {
+		if err := resp.Error(); err != nil {
+			return "", fmt.Errorf("failed to store refresh token in valkey: %w", err)
+		}
+	}
+	return token, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one of the same family and returns the
+// session it belongs to. Reusing a rotated token revokes the family.
+func (s *TokenService) RotateRefreshToken(ctx context.Context, audience, refreshToken string) (*RefreshSession, string, error) {
+	familyID, hash, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, "", ErrInvalidRefreshToken
+	}
+	newToken, newHash, err := newRefreshToken(familyID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := rotateRefreshTokenScript.Exec(ctx, s.client,
+		[]string{s.familyKey(audience, familyID), s.usedKey(audience, familyID)},
+		[]string{hash, newHash, fmt.Sprint(int64(s.refreshTTL.Seconds()))},
+	).AsStrSlice()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	switch result[0] {
+	case "rotated":
+		session := &RefreshSession{Subject: result[1]}
+		if issuedAt, err := strconv.ParseInt(result[2], 10, 64); err == nil {
+			session.IssuedAt = time.Unix(issuedAt, 0)
+		}
+		return session, newToken, nil
+	case "reused":
+		log.Printf("[Auth] WARNING: refresh token reuse detected for %s %s, session %s revoked", audience, result[1], familyID)
+		return nil, "", ErrRefreshTokenReused
+	default:
+		return nil, "", ErrInvalidRefreshToken
+	}
+}
+
+// RevokeRefreshToken ends the token family of a refresh token issued to the subject
+func (s *TokenService) RevokeRefreshToken(ctx context.Context, audience, subject, refreshToken string) error {
+	familyID, _, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return ErrInvalidRefreshToken
+	}
+
+	key := s.familyKey(audience, familyID)
+	owner, err := s.client.Do(ctx, s.client.B().Hget().Key(key).Field("subject").Build()).ToString()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get refresh token from valkey: %w", err)
+	}
+	if owner != subject {
+		return ErrInvalidRefreshToken
+	}
+
+	if err := s.client.Do(ctx, s.client.B().Del().Key(key, s.usedKey(audience, familyID)).Build()).Error(); err != nil {
+		return fmt.Errorf("failed to delete refresh token from valkey: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken denylists an access token until it expires
+func (s *TokenService) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if tokenID == "" || ttl <= 0 {
+		return nil
+	}
+
+	cmd := s.client.B().Set().Key(s.denylistKey(tokenID)).Value("1").Ex(ttl.Round(time.Second) + time.Second).Build()
+	if err := s.client.Do(ctx, cmd).Error(); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether an access token is on the denylist
+func (s *TokenService) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if tokenID == "" {
+		return false, nil
+	}
+
+	count, err := s.client.Do(ctx, s.client.B().Exists().Key(s.denylistKey(tokenID)).Build()).AsInt64()
+	if err != nil {
+		return false, fmt.Errorf("failed to check access token denylist: %w", err)
+	}
+	return count > 0, nil
+}
+
+// newRefreshToken returns a refresh token of a family and the hash stored for it
+func newRefreshToken(familyID string) (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	return familyID + "." + encoded, hashRefreshSecret(encoded), nil
+}
+
+// parseRefreshToken splits a refresh token into its family ID and the hash of its secret
+func parseRefreshToken(token string) (string, string, bool) {
+	familyID, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return "", "", false
+	}
+	if _, err := uuid.Parse(familyID); err != nil {
+		return "", "", false
+	}
+	return familyID, hashRefreshSecret(secret), true
+}
+
+func hashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,241 @@
+// Package siwe parses, formats and checks Sign-In with Ethereum messages (EIP-4361).
+package siwe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Version is the only message version defined by EIP-4361
+const Version = "1"
+
+// clockSkew is how far in the future an issued-at time may be
+const clockSkew = time.Minute
+
+const headerSuffix = " wants you to sign in with your Ethereum account:"
+
+var (
+	ErrMalformedMessage = errors.New("siwe: malformed message")
+	ErrAddressMismatch  = errors.New("siwe: address does not match")
+	ErrChainMismatch    = errors.New("siwe: chain id does not match")
+	ErrNonceMismatch    = errors.New("siwe: nonce does not match")
+	ErrExpired          = errors.New("siwe: message has expired")
+	ErrNotYetValid      = errors.New("siwe: message is not valid yet")
+)
+
+// Message is a Sign-In with Ethereum message
+type Message struct {
+	Domain         string // host[:port] of the site asking for the signature
+	Address        string
+	Statement      string // Optional, shown to the user by the wallet
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// String formats the message as it is signed
+func (m *Message) String() string {
+	var b strings.Builder
+	b.WriteString(m.Domain + headerSuffix + "\n")
+	b.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n\n")
+	}
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + m.Version + "\n")
+	b.WriteString("Chain ID: " + strconv.FormatInt(m.ChainID, 10) + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\nNot Before: " + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// Parse parses a message in the EIP-4361 format
+func Parse(text string) (*Message, error) {
+	p := &parser{lines: strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")}
+
+	header := p.next()
+	domain, ok := strings.CutSuffix(header, headerSuffix)
+	if !ok || domain == "" || strings.ContainsAny(domain, " /") {
+		return nil, fmt.Errorf("%w: invalid header", ErrMalformedMessage)
+	}
+	m := &Message{Domain: domain}
+
+	m.Address = p.next()
+	if !common.IsHexAddress(m.Address) {
+		return nil, fmt.Errorf("%w: invalid address", ErrMalformedMessage)
+	}
+	if p.next() != "" {
+		return nil, fmt.Errorf("%w: missing blank line after address", ErrMalformedMessage)
+	}
+	if !strings.HasPrefix(p.peek(), "URI: ") {
+		m.Statement = p.next()
+		if p.next() != "" {
+			return nil, fmt.Errorf("%w: missing blank line after statement", ErrMalformedMessage)
+		}
+	}
+
+	var err error
+	if m.URI, err = p.field("URI", true); err != nil {
+		return nil, err
+	}
+	if m.Version, err = p.field("Version", true); err != nil {
+		return nil, err
+	}
+	if m.Version != Version {
+		return nil, fmt.Errorf("%w: unsupported version %s", ErrMalformedMessage, m.Version)
+	}
+	chainID, err := p.field("Chain ID", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil {
+		return nil, fmt.Errorf("%w: invalid chain id", ErrMalformedMessage)
+	}
+	if m.Nonce, err = p.field("Nonce", true); err != nil {
+		return nil, err
+	}
+	if len(m.Nonce) < 8 || strings.ContainsFunc(m.Nonce, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) {
+		return nil, fmt.Errorf("%w: nonce must be at least 8 alphanumeric characters", ErrMalformedMessage)
+	}
+	if m.IssuedAt, err = p.time("Issued At", true); err != nil {
+		return nil, err
+	}
+
+	expirationTime, err := p.time("Expiration Time", false)
+	if err != nil {
+		return nil, err
+	}
+	if !expirationTime.IsZero() {
+		m.ExpirationTime = &expirationTime
+	}
+	notBefore, err := p.time("Not Before", false)
+	if err != nil {
+		return nil, err
+	}
+	if !notBefore.IsZero() {
+		m.NotBefore = &notBefore
+	}
+	if m.RequestID, err = p.field("Request ID", false); err != nil {
+		return nil, err
+	}
+	if p.peek() == "Resources:" {
+		p.next()
+		for strings.HasPrefix(p.peek(), "- ") {
+			m.Resources = append(m.Resources, strings.TrimPrefix(p.next(), "- "))
+		}
+	}
+
+	if !p.done() {
+		return nil, fmt.Errorf("%w: unexpected line %q", ErrMalformedMessage, p.peek())
+	}
+	return m, nil
+}
+
+// VerifyOptions are the values a message must match
+type VerifyOptions struct {
+	Address string
+	ChainID int64
+	Nonce   string
+	Now     time.Time
+}
+
+// Verify checks the address, chain, nonce and validity period of the message. The domain and URI
+// are checked by the caller against the sites it serves.
+func (m *Message) Verify(opts VerifyOptions) error {
+	if !strings.EqualFold(m.Address, opts.Address) {
+		return ErrAddressMismatch
+	}
+	if m.ChainID != opts.ChainID {
+		return ErrChainMismatch
+	}
+	if m.Nonce != opts.Nonce {
+		return ErrNonceMismatch
+	}
+	if m.IssuedAt.After(opts.Now.Add(clockSkew)) {
+		return ErrNotYetValid
+	}
+	if m.NotBefore != nil && opts.Now.Before(*m.NotBefore) {
+		return ErrNotYetValid
+	}
+	if m.ExpirationTime != nil && !opts.Now.Before(*m.ExpirationTime) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// parser reads the lines of a message in order
+type parser struct {
+	lines []string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.lines)
+}
+
+func (p *parser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.lines[p.pos]
+}
+
+func (p *parser) next() string {
+	line := p.peek()
+	p.pos++
+	return line
+}
+
+// field reads a "Name: value" line. Optional fields that are absent return an empty value.
+func (p *parser) field(name string, required bool) (string, error) {
+	value, ok := strings.CutPrefix(p.peek(), name+": ")
+	if !ok || value == "" {
+		if required {
+			return "", fmt.Errorf("%w: missing %s", ErrMalformedMessage, name)
+		}
+		return "", nil
+	}
+	p.next()
+	return value, nil
+}
+
+// time reads an RFC 3339 time field
+func (p *parser) time(name string, required bool) (time.Time, error) {
+	value, err := p.field(name, required)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %s", ErrMalformedMessage, name)
+	}
+	return t, nil
+}
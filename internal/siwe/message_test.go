@@ -0,0 +1,106 @@
+package siwe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exampleMessage = `app.ownafarm.com wants you to sign in with your Ethereum account:
+0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0
+
+Sign in to OwnaFarm.
+
+URI: https://app.ownafarm.com
+Version: 1
+Chain ID: 5000
+Nonce: a1b2c3d4e5f67890
+Issued At: 2026-10-18T09:00:00Z
+Expiration Time: 2026-10-18T09:05:00Z
+Resources:
+- https://app.ownafarm.com/terms`
+
+func TestParse(t *testing.T) {
+	m, err := Parse(exampleMessage)
+	require.NoError(t, err)
+
+	assert.Equal(t, "app.ownafarm.com", m.Domain)
+	assert.Equal(t, "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0", m.Address)
+	assert.Equal(t, "Sign in to OwnaFarm.", m.Statement)
+	assert.Equal(t, "https://app.ownafarm.com", m.URI)
+	assert.Equal(t, int64(5000), m.ChainID)
+	assert.Equal(t, "a1b2c3d4e5f67890", m.Nonce)
+	assert.Equal(t, time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC), m.IssuedAt)
+	require.NotNil(t, m.ExpirationTime)
+	assert.Nil(t, m.NotBefore)
+	assert.Equal(t, []string{"https://app.ownafarm.com/terms"}, m.Resources)
+
+	// Formatting gives back the signed text
+	assert.Equal(t, exampleMessage, m.String())
+}
+
+func TestParse_WithoutStatement(t *testing.T) {
+	m := &Message{
+		Domain:   "localhost:3000",
+		Address:  "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0",
+		URI:      "http://localhost:3000",
+		Version:  Version,
+		ChainID:  5003,
+		Nonce:    "a1b2c3d4e5f67890",
+		IssuedAt: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+	parsed, err := Parse(m.String())
+	require.NoError(t, err)
+	assert.Equal(t, m, parsed)
+}
+
+func TestParse_Malformed(t *testing.T) {
+	tests := map[string]string{
+		"not siwe":        "Sign this message to login to OwnaFarm.\n\nNonce: a1b2c3d4e5f67890",
+		"bad address":     "app.ownafarm.com wants you to sign in with your Ethereum account:\n0x123\n\nURI: https://app.ownafarm.com\nVersion: 1\nChain ID: 5000\nNonce: a1b2c3d4e5f67890\nIssued At: 2026-10-18T09:00:00Z",
+		"missing nonce":   "app.ownafarm.com wants you to sign in with your Ethereum account:\n0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0\n\nURI: https://app.ownafarm.com\nVersion: 1\nChain ID: 5000\nIssued At: 2026-10-18T09:00:00Z",
+		"short nonce":     "app.ownafarm.com wants you to sign in with your Ethereum account:\n0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0\n\nURI: https://app.ownafarm.com\nVersion: 1\nChain ID: 5000\nNonce: abc\nIssued At: 2026-10-18T09:00:00Z",
+		"wrong version":   "app.ownafarm.com wants you to sign in with your Ethereum account:\n0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0\n\nURI: https://app.ownafarm.com\nVersion: 2\nChain ID: 5000\nNonce: a1b2c3d4e5f67890\nIssued At: 2026-10-18T09:00:00Z",
+		"trailing fields": exampleMessage + "\nExtra: field",
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(text)
+			assert.ErrorIs(t, err, ErrMalformedMessage)
+		})
+	}
+}
+
+func TestMessage_Verify(t *testing.T) {
+	m, err := Parse(exampleMessage)
+	require.NoError(t, err)
+
+	valid := VerifyOptions{
+		Address: "0x742d35cc6634c0532925a3b844bc9e7595f0beb0",
+		ChainID: 5000,
+		Nonce:   "a1b2c3d4e5f67890",
+		Now:     time.Date(2026, 10, 18, 9, 1, 0, 0, time.UTC),
+	}
+	assert.NoError(t, m.Verify(valid))
+
+	tests := []struct {
+		name   string
+		modify func(*VerifyOptions)
+		want   error
+	}{
+		{"other address", func(o *VerifyOptions) { o.Address = "0x1234567890abcdef1234567890abcdef12345678" }, ErrAddressMismatch},
+		{"other chain", func(o *VerifyOptions) { o.ChainID = 1 }, ErrChainMismatch},
+		{"other nonce", func(o *VerifyOptions) { o.Nonce = "0000000000000000" }, ErrNonceMismatch},
+		{"expired", func(o *VerifyOptions) { o.Now = o.Now.Add(10 * time.Minute) }, ErrExpired},
+		{"issued in the future", func(o *VerifyOptions) { o.Now = o.Now.Add(-time.Hour) }, ErrNotYetValid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+			assert.ErrorIs(t, m.Verify(opts), tt.want)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
 )
 
@@ -18,45 +19,48 @@ type AdminClaims struct {
 
 // AdminJWTUtil handles JWT operations for admin users
 type AdminJWTUtil struct {
-	secret          []byte
-	expirationHours int
+	keys *KeySet
+	ttl  time.Duration
 }
 
 // NewAdminJWTUtil creates a new AdminJWTUtil instance
-func NewAdminJWTUtil(cfg *config.JWTConfig) *AdminJWTUtil {
-	return &AdminJWTUtil{
-		secret:          []byte(cfg.Secret),
-		expirationHours: cfg.ExpirationHours,
+func NewAdminJWTUtil(cfg *config.JWTConfig) (*AdminJWTUtil, error) {
+	keys, err := NewKeySet(AudienceAdmin, &cfg.Admin, time.Duration(cfg.KeyGraceMinutes)*time.Minute)
+	if err != nil {
+		return nil, err
 	}
+	return &AdminJWTUtil{
+		keys: keys,
+		ttl:  time.Duration(cfg.AccessTokenTTLMinutes) * time.Minute,
+	}, nil
+}
+
+// KeySet returns the signing keys of admin tokens
+func (j *AdminJWTUtil) KeySet() *KeySet {
+	return j.keys
+}
+
+// TTL returns the lifetime of the access tokens
+func (j *AdminJWTUtil) TTL() time.Duration {
+	return j.ttl
 }
 
 // GenerateToken generates a JWT token for an admin user
 func (j *AdminJWTUtil) GenerateToken(adminID, walletAddress, role string) (string, error) {
 	now := time.Now()
 	claims := AdminClaims{
-		AdminID:       adminID,
-		WalletAddress: walletAddress,
-		Role:          role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(j.expirationHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    "ownafarm-admin",
-		},
+		AdminID:          adminID,
+		WalletAddress:    walletAddress,
+		Role:             role,
+		RegisteredClaims: j.keys.registeredClaims(uuid.NewString(), now, j.ttl),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+	return j.keys.sign(claims)
 }
 
 // ValidateToken validates and parses an admin JWT token
 func (j *AdminJWTUtil) ValidateToken(tokenString string) (*AdminClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AdminClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return j.secret, nil
-	})
+	token, err := j.keys.parse(tokenString, &AdminClaims{})
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -70,10 +74,5 @@ func (j *AdminJWTUtil) ValidateToken(tokenString string) (*AdminClaims, error) {
 		return nil, ErrInvalidToken
 	}
 
-	// Verify issuer for admin tokens
-	if claims.Issuer != "ownafarm-admin" {
-		return nil, ErrInvalidToken
-	}
-
 	return claims, nil
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
 )
 
@@ -13,52 +14,78 @@ var (
 	ErrExpiredFarmerToken = errors.New("farmer token has expired")
 )
 
+// Farmer token scopes
+const (
+	// FarmerScopeFull grants access to every farmer endpoint (approved farmers)
+	FarmerScopeFull = "full"
+	// FarmerScopeApplication only grants access to the farmer's own registration
+	// (status, rejection reason, resubmission) for farmers that are not approved
+	FarmerScopeApplication = "application"
+)
+
 // FarmerClaims represents JWT claims for farmer authentication
 type FarmerClaims struct {
 	FarmerID      string `json:"farmer_id"`
 	WalletAddress string `json:"wallet_address"`
+	Scope         string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// HasFullScope reports whether the token grants full farmer access.
+// Tokens issued before scopes existed carry no scope and were only given to approved farmers.
+func (c *FarmerClaims) HasFullScope() bool {
+	return c.Scope == "" || c.Scope == FarmerScopeFull
+}
+
 // FarmerJWTUtil handles JWT operations for farmer authentication
 type FarmerJWTUtil struct {
-	secret          []byte
-	expirationHours int
+	keys *KeySet
+	ttl  time.Duration
 }
 
 // NewFarmerJWTUtil creates a new FarmerJWTUtil instance
-func NewFarmerJWTUtil(cfg *config.JWTConfig) *FarmerJWTUtil {
-	return &FarmerJWTUtil{
-		secret:          []byte(cfg.Secret),
-		expirationHours: cfg.ExpirationHours,
+func NewFarmerJWTUtil(cfg *config.JWTConfig) (*FarmerJWTUtil, error) {
+	keys, err := NewKeySet(AudienceFarmer, &cfg.Farmer, time.Duration(cfg.KeyGraceMinutes)*time.Minute)
+	if err != nil {
+		return nil, err
 	}
+	return &FarmerJWTUtil{
+		keys: keys,
+		ttl:  time.Duration(cfg.AccessTokenTTLMinutes) * time.Minute,
+	}, nil
 }
 
-// GenerateToken creates a new JWT token for a farmer
+// KeySet returns the signing keys of farmer tokens
+func (j *FarmerJWTUtil) KeySet() *KeySet {
+	return j.keys
+}
+
+// TTL returns the lifetime of the access tokens
+func (j *FarmerJWTUtil) TTL() time.Duration {
+	return j.ttl
+}
+
+// GenerateToken creates a new full scope JWT token for a farmer
 func (j *FarmerJWTUtil) GenerateToken(farmerID, walletAddress string) (string, error) {
+	return j.GenerateScopedToken(farmerID, walletAddress, FarmerScopeFull)
+}
+
+// GenerateScopedToken creates a new JWT token for a farmer limited to the given scope
+func (j *FarmerJWTUtil) GenerateScopedToken(farmerID, walletAddress, scope string) (string, error) {
 	now := time.Now()
 	claims := FarmerClaims{
-		FarmerID:      farmerID,
-		WalletAddress: walletAddress,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(j.expirationHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-		},
+		FarmerID:         farmerID,
+		WalletAddress:    walletAddress,
+		Scope:            scope,
+		RegisteredClaims: j.keys.registeredClaims(uuid.NewString(), now, j.ttl),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+	return j.keys.sign(claims)
 }
 
 // ValidateToken validates a JWT token and returns the farmer claims
 func (j *FarmerJWTUtil) ValidateToken(tokenString string) (*FarmerClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &FarmerClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidFarmerToken
-		}
-		return j.secret, nil
-	})
+	token, err := j.keys.parse(tokenString, &FarmerClaims{})
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/ownafarm/ownafarm-backend/internal/config"
 )
 
@@ -20,40 +21,44 @@ type Claims struct {
 }
 
 type JWTUtil struct {
-	secret          []byte
-	expirationHours int
+	keys *KeySet
+	ttl  time.Duration
 }
 
-func NewJWTUtil(cfg *config.JWTConfig) *JWTUtil {
-	return &JWTUtil{
-		secret:          []byte(cfg.Secret),
-		expirationHours: cfg.ExpirationHours,
+func NewJWTUtil(cfg *config.JWTConfig) (*JWTUtil, error) {
+	keys, err := NewKeySet(AudienceInvestor, &cfg.Investor, time.Duration(cfg.KeyGraceMinutes)*time.Minute)
+	if err != nil {
+		return nil, err
 	}
+	return &JWTUtil{
+		keys: keys,
+		ttl:  time.Duration(cfg.AccessTokenTTLMinutes) * time.Minute,
+	}, nil
+}
+
+// KeySet returns the signing keys of investor tokens
+func (j *JWTUtil) KeySet() *KeySet {
+	return j.keys
+}
+
+// TTL returns the lifetime of the access tokens
+func (j *JWTUtil) TTL() time.Duration {
+	return j.ttl
 }
 
 func (j *JWTUtil) GenerateToken(userID, walletAddress string) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserID:        userID,
-		WalletAddress: walletAddress,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(j.expirationHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-		},
+		UserID:           userID,
+		WalletAddress:    walletAddress,
+		RegisteredClaims: j.keys.registeredClaims(uuid.NewString(), now, j.ttl),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+	return j.keys.sign(claims)
 }
 
 func (j *JWTUtil) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return j.secret, nil
-	})
+	token, err := j.keys.parse(tokenString, &Claims{})
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
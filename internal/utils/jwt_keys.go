@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+)
+
+// Token audiences. Each audience signs with its own key set, so a leaked key only affects
+// the sessions of that audience.
+const (
+	AudienceInvestor = "investor"
+	AudienceFarmer   = "farmer"
+	AudienceAdmin    = "admin"
+	AudienceSession  = "session"
+)
+
+// TokenIssuer is the iss claim of every OwnaFarm token
+const TokenIssuer = "ownafarm"
+
+// signingKey is an Ed25519 key of a key set
+type signingKey struct {
+	id         string // kid header, "<audience>-<config id>" so ids are unique across the JWKS
+	privateKey ed25519.PrivateKey
+	retiredAt  *time.Time
+}
+
+// KeySet holds the Ed25519 signing keys of one token audience. The active key signs new tokens;
+// retired keys keep verifying the tokens they signed before retirement until the grace period ends.
+type KeySet struct {
+	audience string
+	active   *signingKey
+	keys     map[string]*signingKey
+	grace    time.Duration
+}
+
+// NewKeySet builds the key set of an audience from config. Keys are listed as id:base64 pairs of
+// 32 byte Ed25519 seeds, retired keys as id:base64:<RFC 3339 retirement time>.
+func NewKeySet(audience string, cfg *config.JWTKeyConfig, grace time.Duration) (*KeySet, error) {
+	if cfg.ActiveKeyID == "" {
+		return nil, fmt.Errorf("jwt: %s active key id is required", audience)
+	}
+
+	keys := make(map[string]*signingKey, len(cfg.Keys))
+	for _, entry := range cfg.Keys {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("jwt: %s key entry %q must be id:base64", audience, parts[0])
+		}
+		seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("jwt: %s key %s must be %d bytes of base64", audience, parts[0], ed25519.SeedSize)
+		}
+
+		key := &signingKey{
+			id:         audience + "-" + parts[0],
+			privateKey: ed25519.NewKeyFromSeed(seed),
+		}
+		if len(parts) == 3 {
+			retiredAt, err := time.Parse(time.RFC3339, parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("jwt: %s key %s retirement time must be RFC 3339", audience, parts[0])
+			}
+			key.retiredAt = &retiredAt
+		}
+		keys[key.id] = key
+	}
+
+	active, ok := keys[audience+"-"+cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("jwt: %s active key %s is not listed in the keys", audience, cfg.ActiveKeyID)
+	}
+	if active.retiredAt != nil {
+		return nil, fmt.Errorf("jwt: %s active key %s is retired", audience, cfg.ActiveKeyID)
+	}
+
+	return &KeySet{audience: audience, active: active, keys: keys, grace: grace}, nil
+}
+
+// GenerateSigningKey returns a new base64 Ed25519 seed for the key config
+func GenerateSigningKey() (string, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return "", fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(seed), nil
+}
+
+// Audience returns the audience the key set signs tokens for
+func (k *KeySet) Audience() string {
+	return k.audience
+}
+
+// sign signs the claims with the active key
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.active.id
+	return token.SignedString(k.active.privateKey)
+}
+
+// parse verifies a token of the audience and fills the claims. Tokens without a known kid,
+// for another audience, or signed by a retired key outside of its grace period are rejected.
+func (k *KeySet) parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	var key *signingKey
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key = k.keys[kid]
+		if key == nil {
+			return nil, ErrInvalidToken
+		}
+		return key.privateKey.Public(), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithAudience(k.audience),
+		jwt.WithIssuer(TokenIssuer),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.retiredAt != nil {
+		issuedAt, err := claims.GetIssuedAt()
+		if err != nil || issuedAt == nil || issuedAt.After(*key.retiredAt) || !k.inGrace(key) {
+			return nil, ErrInvalidToken
+		}
+	}
+	return token, nil
+}
+
+// registeredClaims returns the standard claims of a new token
+func (k *KeySet) registeredClaims(id string, now time.Time, ttl time.Duration) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		ID:        id,
+		Issuer:    TokenIssuer,
+		Audience:  jwt.ClaimStrings{k.audience},
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+}
+
+func (k *KeySet) inGrace(key *signingKey) bool {
+	return key.retiredAt == nil || time.Now().Before(key.retiredAt.Add(k.grace))
+}
+
+// JWK is the public part of a signing key as a JSON Web Key (RFC 8037)
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+// PublicKeys returns the keys that currently verify tokens of the audience, sorted by kid
+func (k *KeySet) PublicKeys() []JWK {
+	jwks := make([]JWK, 0, len(k.keys))
+	for _, key := range k.keys {
+		if !k.inGrace(key) {
+			continue
+		}
+		jwks = append(jwks, JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key.privateKey.Public().(ed25519.PublicKey)),
+			Kid: key.id,
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Use: "sig",
+		})
+	}
+	sort.Slice(jwks, func(i, j int) bool { return jwks[i].Kid < jwks[j].Kid })
+	return jwks
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKey, err := GenerateSigningKey()
+	require.NoError(t, err)
+	newKey, err := GenerateSigningKey()
+	require.NoError(t, err)
+
+	before, err := NewKeySet(AudienceInvestor, &config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:" + oldKey}}, time.Hour)
+	require.NoError(t, err)
+
+	now := time.Now()
+	fresh, err := before.sign(&Claims{UserID: "user-id", RegisteredClaims: before.registeredClaims(uuid.NewString(), now, 15*time.Minute)})
+	require.NoError(t, err)
+	// Issued long ago but not expired, to outlive the grace period
+	longLived, err := before.sign(&Claims{UserID: "user-id", RegisteredClaims: before.registeredClaims(uuid.NewString(), now.Add(-3*time.Hour), 4*time.Hour)})
+	require.NoError(t, err)
+
+	rotated := func(retiredAt time.Time) *KeySet {
+		keys, err := NewKeySet(AudienceInvestor, &config.JWTKeyConfig{
+			ActiveKeyID: "2",
+			Keys:        []string{"1:" + oldKey + ":" + retiredAt.UTC().Format(time.RFC3339), "2:" + newKey},
+		}, time.Hour)
+		require.NoError(t, err)
+		return keys
+	}
+
+	t.Run("retired key verifies its tokens during the grace period", func(t *testing.T) {
+		keys := rotated(now.Add(time.Second))
+		_, err := keys.parse(fresh, &Claims{})
+		assert.NoError(t, err)
+		assert.Len(t, keys.PublicKeys(), 2)
+
+		token, err := keys.sign(&Claims{UserID: "user-id", RegisteredClaims: keys.registeredClaims(uuid.NewString(), now, 15*time.Minute)})
+		require.NoError(t, err)
+		_, err = keys.parse(token, &Claims{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("retired key is rejected after the grace period", func(t *testing.T) {
+		keys := rotated(now.Add(-2 * time.Hour))
+		_, err := keys.parse(longLived, &Claims{})
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		require.Len(t, keys.PublicKeys(), 1)
+		assert.Equal(t, "investor-2", keys.PublicKeys()[0].Kid)
+	})
+
+	t.Run("retired key cannot sign new tokens", func(t *testing.T) {
+		keys := rotated(now.Add(-time.Minute))
+		_, err := keys.parse(fresh, &Claims{})
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestKeySet_RejectsOtherAudience(t *testing.T) {
+	seed, err := GenerateSigningKey()
+	require.NoError(t, err)
+	cfg := &config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:" + seed}}
+
+	// Even with the same key material, an admin token is not an investor token
+	adminKeys, err := NewKeySet(AudienceAdmin, cfg, time.Hour)
+	require.NoError(t, err)
+	investorKeys, err := NewKeySet(AudienceInvestor, cfg, time.Hour)
+	require.NoError(t, err)
+
+	token, err := adminKeys.sign(&AdminClaims{AdminID: "admin-id", RegisteredClaims: adminKeys.registeredClaims(uuid.NewString(), time.Now(), time.Minute)})
+	require.NoError(t, err)
+
+	_, err = investorKeys.parse(token, &Claims{})
+	assert.Error(t, err)
+	_, err = adminKeys.parse(token, &AdminClaims{})
+	assert.NoError(t, err)
+}
+
+func TestNewKeySet_InvalidConfig(t *testing.T) {
+	seed, err := GenerateSigningKey()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		cfg  config.JWTKeyConfig
+	}{
+		{name: "no active key", cfg: config.JWTKeyConfig{Keys: []string{"1:" + seed}}},
+		{name: "active key not listed", cfg: config.JWTKeyConfig{ActiveKeyID: "2", Keys: []string{"1:" + seed}}},
+		{name: "short key", cfg: config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:c2hvcnQ="}}},
+		{name: "retired active key", cfg: config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:" + seed + ":2026-01-01T00:00:00Z"}}},
+		{name: "bad retirement time", cfg: config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:" + seed, "2:" + seed + ":yesterday"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeySet(AudienceFarmer, &tt.cfg, time.Hour)
+			assert.Error(t, err)
+		})
+	}
+}
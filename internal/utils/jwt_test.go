@@ -9,12 +9,27 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// testKeyConfig returns a key config with one new active key
+func testKeyConfig(t *testing.T) config.JWTKeyConfig {
+	t.Helper()
+	seed, err := GenerateSigningKey()
+	require.NoError(t, err)
+	return config.JWTKeyConfig{ActiveKeyID: "1", Keys: []string{"1:" + seed}}
+}
+
+func newTestJWTUtil(t *testing.T, ttlMinutes int) *JWTUtil {
+	t.Helper()
+	jwtUtil, err := NewJWTUtil(&config.JWTConfig{
+		Investor:              testKeyConfig(t),
+		KeyGraceMinutes:       60,
+		AccessTokenTTLMinutes: ttlMinutes,
+	})
+	require.NoError(t, err)
+	return jwtUtil
+}
+
 func TestJWTUtil_GenerateAndValidateToken(t *testing.T) {
-	cfg := &config.JWTConfig{
-		Secret:          "test-secret-key-for-jwt-testing-32chars",
-		ExpirationHours: 24,
-	}
-	jwtUtil := NewJWTUtil(cfg)
+	jwtUtil := newTestJWTUtil(t, 15)
 
 	userID := "test-user-id-123"
 	walletAddress := "0x1234567890abcdef1234567890abcdef12345678"
@@ -32,45 +47,29 @@ func TestJWTUtil_GenerateAndValidateToken(t *testing.T) {
 }
 
 func TestJWTUtil_ValidateToken_InvalidToken(t *testing.T) {
-	cfg := &config.JWTConfig{
-		Secret:          "test-secret-key-for-jwt-testing-32chars",
-		ExpirationHours: 24,
-	}
-	jwtUtil := NewJWTUtil(cfg)
+	jwtUtil := newTestJWTUtil(t, 15)
 
 	// Test with invalid token
 	_, err := jwtUtil.ValidateToken("invalid-token")
 	assert.ErrorIs(t, err, ErrInvalidToken)
 }
 
-func TestJWTUtil_ValidateToken_WrongSecret(t *testing.T) {
-	cfg1 := &config.JWTConfig{
-		Secret:          "secret-key-one-for-jwt-testing-32chars",
-		ExpirationHours: 24,
-	}
-	cfg2 := &config.JWTConfig{
-		Secret:          "secret-key-two-for-jwt-testing-32chars",
-		ExpirationHours: 24,
-	}
-
-	jwtUtil1 := NewJWTUtil(cfg1)
-	jwtUtil2 := NewJWTUtil(cfg2)
+func TestJWTUtil_ValidateToken_WrongKey(t *testing.T) {
+	// Both key sets use key id 1 with different keys
+	jwtUtil1 := newTestJWTUtil(t, 15)
+	jwtUtil2 := newTestJWTUtil(t, 15)
 
-	// Generate token with first secret
+	// Generate token with first key
 	token, err := jwtUtil1.GenerateToken("user-id", "0x1234")
 	require.NoError(t, err)
 
-	// Try to validate with different secret
+	// Try to validate with different key
 	_, err = jwtUtil2.ValidateToken(token)
 	assert.ErrorIs(t, err, ErrInvalidToken)
 }
 
 func TestJWTUtil_ValidateToken_ExpiredToken(t *testing.T) {
-	cfg := &config.JWTConfig{
-		Secret:          "test-secret-key-for-jwt-testing-32chars",
-		ExpirationHours: 0, // 0 hours = expires immediately
-	}
-	jwtUtil := NewJWTUtil(cfg)
+	jwtUtil := newTestJWTUtil(t, 0) // expires immediately
 
 	// Generate a token that expires immediately
 	token, err := jwtUtil.GenerateToken("user-id", "0x1234")
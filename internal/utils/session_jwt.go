@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+)
+
+// Roles a wallet can hold in a session token
+const (
+	RoleInvestor = "investor"
+	RoleFarmer   = "farmer"
+	RoleAdmin    = "admin"
+)
+
+// SessionClaims represents JWT claims of a wallet session. The token carries every role the
+// wallet holds, with the account ID of each role.
+type SessionClaims struct {
+	WalletAddress string   `json:"wallet_address"`
+	Roles         []string `json:"roles"`
+	UserID        string   `json:"user_id,omitempty"`
+	FarmerID      string   `json:"farmer_id,omitempty"`
+	FarmerScope   string   `json:"farmer_scope,omitempty"` // FarmerScopeFull or FarmerScopeApplication
+	AdminID       string   `json:"admin_id,omitempty"`
+	AdminRole     string   `json:"admin_role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// HasRole reports whether the session grants the role
+func (c *SessionClaims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// SessionJWTUtil handles JWT operations for wallet sessions
+type SessionJWTUtil struct {
+	keys *KeySet
+	ttl  time.Duration
+}
+
+// NewSessionJWTUtil creates a new SessionJWTUtil instance
+func NewSessionJWTUtil(cfg *config.JWTConfig) (*SessionJWTUtil, error) {
+	keys, err := NewKeySet(AudienceSession, &cfg.Session, time.Duration(cfg.KeyGraceMinutes)*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionJWTUtil{
+		keys: keys,
+		ttl:  time.Duration(cfg.AccessTokenTTLMinutes) * time.Minute,
+	}, nil
+}
+
+// KeySet returns the signing keys of session tokens
+func (j *SessionJWTUtil) KeySet() *KeySet {
+	return j.keys
+}
+
+// TTL returns the lifetime of the access tokens
+func (j *SessionJWTUtil) TTL() time.Duration {
+	return j.ttl
+}
+
+// GenerateToken creates a session token with the wallet, roles and account IDs of the claims.
+// The registered claims are filled in, with the wallet address as subject.
+func (j *SessionJWTUtil) GenerateToken(claims SessionClaims) (string, error) {
+	claims.RegisteredClaims = j.keys.registeredClaims(uuid.NewString(), time.Now(), j.ttl)
+	claims.Subject = claims.WalletAddress
+
+	return j.keys.sign(claims)
+}
+
+// ValidateToken validates a session token and returns its claims
+func (j *SessionJWTUtil) ValidateToken(tokenString string) (*SessionClaims, error) {
+	token, err := j.keys.parse(tokenString, &SessionClaims{})
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*SessionClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ownafarm/ownafarm-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionJWTUtil_GenerateAndValidateToken(t *testing.T) {
+	sessionJwtUtil, err := NewSessionJWTUtil(&config.JWTConfig{
+		Session:               testKeyConfig(t),
+		KeyGraceMinutes:       60,
+		AccessTokenTTLMinutes: 15,
+	})
+	require.NoError(t, err)
+
+	walletAddress := "0x1234567890AbcdEF1234567890aBcdef12345678"
+	token, err := sessionJwtUtil.GenerateToken(SessionClaims{
+		WalletAddress: walletAddress,
+		Roles:         []string{RoleInvestor, RoleFarmer},
+		UserID:        "user-uuid-123",
+		FarmerID:      "farmer-uuid-456",
+		FarmerScope:   FarmerScopeApplication,
+	})
+	require.NoError(t, err)
+
+	claims, err := sessionJwtUtil.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, walletAddress, claims.Subject)
+	assert.Equal(t, "user-uuid-123", claims.UserID)
+	assert.Equal(t, "farmer-uuid-456", claims.FarmerID)
+	assert.Equal(t, FarmerScopeApplication, claims.FarmerScope)
+	assert.True(t, claims.HasRole(RoleInvestor))
+	assert.True(t, claims.HasRole(RoleFarmer))
+	assert.False(t, claims.HasRole(RoleAdmin))
+
+	// Per-audience tokens are not session tokens
+	investorToken, err := newTestJWTUtil(t, 15).GenerateToken("user-uuid-123", walletAddress)
+	require.NoError(t, err)
+	_, err = sessionJwtUtil.ValidateToken(investorToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,223 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Gender as encoded in a NIK
+type Gender string
+
+const (
+	GenderMale   Gender = "male"
+	GenderFemale Gender = "female"
+)
+
+var (
+	digitsRegex    = regexp.MustCompile(`^[0-9]+$`)
+	npwpSeparators = strings.NewReplacer(".", "", "-", "", " ", "")
+	phoneFormatter = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+)
+
+// province holds the Dukcapil region code used in NIKs and the leading two digits of its postal codes
+type province struct {
+	Code           string
+	PostalPrefixes []string
+}
+
+// provinces maps normalized province names to their NIK region code and postal code prefixes.
+// Postal prefixes follow the Pos Indonesia zones; provinces split off later share their parent's prefixes.
+var provinces = map[string]province{
+	"aceh":                      {Code: "11", PostalPrefixes: []string{"23", "24"}},
+	"sumatera utara":            {Code: "12", PostalPrefixes: []string{"20", "21", "22"}},
+	"sumatera barat":            {Code: "13", PostalPrefixes: []string{"25", "26", "27"}},
+	"riau":                      {Code: "14", PostalPrefixes: []string{"28", "29"}},
+	"jambi":                     {Code: "15", PostalPrefixes: []string{"36", "37"}},
+	"sumatera selatan":          {Code: "16", PostalPrefixes: []string{"30", "31", "32"}},
+	"bengkulu":                  {Code: "17", PostalPrefixes: []string{"38", "39"}},
+	"lampung":                   {Code: "18", PostalPrefixes: []string{"34", "35"}},
+	"kepulauan bangka belitung": {Code: "19", PostalPrefixes: []string{"33"}},
+	"kepulauan riau":            {Code: "21", PostalPrefixes: []string{"29"}},
+	"dki jakarta":               {Code: "31", PostalPrefixes: []string{"10", "11", "12", "13", "14"}},
+	"jawa barat":                {Code: "32", PostalPrefixes: []string{"16", "17", "40", "41", "43", "44", "45", "46"}},
+	"jawa tengah":               {Code: "33", PostalPrefixes: []string{"50", "51", "52", "53", "54", "56", "57", "58", "59"}},
+	"di yogyakarta":             {Code: "34", PostalPrefixes: []string{"55"}},
+	"jawa timur":                {Code: "35", PostalPrefixes: []string{"60", "61", "62", "63", "64", "65", "66", "67", "68", "69"}},
+	"banten":                    {Code: "36", PostalPrefixes: []string{"15", "42"}},
+	"bali":                      {Code: "51", PostalPrefixes: []string{"80", "81", "82"}},
+	"nusa tenggara barat":       {Code: "52", PostalPrefixes: []string{"83", "84"}},
+	"nusa tenggara timur":       {Code: "53", PostalPrefixes: []string{"85", "86", "87"}},
+	"kalimantan barat":          {Code: "61", PostalPrefixes: []string{"78", "79"}},
+	"kalimantan tengah":         {Code: "62", PostalPrefixes: []string{"73", "74"}},
+	"kalimantan selatan":        {Code: "63", PostalPrefixes: []string{"70", "71", "72"}},
+	"kalimantan timur":          {Code: "64", PostalPrefixes: []string{"75", "76", "77"}},
+	"kalimantan utara":          {Code: "65", PostalPrefixes: []string{"77"}},
+	"sulawesi utara":            {Code: "71", PostalPrefixes: []string{"95"}},
+	"sulawesi tengah":           {Code: "72", PostalPrefixes: []string{"94"}},
+	"sulawesi selatan":          {Code: "73", PostalPrefixes: []string{"90", "91", "92"}},
+	"sulawesi tenggara":         {Code: "74", PostalPrefixes: []string{"93"}},
+	"gorontalo":                 {Code: "75", PostalPrefixes: []string{"96"}},
+	"sulawesi barat":            {Code: "76", PostalPrefixes: []string{"91"}},
+	"maluku":                    {Code: "81", PostalPrefixes: []string{"97"}},
+	"maluku utara":              {Code: "82", PostalPrefixes: []string{"97"}},
+	"papua":                     {Code: "91", PostalPrefixes: []string{"98", "99"}},
+	"papua barat":               {Code: "92", PostalPrefixes: []string{"98"}},
+	"papua selatan":             {Code: "93", PostalPrefixes: []string{"99"}},
+	"papua tengah":              {Code: "94", PostalPrefixes: []string{"98"}},
+	"papua pegunungan":          {Code: "95", PostalPrefixes: []string{"99"}},
+	"papua barat daya":          {Code: "96", PostalPrefixes: []string{"98"}},
+}
+
+// provinceAliases maps common short or alternative names to the names used in provinces
+var provinceAliases = map[string]string{
+	"nad":                           "aceh",
+	"nanggroe aceh darussalam":      "aceh",
+	"sumut":                         "sumatera utara",
+	"sumbar":                        "sumatera barat",
+	"sumsel":                        "sumatera selatan",
+	"babel":                         "kepulauan bangka belitung",
+	"bangka belitung":               "kepulauan bangka belitung",
+	"kepri":                         "kepulauan riau",
+	"jakarta":                       "dki jakarta",
+	"daerah khusus ibukota jakarta": "dki jakarta",
+	"jabar":                         "jawa barat",
+	"jateng":                        "jawa tengah",
+	"jatim":                         "jawa timur",
+	"yogyakarta":                    "di yogyakarta",
+	"diy":                           "di yogyakarta",
+	"daerah istimewa yogyakarta":    "di yogyakarta",
+	"ntb":                           "nusa tenggara barat",
+	"ntt":                           "nusa tenggara timur",
+	"kalbar":                        "kalimantan barat",
+	"kalteng":                       "kalimantan tengah",
+	"kalsel":                        "kalimantan selatan",
+	"kaltim":                        "kalimantan timur",
+	"kaltara":                       "kalimantan utara",
+	"sulut":                         "sulawesi utara",
+	"sulteng":                       "sulawesi tengah",
+	"sulsel":                        "sulawesi selatan",
+	"sultra":                        "sulawesi tenggara",
+	"sulbar":                        "sulawesi barat",
+	"malut":                         "maluku utara",
+}
+
+// lookupProvince finds a province by name, ignoring case, a "provinsi" prefix and known aliases
+func lookupProvince(name string) (province, bool) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	normalized = strings.TrimPrefix(normalized, "provinsi ")
+	if alias, ok := provinceAliases[normalized]; ok {
+		normalized = alias
+	}
+	p, ok := provinces[normalized]
+	return p, ok
+}
+
+// isProvinceCode reports whether a NIK region code belongs to a known province
+func isProvinceCode(code string) bool {
+	for _, p := range provinces {
+		if p.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateNIK checks the structure of a 16 digit NIK: a known province code, regency and district
+// codes, a birth date matching dateOfBirth (day + 40 for women) and a non-zero serial number.
+// It returns the gender encoded in the NIK.
+func ValidateNIK(nik string, dateOfBirth time.Time) (Gender, error) {
+	if len(nik) != 16 || !digitsRegex.MatchString(nik) {
+		return "", errors.New("must be 16 digits")
+	}
+	if !isProvinceCode(nik[0:2]) {
+		return "", fmt.Errorf("region code %s is not a known province", nik[0:2])
+	}
+	if nik[2:4] == "00" || nik[4:6] == "00" {
+		return "", errors.New("regency and district codes must not be 00")
+	}
+
+	day, _ := strconv.Atoi(nik[6:8])
+	month, _ := strconv.Atoi(nik[8:10])
+	year, _ := strconv.Atoi(nik[10:12])
+
+	gender := GenderMale
+	if day > 40 {
+		gender = GenderFemale
+		day -= 40
+	}
+	if day < 1 || day > 31 || month < 1 || month > 12 {
+		return "", errors.New("birth date section is not a valid date")
+	}
+	if day != dateOfBirth.Day() || month != int(dateOfBirth.Month()) || year != dateOfBirth.Year()%100 {
+		return "", errors.New("birth date does not match date_of_birth")
+	}
+
+	if nik[12:16] == "0000" {
+		return "", errors.New("serial number must not be 0000")
+	}
+	return gender, nil
+}
+
+// NormalizeNPWP validates an NPWP, formatted (12.345.678.9-123.000) or not, and returns its digits.
+// Both the 15 digit format and the 16 digit format introduced in 2024 are accepted.
+func NormalizeNPWP(npwp string) (string, error) {
+	digits := npwpSeparators.Replace(npwp)
+	if !digitsRegex.MatchString(digits) || (len(digits) != 15 && len(digits) != 16) {
+		return "", errors.New("must be 15 or 16 digits")
+	}
+	return digits, nil
+}
+
+// NormalizePhoneNumber converts an Indonesian phone number (08xx, 628xx or +628xx, with optional
+// separators) to E.164 format (+628xx)
+func NormalizePhoneNumber(phone string) (string, error) {
+	number := phoneFormatter.Replace(strings.TrimSpace(phone))
+
+	var national string
+	switch {
+	case strings.HasPrefix(number, "+62"):
+		national = number[3:]
+	case strings.HasPrefix(number, "62"):
+		national = number[2:]
+	case strings.HasPrefix(number, "0"):
+		national = number[1:]
+	default:
+		return "", errors.New("must be an Indonesian number starting with 0, 62 or +62")
+	}
+
+	// National significant numbers are 8-12 digits without a trunk prefix
+	if !digitsRegex.MatchString(national) || strings.HasPrefix(national, "0") || len(national) < 8 || len(national) > 12 {
+		return "", errors.New("is not a valid Indonesian phone number")
+	}
+	return "+62" + national, nil
+}
+
+// ValidatePostalCode checks that a postal code has 5 digits and belongs to the province
+func ValidatePostalCode(postalCode, provinceName string) error {
+	if len(postalCode) != 5 || !digitsRegex.MatchString(postalCode) {
+		return errors.New("must be 5 digits")
+	}
+	p, ok := lookupProvince(provinceName)
+	if !ok {
+		// Unknown provinces are reported on the province field
+		return nil
+	}
+	for _, prefix := range p.PostalPrefixes {
+		if strings.HasPrefix(postalCode, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("does not belong to province %s", provinceName)
+}
+
+// ValidateProvince checks that the province is a known Indonesian province
+func ValidateProvince(provinceName string) error {
+	if _, ok := lookupProvince(provinceName); !ok {
+		return errors.New("is not a known Indonesian province")
+	}
+	return nil
+}
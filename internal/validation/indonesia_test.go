@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNIK(t *testing.T) {
+	dob := time.Date(1990, time.August, 17, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		nik        string
+		wantGender Gender
+		wantErr    bool
+	}{
+		{name: "male", nik: "3273011708900001", wantGender: GenderMale},
+		{name: "female day plus 40", nik: "3273015708900002", wantGender: GenderFemale},
+		{name: "too short", nik: "327301170890001", wantErr: true},
+		{name: "not digits", nik: "32730117089O0001", wantErr: true},
+		{name: "unknown province", nik: "9973011708900001", wantErr: true},
+		{name: "zero regency", nik: "3200011708900001", wantErr: true},
+		{name: "birth date mismatch", nik: "3273011808900001", wantErr: true},
+		{name: "invalid month", nik: "3273011713900001", wantErr: true},
+		{name: "zero serial", nik: "3273011708900000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gender, err := ValidateNIK(tt.nik, dob)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantGender, gender)
+		})
+	}
+}
+
+func TestNormalizeNPWP(t *testing.T) {
+	digits, err := NormalizeNPWP("12.345.678.9-123.000")
+	require.NoError(t, err)
+	assert.Equal(t, "123456789123000", digits)
+
+	digits, err = NormalizeNPWP("3273011708900001")
+	require.NoError(t, err)
+	assert.Equal(t, "3273011708900001", digits)
+
+	_, err = NormalizeNPWP("12.345.678.9-123")
+	assert.Error(t, err)
+	_, err = NormalizeNPWP("12.345.678.9-123.00A")
+	assert.Error(t, err)
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "081234567890", want: "+6281234567890"},
+		{input: "0812-3456-7890", want: "+6281234567890"},
+		{input: "6281234567890", want: "+6281234567890"},
+		{input: "+62 812 3456 7890", want: "+6281234567890"},
+		{input: "(022) 7654321", want: "+62227654321"},
+		{input: "+1 415 555 0100", wantErr: true},
+		{input: "+6208123456789", wantErr: true},
+		{input: "0812345", wantErr: true},
+		{input: "08123456789012345", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizePhoneNumber(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidatePostalCode(t *testing.T) {
+	assert.NoError(t, ValidatePostalCode("40115", "Jawa Barat"))
+	assert.NoError(t, ValidatePostalCode("40115", "provinsi  JAWA barat"))
+	assert.NoError(t, ValidatePostalCode("12190", "Jakarta"))
+	assert.NoError(t, ValidatePostalCode("55281", "DIY"))
+
+	assert.Error(t, ValidatePostalCode("40115", "Bali"))
+	assert.Error(t, ValidatePostalCode("4011", "Jawa Barat"))
+	assert.Error(t, ValidatePostalCode("4011A", "Jawa Barat"))
+
+	assert.NoError(t, ValidateProvince("Kaltim"))
+	assert.Error(t, ValidateProvince("Atlantis"))
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	assert.NoError(t, errs.Err())
+
+	errs.Add("personal_info.id_number", "must be 16 digits")
+	errs.Add("personal_info.postal_code", "must be 5 digits")
+	require.Error(t, errs.Err())
+	assert.Equal(t, "personal_info.id_number: must be 16 digits; personal_info.postal_code: must be 5 digits", errs.Error())
+}
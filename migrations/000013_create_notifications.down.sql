@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_user_unread;
+DROP INDEX IF EXISTS idx_notifications_user_created_at;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,24 @@
+-- =====================
+-- INVESTOR NOTIFICATIONS
+-- =====================
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB,
+
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+COMMENT ON COLUMN notifications.type IS 'crop_ready, harvest_synced, level_up, achievement_unlocked, invoice_fully_funded';
+COMMENT ON COLUMN notifications.data IS 'Event payload (crop_id, invoice_id, level, etc.) for deep linking';
+COMMENT ON COLUMN notifications.read_at IS 'NULL while the notification is unread';
+
+-- Indexes
+CREATE INDEX idx_notifications_user_created_at ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_farmer_notification_deliveries_due;
+DROP INDEX IF EXISTS idx_farmer_notification_deliveries_farmer_id;
+DROP TABLE IF EXISTS farmer_notification_deliveries;
+
+ALTER TABLE farmers DROP COLUMN IF EXISTS preferred_language;
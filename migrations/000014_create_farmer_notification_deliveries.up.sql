@@ -0,0 +1,39 @@
+-- =====================
+-- FARMER NOTIFICATIONS
+-- =====================
+
+-- Preferred language for farmer notification templates
+ALTER TABLE farmers ADD COLUMN preferred_language VARCHAR(5) NOT NULL DEFAULT 'id';
+
+COMMENT ON COLUMN farmers.preferred_language IS 'Notification language: id (Bahasa Indonesia), en (English)';
+
+CREATE TABLE farmer_notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    farmer_id UUID NOT NULL REFERENCES farmers(id),
+
+    event VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    language VARCHAR(5) NOT NULL,
+    subject VARCHAR(255),
+    body TEXT NOT NULL,
+
+    -- Delivery state
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT now(),
+    sent_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+COMMENT ON COLUMN farmer_notification_deliveries.event IS 'farmer_approved, farmer_rejected, invoice_approved, invoice_rejected';
+COMMENT ON COLUMN farmer_notification_deliveries.channel IS 'email, sms, whatsapp';
+COMMENT ON COLUMN farmer_notification_deliveries.status IS 'pending (waiting for next attempt), sent, failed (attempts exhausted)';
+COMMENT ON COLUMN farmer_notification_deliveries.next_attempt_at IS 'When the retry worker may pick up the delivery again';
+
+-- Indexes
+CREATE INDEX idx_farmer_notification_deliveries_farmer_id ON farmer_notification_deliveries(farmer_id);
+CREATE INDEX idx_farmer_notification_deliveries_due ON farmer_notification_deliveries(next_attempt_at) WHERE status = 'pending';
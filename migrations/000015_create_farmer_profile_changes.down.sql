@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_farmer_profile_changes_one_pending;
+DROP INDEX IF EXISTS idx_farmer_profile_changes_status;
+DROP INDEX IF EXISTS idx_farmer_profile_changes_farmer_id;
+DROP TABLE IF EXISTS farmer_profile_changes;
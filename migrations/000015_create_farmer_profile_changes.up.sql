@@ -0,0 +1,33 @@
+-- =====================
+-- FARMER PROFILE CHANGES
+-- =====================
+
+CREATE TABLE farmer_profile_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    farmer_id UUID NOT NULL REFERENCES farmers(id),
+
+    -- Changed fields as {"field": value}
+    old_values JSONB NOT NULL,
+    new_values JSONB NOT NULL,
+    requires_review BOOLEAN NOT NULL DEFAULT false,
+
+    status VARCHAR(20) NOT NULL,
+
+    -- Admin review
+    reviewed_by UUID REFERENCES admin_users(id),
+    reviewed_at TIMESTAMP,
+    rejection_reason TEXT,
+
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+COMMENT ON COLUMN farmer_profile_changes.requires_review IS 'true for sensitive fields (bank account, id_number, npwp) that need admin approval';
+COMMENT ON COLUMN farmer_profile_changes.status IS 'applied (direct edit), pending, approved, rejected';
+
+-- Indexes
+CREATE INDEX idx_farmer_profile_changes_farmer_id ON farmer_profile_changes(farmer_id, created_at DESC);
+CREATE INDEX idx_farmer_profile_changes_status ON farmer_profile_changes(status);
+
+-- At most one sensitive change waiting for review per farmer
+CREATE UNIQUE INDEX idx_farmer_profile_changes_one_pending ON farmer_profile_changes(farmer_id) WHERE status = 'pending';
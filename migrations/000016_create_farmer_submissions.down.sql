@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_farmer_submissions_farmer_id;
+DROP TABLE IF EXISTS farmer_submissions;
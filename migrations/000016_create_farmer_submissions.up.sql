@@ -0,0 +1,49 @@
+-- =====================
+-- FARMER SUBMISSIONS
+-- =====================
+
+CREATE TABLE farmer_submissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    farmer_id UUID NOT NULL REFERENCES farmers(id),
+    submission_number INT NOT NULL,
+
+    -- Profile and documents exactly as submitted
+    snapshot JSONB NOT NULL,
+
+    -- Review decision
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reviewed_by UUID REFERENCES admin_users(id),
+    reviewed_at TIMESTAMP,
+    rejection_reason TEXT,
+
+    submitted_at TIMESTAMP DEFAULT now(),
+
+    UNIQUE (farmer_id, submission_number)
+);
+
+COMMENT ON COLUMN farmer_submissions.submission_number IS '1 for the original registration, incremented on every resubmission';
+COMMENT ON COLUMN farmer_submissions.status IS 'pending, approved, rejected';
+
+-- Indexes
+CREATE INDEX idx_farmer_submissions_farmer_id ON farmer_submissions(farmer_id);
+
+-- Existing registrations become submission 1
+INSERT INTO farmer_submissions (farmer_id, submission_number, snapshot, status, reviewed_by, reviewed_at, rejection_reason, submitted_at)
+SELECT
+    f.id,
+    1,
+    to_jsonb(f) || jsonb_build_object('documents', COALESCE(
+        (SELECT jsonb_agg(to_jsonb(d)) FROM farmer_documents d WHERE d.farmer_id = f.id),
+        '[]'::jsonb
+    )),
+    CASE f.status
+        WHEN 'approved' THEN 'approved'
+        WHEN 'suspended' THEN 'approved'
+        WHEN 'rejected' THEN 'rejected'
+        ELSE 'pending'
+    END,
+    f.reviewed_by,
+    f.reviewed_at,
+    f.rejection_reason,
+    f.created_at
+FROM farmers f;
@@ -0,0 +1,12 @@
+-- =====================
+-- FARMER SUSPENSION
+-- =====================
+
+ALTER TABLE farmers
+    ADD COLUMN suspended_by UUID REFERENCES admin_users(id),
+    ADD COLUMN suspended_at TIMESTAMP,
+    ADD COLUMN suspension_reason TEXT;
+
+COMMENT ON COLUMN farmers.reviewed_by IS 'Admin who reviewed the registration, or who claimed it while status is under_review';
+COMMENT ON COLUMN farmers.suspended_by IS 'Admin who suspended the farmer, cleared on reinstatement';
+COMMENT ON COLUMN farmers.suspension_reason IS 'Reason given when suspending an approved farmer';
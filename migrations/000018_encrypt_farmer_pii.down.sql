@@ -0,0 +1,9 @@
+-- Encrypted values cannot be converted back in SQL; run cmd/pii-rotate -decrypt first
+DROP INDEX IF EXISTS idx_farmers_id_number_bidx;
+
+ALTER TABLE farmers
+    DROP COLUMN IF EXISTS id_number_bidx,
+    ALTER COLUMN date_of_birth TYPE DATE USING date_of_birth::date,
+    ALTER COLUMN bank_account_number TYPE VARCHAR(30),
+    ALTER COLUMN npwp TYPE VARCHAR(30),
+    ALTER COLUMN id_number TYPE VARCHAR(20);
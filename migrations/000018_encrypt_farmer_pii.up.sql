@@ -0,0 +1,20 @@
+-- =====================
+-- FARMER PII ENCRYPTION
+-- =====================
+
+-- Encrypted values are longer than the plaintext and dates are stored as encrypted text.
+-- Existing plaintext values stay readable and are encrypted by running cmd/pii-rotate.
+ALTER TABLE farmers
+    ALTER COLUMN id_number TYPE TEXT,
+    ALTER COLUMN npwp TYPE TEXT,
+    ALTER COLUMN bank_account_number TYPE TEXT,
+    ALTER COLUMN date_of_birth TYPE TEXT USING to_char(date_of_birth, 'YYYY-MM-DD'),
+    ADD COLUMN id_number_bidx VARCHAR(64);
+
+CREATE INDEX idx_farmers_id_number_bidx ON farmers(id_number_bidx);
+
+COMMENT ON COLUMN farmers.id_number IS 'NIK, envelope encrypted (enc:v1:<key id>:<wrapped data key>:<ciphertext>)';
+COMMENT ON COLUMN farmers.npwp IS 'NPWP, envelope encrypted';
+COMMENT ON COLUMN farmers.bank_account_number IS 'Bank account number, envelope encrypted';
+COMMENT ON COLUMN farmers.date_of_birth IS 'Date of birth as YYYY-MM-DD, envelope encrypted';
+COMMENT ON COLUMN farmers.id_number_bidx IS 'HMAC-SHA256 blind index of the NIK for duplicate checks';
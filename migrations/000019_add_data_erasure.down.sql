@@ -0,0 +1,8 @@
+-- The 'erased' farmer_status value cannot be dropped from the enum and is left in place
+ALTER TABLE users
+    DROP COLUMN IF EXISTS erased_by,
+    DROP COLUMN IF EXISTS erased_at;
+
+ALTER TABLE farmers
+    DROP COLUMN IF EXISTS erased_by,
+    DROP COLUMN IF EXISTS erased_at;
@@ -0,0 +1,19 @@
+-- =====================
+-- DATA SUBJECT ERASURE
+-- =====================
+
+-- Terminal status for farmers whose personal data was erased
+ALTER TYPE farmer_status ADD VALUE IF NOT EXISTS 'erased';
+
+ALTER TABLE farmers
+    ADD COLUMN erased_at TIMESTAMP,
+    ADD COLUMN erased_by UUID REFERENCES admin_users(id);
+
+ALTER TABLE users
+    ADD COLUMN erased_at TIMESTAMP,
+    ADD COLUMN erased_by UUID REFERENCES admin_users(id);
+
+COMMENT ON COLUMN farmers.erased_at IS 'When the personal data of the farmer was anonymized; financial records are kept';
+COMMENT ON COLUMN farmers.erased_by IS 'Admin who executed the erasure request';
+COMMENT ON COLUMN users.erased_at IS 'When the personal data of the investor was anonymized; investments are kept';
+COMMENT ON COLUMN users.erased_by IS 'Admin who executed the erasure request';
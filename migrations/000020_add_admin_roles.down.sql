@@ -0,0 +1,7 @@
+ALTER TABLE admin_users
+    DROP CONSTRAINT IF EXISTS chk_admin_users_role,
+    ALTER COLUMN role DROP NOT NULL,
+    ALTER COLUMN role SET DEFAULT 'admin';
+
+-- Without role checks every admin has full access again
+UPDATE admin_users SET role = 'admin';
@@ -0,0 +1,13 @@
+-- =====================
+-- ADMIN ROLES
+-- =====================
+
+-- Every existing admin had full access
+UPDATE admin_users SET role = 'super_admin' WHERE role IS NULL OR role = 'admin';
+
+ALTER TABLE admin_users
+    ALTER COLUMN role SET DEFAULT 'read_only',
+    ALTER COLUMN role SET NOT NULL,
+    ADD CONSTRAINT chk_admin_users_role CHECK (role IN ('super_admin', 'reviewer', 'finance', 'read_only'));
+
+COMMENT ON COLUMN admin_users.role IS 'Permission set of the admin: super_admin, reviewer, finance or read_only';
@@ -0,0 +1,6 @@
+ALTER TABLE admin_users
+    DROP COLUMN IF EXISTS tokens_revoked_at,
+    DROP COLUMN IF EXISTS deactivation_reason,
+    DROP COLUMN IF EXISTS deactivated_by,
+    DROP COLUMN IF EXISTS deactivated_at,
+    DROP COLUMN IF EXISTS invited_by;
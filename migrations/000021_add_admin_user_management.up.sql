@@ -0,0 +1,16 @@
+-- =====================
+-- ADMIN USER MANAGEMENT
+-- =====================
+
+ALTER TABLE admin_users
+    ADD COLUMN invited_by UUID REFERENCES admin_users(id),
+    ADD COLUMN deactivated_at TIMESTAMP,
+    ADD COLUMN deactivated_by UUID REFERENCES admin_users(id),
+    ADD COLUMN deactivation_reason TEXT,
+    ADD COLUMN tokens_revoked_at TIMESTAMP;
+
+COMMENT ON COLUMN admin_users.invited_by IS 'Super admin who invited the admin, NULL for seeded admins';
+COMMENT ON COLUMN admin_users.deactivated_at IS 'When the admin was last deactivated, cleared on reactivation';
+COMMENT ON COLUMN admin_users.deactivated_by IS 'Super admin who deactivated the admin';
+COMMENT ON COLUMN admin_users.deactivation_reason IS 'Reason given for the deactivation';
+COMMENT ON COLUMN admin_users.tokens_revoked_at IS 'Admin JWTs issued before this time are rejected';
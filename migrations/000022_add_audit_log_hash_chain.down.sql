@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS audit_log_anchors;
+
+DROP INDEX IF EXISTS idx_admin_audit_logs_sequence;
+
+ALTER TABLE admin_audit_logs
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,43 @@
+-- =====================
+-- AUDIT LOG HASH CHAIN
+-- =====================
+
+ALTER TABLE admin_audit_logs
+    ADD COLUMN sequence BIGINT,
+    ADD COLUMN prev_hash VARCHAR(64),
+    ADD COLUMN hash VARCHAR(64);
+
+-- Existing entries keep their order. Their hashes are filled in by the next audit log write
+-- or by go run ./cmd/audit-verify -seal
+UPDATE admin_audit_logs l
+SET sequence = o.sequence
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS sequence
+    FROM admin_audit_logs
+) o
+WHERE l.id = o.id;
+
+ALTER TABLE admin_audit_logs ALTER COLUMN sequence SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_admin_audit_logs_sequence ON admin_audit_logs(sequence);
+
+COMMENT ON COLUMN admin_audit_logs.sequence IS 'Position in the hash chain, starting at 1 without gaps';
+COMMENT ON COLUMN admin_audit_logs.prev_hash IS 'Hash of the previous entry, zeros for the first entry';
+COMMENT ON COLUMN admin_audit_logs.hash IS 'SHA-256 of the entry and prev_hash, see internal/auditchain';
+
+CREATE TABLE audit_log_anchors (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_sequence BIGINT NOT NULL,
+    to_sequence BIGINT NOT NULL,
+    merkle_root VARCHAR(64) NOT NULL,
+    tx_hash VARCHAR(66),
+    anchored_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT now(),
+
+    CONSTRAINT chk_audit_log_anchors_range CHECK (from_sequence <= to_sequence)
+);
+
+CREATE UNIQUE INDEX idx_audit_log_anchors_to_sequence ON audit_log_anchors(to_sequence);
+
+COMMENT ON COLUMN audit_log_anchors.merkle_root IS 'Merkle root of the hashes of entries from_sequence to to_sequence';
+COMMENT ON COLUMN audit_log_anchors.tx_hash IS 'Transaction that published the root on-chain, NULL until anchored';
@@ -0,0 +1,11 @@
+-- Entries of other actors cannot be kept without an admin
+DELETE FROM admin_audit_logs WHERE admin_id IS NULL;
+
+DROP INDEX IF EXISTS idx_admin_audit_logs_entity;
+DROP INDEX IF EXISTS idx_admin_audit_logs_actor;
+
+ALTER TABLE admin_audit_logs
+    DROP CONSTRAINT IF EXISTS chk_admin_audit_logs_actor_type,
+    ALTER COLUMN admin_id SET NOT NULL,
+    DROP COLUMN IF EXISTS actor_id,
+    DROP COLUMN IF EXISTS actor_type;
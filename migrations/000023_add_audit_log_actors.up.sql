@@ -0,0 +1,22 @@
+-- =====================
+-- AUDIT LOG ACTORS
+-- =====================
+
+-- Audit logs also record actions of farmers, investors and the system. admin_id stays set for
+-- admin actors, so existing queries and hashes keep working.
+ALTER TABLE admin_audit_logs
+    ADD COLUMN actor_type VARCHAR(20) NOT NULL DEFAULT 'admin',
+    ADD COLUMN actor_id UUID;
+
+UPDATE admin_audit_logs SET actor_id = admin_id;
+
+ALTER TABLE admin_audit_logs
+    ALTER COLUMN admin_id DROP NOT NULL,
+    ADD CONSTRAINT chk_admin_audit_logs_actor_type CHECK (actor_type IN ('admin', 'farmer', 'investor', 'system'));
+
+CREATE INDEX idx_admin_audit_logs_actor ON admin_audit_logs(actor_type, actor_id);
+CREATE INDEX idx_admin_audit_logs_entity ON admin_audit_logs(entity_type, entity_id);
+
+COMMENT ON COLUMN admin_audit_logs.actor_type IS 'Kind of account that performed the action: admin, farmer, investor or system';
+COMMENT ON COLUMN admin_audit_logs.actor_id IS 'ID of the admin user, farmer or user, NULL for system and anonymous actions';
+COMMENT ON COLUMN admin_audit_logs.admin_id IS 'Set when actor_type is admin';
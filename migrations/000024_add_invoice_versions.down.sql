@@ -0,0 +1,4 @@
+-- The 'withdrawn' invoice_status value cannot be dropped from the enum and is left in place
+DROP TABLE IF EXISTS invoice_versions;
+
+ALTER TABLE invoices DROP COLUMN IF EXISTS withdrawn_at;
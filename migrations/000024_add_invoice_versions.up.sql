@@ -0,0 +1,51 @@
+-- =====================
+-- INVOICE VERSIONS AND WITHDRAWAL
+-- =====================
+
+-- Pending invoices withdrawn by their farmer before review
+ALTER TYPE invoice_status ADD VALUE IF NOT EXISTS 'withdrawn';
+
+ALTER TABLE invoices ADD COLUMN withdrawn_at TIMESTAMP;
+
+COMMENT ON COLUMN invoices.withdrawn_at IS 'When the farmer withdrew the invoice before review';
+
+CREATE TABLE invoice_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    version_number INT NOT NULL,
+
+    -- Editable fields exactly as submitted or edited
+    snapshot JSONB NOT NULL,
+
+    -- Fields changed since the previous version, empty for version 1
+    old_values JSONB NOT NULL DEFAULT '{}'::jsonb,
+    new_values JSONB NOT NULL DEFAULT '{}'::jsonb,
+
+    edited_by UUID REFERENCES farmers(id),
+    created_at TIMESTAMP DEFAULT now(),
+
+    UNIQUE (invoice_id, version_number)
+);
+
+COMMENT ON COLUMN invoice_versions.version_number IS '1 for the submitted invoice, incremented on every edit while pending';
+
+-- Indexes
+CREATE INDEX idx_invoice_versions_invoice_id ON invoice_versions(invoice_id);
+
+-- Existing invoices become version 1
+INSERT INTO invoice_versions (invoice_id, version_number, snapshot, created_at)
+SELECT
+    i.id,
+    1,
+    jsonb_build_object(
+        'farm_id', i.farm_id,
+        'name', i.name,
+        'description', i.description,
+        'image_url', i.image_url,
+        'target_fund', i.target_fund::text,
+        'yield_percent', i.yield_percent::text,
+        'duration_days', i.duration_days,
+        'offtaker_id', i.offtaker_id
+    ),
+    i.created_at
+FROM invoices i;
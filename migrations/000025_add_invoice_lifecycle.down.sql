@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS invoice_status_transitions;
+
+-- Every state after approval maps back to 'approved'
+CREATE TYPE invoice_status_v1 AS ENUM ('pending', 'approved', 'rejected', 'withdrawn');
+
+ALTER TABLE invoices ALTER COLUMN status DROP DEFAULT;
+
+ALTER TABLE invoices ALTER COLUMN status TYPE invoice_status_v1 USING (
+    CASE
+        WHEN status IN ('pending', 'rejected', 'withdrawn') THEN status::text
+        ELSE 'approved'
+    END
+)::invoice_status_v1;
+
+DROP TYPE invoice_status;
+ALTER TYPE invoice_status_v1 RENAME TO invoice_status;
+
+ALTER TABLE invoices ALTER COLUMN status SET DEFAULT 'pending';
+
+COMMENT ON COLUMN invoices.status IS 'Invoice approval status: pending, approved, rejected';
+COMMENT ON COLUMN invoices.funding_deadline IS NULL;
+COMMENT ON COLUMN invoices.maturity_date IS NULL;
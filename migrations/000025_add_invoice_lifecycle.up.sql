@@ -0,0 +1,58 @@
+-- =====================
+-- INVOICE LIFECYCLE
+-- =====================
+
+-- Approved invoices move through the funding and crop cycle instead of staying 'approved'.
+-- The enum is recreated because values added with ALTER TYPE cannot be used in the same
+-- transaction, and 'approved' is replaced by 'funding' or 'funded'.
+CREATE TYPE invoice_status_v2 AS ENUM (
+    'pending',
+    'rejected',
+    'withdrawn',
+    'funding',
+    'funded',
+    'funding_failed',
+    'growing',
+    'matured',
+    'completed',
+    'defaulted'
+);
+
+ALTER TABLE invoices ALTER COLUMN status DROP DEFAULT;
+
+ALTER TABLE invoices ALTER COLUMN status TYPE invoice_status_v2 USING (
+    CASE
+        WHEN status = 'approved' AND is_fully_funded THEN 'funded'
+        WHEN status = 'approved' THEN 'funding'
+        ELSE status::text
+    END
+)::invoice_status_v2;
+
+DROP TYPE invoice_status;
+ALTER TYPE invoice_status_v2 RENAME TO invoice_status;
+
+ALTER TABLE invoices ALTER COLUMN status SET DEFAULT 'pending';
+
+COMMENT ON COLUMN invoices.status IS 'Invoice lifecycle status: pending, rejected, withdrawn, funding, funded, funding_failed, growing, matured, completed, defaulted';
+COMMENT ON COLUMN invoices.funding_deadline IS 'End of the funding window; unfunded invoices fail and funded invoices start growing';
+COMMENT ON COLUMN invoices.maturity_date IS 'End of the crop cycle, set when the invoice starts growing';
+
+CREATE TABLE invoice_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    from_status invoice_status NOT NULL,
+    to_status invoice_status NOT NULL,
+    reason TEXT NOT NULL,
+
+    -- Who caused the transition: the reviewing admin, the farmer, or the system for
+    -- transitions driven by time and chain data
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id UUID,
+
+    created_at TIMESTAMP DEFAULT now(),
+
+    CONSTRAINT chk_invoice_status_transitions_actor_type CHECK (actor_type IN ('admin', 'farmer', 'investor', 'system'))
+);
+
+-- Indexes
+CREATE INDEX idx_invoice_status_transitions_invoice_id ON invoice_status_transitions(invoice_id, created_at);
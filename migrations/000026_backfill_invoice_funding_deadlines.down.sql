@@ -0,0 +1,5 @@
+-- Backfilled deadlines and maturity dates are kept
+DROP INDEX IF EXISTS idx_invoices_funding_deadline;
+
+COMMENT ON COLUMN invoices.funding_deadline IS 'End of the funding window; unfunded invoices fail and funded invoices start growing';
+COMMENT ON COLUMN invoices.maturity_date IS 'End of the crop cycle, set when the invoice starts growing';
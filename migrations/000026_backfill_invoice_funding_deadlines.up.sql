@@ -0,0 +1,21 @@
+-- =====================
+-- INVOICE FUNDING DEADLINES
+-- =====================
+
+-- Approval now sets the funding deadline and the maturity date. Invoices approved before
+-- get the default 30 day funding window from their approval; the lifecycle worker fails
+-- the ones whose window has already passed without reaching the target.
+UPDATE invoices
+SET funding_deadline = COALESCE(approved_at, reviewed_at, created_at) + INTERVAL '30 days'
+WHERE status IN ('funding', 'funded')
+  AND funding_deadline IS NULL;
+
+UPDATE invoices
+SET maturity_date = funding_deadline + make_interval(days => duration_days)
+WHERE status IN ('funding', 'funded')
+  AND maturity_date IS NULL;
+
+CREATE INDEX idx_invoices_funding_deadline ON invoices(funding_deadline) WHERE status = 'funding';
+
+COMMENT ON COLUMN invoices.funding_deadline IS 'End of the funding window, set on approval; unfunded invoices fail and funded invoices start growing';
+COMMENT ON COLUMN invoices.maturity_date IS 'End of the crop cycle, duration_days after the funding deadline';
@@ -0,0 +1,2 @@
+-- Normalized phone numbers are kept
+COMMENT ON COLUMN farmers.phone_number IS NULL;
@@ -0,0 +1,15 @@
+-- =====================
+-- FARMER PHONE NUMBERS
+-- =====================
+
+-- Registration and profile updates store phone numbers in E.164 (+62...), and duplicate
+-- checks compare that form. Rows saved before as 08... or 62..., with or without spaces,
+-- dashes, dots or parentheses, are rewritten the same way so they are found as duplicates.
+-- Numbers that are not valid Indonesian numbers, and erased farmers, are left as they are.
+UPDATE farmers
+SET phone_number = '+62' || substring(regexp_replace(phone_number, '[ .()-]', '', 'g') FROM '^(?:\+62|62|0)([1-9][0-9]{7,11})$'),
+    updated_at = NOW()
+WHERE regexp_replace(phone_number, '[ .()-]', '', 'g') ~ '^(\+62|62|0)[1-9][0-9]{7,11}$'
+  AND phone_number !~ '^\+62[1-9][0-9]{7,11}$';
+
+COMMENT ON COLUMN farmers.phone_number IS 'Indonesian phone number in E.164 (+62 followed by 8-12 digits)';